@@ -1,5 +1,5 @@
 /*
-Package gortsplib is a RTSP 1.0 library for the Go programming language,
+Package gortsplib is a RTSP 1.0 and RTSP 2.0 library for the Go programming language,
 written for rtsp-simple-server.
 
 Examples are available at https://github.com/cobalt-robotics/gortsplib/tree/master/examples
@@ -24,14 +24,18 @@ import (
 	"github.com/cobalt-robotics/gortsplib/pkg/base"
 	"github.com/cobalt-robotics/gortsplib/pkg/conn"
 	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+	"github.com/cobalt-robotics/gortsplib/pkg/httptunnel"
 	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
 	"github.com/cobalt-robotics/gortsplib/pkg/ringbuffer"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtcpreceiver"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtcpsender"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtpcleaner"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtpreorderer"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpretransmitter"
 	"github.com/cobalt-robotics/gortsplib/pkg/sdp"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
 	"github.com/cobalt-robotics/gortsplib/pkg/url"
+	"github.com/cobalt-robotics/gortsplib/pkg/wsconn"
 )
 
 func isAnyPort(p int) bool {
@@ -73,6 +77,25 @@ func findBaseURL(sd *sdp.SessionDescription, res *base.Response, u *url.URL) (*u
 	return u, nil
 }
 
+// hostWithDefaultPort adds the default port of the scheme to a host, if it is missing.
+func hostWithDefaultPort(scheme string, host string) string {
+	_, _, err := net.SplitHostPort(host)
+	if err == nil {
+		return host
+	}
+
+	switch scheme {
+	case "rtsp":
+		return net.JoinHostPort(host, "554")
+	case "rtsps":
+		return net.JoinHostPort(host, "322")
+	case "rtsph":
+		return net.JoinHostPort(host, "80")
+	default: // rtsphs
+		return net.JoinHostPort(host, "443")
+	}
+}
+
 type clientState int
 
 const (
@@ -83,21 +106,38 @@ const (
 	clientStateRecord
 )
 
+type clientSession struct {
+	baseURL *url.URL
+	id      string
+	tracks  []*clientTrack
+	active  bool // playing or recording
+}
+
 type clientTrack struct {
 	id              int
 	track           Track
+	session         *clientSession
 	tcpChannel      int
 	udpRTPListener  *clientUDPListener
 	udpRTCPListener *clientUDPListener
 
+	// SRTP crypto context, available when the RTP/SAVP profile is in use
+	srtpContext *srtp.Context
+
+	// feedback capabilities, available when the RTP/AVPF profile is in use
+	feedback *trackFeedback
+
 	// play
 	udpRTPPacketBuffer *rtpPacketMultiBuffer
 	udpRTCPReceiver    *rtcpreceiver.RTCPReceiver
 	reorderer          *rtpreorderer.Reorderer
 	cleaner            *rtpcleaner.Cleaner
+	feedbackReceiver   *trackFeedbackReceiver
+	fecReceiver        *trackFECReceiver
 
 	// record
 	udpRTCPSender *rtcpsender.RTCPSender
+	retransmitter *rtpretransmitter.Retransmitter
 }
 
 func (s clientState) String() string {
@@ -117,22 +157,26 @@ func (s clientState) String() string {
 }
 
 type optionsReq struct {
+	ctx context.Context
 	url *url.URL
 	res chan clientRes
 }
 
 type describeReq struct {
+	ctx context.Context
 	url *url.URL
 	res chan clientRes
 }
 
 type announceReq struct {
+	ctx    context.Context
 	url    *url.URL
 	tracks Tracks
 	res    chan clientRes
 }
 
 type setupReq struct {
+	ctx      context.Context
 	forPlay  bool
 	track    Track
 	baseURL  *url.URL
@@ -141,17 +185,36 @@ type setupReq struct {
 	res      chan clientRes
 }
 
+// ClientPlayOptions contains the options of a PLAY request.
+type ClientPlayOptions struct {
+	// (optional) range of the stream to play.
+	// It defaults to npt=0-.
+	Range *headers.Range
+
+	// (optional) rate at which the stream is played,
+	// used for fast forward, slow motion and reverse playback.
+	Scale *headers.Scale
+
+	// (optional) rate at which the stream is delivered.
+	Speed *headers.Speed
+}
+
 type playReq struct {
-	ra  *headers.Range
-	res chan clientRes
+	ctx     context.Context
+	baseURL *url.URL
+	opts    ClientPlayOptions
+	res     chan clientRes
 }
 
 type recordReq struct {
+	ctx context.Context
 	res chan clientRes
 }
 
 type pauseReq struct {
-	res chan clientRes
+	ctx     context.Context
+	baseURL *url.URL
+	res     chan clientRes
 }
 
 type clientRes struct {
@@ -176,6 +239,13 @@ type ClientOnPacketRTCPCtx struct {
 	Packet  rtcp.Packet
 }
 
+// ClientOnServerRequestCtx is the context of a request sent by the server.
+type ClientOnServerRequestCtx struct {
+	Request *base.Request
+	// tracks contained in an ANNOUNCE request.
+	Tracks Tracks
+}
+
 // Client is a RTSP client.
 type Client struct {
 	//
@@ -198,6 +268,24 @@ type Client struct {
 	// This can be a security issue.
 	// It defaults to false.
 	AnyPortEnable bool
+	// allow to setup tracks with different base URLs. Tracks with the same
+	// base URL are grouped into a session, and sessions are multiplexed on the
+	// same connection. Each session can be played and paused independently
+	// with PlaySession() and PauseSession().
+	// It defaults to false.
+	MultiSessionEnable bool
+	// encrypt published tracks with SRTP and the RTP/SAVP profile.
+	// Keys are sent to the server inside the SDP, through MIKEY, therefore
+	// TLS (rtsps) should be used too. When reading, SRTP is used automatically
+	// if the server provides keys.
+	// It defaults to false.
+	SRTPEnable bool
+	// advertise support for RTCP-based feedback (RTP/AVPF) when publishing,
+	// and keep a history of recent packets, in order to retransmit lost packets
+	// that are requested by the server with NACKs (UDP only).
+	// When reading, NACKs are sent automatically if the server supports them.
+	// It defaults to false.
+	RetransmissionEnable bool
 	// the stream transport (UDP, Multicast or TCP).
 	// If nil, it is chosen automatically (first UDP, then, if it fails, TCP).
 	// It defaults to nil.
@@ -218,6 +306,18 @@ type Client struct {
 	// user agent header
 	// It defaults to "gortsplib"
 	UserAgent string
+	// RTSP version used to communicate with the server.
+	// When RTSP 2.0 is not supported by the server, the client falls back to RTSP 1.0.
+	// It defaults to base.Version10.
+	Version base.Version
+	// policy used to reconnect automatically when the connection is lost
+	// while reading or publishing.
+	// If nil, the client doesn't reconnect and Wait() returns the error.
+	// While the client is waiting to reconnect, requests fail with ErrClientReconnecting.
+	// Reconnection is not supported when multiple sessions have been set up
+	// with MultiSessionEnable: in that case, the policy is ignored and Wait() returns the error.
+	// It defaults to nil.
+	ReconnectPolicy *ClientReconnectPolicy
 
 	//
 	// system functions (all optional)
@@ -240,6 +340,15 @@ type Client struct {
 	OnPacketRTP func(*ClientOnPacketRTPCtx)
 	// called when a RTCP packet arrives.
 	OnPacketRTCP func(*ClientOnPacketRTCPCtx)
+	// called when the connection is lost and ReconnectPolicy is set.
+	OnDisconnect func(*ClientOnDisconnectCtx)
+	// called when the client has reconnected successfully.
+	OnReconnect func(*ClientOnReconnectCtx)
+	// called when the server sends a request (REDIRECT, ANNOUNCE, GET_PARAMETER,
+	// SET_PARAMETER, PLAY_NOTIFY). The request is answered automatically.
+	// After a REDIRECT, a client that is reading or publishing is disconnected,
+	// and, if ReconnectPolicy is set, it reconnects to the new location.
+	OnServerRequest func(*ClientOnServerRequestCtx)
 
 	//
 	// private
@@ -250,33 +359,46 @@ type Client struct {
 	checkStreamPeriod       time.Duration
 	keepalivePeriod         time.Duration
 
-	scheme             string
-	host               string
-	ctx                context.Context
-	ctxCancel          func()
-	state              clientState
-	nconn              net.Conn
-	conn               *conn.Conn
-	session            string
-	sender             *auth.Sender
-	cseq               int
-	optionsSent        bool
-	useGetParameter    bool
-	lastDescribeURL    *url.URL
-	baseURL            *url.URL
-	effectiveTransport *Transport
-	tracks             []*clientTrack
-	tcpTracksByChannel map[int]*clientTrack
-	lastRange          *headers.Range
-	writeMutex         sync.RWMutex // publish
-	writeFrameAllowed  bool         // publish
-	checkStreamTimer   *time.Timer
-	checkStreamInitial bool
-	tcpLastFrameTime   *int64
-	keepaliveTimer     *time.Timer
-	closeError         error
-	writerRunning      bool
-	writeBuffer        *ringbuffer.RingBuffer
+	scheme                string
+	host                  string
+	version               base.Version
+	ctx                   context.Context
+	ctxCancel             func()
+	state                 clientState
+	nconn                 net.Conn
+	conn                  *conn.Conn
+	session               string
+	sender                *auth.Sender
+	cseq                  int
+	optionsSent           bool
+	useGetParameter       bool
+	lastDescribeURL       *url.URL
+	lastDescribeTracks    Tracks
+	lastDescribeKeys      []*srtpKey
+	lastDescribeFeedbacks []*trackFeedback
+	lastDescribeFECs      []*trackFEC
+	lastAnnounceURL       *url.URL
+	lastAnnounceTracks    Tracks
+	lastAnnounceKeys      []*srtpKey
+	lastAnnounceFeedbacks []*trackFeedback
+	baseURL               *url.URL
+	effectiveTransport    *Transport
+	sessions              []*clientSession
+	tracks                []*clientTrack
+	tcpTracksByChannel    map[int]*clientTrack
+	lastPlayOptions       ClientPlayOptions
+	requestCtx            context.Context
+	writeMutex            sync.RWMutex // publish
+	writeFrameAllowed     bool         // publish
+	checkStreamTimer      *time.Timer
+	checkStreamInitial    bool
+	tcpLastFrameTime      *int64
+	keepaliveTimer        *time.Timer
+	reconnectTimer        *time.Timer
+	reconnectState        *clientReconnectState
+	closeError            error
+	writerRunning         bool
+	writeBuffer           *ringbuffer.RingBuffer
 
 	// connCloser channels
 	connCloserTerminate chan struct{}
@@ -362,10 +484,12 @@ func (c *Client) Start(scheme string, host string) error {
 
 	c.scheme = scheme
 	c.host = host
+	c.version = c.Version
 	c.ctx = ctx
 	c.ctxCancel = ctxCancel
 	c.checkStreamTimer = emptyTimer()
 	c.keepaliveTimer = emptyTimer()
+	c.reconnectTimer = emptyTimer()
 	c.options = make(chan optionsReq)
 	c.describe = make(chan describeReq)
 	c.announce = make(chan announceReq)
@@ -456,31 +580,45 @@ func (c *Client) runInner() error {
 	for {
 		select {
 		case req := <-c.options:
+			c.requestCtx = req.ctx
 			res, err := c.doOptions(req.url)
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case req := <-c.describe:
+			c.requestCtx = req.ctx
 			tracks, baseURL, res, err := c.doDescribe(req.url)
+			c.requestCtx = nil
 			req.res <- clientRes{tracks: tracks, baseURL: baseURL, res: res, err: err}
 
 		case req := <-c.announce:
+			c.requestCtx = req.ctx
 			res, err := c.doAnnounce(req.url, req.tracks)
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case req := <-c.setup:
+			c.requestCtx = req.ctx
 			res, err := c.doSetup(req.forPlay, req.track, req.baseURL, req.rtpPort, req.rtcpPort)
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case req := <-c.play:
-			res, err := c.doPlay(req.ra, false)
+			c.requestCtx = req.ctx
+			res, err := c.doPlay(req.opts, req.baseURL, false)
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case req := <-c.record:
+			c.requestCtx = req.ctx
 			res, err := c.doRecord()
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case req := <-c.pause:
-			res, err := c.doPause()
+			c.requestCtx = req.ctx
+			res, err := c.doPause(req.baseURL)
+			c.requestCtx = nil
 			req.res <- clientRes{res: res, err: err}
 
 		case <-c.checkStreamTimer.C:
@@ -527,7 +665,11 @@ func (c *Client) runInner() error {
 						return true
 					}()
 					if inTimeout {
-						return liberrors.ErrClientUDPTimeout{}
+						err := c.tryReconnecting(liberrors.ErrClientUDPTimeout{})
+						if err != nil {
+							return err
+						}
+						continue
 					}
 				}
 			} else { // TCP
@@ -537,33 +679,62 @@ func (c *Client) runInner() error {
 					return now.Sub(lft) >= c.ReadTimeout
 				}()
 				if inTimeout {
-					return liberrors.ErrClientTCPTimeout{}
+					err := c.tryReconnecting(liberrors.ErrClientTCPTimeout{})
+					if err != nil {
+						return err
+					}
+					continue
 				}
 			}
 
 			c.checkStreamTimer = time.NewTimer(c.checkStreamPeriod)
 
 		case <-c.keepaliveTimer.C:
-			_, err := c.do(&base.Request{
-				Method: func() base.Method {
-					// the VLC integrated rtsp server requires GET_PARAMETER
-					if c.useGetParameter {
-						return base.GetParameter
-					}
-					return base.Options
-				}(),
-				// use the stream base URL, otherwise some cameras do not reply
-				URL: c.baseURL,
-			}, true, false)
+			var err error
+			for _, sess := range c.sessions {
+				_, err = c.doWithSession(sess, &base.Request{
+					Method: func() base.Method {
+						// the VLC integrated rtsp server requires GET_PARAMETER
+						if c.useGetParameter {
+							return base.GetParameter
+						}
+						return base.Options
+					}(),
+					// use the stream base URL, otherwise some cameras do not reply
+					URL: sess.baseURL,
+				}, true, false)
+				if err != nil {
+					break
+				}
+			}
 			if err != nil {
-				return err
+				err = c.tryReconnecting(err)
+				if err != nil {
+					return err
+				}
+				continue
 			}
 
 			c.keepaliveTimer = time.NewTimer(c.keepalivePeriod)
 
+		case <-c.reconnectTimer.C:
+			err := c.doReconnect()
+			if err != nil {
+				return err
+			}
+
 		case err := <-c.readerErr:
 			c.readerErr = nil
-			return err
+			if rerr, ok := err.(liberrors.ErrClientRedirected); ok {
+				err2 := c.applyRedirect(rerr.Location)
+				if err2 != nil {
+					return err2
+				}
+			}
+			err = c.tryReconnecting(err)
+			if err != nil {
+				return err
+			}
 
 		case <-c.ctx.Done():
 			return liberrors.ErrClientTerminated{}
@@ -575,10 +746,12 @@ func (c *Client) doClose() {
 	if c.state == clientStatePlay || c.state == clientStateRecord {
 		c.playRecordStop(true)
 
-		c.do(&base.Request{
-			Method: base.Teardown,
-			URL:    c.baseURL,
-		}, true, false)
+		for _, sess := range c.sessions {
+			c.doWithSession(sess, &base.Request{
+				Method: base.Teardown,
+				URL:    sess.baseURL,
+			}, true, false)
+		}
 
 		c.nconn.Close()
 		c.nconn = nil
@@ -609,11 +782,16 @@ func (c *Client) reset() {
 	c.useGetParameter = false
 	c.baseURL = nil
 	c.effectiveTransport = nil
+	c.sessions = nil
 	c.tracks = nil
 	c.tcpTracksByChannel = nil
 }
 
 func (c *Client) checkState(allowed map[clientState]struct{}) error {
+	if c.reconnectState != nil {
+		return liberrors.ErrClientReconnecting{}
+	}
+
 	if _, ok := allowed[c.state]; ok {
 		return nil
 	}
@@ -628,13 +806,69 @@ func (c *Client) checkState(allowed map[clientState]struct{}) error {
 	return liberrors.ErrClientInvalidState{AllowedList: allowedList, State: c.state}
 }
 
+func (c *Client) findSession(baseURL *url.URL) *clientSession {
+	for _, sess := range c.sessions {
+		if *sess.baseURL == *baseURL {
+			return sess
+		}
+	}
+	return nil
+}
+
+// suspendPlay stops reading and writing in order to send a request that
+// involves a single session, without resetting the tracks of the other sessions.
+func (c *Client) suspendPlay() {
+	c.ioStop(false)
+	c.state = clientStatePrePlay
+}
+
+// resumePlay restarts reading when at least one session is still playing,
+// and stops the tracks of the sessions that are not playing anymore.
+func (c *Client) resumePlay() {
+	if c.state != clientStatePrePlay {
+		return
+	}
+
+	// the connection has been closed because a request has been cancelled,
+	// therefore playback can't be resumed.
+	if c.nconn == nil {
+		c.playTracksStop(c.tracks)
+		c.reset()
+		return
+	}
+
+	for _, sess := range c.sessions {
+		if !sess.active {
+			c.playTracksStop(sess.tracks)
+		}
+	}
+
+	for _, sess := range c.sessions {
+		if sess.active {
+			c.state = clientStatePlay
+			c.playRecordStart()
+			return
+		}
+	}
+}
+
 func (c *Client) trySwitchingProtocol() error {
 	prevScheme := c.scheme
 	prevHost := c.host
-	prevBaseURL := c.baseURL
 	oldUseGetParameter := c.useGetParameter
 	prevTracks := c.tracks
 
+	// sessions that have been paused individually must not be resumed.
+	allActive := true
+	var activeURLs []*url.URL
+	for _, sess := range c.sessions {
+		if sess.active {
+			activeURLs = append(activeURLs, sess.baseURL)
+		} else {
+			allActive = false
+		}
+	}
+
 	c.reset()
 
 	v := TransportTCP
@@ -650,15 +884,26 @@ func (c *Client) trySwitchingProtocol() error {
 	}
 
 	for _, track := range prevTracks {
-		_, err := c.doSetup(true, track.track, prevBaseURL, 0, 0)
+		_, err := c.doSetup(true, track.track, track.session.baseURL, 0, 0)
 		if err != nil {
 			return err
 		}
 	}
 
-	_, err = c.doPlay(c.lastRange, true)
-	if err != nil {
-		return err
+	if allActive {
+		_, err = c.doPlay(c.lastPlayOptions, nil, true)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	for _, u := range activeURLs {
+		_, err = c.doPlay(c.lastPlayOptions, u, true)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -668,71 +913,25 @@ func (c *Client) playRecordStart() {
 	// stop connCloser
 	c.connCloserStop()
 
-	// start writer
-	if c.state == clientStatePlay {
-		// when reading, writeBuffer is only used to send RTCP receiver reports,
-		// that are much smaller than RTP packets and are sent at a fixed interval.
-		// decrease RAM consumption by allocating less buffers.
-		c.writeBuffer, _ = ringbuffer.New(8)
-	} else {
-		c.writeBuffer, _ = ringbuffer.New(uint64(c.WriteBufferCount))
-	}
-	c.writerRunning = true
-	c.writerDone = make(chan struct{})
-	go c.runWriter()
-
-	// allow writing
-	c.writeMutex.Lock()
-	c.writeFrameAllowed = true
-	c.writeMutex.Unlock()
+	c.writerStart()
 
 	if c.state == clientStatePlay {
-		for _, ct := range c.tracks {
-			if *c.effectiveTransport == TransportUDP || *c.effectiveTransport == TransportUDPMulticast {
-				ct.reorderer = rtpreorderer.New()
+		for _, sess := range c.sessions {
+			if sess.active {
+				c.playTracksStart(sess.tracks)
 			}
-			_, isH264 := ct.track.(*TrackH264)
-			ct.cleaner = rtpcleaner.New(isH264, *c.effectiveTransport == TransportTCP)
 		}
 
 		c.keepaliveTimer = time.NewTimer(c.keepalivePeriod)
 
 		switch *c.effectiveTransport {
 		case TransportUDP:
-			for trackID, ct := range c.tracks {
-				ctrackID := trackID
-				ct.udpRTPPacketBuffer = newRTPPacketMultiBuffer(uint64(c.ReadBufferCount))
-				ct.udpRTCPReceiver = rtcpreceiver.New(c.udpReceiverReportPeriod, nil,
-					ct.track.ClockRate(), func(pkt rtcp.Packet) {
-						c.WritePacketRTCP(ctrackID, pkt)
-					})
-			}
-
 			c.checkStreamTimer = time.NewTimer(c.InitialUDPReadTimeout)
 			c.checkStreamInitial = true
 
-			for _, ct := range c.tracks {
-				ct.udpRTPListener.start(true)
-				ct.udpRTCPListener.start(true)
-			}
-
 		case TransportUDPMulticast:
-			for trackID, ct := range c.tracks {
-				ctrackID := trackID
-				ct.udpRTPPacketBuffer = newRTPPacketMultiBuffer(uint64(c.ReadBufferCount))
-				ct.udpRTCPReceiver = rtcpreceiver.New(c.udpReceiverReportPeriod, nil,
-					ct.track.ClockRate(), func(pkt rtcp.Packet) {
-						c.WritePacketRTCP(ctrackID, pkt)
-					})
-			}
-
 			c.checkStreamTimer = time.NewTimer(c.checkStreamPeriod)
 
-			for _, ct := range c.tracks {
-				ct.udpRTPListener.start(true)
-				ct.udpRTCPListener.start(true)
-			}
-
 		default: // TCP
 			c.checkStreamTimer = time.NewTimer(c.checkStreamPeriod)
 			v := time.Now().Unix()
@@ -746,6 +945,10 @@ func (c *Client) playRecordStart() {
 				ct.track.ClockRate(), func(pkt rtcp.Packet) {
 					c.WritePacketRTCP(ctrackID, pkt)
 				})
+
+			if ct.feedback != nil && ct.feedback.rtxPayloadType != 0 {
+				ct.retransmitter = rtpretransmitter.New(ct.feedback.rtxPayloadType)
+			}
 		}
 
 		for _, ct := range c.tracks {
@@ -754,24 +957,123 @@ func (c *Client) playRecordStart() {
 		}
 	}
 
+	c.readerStart()
+}
+
+// playTracksStart starts processing the incoming packets of the given tracks.
+// Tracks that have already been started are skipped.
+func (c *Client) playTracksStart(tracks []*clientTrack) {
+	for _, ct := range tracks {
+		if ct.cleaner != nil {
+			continue
+		}
+
+		if *c.effectiveTransport == TransportUDP || *c.effectiveTransport == TransportUDPMulticast {
+			ct.reorderer = rtpreorderer.New()
+		}
+		_, isH264 := ct.track.(*TrackH264)
+		ct.cleaner = rtpcleaner.New(isH264, *c.effectiveTransport == TransportTCP)
+
+		if *c.effectiveTransport == TransportUDP || *c.effectiveTransport == TransportUDPMulticast {
+			ctrackID := ct.id
+			ct.udpRTPPacketBuffer = newRTPPacketMultiBuffer(uint64(c.ReadBufferCount))
+			ct.udpRTCPReceiver = rtcpreceiver.New(c.udpReceiverReportPeriod, nil,
+				ct.track.ClockRate(), func(pkt rtcp.Packet) {
+					c.WritePacketRTCP(ctrackID, pkt)
+				})
+
+			ct.udpRTPListener.start(true)
+			ct.udpRTCPListener.start(true)
+		}
+	}
+}
+
+// playTracksStop stops processing the incoming packets of the given tracks.
+// Tracks that have not been started are skipped.
+func (c *Client) playTracksStop(tracks []*clientTrack) {
+	for _, ct := range tracks {
+		if ct.cleaner == nil {
+			continue
+		}
+
+		if *c.effectiveTransport == TransportUDP || *c.effectiveTransport == TransportUDPMulticast {
+			ct.udpRTPListener.stop()
+			ct.udpRTCPListener.stop()
+
+			ct.udpRTPPacketBuffer = nil
+			ct.udpRTCPReceiver.Close()
+			ct.udpRTCPReceiver = nil
+		}
+
+		ct.cleaner = nil
+		ct.reorderer = nil
+	}
+}
+
+func (c *Client) writerStart() {
+	if c.state == clientStatePlay {
+		// when reading, writeBuffer is only used to send RTCP receiver reports,
+		// that are much smaller than RTP packets and are sent at a fixed interval.
+		// decrease RAM consumption by allocating less buffers.
+		c.writeBuffer, _ = ringbuffer.New(8)
+	} else {
+		c.writeBuffer, _ = ringbuffer.New(uint64(c.WriteBufferCount))
+	}
+	c.writerRunning = true
+	c.writerDone = make(chan struct{})
+	go c.runWriter()
+
+	// allow writing
+	c.writeMutex.Lock()
+	c.writeFrameAllowed = true
+	c.writeMutex.Unlock()
+}
+
+func (c *Client) writerStop() {
+	// forbid writing
+	c.writeMutex.Lock()
+	c.writeFrameAllowed = false
+	c.writeMutex.Unlock()
+
+	c.writeBuffer.Close()
+	<-c.writerDone
+	c.writerRunning = false
+	c.writeBuffer = nil
+}
+
+func (c *Client) readerStart() {
 	// for some reason, SetReadDeadline() must always be called in the same
 	// goroutine, otherwise Read() freezes.
 	// therefore, we disable the deadline and perform a check with a ticker.
 	c.nconn.SetReadDeadline(time.Time{})
 
-	// start reader
 	c.readerErr = make(chan error)
 	go c.runReader()
 }
 
+func (c *Client) readerStop() {
+	if c.readerErr != nil {
+		c.nconn.SetReadDeadline(time.Now())
+		<-c.readerErr
+		c.readerErr = nil
+	}
+}
+
 func (c *Client) runReader() {
 	c.readerErr <- func() error {
 		if *c.effectiveTransport == TransportUDP || *c.effectiveTransport == TransportUDPMulticast {
 			for {
-				_, err := c.conn.ReadResponse()
+				what, err := c.conn.ReadRequestOrResponse()
 				if err != nil {
 					return err
 				}
+
+				if req, ok := what.(*base.Request); ok {
+					err := c.handleServerRequest(req, true)
+					if err != nil {
+						return err
+					}
+				}
 			}
 		} else {
 			var processFunc func(*clientTrack, bool, []byte) error
@@ -780,16 +1082,33 @@ func (c *Client) runReader() {
 				tcpRTPPacketBuffer := newRTPPacketMultiBuffer(uint64(c.ReadBufferCount))
 
 				processFunc = func(track *clientTrack, isRTP bool, payload []byte) error {
+					// the session of the track is paused
+					if track.cleaner == nil {
+						return nil
+					}
+
 					now := time.Now()
 					atomic.StoreInt64(c.tcpLastFrameTime, now.Unix())
 
 					if isRTP {
+						payload, ok := srtpDecryptRTP(track.srtpContext, payload)
+						if !ok {
+							return nil
+						}
+
 						pkt := tcpRTPPacketBuffer.next()
 						err := pkt.Unmarshal(payload)
 						if err != nil {
 							return err
 						}
 
+						if track.feedbackReceiver != nil {
+							pkt, ok = track.feedbackReceiver.processRTP(pkt)
+							if !ok {
+								return nil
+							}
+						}
+
 						out, err := track.cleaner.Process(pkt)
 						if err != nil {
 							return err
@@ -810,6 +1129,11 @@ func (c *Client) runReader() {
 								len(payload), maxPacketSize)
 						}
 
+						payload, ok := srtpDecryptRTCP(track.srtpContext, payload)
+						if !ok {
+							return nil
+						}
+
 						packets, err := rtcp.Unmarshal(payload)
 						if err != nil {
 							// some cameras send invalid RTCP packets.
@@ -835,6 +1159,11 @@ func (c *Client) runReader() {
 								len(payload), maxPacketSize)
 						}
 
+						payload, ok := srtpDecryptRTCP(track.srtpContext, payload)
+						if !ok {
+							return nil
+						}
+
 						packets, err := rtcp.Unmarshal(payload)
 						if err != nil {
 							return err
@@ -853,13 +1182,20 @@ func (c *Client) runReader() {
 			}
 
 			for {
-				what, err := c.conn.ReadInterleavedFrameOrResponse()
+				what, err := c.conn.ReadInterleavedFrameOrRequestOrResponse()
 				if err != nil {
 					return err
 				}
 
-				if fr, ok := what.(*base.InterleavedFrame); ok {
-					channel := fr.Channel
+				switch twhat := what.(type) {
+				case *base.Request:
+					err := c.handleServerRequest(twhat, true)
+					if err != nil {
+						return err
+					}
+
+				case *base.InterleavedFrame:
+					channel := twhat.Channel
 					isRTP := true
 					if (channel % 2) != 0 {
 						channel--
@@ -871,7 +1207,7 @@ func (c *Client) runReader() {
 						continue
 					}
 
-					err := processFunc(track, isRTP, fr.Payload)
+					err := processFunc(track, isRTP, twhat.Payload)
 					if err != nil {
 						return err
 					}
@@ -882,61 +1218,45 @@ func (c *Client) runReader() {
 }
 
 func (c *Client) playRecordStop(isClosing bool) {
-	// stop reader
-	if c.readerErr != nil {
-		c.nconn.SetReadDeadline(time.Now())
-		<-c.readerErr
-	}
-
-	// forbid writing
-	c.writeMutex.Lock()
-	c.writeFrameAllowed = false
-	c.writeMutex.Unlock()
+	c.readerStop()
 
-	if *c.effectiveTransport == TransportUDP ||
+	if c.state == clientStatePlay {
+		c.playTracksStop(c.tracks)
+	} else if *c.effectiveTransport == TransportUDP ||
 		*c.effectiveTransport == TransportUDPMulticast {
 		for _, ct := range c.tracks {
 			ct.udpRTPListener.stop()
 			ct.udpRTCPListener.stop()
 		}
 
-		if c.state == clientStatePlay {
-			for _, ct := range c.tracks {
-				ct.udpRTPPacketBuffer = nil
-				ct.udpRTCPReceiver.Close()
-				ct.udpRTCPReceiver = nil
-			}
-		} else {
-			for _, ct := range c.tracks {
-				ct.udpRTCPSender.Close()
-				ct.udpRTCPSender = nil
-			}
+		for _, ct := range c.tracks {
+			ct.udpRTCPSender.Close()
+			ct.udpRTCPSender = nil
+			ct.retransmitter = nil
 		}
 	}
 
-	for _, ct := range c.tracks {
-		ct.cleaner = nil
-		ct.reorderer = nil
-	}
+	c.ioStop(isClosing)
+}
+
+// ioStop stops reading, writing and timers, without touching tracks.
+func (c *Client) ioStop(isClosing bool) {
+	c.readerStop()
+	c.writerStop()
 
 	// stop timers
 	c.checkStreamTimer = emptyTimer()
 	c.keepaliveTimer = emptyTimer()
 
-	// stop writer
-	c.writeBuffer.Close()
-	<-c.writerDone
-	c.writerRunning = false
-	c.writeBuffer = nil
-
 	// start connCloser
 	if !isClosing {
 		c.connCloserStart()
 	}
 }
 
-func (c *Client) connOpen() error {
-	if c.scheme != "rtsp" && c.scheme != "rtsps" {
+func (c *Client) connOpen(u *url.URL) error {
+	if c.scheme != "rtsp" && c.scheme != "rtsps" &&
+		c.scheme != "rtsph" && c.scheme != "rtsphs" {
 		return fmt.Errorf("unsupported scheme '%s'", c.scheme)
 	}
 
@@ -944,46 +1264,86 @@ func (c *Client) connOpen() error {
 		return fmt.Errorf("RTSPS can be used only with TCP")
 	}
 
-	// add default port
-	_, _, err := net.SplitHostPort(c.host)
-	if err != nil {
-		if c.scheme == "rtsp" {
-			c.host = net.JoinHostPort(c.host, "554")
-		} else { // rtsps
-			c.host = net.JoinHostPort(c.host, "322")
-		}
+	if (c.scheme == "rtsph" || c.scheme == "rtsphs") &&
+		c.Transport != nil && *c.Transport != TransportTCP {
+		return fmt.Errorf("RTSP over HTTP can be used only with TCP")
 	}
 
+	c.host = hostWithDefaultPort(c.scheme, c.host)
+
 	ctx, cancel := context.WithTimeout(c.ctx, c.ReadTimeout)
 	defer cancel()
 
-	nconn, err := c.DialContext(ctx, "tcp", c.host)
-	if err != nil {
-		return err
+	// abort dialing when the context of the request is done
+	if c.requestCtx != nil {
+		dialDone := make(chan struct{})
+		defer close(dialDone)
+
+		go func(requestCtx context.Context) {
+			select {
+			case <-requestCtx.Done():
+				cancel()
+			case <-dialDone:
+			}
+		}(c.requestCtx)
 	}
 
-	c.nconn = func() net.Conn {
-		if c.scheme == "rtsps" {
-			tlsConfig := c.TLSConfig
+	tlsConfig := func() *tls.Config {
+		if c.scheme != "rtsps" && c.scheme != "rtsphs" {
+			return nil
+		}
+
+		tlsConfig := c.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+
+		host, _, _ := net.SplitHostPort(c.host)
+		tlsConfig.ServerName = host
+		return tlsConfig
+	}()
 
-			if tlsConfig == nil {
-				tlsConfig = &tls.Config{}
+	nconn, err := func() (net.Conn, error) {
+		if c.scheme == "rtsph" || c.scheme == "rtsphs" {
+			path := u.Path
+			if path == "" {
+				path = "/"
 			}
+			if u.RawQuery != "" {
+				path += "?" + u.RawQuery
+			}
+			return httptunnel.Dial(ctx, c.DialContext, c.host, path, tlsConfig)
+		}
 
-			host, _, _ := net.SplitHostPort(c.host)
-			tlsConfig.ServerName = host
+		nconn, err := c.DialContext(ctx, "tcp", c.host)
+		if err != nil {
+			return nil, err
+		}
 
-			return tls.Client(nconn, tlsConfig)
+		if tlsConfig != nil {
+			return tls.Client(nconn, tlsConfig), nil
 		}
-		return nconn
+		return nconn, nil
 	}()
+	if err != nil {
+		if c.requestCtx != nil && c.requestCtx.Err() != nil {
+			return c.requestCtx.Err()
+		}
+		return err
+	}
 
+	c.nconn = nconn
 	c.conn = conn.NewConn(c.nconn)
 
 	c.connCloserStart()
 	return nil
 }
 
+func (c *Client) isWebSocket() bool {
+	_, ok := c.nconn.(*wsconn.Conn)
+	return ok
+}
+
 func (c *Client) connCloserStart() {
 	c.connCloserTerminate = make(chan struct{})
 	c.connCloserDone = make(chan struct{})
@@ -1006,9 +1366,70 @@ func (c *Client) connCloserStop() {
 	c.connCloserDone = nil
 }
 
-func (c *Client) do(req *base.Request, skipResponse bool, allowFrames bool) (*base.Response, error) {
+// startRequestCtxWatcher interrupts pending read and write operations
+// when the context of the request is done.
+func (c *Client) startRequestCtxWatcher() func() {
+	if c.requestCtx == nil {
+		return func() {}
+	}
+
+	terminate := make(chan struct{})
+	done := make(chan struct{})
+
+	go func(requestCtx context.Context, nconn net.Conn) {
+		defer close(done)
+
+		select {
+		case <-requestCtx.Done():
+			nconn.SetDeadline(time.Now())
+		case <-terminate:
+		}
+	}(c.requestCtx, c.nconn)
+
+	return func() {
+		close(terminate)
+		<-done
+	}
+}
+
+// requestError checks whether an error has been caused by the cancellation of
+// the request context. In that case, the connection is closed, since it is
+// not possible to know how much of the request has been processed.
+func (c *Client) requestError(err error) error {
+	if c.requestCtx == nil || c.requestCtx.Err() == nil {
+		return err
+	}
+
+	if c.nconn != nil {
+		c.connCloserStop()
+		c.nconn.Close()
+		c.nconn = nil
+		c.conn = nil
+	}
+
+	return c.requestCtx.Err()
+}
+
+// doWithSession writes a request that belongs to a session.
+func (c *Client) doWithSession(
+	sess *clientSession,
+	req *base.Request,
+	skipResponse bool,
+	allowFrames bool,
+) (*base.Response, error) {
+	c.session = sess.id
+	res, err := c.do(req, skipResponse, allowFrames)
+	sess.id = c.session
+	return res, err
+}
+
+func (c *Client) do(req *base.Request, skipResponse bool, allowFrames bool) (*base.Response, error) {
+	if c.requestCtx != nil && c.requestCtx.Err() != nil {
+		return nil, c.requestCtx.Err()
+	}
+
 	if c.nconn == nil {
-		err := c.connOpen()
+		err := c.connOpen(req.URL)
 		if err != nil {
 			return nil, err
 		}
@@ -1025,6 +1446,18 @@ func (c *Client) do(req *base.Request, skipResponse bool, allowFrames bool) (*ba
 		req.Header = make(base.Header)
 	}
 
+	req.Version = c.version
+
+	// URLs of tunneled requests use the plain RTSP scheme
+	if req.URL != nil && (req.URL.Scheme == "rtsph" || req.URL.Scheme == "rtsphs") {
+		req.URL = req.URL.Clone()
+		if req.URL.Scheme == "rtsph" {
+			req.URL.Scheme = "rtsp"
+		} else {
+			req.URL.Scheme = "rtsps"
+		}
+	}
+
 	if c.session != "" {
 		req.Header["Session"] = base.HeaderValue{c.session}
 	}
@@ -1042,35 +1475,53 @@ func (c *Client) do(req *base.Request, skipResponse bool, allowFrames bool) (*ba
 		c.OnRequest(req)
 	}
 
+	stopWatcher := c.startRequestCtxWatcher()
+
 	c.nconn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	if c.requestCtx != nil && c.requestCtx.Err() != nil {
+		stopWatcher()
+		return nil, c.requestError(nil)
+	}
+
 	err := c.conn.WriteRequest(req)
 	if err != nil {
-		return nil, err
+		stopWatcher()
+		return nil, c.requestError(err)
 	}
 
 	if skipResponse {
+		stopWatcher()
 		return nil, nil
 	}
 
 	c.nconn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
-	var res *base.Response
-	if allowFrames {
-		// read the response and ignore interleaved frames in between;
-		// interleaved frames are sent in two cases:
-		// * when the server is v4lrtspserver, before the PLAY response
-		// * when the stream is already playing
-		res, err = c.conn.ReadResponseIgnoreFrames()
-	} else {
-		res, err = c.conn.ReadResponse()
+	if c.requestCtx != nil && c.requestCtx.Err() != nil {
+		stopWatcher()
+		return nil, c.requestError(nil)
 	}
+
+	res, err := c.readResponse(allowFrames)
+	stopWatcher()
 	if err != nil {
-		return nil, err
+		return nil, c.requestError(err)
 	}
 
 	if c.OnResponse != nil {
 		c.OnResponse(res)
 	}
 
+	// fall back to RTSP 1.0 when RTSP 2.0 is not supported
+	if c.version == base.Version20 {
+		if res.StatusCode == base.StatusRTSPVersionNotSupported {
+			c.version = base.Version10
+			return c.do(req, skipResponse, allowFrames)
+		}
+
+		if res.Version == base.Version10 {
+			c.version = base.Version10
+		}
+	}
+
 	// get session from response
 	if v, ok := res.Header["Session"]; ok {
 		var sx headers.Session
@@ -1102,6 +1553,145 @@ func (c *Client) do(req *base.Request, skipResponse bool, allowFrames bool) (*ba
 	return res, nil
 }
 
+func (c *Client) readResponse(allowFrames bool) (*base.Response, error) {
+	for {
+		var what interface{}
+		var err error
+
+		if allowFrames {
+			// read the response and ignore interleaved frames in between;
+			// interleaved frames are sent in two cases:
+			// * when the server is v4lrtspserver, before the PLAY response
+			// * when the stream is already playing
+			what, err = c.conn.ReadInterleavedFrameOrRequestOrResponse()
+		} else {
+			what, err = c.conn.ReadRequestOrResponse()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch twhat := what.(type) {
+		case *base.Response:
+			return twhat, nil
+
+		case *base.Request:
+			// redirects are honored only while reading or publishing
+			err := c.handleServerRequest(twhat, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// handleServerRequest answers to a request sent by the server.
+// It can be called by the reader routine too, therefore it must not touch the client state.
+func (c *Client) handleServerRequest(req *base.Request, redirectAllowed bool) error {
+	res := &base.Response{
+		Version:    req.Version,
+		StatusCode: base.StatusOK,
+		Header:     base.Header{},
+	}
+
+	if v, ok := req.Header["CSeq"]; ok {
+		res.Header["CSeq"] = v
+	}
+
+	if v, ok := req.Header["Session"]; ok {
+		res.Header["Session"] = v
+	}
+
+	ctx := &ClientOnServerRequestCtx{
+		Request: req,
+	}
+
+	var retErr error
+
+	switch req.Method {
+	case base.Options:
+		res.Header["Public"] = base.HeaderValue{strings.Join([]string{
+			string(base.Announce),
+			string(base.GetParameter),
+			string(base.Options),
+			string(base.PlayNotify),
+			string(base.Redirect),
+			string(base.SetParameter),
+		}, ", ")}
+
+	case base.Announce:
+		var tracks Tracks
+		_, err := tracks.Unmarshal(req.Body, true)
+		if err != nil {
+			res.StatusCode = base.StatusBadRequest
+		} else {
+			ctx.Tracks = tracks
+		}
+
+	case base.Redirect:
+		switch {
+		case !redirectAllowed:
+			res.StatusCode = base.StatusMethodNotValidInThisState
+
+		case len(req.Header["Location"]) != 1:
+			res.StatusCode = base.StatusBadRequest
+
+		default:
+			retErr = liberrors.ErrClientRedirected{Location: req.Header["Location"][0]}
+		}
+
+	case base.GetParameter, base.SetParameter, base.PlayNotify:
+
+	default:
+		res.StatusCode = base.StatusNotImplemented
+	}
+
+	if res.StatusCode == base.StatusOK && c.OnServerRequest != nil {
+		c.OnServerRequest(ctx)
+	}
+
+	c.nconn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	err := c.conn.WriteResponse(res)
+	if err != nil {
+		return err
+	}
+
+	return retErr
+}
+
+func (c *Client) applyRedirect(location string) error {
+	u, err := url.Parse(location)
+	if err != nil {
+		return err
+	}
+
+	if (c.scheme == "rtsps" || c.scheme == "rtsphs") &&
+		u.Scheme != "rtsps" && u.Scheme != "rtsphs" {
+		return liberrors.ErrClientRedirectInsecure{Location: location}
+	}
+
+	// credentials are sent only to the server they were provided for
+	sameServer := u.Scheme == c.scheme && hostWithDefaultPort(u.Scheme, u.Host) == c.host
+
+	if c.lastDescribeURL != nil {
+		if u.User == nil && sameServer {
+			u.User = c.lastDescribeURL.User
+		}
+		c.lastDescribeURL = u
+	}
+
+	if c.lastAnnounceURL != nil {
+		if u.User == nil && sameServer {
+			u.User = c.lastAnnounceURL.User
+		}
+		c.lastAnnounceURL = u
+	}
+
+	c.scheme = u.Scheme
+	c.host = u.Host
+	return nil
+}
+
 func (c *Client) doOptions(u *url.URL) (*base.Response, error) {
 	err := c.checkState(map[clientState]struct{}{
 		clientStateInitial:   {},
@@ -1150,12 +1740,21 @@ func (c *Client) doOptions(u *url.URL) (*base.Response, error) {
 
 // Options writes an OPTIONS request and reads a response.
 func (c *Client) Options(u *url.URL) (*base.Response, error) {
+	return c.OptionsContext(context.Background(), u)
+}
+
+// OptionsContext writes an OPTIONS request and reads a response.
+// The request is aborted when the context is done.
+func (c *Client) OptionsContext(ctx context.Context, u *url.URL) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.options <- optionsReq{url: u, res: cres}:
+	case c.options <- optionsReq{ctx: ctx, url: u, res: cres}:
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
@@ -1221,7 +1820,12 @@ func (c *Client) doDescribe(u *url.URL) (Tracks, *url.URL, *base.Response, error
 	}
 
 	var tracks Tracks
-	sd, err := tracks.Unmarshal(res.Body, true)
+	sd, mds, err := tracks.unmarshal(res.Body, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	keys, err := srtpKeysFromMediaDescriptions(sd, mds)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -1232,18 +1836,31 @@ func (c *Client) doDescribe(u *url.URL) (Tracks, *url.URL, *base.Response, error
 	}
 
 	c.lastDescribeURL = u
+	c.lastDescribeTracks = tracks
+	c.lastDescribeKeys = keys
+	c.lastDescribeFeedbacks = trackFeedbacksFromMediaDescriptions(mds)
+	c.lastDescribeFECs = trackFECsFromMediaDescriptions(mds)
 
 	return tracks, baseURL, res, nil
 }
 
 // Describe writes a DESCRIBE request and reads a Response.
 func (c *Client) Describe(u *url.URL) (Tracks, *url.URL, *base.Response, error) {
+	return c.DescribeContext(context.Background(), u)
+}
+
+// DescribeContext writes a DESCRIBE request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) DescribeContext(ctx context.Context, u *url.URL) (Tracks, *url.URL, *base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.describe <- describeReq{url: u, res: cres}:
+	case c.describe <- describeReq{ctx: ctx, url: u, res: cres}:
 		res := <-cres
 		return res.tracks, res.baseURL, res.res, res.err
 
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, nil, nil, liberrors.ErrClientTerminated{}
 	}
@@ -1259,13 +1876,31 @@ func (c *Client) doAnnounce(u *url.URL, tracks Tracks) (*base.Response, error) {
 
 	tracks.setControls()
 
+	var keys []*srtpKey
+	if c.SRTPEnable {
+		keys, err = newSRTPKeys(len(tracks))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var feedbacks []*trackFeedback
+	if c.RetransmissionEnable {
+		feedbacks = newTrackFeedbacks(tracks)
+	}
+
+	byts, err := tracks.marshal(false, keys, feedbacks, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := c.do(&base.Request{
 		Method: base.Announce,
 		URL:    u,
 		Header: base.Header{
 			"Content-Type": base.HeaderValue{"application/sdp"},
 		},
-		Body: tracks.Marshal(false),
+		Body: byts,
 	}, false, false)
 	if err != nil {
 		return nil, err
@@ -1278,6 +1913,10 @@ func (c *Client) doAnnounce(u *url.URL, tracks Tracks) (*base.Response, error) {
 	}
 
 	c.baseURL = u.Clone()
+	c.lastAnnounceURL = u
+	c.lastAnnounceTracks = tracks
+	c.lastAnnounceKeys = keys
+	c.lastAnnounceFeedbacks = feedbacks
 	c.state = clientStatePreRecord
 
 	return res, nil
@@ -1285,12 +1924,21 @@ func (c *Client) doAnnounce(u *url.URL, tracks Tracks) (*base.Response, error) {
 
 // Announce writes an ANNOUNCE request and reads a Response.
 func (c *Client) Announce(u *url.URL, tracks Tracks) (*base.Response, error) {
+	return c.AnnounceContext(context.Background(), u, tracks)
+}
+
+// AnnounceContext writes an ANNOUNCE request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) AnnounceContext(ctx context.Context, u *url.URL, tracks Tracks) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.announce <- announceReq{url: u, tracks: tracks, res: cres}:
+	case c.announce <- announceReq{ctx: ctx, url: u, tracks: tracks, res: cres}:
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
@@ -1303,6 +1951,12 @@ func (c *Client) doSetup(
 	rtpPort int,
 	rtcpPort int,
 ) (*base.Response, error) {
+	// tracks can be added to a playing client when sessions are independent
+	if c.MultiSessionEnable && forPlay && c.state == clientStatePlay {
+		c.suspendPlay()
+		defer c.resumePlay()
+	}
+
 	err := c.checkState(map[clientState]struct{}{
 		clientStateInitial:   {},
 		clientStatePrePlay:   {},
@@ -1318,12 +1972,19 @@ func (c *Client) doSetup(
 		return nil, liberrors.ErrClientCannotReadPublishAtSameTime{}
 	}
 
-	if c.baseURL != nil && *baseURL != *c.baseURL {
+	if c.baseURL != nil && *baseURL != *c.baseURL && !c.MultiSessionEnable {
 		return nil, liberrors.ErrClientCannotSetupTracksDifferentURLs{}
 	}
 
-	// always use TCP if encrypted
-	if c.scheme == "rtsps" {
+	sess := c.findSession(baseURL)
+	if sess == nil {
+		sess = &clientSession{
+			baseURL: baseURL,
+		}
+	}
+
+	// always use TCP if encrypted or tunneled
+	if c.scheme == "rtsps" || c.scheme == "rtsph" || c.scheme == "rtsphs" || c.isWebSocket() {
 		v := TransportTCP
 		c.effectiveTransport = &v
 	}
@@ -1348,16 +2009,51 @@ func (c *Client) doSetup(
 		mode = headers.TransportModeRecord
 	}
 
-	th := headers.Transport{
-		Mode: &mode,
+	ct := &clientTrack{
+		track: track,
 	}
 
-	trackID := len(c.tracks)
+	var key *srtpKey
+	var feedback *trackFeedback
+	var fec *trackFEC
 
-	ct := &clientTrack{
-		track: track,
+	func() {
+		tracks, keys, feedbacks := c.lastDescribeTracks, c.lastDescribeKeys, c.lastDescribeFeedbacks
+		if !forPlay {
+			tracks, keys, feedbacks = c.lastAnnounceTracks, c.lastAnnounceKeys, c.lastAnnounceFeedbacks
+		}
+
+		i := findTrack(tracks, track)
+		if i < 0 {
+			return
+		}
+
+		if keys != nil {
+			key = keys[i]
+		}
+		if feedbacks != nil {
+			feedback = feedbacks[i]
+		}
+		if forPlay && c.lastDescribeFECs != nil {
+			fec = c.lastDescribeFECs[i]
+		}
+	}()
+
+	if key != nil {
+		var err error
+		ct.srtpContext, err = key.context()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	th := headers.Transport{
+		Profile: transportProfile(ct.srtpContext, feedback),
+		Mode:    &mode,
 	}
 
+	trackID := len(c.tracks)
+
 	switch transport {
 	case TransportUDP:
 		if (rtpPort == 0 && rtcpPort != 0) ||
@@ -1424,12 +2120,19 @@ func (c *Client) doSetup(
 		return nil, err
 	}
 
-	res, err := c.do(&base.Request{
+	header := base.Header{}
+
+	if c.version == base.Version20 {
+		transportHeaderToV20(&th)
+		header["Accept-Ranges"] = headers.AcceptRanges{"npt", "clock", "smpte"}.Marshal()
+	}
+
+	header["Transport"] = th.Marshal()
+
+	res, err := c.doWithSession(sess, &base.Request{
 		Method: base.Setup,
 		URL:    trackURL,
-		Header: base.Header{
-			"Transport": th.Marshal(),
-		},
+		Header: header,
 	}, false, false)
 	if err != nil {
 		if transport == TransportUDP {
@@ -1468,6 +2171,22 @@ func (c *Client) doSetup(
 		return nil, liberrors.ErrClientTransportHeaderInvalid{Err: err}
 	}
 
+	transportHeaderFromV20(&thRes, false)
+
+	// feedback is used only if the server accepted the RTP/AVPF profile.
+	if thRes.Profile.HasFeedback() && feedback != nil {
+		ct.feedback = feedback
+		// feedback can't be sent to multicast groups.
+		if forPlay && transport != TransportUDPMulticast {
+			ct.feedbackReceiver = newTrackFeedbackReceiver(feedback)
+		}
+	}
+
+	// repair packets are sent only with UDP and UDP-multicast.
+	if fec != nil && transport != TransportTCP {
+		ct.fecReceiver = newTrackFECReceiver(fec)
+	}
+
 	switch transport {
 	case TransportUDP:
 		if thRes.Delivery != nil && *thRes.Delivery != headers.TransportDeliveryUnicast {
@@ -1591,7 +2310,15 @@ func (c *Client) doSetup(
 	c.tracks = append(c.tracks, ct)
 	ct.id = trackID
 
-	c.baseURL = baseURL
+	if len(sess.tracks) == 0 {
+		c.sessions = append(c.sessions, sess)
+	}
+	sess.tracks = append(sess.tracks, ct)
+	ct.session = sess
+
+	if c.baseURL == nil {
+		c.baseURL = baseURL
+	}
 	c.effectiveTransport = &transport
 
 	if mode == headers.TransportModePlay {
@@ -1612,10 +2339,24 @@ func (c *Client) Setup(
 	baseURL *url.URL,
 	rtpPort int,
 	rtcpPort int,
+) (*base.Response, error) {
+	return c.SetupContext(context.Background(), forPlay, track, baseURL, rtpPort, rtcpPort)
+}
+
+// SetupContext writes a SETUP request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) SetupContext(
+	ctx context.Context,
+	forPlay bool,
+	track Track,
+	baseURL *url.URL,
+	rtpPort int,
+	rtcpPort int,
 ) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
 	case c.setup <- setupReq{
+		ctx:      ctx,
 		forPlay:  forPlay,
 		track:    track,
 		baseURL:  baseURL,
@@ -1626,34 +2367,80 @@ func (c *Client) Setup(
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
 }
 
-func (c *Client) doPlay(ra *headers.Range, isSwitchingProtocol bool) (*base.Response, error) {
-	err := c.checkState(map[clientState]struct{}{
-		clientStatePrePlay: {},
-	})
-	if err != nil {
-		return nil, err
+func (c *Client) doPlay(opts ClientPlayOptions, baseURL *url.URL, isSwitchingProtocol bool) (*base.Response, error) {
+	var sessions []*clientSession
+
+	if baseURL == nil {
+		err := c.checkState(map[clientState]struct{}{
+			clientStatePrePlay: {},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = c.sessions
+	} else {
+		if !c.MultiSessionEnable {
+			return nil, liberrors.ErrClientMultiSessionDisabled{}
+		}
+
+		err := c.checkState(map[clientState]struct{}{
+			clientStatePrePlay: {},
+			clientStatePlay:    {},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sess := c.findSession(baseURL)
+		if sess == nil {
+			return nil, liberrors.ErrClientSessionNotFound{}
+		}
+
+		if sess.active {
+			return nil, liberrors.ErrClientInvalidState{
+				AllowedList: []fmt.Stringer{clientStatePrePlay},
+				State:       clientStatePlay,
+			}
+		}
+
+		sessions = []*clientSession{sess}
+
+		// stop reading in order to read the response
+		if c.state == clientStatePlay {
+			c.suspendPlay()
+		}
 	}
 
+	// start reading if at least one session is playing
+	defer c.resumePlay()
+
 	// open the firewall by sending test packets to the counterpart.
 	// do this before sending the request.
 	// don't do this with multicast, otherwise the RTP packet is going to be broadcasted
 	// to all listeners, including us, messing up the stream.
 	if *c.effectiveTransport == TransportUDP {
-		for _, ct := range c.tracks {
-			byts, _ := (&rtp.Packet{Header: rtp.Header{Version: 2}}).Marshal()
-			ct.udpRTPListener.write(byts)
+		for _, sess := range sessions {
+			for _, ct := range sess.tracks {
+				byts, _ := (&rtp.Packet{Header: rtp.Header{Version: 2}}).Marshal()
+				ct.udpRTPListener.write(byts)
 
-			byts, _ = (&rtcp.ReceiverReport{}).Marshal()
-			ct.udpRTCPListener.write(byts)
+				byts, _ = (&rtcp.ReceiverReport{}).Marshal()
+				ct.udpRTCPListener.write(byts)
+			}
 		}
 	}
 
 	// Range is mandatory in Parrot Streaming Server
+	ra := opts.Range
 	if ra == nil {
 		ra = &headers.Range{
 			Value: &headers.RangeNPT{
@@ -1662,26 +2449,41 @@ func (c *Client) doPlay(ra *headers.Range, isSwitchingProtocol bool) (*base.Resp
 		}
 	}
 
-	res, err := c.do(&base.Request{
-		Method: base.Play,
-		URL:    c.baseURL,
-		Header: base.Header{
-			"Range": ra.Marshal(),
-		},
-	}, false, *c.effectiveTransport == TransportTCP)
-	if err != nil {
-		return nil, err
+	header := base.Header{
+		"Range": ra.Marshal(),
 	}
 
-	if res.StatusCode != base.StatusOK {
-		return nil, liberrors.ErrClientBadStatusCode{
-			Code: res.StatusCode, Message: res.StatusMessage,
+	if opts.Scale != nil {
+		header["Scale"] = opts.Scale.Marshal()
+	}
+
+	if opts.Speed != nil {
+		header["Speed"] = opts.Speed.Marshal()
+	}
+
+	var res *base.Response
+
+	for _, sess := range sessions {
+		var err error
+		res, err = c.doWithSession(sess, &base.Request{
+			Method: base.Play,
+			URL:    sess.baseURL,
+			Header: header,
+		}, false, *c.effectiveTransport == TransportTCP)
+		if err != nil {
+			return nil, err
 		}
+
+		if res.StatusCode != base.StatusOK {
+			return nil, liberrors.ErrClientBadStatusCode{
+				Code: res.StatusCode, Message: res.StatusMessage,
+			}
+		}
+
+		sess.active = true
 	}
 
-	c.lastRange = ra
-	c.state = clientStatePlay
-	c.playRecordStart()
+	c.lastPlayOptions = opts
 
 	return res, nil
 }
@@ -1689,12 +2491,62 @@ func (c *Client) doPlay(ra *headers.Range, isSwitchingProtocol bool) (*base.Resp
 // Play writes a PLAY request and reads a Response.
 // This can be called only after Setup().
 func (c *Client) Play(ra *headers.Range) (*base.Response, error) {
+	return c.PlayContext(context.Background(), ra)
+}
+
+// PlayContext writes a PLAY request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) PlayContext(ctx context.Context, ra *headers.Range) (*base.Response, error) {
+	return c.PlayWithOptionsContext(ctx, ClientPlayOptions{Range: ra})
+}
+
+// PlayWithOptions writes a PLAY request with the given options and reads a Response.
+// This can be called only after Setup().
+// The Scale and Speed that are actually used by the server can be read from the Response.
+func (c *Client) PlayWithOptions(opts ClientPlayOptions) (*base.Response, error) {
+	return c.PlayWithOptionsContext(context.Background(), opts)
+}
+
+// PlayWithOptionsContext writes a PLAY request with the given options and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) PlayWithOptionsContext(ctx context.Context, opts ClientPlayOptions) (*base.Response, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.play <- playReq{ctx: ctx, opts: opts, res: cres}:
+		res := <-cres
+		return res.res, res.err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-c.ctx.Done():
+		return nil, liberrors.ErrClientTerminated{}
+	}
+}
+
+// PlaySession writes a PLAY request that involves only the session with
+// the given base URL. It can be used only when MultiSessionEnable is true.
+// This can be called only after Setup().
+func (c *Client) PlaySession(baseURL *url.URL, ra *headers.Range) (*base.Response, error) {
+	return c.PlaySessionContext(context.Background(), baseURL, ra)
+}
+
+// PlaySessionContext writes a PLAY request that involves only the session with
+// the given base URL. The request is aborted when the context is done.
+func (c *Client) PlaySessionContext(
+	ctx context.Context,
+	baseURL *url.URL,
+	ra *headers.Range,
+) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.play <- playReq{ra: ra, res: cres}:
+	case c.play <- playReq{ctx: ctx, baseURL: baseURL, opts: ClientPlayOptions{Range: ra}, res: cres}:
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
@@ -1702,14 +2554,20 @@ func (c *Client) Play(ra *headers.Range) (*base.Response, error) {
 
 // SetupAndPlay setups and play the given tracks.
 func (c *Client) SetupAndPlay(tracks Tracks, baseURL *url.URL) error {
+	return c.SetupAndPlayContext(context.Background(), tracks, baseURL)
+}
+
+// SetupAndPlayContext setups and play the given tracks.
+// Requests are aborted when the context is done.
+func (c *Client) SetupAndPlayContext(ctx context.Context, tracks Tracks, baseURL *url.URL) error {
 	for _, t := range tracks {
-		_, err := c.Setup(true, t, baseURL, 0, 0)
+		_, err := c.SetupContext(ctx, true, t, baseURL, 0, 0)
 		if err != nil {
 			return err
 		}
 	}
 
-	_, err := c.Play(nil)
+	_, err := c.PlayContext(ctx, nil)
 	return err
 }
 
@@ -1721,18 +2579,22 @@ func (c *Client) doRecord() (*base.Response, error) {
 		return nil, err
 	}
 
-	res, err := c.do(&base.Request{
-		Method: base.Record,
-		URL:    c.baseURL,
-	}, false, false)
-	if err != nil {
-		return nil, err
-	}
+	for _, sess := range c.sessions {
+		res, err := c.doWithSession(sess, &base.Request{
+			Method: base.Record,
+			URL:    sess.baseURL,
+		}, false, false)
+		if err != nil {
+			return nil, err
+		}
 
-	if res.StatusCode != base.StatusOK {
-		return nil, liberrors.ErrClientBadStatusCode{
-			Code: res.StatusCode, Message: res.StatusMessage,
+		if res.StatusCode != base.StatusOK {
+			return nil, liberrors.ErrClientBadStatusCode{
+				Code: res.StatusCode, Message: res.StatusMessage,
+			}
 		}
+
+		sess.active = true
 	}
 
 	c.state = clientStateRecord
@@ -1744,47 +2606,109 @@ func (c *Client) doRecord() (*base.Response, error) {
 // Record writes a RECORD request and reads a Response.
 // This can be called only after Announce() and Setup().
 func (c *Client) Record() (*base.Response, error) {
+	return c.RecordContext(context.Background())
+}
+
+// RecordContext writes a RECORD request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) RecordContext(ctx context.Context) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.record <- recordReq{res: cres}:
+	case c.record <- recordReq{ctx: ctx, res: cres}:
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
 }
 
-func (c *Client) doPause() (*base.Response, error) {
-	err := c.checkState(map[clientState]struct{}{
-		clientStatePlay:   {},
-		clientStateRecord: {},
-	})
-	if err != nil {
-		return nil, err
+func (c *Client) doPause(baseURL *url.URL) (*base.Response, error) {
+	var sessions []*clientSession
+
+	if baseURL == nil {
+		err := c.checkState(map[clientState]struct{}{
+			clientStatePlay:   {},
+			clientStateRecord: {},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sess := range c.sessions {
+			if sess.active {
+				sessions = append(sessions, sess)
+			}
+		}
+	} else {
+		if !c.MultiSessionEnable {
+			return nil, liberrors.ErrClientMultiSessionDisabled{}
+		}
+
+		err := c.checkState(map[clientState]struct{}{
+			clientStatePlay: {},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sess := c.findSession(baseURL)
+		if sess == nil {
+			return nil, liberrors.ErrClientSessionNotFound{}
+		}
+
+		if !sess.active {
+			return nil, liberrors.ErrClientInvalidState{
+				AllowedList: []fmt.Stringer{clientStatePlay},
+				State:       clientStatePrePlay,
+			}
+		}
+
+		sessions = []*clientSession{sess}
 	}
 
-	c.playRecordStop(false)
+	if baseURL == nil {
+		c.playRecordStop(false)
 
-	// change state regardless of the response
-	switch c.state {
-	case clientStatePlay:
-		c.state = clientStatePrePlay
-	case clientStateRecord:
-		c.state = clientStatePreRecord
+		// change state regardless of the response
+		switch c.state {
+		case clientStatePlay:
+			c.state = clientStatePrePlay
+		case clientStateRecord:
+			c.state = clientStatePreRecord
+		}
+	} else {
+		// stop reading in order to read the response.
+		// tracks of the session are stopped by resumePlay().
+		c.suspendPlay()
 	}
 
-	res, err := c.do(&base.Request{
-		Method: base.Pause,
-		URL:    c.baseURL,
-	}, false, *c.effectiveTransport == TransportTCP)
-	if err != nil {
-		return nil, err
+	for _, sess := range sessions {
+		sess.active = false
 	}
 
-	if res.StatusCode != base.StatusOK {
-		return nil, liberrors.ErrClientBadStatusCode{
-			Code: res.StatusCode, Message: res.StatusMessage,
+	// resume reading if other sessions are still playing
+	defer c.resumePlay()
+
+	var res *base.Response
+
+	for _, sess := range sessions {
+		var err error
+		res, err = c.doWithSession(sess, &base.Request{
+			Method: base.Pause,
+			URL:    sess.baseURL,
+		}, false, *c.effectiveTransport == TransportTCP)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != base.StatusOK {
+			return nil, liberrors.ErrClientBadStatusCode{
+				Code: res.StatusCode, Message: res.StatusMessage,
+			}
 		}
 	}
 
@@ -1794,12 +2718,45 @@ func (c *Client) doPause() (*base.Response, error) {
 // Pause writes a PAUSE request and reads a Response.
 // This can be called only after Play() or Record().
 func (c *Client) Pause() (*base.Response, error) {
+	return c.PauseContext(context.Background())
+}
+
+// PauseContext writes a PAUSE request and reads a Response.
+// The request is aborted when the context is done.
+func (c *Client) PauseContext(ctx context.Context) (*base.Response, error) {
 	cres := make(chan clientRes)
 	select {
-	case c.pause <- pauseReq{res: cres}:
+	case c.pause <- pauseReq{ctx: ctx, res: cres}:
 		res := <-cres
 		return res.res, res.err
 
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-c.ctx.Done():
+		return nil, liberrors.ErrClientTerminated{}
+	}
+}
+
+// PauseSession writes a PAUSE request that involves only the session with
+// the given base URL. It can be used only when MultiSessionEnable is true.
+// This can be called only after PlaySession() or Play().
+func (c *Client) PauseSession(baseURL *url.URL) (*base.Response, error) {
+	return c.PauseSessionContext(context.Background(), baseURL)
+}
+
+// PauseSessionContext writes a PAUSE request that involves only the session with
+// the given base URL. The request is aborted when the context is done.
+func (c *Client) PauseSessionContext(ctx context.Context, baseURL *url.URL) (*base.Response, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.pause <- pauseReq{ctx: ctx, baseURL: baseURL, res: cres}:
+		res := <-cres
+		return res.res, res.err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
 	case <-c.ctx.Done():
 		return nil, liberrors.ErrClientTerminated{}
 	}
@@ -1807,12 +2764,18 @@ func (c *Client) Pause() (*base.Response, error) {
 
 // Seek asks the server to re-start the stream from a specific timestamp.
 func (c *Client) Seek(ra *headers.Range) (*base.Response, error) {
-	_, err := c.Pause()
+	return c.SeekContext(context.Background(), ra)
+}
+
+// SeekContext asks the server to re-start the stream from a specific timestamp.
+// Requests are aborted when the context is done.
+func (c *Client) SeekContext(ctx context.Context, ra *headers.Range) (*base.Response, error) {
+	_, err := c.PauseContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.Play(ra)
+	return c.PlayContext(ctx, ra)
 }
 
 func (c *Client) runWriter() {
@@ -1890,6 +2853,17 @@ func (c *Client) WritePacketRTP(trackID int, pkt *rtp.Packet, ptsEqualsDTS bool)
 	}
 	byts = byts[:n]
 
+	if r := c.tracks[trackID].retransmitter; r != nil {
+		r.Add(pkt.SequenceNumber, byts)
+	}
+
+	if ctx := c.tracks[trackID].srtpContext; ctx != nil {
+		byts, err = ctx.EncryptRTP(byts)
+		if err != nil {
+			return err
+		}
+	}
+
 	if c.tracks[trackID].udpRTCPSender != nil {
 		c.tracks[trackID].udpRTCPSender.ProcessPacketRTP(time.Now(), pkt, ptsEqualsDTS)
 	}
@@ -1921,6 +2895,13 @@ func (c *Client) WritePacketRTCP(trackID int, pkt rtcp.Packet) error {
 		return err
 	}
 
+	if ctx := c.tracks[trackID].srtpContext; ctx != nil {
+		byts, err = ctx.EncryptRTCP(byts)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.writeBuffer.Push(trackTypePayload{
 		trackID: trackID,
 		isRTP:   false,
@@ -1928,3 +2909,62 @@ func (c *Client) WritePacketRTCP(trackID int, pkt rtcp.Packet) error {
 	})
 	return nil
 }
+
+// retransmit sends again the packets requested by a NACK.
+func (c *Client) retransmit(trackID int, nack *rtcp.TransportLayerNack) {
+	c.writeMutex.RLock()
+	defer c.writeMutex.RUnlock()
+
+	if !c.writeFrameAllowed {
+		return
+	}
+
+	ct := c.tracks[trackID]
+	if ct.retransmitter == nil {
+		return
+	}
+
+	for _, pair := range nack.Nacks {
+		for _, seq := range pair.PacketList() {
+			pkt := ct.retransmitter.Retransmit(seq)
+			if pkt == nil {
+				continue
+			}
+
+			byts, err := pkt.Marshal()
+			if err != nil {
+				continue
+			}
+
+			if ct.srtpContext != nil {
+				byts, err = ct.srtpContext.EncryptRTP(byts)
+				if err != nil {
+					continue
+				}
+			}
+
+			c.writeBuffer.Push(trackTypePayload{
+				trackID: trackID,
+				isRTP:   true,
+				payload: byts,
+			})
+		}
+	}
+}
+
+// RequestKeyFrame asks the server to send a key frame of a track that is being read,
+// by sending a PLI or FIR message.
+// The server must support these messages and the RTP/AVPF profile.
+func (c *Client) RequestKeyFrame(trackID int) error {
+	fr := c.tracks[trackID].feedbackReceiver
+	if fr == nil {
+		return liberrors.ErrClientKeyFrameRequestsNotSupported{}
+	}
+
+	pkt := fr.keyFrameRequest()
+	if pkt == nil {
+		return liberrors.ErrClientKeyFrameRequestsNotSupported{}
+	}
+
+	return c.WritePacketRTCP(trackID, pkt)
+}
@@ -1105,3 +1105,280 @@ func TestClientPublishIgnoreTCPRTPPackets(t *testing.T) {
 
 	<-rtcpReceived
 }
+
+func TestClientPublishReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		for i := 0; i < 2; i++ {
+			nconn, err := l.Accept()
+			require.NoError(t, err)
+			conn := conn.NewConn(nconn)
+
+			req, err := conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Options, req.Method)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Public": base.HeaderValue{strings.Join([]string{
+						string(base.Announce),
+						string(base.Setup),
+						string(base.Record),
+					}, ", ")},
+				},
+			})
+			require.NoError(t, err)
+
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Announce, req.Method)
+			require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream"), req.URL)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+			})
+			require.NoError(t, err)
+
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Setup, req.Method)
+			require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream/trackID=0"), req.URL)
+
+			var inTH headers.Transport
+			err = inTH.Unmarshal(req.Header["Transport"])
+			require.NoError(t, err)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Transport": headers.Transport{
+						Protocol: headers.TransportProtocolTCP,
+						Delivery: func() *headers.TransportDelivery {
+							v := headers.TransportDeliveryUnicast
+							return &v
+						}(),
+						InterleavedIDs: inTH.InterleavedIDs,
+					}.Marshal(),
+				},
+			})
+			require.NoError(t, err)
+
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Record, req.Method)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+			})
+			require.NoError(t, err)
+
+			if i == 0 {
+				nconn.Close()
+				continue
+			}
+
+			f, err := conn.ReadInterleavedFrame()
+			require.NoError(t, err)
+			require.Equal(t, 0, f.Channel)
+			var pkt rtp.Packet
+			err = pkt.Unmarshal(f.Payload)
+			require.NoError(t, err)
+			require.Equal(t, testRTPPacket, pkt)
+
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Teardown, req.Method)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+			})
+			require.NoError(t, err)
+
+			nconn.Close()
+		}
+	}()
+
+	reconnected := make(chan *ClientOnReconnectCtx, 1)
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		ReconnectPolicy: &ClientReconnectPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+		},
+		OnReconnect: func(ctx *ClientOnReconnectCtx) {
+			reconnected <- ctx
+		},
+	}
+
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	err = c.StartPublishing("rtsp://localhost:8554/teststream",
+		Tracks{track})
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := <-reconnected
+	require.Equal(t, 1, ctx.Attempts)
+	require.Equal(t, false, ctx.SDPChanged)
+
+	err = c.WritePacketRTP(0, &testRTPPacket, true)
+	require.NoError(t, err)
+}
+
+func TestClientPublishReconnectFailed(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		conn := conn.NewConn(nconn)
+
+		for _, method := range []base.Method{base.Options, base.Announce, base.Setup, base.Record} {
+			req, err := conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, method, req.Method)
+
+			var th headers.Transport
+			if method == base.Setup {
+				err = th.Unmarshal(req.Header["Transport"])
+				require.NoError(t, err)
+				th.Delivery = func() *headers.TransportDelivery {
+					v := headers.TransportDeliveryUnicast
+					return &v
+				}()
+				th.Mode = nil
+			}
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Transport": th.Marshal(),
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		nconn.Close()
+		l.Close()
+	}()
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		ReconnectPolicy: &ClientReconnectPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: 50 * time.Millisecond,
+		},
+	}
+
+	err = c.StartPublishing("rtsp://localhost:8554/teststream",
+		Tracks{&TrackH264{
+			PayloadType: 96,
+			SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		}})
+	require.NoError(t, err)
+
+	<-serverDone
+
+	err = c.Wait()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to reconnect after 2 attempts")
+}
+
+func TestClientPublishReconnectRequest(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		conn := conn.NewConn(nconn)
+
+		for _, method := range []base.Method{base.Options, base.Announce, base.Setup, base.Record} {
+			req, err := conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, method, req.Method)
+
+			var th headers.Transport
+			if method == base.Setup {
+				err = th.Unmarshal(req.Header["Transport"])
+				require.NoError(t, err)
+				th.Delivery = func() *headers.TransportDelivery {
+					v := headers.TransportDeliveryUnicast
+					return &v
+				}()
+				th.Mode = nil
+			}
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Transport": th.Marshal(),
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		nconn.Close()
+	}()
+
+	disconnected := make(chan struct{})
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		ReconnectPolicy: &ClientReconnectPolicy{
+			InitialBackoff: 1 * time.Hour,
+		},
+		OnDisconnect: func(ctx *ClientOnDisconnectCtx) {
+			close(disconnected)
+		},
+	}
+
+	err = c.StartPublishing("rtsp://localhost:8554/teststream",
+		Tracks{&TrackH264{
+			PayloadType: 96,
+			SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		}})
+	require.NoError(t, err)
+
+	<-serverDone
+	<-disconnected
+
+	// requests must not wait for the reconnection backoff
+	_, err = c.Options(mustParseURL("rtsp://localhost:8554/teststream"))
+	require.EqualError(t, err, "the client is reconnecting to the server")
+
+	c.Close()
+	err = c.Wait()
+	require.EqualError(t, err, "terminated")
+}
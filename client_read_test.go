@@ -1,6 +1,7 @@
 package gortsplib
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -2712,3 +2713,1074 @@ func TestClientReadDifferentSource(t *testing.T) {
 
 	<-packetRecv
 }
+
+func TestClientReadReconnect(t *testing.T) {
+	for _, ca := range []string{
+		"same sdp",
+		"changed sdp",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			l, err := net.Listen("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer l.Close()
+
+			serverDone := make(chan struct{})
+			defer func() { <-serverDone }()
+			go func() {
+				defer close(serverDone)
+
+				for i := 0; i < 2; i++ {
+					nconn, err := l.Accept()
+					require.NoError(t, err)
+					conn := conn.NewConn(nconn)
+
+					req, err := conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Options, req.Method)
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Public": base.HeaderValue{strings.Join([]string{
+								string(base.Describe),
+								string(base.Setup),
+								string(base.Play),
+							}, ", ")},
+						},
+					})
+					require.NoError(t, err)
+
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Describe, req.Method)
+
+					track := &TrackGeneric{
+						Media:   "application",
+						Formats: []string{"97"},
+						RTPMap:  "97 private/90000",
+					}
+
+					if i == 1 && ca == "changed sdp" {
+						track.RTPMap = "97 private2/90000"
+					}
+
+					tracks := Tracks{track}
+					tracks.setControls()
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Content-Type": base.HeaderValue{"application/sdp"},
+							"Content-Base": base.HeaderValue{"rtsp://localhost:8554/teststream/"},
+						},
+						Body: tracks.Marshal(false),
+					})
+					require.NoError(t, err)
+
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Setup, req.Method)
+					require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream/trackID=0"), req.URL)
+
+					var inTH headers.Transport
+					err = inTH.Unmarshal(req.Header["Transport"])
+					require.NoError(t, err)
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Transport": headers.Transport{
+								Protocol: headers.TransportProtocolTCP,
+								Delivery: func() *headers.TransportDelivery {
+									v := headers.TransportDeliveryUnicast
+									return &v
+								}(),
+								InterleavedIDs: inTH.InterleavedIDs,
+							}.Marshal(),
+						},
+					})
+					require.NoError(t, err)
+
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Play, req.Method)
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+					})
+					require.NoError(t, err)
+
+					err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+						Channel: 0,
+						Payload: testRTPPacketMarshaled,
+					}, make([]byte, 1024))
+					require.NoError(t, err)
+
+					if i == 0 {
+						nconn.Close()
+						continue
+					}
+
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Teardown, req.Method)
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+					})
+					require.NoError(t, err)
+
+					nconn.Close()
+				}
+			}()
+
+			packetRecv := make(chan struct{}, 2)
+			disconnected := make(chan struct{})
+			reconnected := make(chan *ClientOnReconnectCtx, 1)
+
+			c := Client{
+				Transport: func() *Transport {
+					v := TransportTCP
+					return &v
+				}(),
+				ReconnectPolicy: &ClientReconnectPolicy{
+					MaxAttempts:    3,
+					InitialBackoff: 100 * time.Millisecond,
+				},
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					require.Equal(t, 0, ctx.TrackID)
+					require.Equal(t, &testRTPPacket, ctx.Packet)
+					packetRecv <- struct{}{}
+				},
+				OnDisconnect: func(ctx *ClientOnDisconnectCtx) {
+					require.Error(t, ctx.Error)
+					close(disconnected)
+				},
+				OnReconnect: func(ctx *ClientOnReconnectCtx) {
+					reconnected <- ctx
+				},
+			}
+
+			err = startReading(&c, "rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			<-packetRecv
+			<-disconnected
+
+			ctx := <-reconnected
+			require.Equal(t, 1, ctx.Attempts)
+			require.Equal(t, ca == "changed sdp", ctx.SDPChanged)
+			require.Equal(t, 1, len(ctx.Tracks))
+
+			if ca == "changed sdp" {
+				require.Equal(t, "97 private2/90000", ctx.Tracks[0].(*TrackGeneric).RTPMap)
+			}
+
+			<-packetRecv
+		})
+	}
+}
+
+func TestClientReadMultipleSessions(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err := conn.ReadRequest()
+		require.NoError(t, err)
+		require.Equal(t, base.Options, req.Method)
+
+		err = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+					string(base.Setup),
+					string(base.Play),
+					string(base.Pause),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err)
+
+		for i, session := range []string{"session1", "session2"} {
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Setup, req.Method)
+			require.Equal(t, mustParseURL(fmt.Sprintf("rtsp://localhost:8554/channel%d/trackID=0", i+1)), req.URL)
+
+			_, ok := req.Header["Session"]
+			require.Equal(t, false, ok)
+
+			var inTH headers.Transport
+			err = inTH.Unmarshal(req.Header["Transport"])
+			require.NoError(t, err)
+			require.Equal(t, &[2]int{i * 2, i*2 + 1}, inTH.InterleavedIDs)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Transport": headers.Transport{
+						Protocol: headers.TransportProtocolTCP,
+						Delivery: func() *headers.TransportDelivery {
+							v := headers.TransportDeliveryUnicast
+							return &v
+						}(),
+						InterleavedIDs: inTH.InterleavedIDs,
+					}.Marshal(),
+					"Session": base.HeaderValue{session},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		for _, step := range []struct {
+			method   base.Method
+			url      string
+			session  string
+			channels []int
+		}{
+			{base.Play, "rtsp://localhost:8554/channel1", "session1", []int{0}},
+			{base.Play, "rtsp://localhost:8554/channel2", "session2", []int{2}},
+			// packets of the paused session are discarded
+			{base.Pause, "rtsp://localhost:8554/channel1", "session1", []int{0, 2}},
+		} {
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, step.method, req.Method)
+			require.Equal(t, mustParseURL(step.url), req.URL)
+			require.Equal(t, base.HeaderValue{step.session}, req.Header["Session"])
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Session": base.HeaderValue{step.session},
+				},
+			})
+			require.NoError(t, err)
+
+			for _, channel := range step.channels {
+				err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+					Channel: channel,
+					Payload: testRTPPacketMarshaled,
+				}, make([]byte, 1024))
+				require.NoError(t, err)
+			}
+		}
+
+		for i, session := range []string{"session1", "session2"} {
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Teardown, req.Method)
+			require.Equal(t, mustParseURL(fmt.Sprintf("rtsp://localhost:8554/channel%d", i+1)), req.URL)
+			require.Equal(t, base.HeaderValue{session}, req.Header["Session"])
+		}
+	}()
+
+	packetRecv := make(chan int)
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		MultiSessionEnable: true,
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			require.Equal(t, &testRTPPacket, ctx.Packet)
+			packetRecv <- ctx.TrackID
+		},
+	}
+
+	err = c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+	defer c.Close()
+
+	baseURLs := []*url.URL{
+		mustParseURL("rtsp://localhost:8554/channel1"),
+		mustParseURL("rtsp://localhost:8554/channel2"),
+	}
+
+	for _, baseURL := range baseURLs {
+		tracks := Tracks{&TrackGeneric{
+			Media:   "application",
+			Formats: []string{"97"},
+			RTPMap:  "97 private/90000",
+		}}
+		tracks.setControls()
+
+		_, err = c.Setup(true, tracks[0], baseURL, 0, 0)
+		require.NoError(t, err)
+	}
+
+	_, err = c.PlaySession(baseURLs[0], nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, <-packetRecv)
+
+	_, err = c.PlaySession(baseURLs[1], nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, <-packetRecv)
+
+	_, err = c.PauseSession(baseURLs[0])
+	require.NoError(t, err)
+	require.Equal(t, 1, <-packetRecv)
+
+	_, err = c.PauseSession(baseURLs[0])
+	require.EqualError(t, err, "must be in state [play], while is in state prePlay")
+}
+
+func TestClientReadMultipleSessionsRequestContext(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	requestReceived := make(chan struct{})
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err := conn.ReadRequest()
+		require.NoError(t, err)
+		require.Equal(t, base.Options, req.Method)
+
+		err = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Describe),
+					string(base.Setup),
+					string(base.Play),
+					string(base.Pause),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err)
+
+		for _, session := range []string{"session1", "session2"} {
+			req, err = conn.ReadRequest()
+			require.NoError(t, err)
+			require.Equal(t, base.Setup, req.Method)
+
+			var inTH headers.Transport
+			err = inTH.Unmarshal(req.Header["Transport"])
+			require.NoError(t, err)
+
+			err = conn.WriteResponse(&base.Response{
+				StatusCode: base.StatusOK,
+				Header: base.Header{
+					"Transport": headers.Transport{
+						Protocol: headers.TransportProtocolTCP,
+						Delivery: func() *headers.TransportDelivery {
+							v := headers.TransportDeliveryUnicast
+							return &v
+						}(),
+						InterleavedIDs: inTH.InterleavedIDs,
+					}.Marshal(),
+					"Session": base.HeaderValue{session},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		req, err = conn.ReadRequest()
+		require.NoError(t, err)
+		require.Equal(t, base.Play, req.Method)
+		require.Equal(t, base.HeaderValue{"session1"}, req.Header["Session"])
+
+		err = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Session": base.HeaderValue{"session1"},
+			},
+		})
+		require.NoError(t, err)
+
+		req, err = conn.ReadRequest()
+		require.NoError(t, err)
+		require.Equal(t, base.Play, req.Method)
+		require.Equal(t, base.HeaderValue{"session2"}, req.Header["Session"])
+
+		close(requestReceived)
+
+		// the client closes the connection after aborting the request
+		_, err = conn.ReadRequest()
+		require.Error(t, err)
+	}()
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		MultiSessionEnable: true,
+	}
+
+	err = c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+	defer c.Close()
+
+	baseURLs := []*url.URL{
+		mustParseURL("rtsp://localhost:8554/channel1"),
+		mustParseURL("rtsp://localhost:8554/channel2"),
+	}
+
+	for _, baseURL := range baseURLs {
+		tracks := Tracks{&TrackGeneric{
+			Media:   "application",
+			Formats: []string{"97"},
+			RTPMap:  "97 private/90000",
+		}}
+		tracks.setControls()
+
+		_, err = c.Setup(true, tracks[0], baseURL, 0, 0)
+		require.NoError(t, err)
+	}
+
+	_, err = c.PlaySession(baseURLs[0], nil)
+	require.NoError(t, err)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	go func() {
+		<-requestReceived
+		ctxCancel()
+	}()
+
+	_, err = c.PlaySessionContext(ctx, baseURLs[1], nil)
+	require.Equal(t, context.Canceled, err)
+
+	// the client has been moved to the initial state
+	_, err = c.PauseSession(baseURLs[0])
+	require.EqualError(t, err, "must be in state [play], while is in state initial")
+}
+
+func TestClientReadSessionsWithoutMultiSession(t *testing.T) {
+	c := Client{}
+
+	err := c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+	defer c.Close()
+
+	baseURL := mustParseURL("rtsp://localhost:8554/channel1")
+
+	_, err = c.PlaySession(baseURL, nil)
+	require.EqualError(t, err, "sessions can be played or paused individually only when MultiSessionEnable is true")
+
+	_, err = c.PauseSession(baseURL)
+	require.EqualError(t, err, "sessions can be played or paused individually only when MultiSessionEnable is true")
+}
+
+func TestClientReadMultipleSessionsSwitchProtocol(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	tracks := Tracks{&TrackGeneric{
+		Media:   "application",
+		Formats: []string{"97"},
+		RTPMap:  "97 private/90000",
+	}}
+	tracks.setControls()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		for _, proto := range []headers.TransportProtocol{
+			headers.TransportProtocolUDP,
+			headers.TransportProtocolTCP,
+		} {
+			func() {
+				nconn, err := l.Accept()
+				require.NoError(t, err)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				req, err := conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Options, req.Method)
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Public": base.HeaderValue{strings.Join([]string{
+							string(base.Describe),
+							string(base.Setup),
+							string(base.Play),
+							string(base.Pause),
+						}, ", ")},
+					},
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Describe, req.Method)
+				require.Equal(t, mustParseURL("rtsp://localhost:8554/channel1"), req.URL)
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Content-Type": base.HeaderValue{"application/sdp"},
+						"Content-Base": base.HeaderValue{"rtsp://localhost:8554/channel1/"},
+					},
+					Body: tracks.Marshal(false),
+				})
+				require.NoError(t, err)
+
+				for i, session := range []string{"session1", "session2"} {
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Setup, req.Method)
+					require.Equal(t, mustParseURL(fmt.Sprintf("rtsp://localhost:8554/channel%d/trackID=0", i+1)), req.URL)
+
+					var inTH headers.Transport
+					err = inTH.Unmarshal(req.Header["Transport"])
+					require.NoError(t, err)
+
+					th := headers.Transport{
+						Protocol: proto,
+						Delivery: func() *headers.TransportDelivery {
+							v := headers.TransportDeliveryUnicast
+							return &v
+						}(),
+					}
+
+					if proto == headers.TransportProtocolUDP {
+						th.ServerPorts = &[2]int{34556 + i*2, 34557 + i*2}
+						th.ClientPorts = inTH.ClientPorts
+					} else {
+						th.InterleavedIDs = inTH.InterleavedIDs
+					}
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Transport": th.Marshal(),
+							"Session":   base.HeaderValue{session},
+						},
+					})
+					require.NoError(t, err)
+				}
+
+				steps := []struct {
+					method  base.Method
+					url     string
+					session string
+				}{
+					{base.Play, "rtsp://localhost:8554/channel1", "session1"},
+					{base.Play, "rtsp://localhost:8554/channel2", "session2"},
+					{base.Pause, "rtsp://localhost:8554/channel1", "session1"},
+				}
+
+				// after switching protocol, only the session that was playing is resumed
+				if proto == headers.TransportProtocolTCP {
+					steps = steps[1:2]
+				}
+
+				for _, step := range steps {
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, step.method, req.Method)
+					require.Equal(t, mustParseURL(step.url), req.URL)
+					require.Equal(t, base.HeaderValue{step.session}, req.Header["Session"])
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Session": base.HeaderValue{step.session},
+						},
+					})
+					require.NoError(t, err)
+				}
+
+				if proto == headers.TransportProtocolTCP {
+					err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+						Channel: 2,
+						Payload: testRTPPacketMarshaled,
+					}, make([]byte, 1024))
+					require.NoError(t, err)
+				}
+
+				for i, session := range []string{"session1", "session2"} {
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Teardown, req.Method)
+					require.Equal(t, mustParseURL(fmt.Sprintf("rtsp://localhost:8554/channel%d", i+1)), req.URL)
+					require.Equal(t, base.HeaderValue{session}, req.Header["Session"])
+				}
+			}()
+		}
+	}()
+
+	packetRecv := make(chan int)
+
+	c := Client{
+		MultiSessionEnable:    true,
+		InitialUDPReadTimeout: 1 * time.Second,
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			require.Equal(t, &testRTPPacket, ctx.Packet)
+			packetRecv <- ctx.TrackID
+		},
+	}
+
+	err = c.Start("rtsp", "localhost:8554")
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, _, _, err = c.Describe(mustParseURL("rtsp://localhost:8554/channel1"))
+	require.NoError(t, err)
+
+	baseURLs := []*url.URL{
+		mustParseURL("rtsp://localhost:8554/channel1"),
+		mustParseURL("rtsp://localhost:8554/channel2"),
+	}
+
+	for _, baseURL := range baseURLs {
+		_, err = c.Setup(true, tracks[0], baseURL, 0, 0)
+		require.NoError(t, err)
+	}
+
+	for _, baseURL := range baseURLs {
+		_, err = c.PlaySession(baseURL, nil)
+		require.NoError(t, err)
+	}
+
+	_, err = c.PauseSession(baseURLs[0])
+	require.NoError(t, err)
+
+	require.Equal(t, 1, <-packetRecv)
+}
+
+func TestClientReadRTSP20(t *testing.T) {
+	for _, ca := range []string{
+		"supported",
+		"fallback",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			l, err := net.Listen("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer l.Close()
+
+			serverDone := make(chan struct{})
+			defer func() { <-serverDone }()
+			go func() {
+				defer close(serverDone)
+
+				nconn, err := l.Accept()
+				require.NoError(t, err)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				req, err := conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Options, req.Method)
+				require.Equal(t, base.Version20, req.Version)
+
+				version := base.Version20
+
+				if ca == "fallback" {
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusRTSPVersionNotSupported,
+					})
+					require.NoError(t, err)
+
+					req, err = conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Options, req.Method)
+					require.Equal(t, base.Version10, req.Version)
+
+					version = base.Version10
+				}
+
+				err = conn.WriteResponse(&base.Response{
+					Version:    version,
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Public": base.HeaderValue{strings.Join([]string{
+							string(base.Describe),
+							string(base.Setup),
+							string(base.Play),
+						}, ", ")},
+					},
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Describe, req.Method)
+				require.Equal(t, version, req.Version)
+
+				tracks := Tracks{&TrackH264{
+					PayloadType: 96,
+					SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+					PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				}}
+				tracks.setControls()
+
+				err = conn.WriteResponse(&base.Response{
+					Version:    version,
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Content-Type": base.HeaderValue{"application/sdp"},
+						"Content-Base": base.HeaderValue{"rtsp://localhost:8554/teststream/"},
+					},
+					Body: tracks.Marshal(false),
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Setup, req.Method)
+				require.Equal(t, version, req.Version)
+
+				var th headers.Transport
+				err = th.Unmarshal(req.Header["Transport"])
+				require.NoError(t, err)
+
+				var clientPorts [2]int
+
+				if ca == "supported" {
+					require.Nil(t, th.ClientPorts)
+					require.Equal(t, 2, len(th.DestinationAddresses))
+					require.Equal(t, base.HeaderValue{"npt, clock, smpte"}, req.Header["Accept-Ranges"])
+					clientPorts = [2]int{th.DestinationAddresses[0].Port, th.DestinationAddresses[1].Port}
+				} else {
+					require.Nil(t, th.DestinationAddresses)
+					clientPorts = *th.ClientPorts
+				}
+
+				l1, err := net.ListenPacket("udp", "localhost:34556")
+				require.NoError(t, err)
+				defer l1.Close()
+
+				l2, err := net.ListenPacket("udp", "localhost:34557")
+				require.NoError(t, err)
+				defer l2.Close()
+
+				resTH := headers.Transport{
+					Protocol: headers.TransportProtocolUDP,
+					Delivery: func() *headers.TransportDelivery {
+						v := headers.TransportDeliveryUnicast
+						return &v
+					}(),
+				}
+
+				if ca == "supported" {
+					resTH.DestinationAddresses = th.DestinationAddresses
+					resTH.SourceAddresses = []headers.TransportAddress{
+						{IP: net.ParseIP("127.0.0.1"), Port: 34556},
+						{IP: net.ParseIP("127.0.0.1"), Port: 34557},
+					}
+				} else {
+					resTH.ClientPorts = th.ClientPorts
+					resTH.ServerPorts = &[2]int{34556, 34557}
+				}
+
+				err = conn.WriteResponse(&base.Response{
+					Version:    version,
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Transport": resTH.Marshal(),
+					},
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Play, req.Method)
+				require.Equal(t, version, req.Version)
+
+				err = conn.WriteResponse(&base.Response{
+					Version:    version,
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err)
+
+				time.Sleep(500 * time.Millisecond)
+
+				l1.WriteTo(testRTPPacketMarshaled, &net.UDPAddr{
+					IP:   net.ParseIP("127.0.0.1"),
+					Port: clientPorts[0],
+				})
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Teardown, req.Method)
+				require.Equal(t, version, req.Version)
+
+				err = conn.WriteResponse(&base.Response{
+					Version:    version,
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err)
+			}()
+
+			packetRecv := make(chan struct{})
+
+			c := Client{
+				Version: base.Version20,
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					require.Equal(t, &testRTPPacket, ctx.Packet)
+					close(packetRecv)
+				},
+			}
+
+			err = startReading(&c, "rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			<-packetRecv
+		})
+	}
+}
+
+func TestClientReadServerRequests(t *testing.T) {
+	for _, ca := range []string{
+		"no reconnect",
+		"reconnect",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			l, err := net.Listen("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer l.Close()
+
+			l2, err := net.Listen("tcp", "localhost:8555")
+			require.NoError(t, err)
+			defer l2.Close()
+
+			serveRead := func(conn *conn.Conn, u string) {
+				req, err := conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Options, req.Method)
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Public": base.HeaderValue{strings.Join([]string{
+							string(base.Describe),
+							string(base.Setup),
+							string(base.Play),
+						}, ", ")},
+					},
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Describe, req.Method)
+				require.Equal(t, mustParseURL(u), req.URL)
+
+				tracks := Tracks{&TrackGeneric{
+					Media:   "application",
+					Formats: []string{"97"},
+					RTPMap:  "97 private/90000",
+				}}
+				tracks.setControls()
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Content-Type": base.HeaderValue{"application/sdp"},
+						"Content-Base": base.HeaderValue{u + "/"},
+					},
+					Body: tracks.Marshal(false),
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Setup, req.Method)
+				require.Equal(t, mustParseURL(u+"/trackID=0"), req.URL)
+
+				var inTH headers.Transport
+				err = inTH.Unmarshal(req.Header["Transport"])
+				require.NoError(t, err)
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Transport": headers.Transport{
+							Protocol: headers.TransportProtocolTCP,
+							Delivery: func() *headers.TransportDelivery {
+								v := headers.TransportDeliveryUnicast
+								return &v
+							}(),
+							InterleavedIDs: inTH.InterleavedIDs,
+						}.Marshal(),
+						"Session": base.HeaderValue{"ABCDE"},
+					},
+				})
+				require.NoError(t, err)
+
+				req, err = conn.ReadRequest()
+				require.NoError(t, err)
+				require.Equal(t, base.Play, req.Method)
+
+				err = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err)
+			}
+
+			serverDone := make(chan struct{})
+			defer func() { <-serverDone }()
+			go func() {
+				defer close(serverDone)
+
+				func() {
+					nconn, err := l.Accept()
+					require.NoError(t, err)
+					defer nconn.Close()
+					conn := conn.NewConn(nconn)
+
+					serveRead(conn, "rtsp://localhost:8554/teststream")
+
+					err = conn.WriteRequest(&base.Request{
+						Method: base.SetParameter,
+						URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+						Header: base.Header{
+							"CSeq":    base.HeaderValue{"1"},
+							"Session": base.HeaderValue{"ABCDE"},
+						},
+						Body: []byte("param: value\r\n"),
+					})
+					require.NoError(t, err)
+
+					res, err := conn.ReadResponseIgnoreFrames()
+					require.NoError(t, err)
+					require.Equal(t, base.StatusOK, res.StatusCode)
+					require.Equal(t, base.HeaderValue{"1"}, res.Header["CSeq"])
+					require.Equal(t, base.HeaderValue{"ABCDE"}, res.Header["Session"])
+
+					tracks := Tracks{&TrackGeneric{
+						Media:   "application",
+						Formats: []string{"98"},
+						RTPMap:  "98 private/90000",
+					}}
+					tracks.setControls()
+
+					err = conn.WriteRequest(&base.Request{
+						Method: base.Announce,
+						URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+						Header: base.Header{
+							"CSeq":         base.HeaderValue{"2"},
+							"Content-Type": base.HeaderValue{"application/sdp"},
+						},
+						Body: tracks.Marshal(false),
+					})
+					require.NoError(t, err)
+
+					res, err = conn.ReadResponseIgnoreFrames()
+					require.NoError(t, err)
+					require.Equal(t, base.StatusOK, res.StatusCode)
+					require.Equal(t, base.HeaderValue{"2"}, res.Header["CSeq"])
+
+					err = conn.WriteRequest(&base.Request{
+						Method: base.Describe,
+						URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+						Header: base.Header{
+							"CSeq": base.HeaderValue{"3"},
+						},
+					})
+					require.NoError(t, err)
+
+					res, err = conn.ReadResponseIgnoreFrames()
+					require.NoError(t, err)
+					require.Equal(t, base.StatusNotImplemented, res.StatusCode)
+
+					err = conn.WriteRequest(&base.Request{
+						Method: base.Redirect,
+						URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+						Header: base.Header{
+							"CSeq":     base.HeaderValue{"4"},
+							"Location": base.HeaderValue{"rtsp://localhost:8555/teststream2"},
+						},
+					})
+					require.NoError(t, err)
+
+					res, err = conn.ReadResponseIgnoreFrames()
+					require.NoError(t, err)
+					require.Equal(t, base.StatusOK, res.StatusCode)
+				}()
+
+				if ca == "reconnect" {
+					nconn, err := l2.Accept()
+					require.NoError(t, err)
+					defer nconn.Close()
+					conn := conn.NewConn(nconn)
+
+					serveRead(conn, "rtsp://localhost:8555/teststream2")
+
+					req, err := conn.ReadRequest()
+					require.NoError(t, err)
+					require.Equal(t, base.Teardown, req.Method)
+
+					err = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+					})
+					require.NoError(t, err)
+				}
+			}()
+
+			var methods []base.Method
+			var announcedTracks Tracks
+			reconnected := make(chan struct{})
+
+			c := Client{
+				Transport: func() *Transport {
+					v := TransportTCP
+					return &v
+				}(),
+				OnServerRequest: func(ctx *ClientOnServerRequestCtx) {
+					methods = append(methods, ctx.Request.Method)
+					if ctx.Request.Method == base.Announce {
+						announcedTracks = ctx.Tracks
+					}
+				},
+			}
+
+			if ca == "reconnect" {
+				c.ReconnectPolicy = &ClientReconnectPolicy{
+					InitialBackoff: 100 * time.Millisecond,
+				}
+				c.OnReconnect = func(ctx *ClientOnReconnectCtx) {
+					close(reconnected)
+				}
+			}
+
+			err = startReading(&c, "rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			if ca == "no reconnect" {
+				err = c.Wait()
+				require.EqualError(t, err, "redirected by the server to rtsp://localhost:8555/teststream2")
+			} else {
+				<-reconnected
+			}
+
+			require.Equal(t, []base.Method{base.SetParameter, base.Announce, base.Redirect}, methods)
+			require.Equal(t, 1, len(announcedTracks))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package gortsplib
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
+)
+
+// ClientReconnectPolicy allows to reconnect automatically to the server
+// when the connection is lost while reading or publishing.
+type ClientReconnectPolicy struct {
+	// maximum number of consecutive reconnection attempts.
+	// It defaults to 0, that means unlimited.
+	MaxAttempts int
+	// time to wait before the first reconnection attempt.
+	// It is doubled after every failed attempt.
+	// It defaults to 1 second.
+	InitialBackoff time.Duration
+	// maximum time to wait between two reconnection attempts.
+	// It defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// ClientOnDisconnectCtx is the context of a disconnection.
+type ClientOnDisconnectCtx struct {
+	Error error
+}
+
+// ClientOnReconnectCtx is the context of a reconnection.
+type ClientOnReconnectCtx struct {
+	// number of attempts that were needed to reconnect.
+	Attempts int
+	// whether the SDP returned by the server is different from the previous one.
+	// It is always false when publishing.
+	SDPChanged bool
+	// tracks that the client is reading or publishing after the reconnection.
+	Tracks Tracks
+}
+
+type clientReconnectState struct {
+	scheme             string
+	host               string
+	state              clientState
+	useGetParameter    bool
+	effectiveTransport *Transport
+	tracks             []*clientTrack
+	describeTracks     Tracks
+	attempt            int
+	backoff            time.Duration
+	maxBackoff         time.Duration
+}
+
+// tryReconnecting schedules a reconnection, that is performed by runInner()
+// when reconnectTimer fires, in order not to block requests in the meanwhile.
+func (c *Client) tryReconnecting(cause error) error {
+	// reconnection is not supported with multiple sessions
+	if c.ReconnectPolicy == nil ||
+		(c.state != clientStatePlay && c.state != clientStateRecord) ||
+		len(c.sessions) > 1 {
+		return cause
+	}
+
+	if c.OnDisconnect != nil {
+		c.OnDisconnect(&ClientOnDisconnectCtx{
+			Error: cause,
+		})
+	}
+
+	prev := &clientReconnectState{
+		scheme:             c.scheme,
+		host:               c.host,
+		state:              c.state,
+		useGetParameter:    c.useGetParameter,
+		effectiveTransport: c.effectiveTransport,
+		tracks:             c.tracks,
+		describeTracks:     c.lastDescribeTracks,
+		backoff:            c.ReconnectPolicy.InitialBackoff,
+		maxBackoff:         c.ReconnectPolicy.MaxBackoff,
+	}
+
+	if prev.backoff == 0 {
+		prev.backoff = 1 * time.Second
+	}
+
+	if prev.maxBackoff == 0 {
+		prev.maxBackoff = 30 * time.Second
+	}
+
+	c.reset()
+
+	c.reconnectState = prev
+	c.reconnectTimer = time.NewTimer(prev.backoff)
+	return nil
+}
+
+func (c *Client) doReconnect() error {
+	prev := c.reconnectState
+	c.reconnectState = nil
+	prev.attempt++
+
+	sdpChanged, err := c.reconnect(prev)
+	if err == nil {
+		if c.OnReconnect != nil {
+			c.OnReconnect(&ClientOnReconnectCtx{
+				Attempts:   prev.attempt,
+				SDPChanged: sdpChanged,
+				Tracks:     c.Tracks(),
+			})
+		}
+		return nil
+	}
+
+	if c.ReconnectPolicy.MaxAttempts != 0 && prev.attempt >= c.ReconnectPolicy.MaxAttempts {
+		return liberrors.ErrClientReconnectFailed{Attempts: prev.attempt, Err: err}
+	}
+
+	c.reset()
+
+	prev.backoff *= 2
+	if prev.backoff > prev.maxBackoff {
+		prev.backoff = prev.maxBackoff
+	}
+
+	c.reconnectState = prev
+	c.reconnectTimer = time.NewTimer(prev.backoff)
+	return nil
+}
+
+func (c *Client) reconnect(prev *clientReconnectState) (bool, error) {
+	c.scheme = prev.scheme
+	c.host = prev.host
+	c.useGetParameter = prev.useGetParameter
+	c.effectiveTransport = prev.effectiveTransport
+
+	if prev.state == clientStateRecord {
+		_, err := c.doAnnounce(c.lastAnnounceURL, c.lastAnnounceTracks)
+		if err != nil {
+			return false, err
+		}
+
+		for _, ct := range prev.tracks {
+			_, err := c.doSetup(false, ct.track, c.baseURL, 0, 0)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		_, err = c.doRecord()
+		return false, err
+	}
+
+	tracks, baseURL, _, err := c.doDescribe(c.lastDescribeURL)
+	if err != nil {
+		return false, err
+	}
+
+	sdpChanged := !bytes.Equal(prev.describeTracks.Marshal(false), tracks.Marshal(false))
+
+	// when the SDP doesn't change, keep using the previous tracks,
+	// in order to associate them with the new SRTP keys.
+	if !sdpChanged {
+		c.lastDescribeTracks = prev.describeTracks
+	}
+
+	for _, ct := range prev.tracks {
+		track := ct.track
+
+		// when the SDP changes, use the track that is in the same position
+		// of the new SDP, in order to preserve track IDs.
+		if sdpChanged {
+			i := findTrack(prev.describeTracks, track)
+			if i < 0 || i >= len(tracks) {
+				return true, liberrors.ErrClientReconnectTrackMissing{}
+			}
+
+			track = tracks[i]
+		}
+
+		// use the new base URL, since the server may have redirected the client.
+		_, err := c.doSetup(true, track, baseURL, 0, 0)
+		if err != nil {
+			return sdpChanged, err
+		}
+	}
+
+	_, err = c.doPlay(c.lastPlayOptions, nil, true)
+	return sdpChanged, err
+}
+
+func findTrack(tracks Tracks, track Track) int {
+	for i, t := range tracks {
+		if t == track {
+			return i
+		}
+	}
+	return -1
+}
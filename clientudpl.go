@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
 	"golang.org/x/net/ipv4"
 )
 
@@ -190,13 +191,40 @@ func (u *clientUDPListener) runReader(forPlay bool) {
 }
 
 func (u *clientUDPListener) processPlayRTP(now time.Time, payload []byte) {
+	payload, ok := srtpDecryptRTP(u.ct.srtpContext, payload)
+	if !ok {
+		return
+	}
+
 	pkt := u.ct.udpRTPPacketBuffer.next()
 	err := pkt.Unmarshal(payload)
 	if err != nil {
 		return
 	}
 
-	packets := u.ct.reorderer.Process(pkt)
+	fr := u.ct.feedbackReceiver
+	if fr != nil {
+		pkt, ok = fr.processRTP(pkt)
+		if !ok {
+			return
+		}
+	}
+
+	var packets []*rtp.Packet
+	if u.ct.fecReceiver != nil {
+		// rebuild lost packets before reordering
+		for _, pkt := range u.ct.fecReceiver.processRTP(pkt) {
+			packets = append(packets, u.ct.reorderer.Process(pkt)...)
+		}
+	} else {
+		packets = u.ct.reorderer.Process(pkt)
+	}
+
+	if fr != nil {
+		if nack := fr.nack(u.ct.reorderer.Missing()); nack != nil {
+			u.c.WritePacketRTCP(u.ct.id, nack)
+		}
+	}
 
 	for _, pkt := range packets {
 		out, err := u.ct.cleaner.Process(pkt)
@@ -218,6 +246,11 @@ func (u *clientUDPListener) processPlayRTP(now time.Time, payload []byte) {
 }
 
 func (u *clientUDPListener) processPlayRTCP(now time.Time, payload []byte) {
+	payload, ok := srtpDecryptRTCP(u.ct.srtpContext, payload)
+	if !ok {
+		return
+	}
+
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
 		return
@@ -233,12 +266,21 @@ func (u *clientUDPListener) processPlayRTCP(now time.Time, payload []byte) {
 }
 
 func (u *clientUDPListener) processRecordRTCP(now time.Time, payload []byte) {
+	payload, ok := srtpDecryptRTCP(u.ct.srtpContext, payload)
+	if !ok {
+		return
+	}
+
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
 		return
 	}
 
 	for _, pkt := range packets {
+		if nack, ok := pkt.(*rtcp.TransportLayerNack); ok {
+			u.c.retransmit(u.ct.id, nack)
+		}
+
 		u.c.OnPacketRTCP(&ClientOnPacketRTCPCtx{
 			TrackID: u.ct.id,
 			Packet:  pkt,
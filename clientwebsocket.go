@@ -0,0 +1,26 @@
+package gortsplib
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/wsconn"
+)
+
+// WebSocketDialContext returns a function that can be used as Client.DialContext
+// in order to reach the server through a WebSocket endpoint (ws:// or wss://)
+// instead of a TCP connection.
+// tlsConfig is used with wss:// URLs and can be nil.
+// When connected through a WebSocket, the client always uses the TCP transport.
+// The address requested by the client is ignored, since the connection is always
+// established with the host of u, and the underlying TCP connection is opened
+// with a net.Dialer, since the returned function replaces Client.DialContext.
+func WebSocketDialContext(
+	u string,
+	tlsConfig *tls.Config,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return wsconn.Dial(ctx, (&net.Dialer{}).DialContext, u, tlsConfig)
+	}
+}
@@ -5,13 +5,14 @@ import (
 	"net"
 
 	"github.com/cobalt-robotics/gortsplib"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpopus"
 	"github.com/pion/rtp"
 )
 
 // This example shows how to
 // 1. generate RTP/Opus packets with GStreamer
 // 2. connect to a RTSP server, announce an Opus track
-// 3. route the packets from GStreamer to the server
+// 3. decode the Opus packets from GStreamer, re-encode them and send them to the server
 
 func main() {
 	// open a listener to receive RTP/Opus packets
@@ -50,6 +51,16 @@ func main() {
 	}
 	defer c.Close()
 
+	// setup a decoder to get Opus packets and their PTS from GStreamer
+	dec := &rtpopus.Decoder{}
+	dec.Init()
+
+	// setup an encoder to generate RTP/Opus packets
+	enc := &rtpopus.Encoder{
+		PayloadType: track.PayloadType,
+	}
+	enc.Init()
+
 	var pkt rtp.Packet
 	for {
 		// parse RTP packet
@@ -58,10 +69,22 @@ func main() {
 			panic(err)
 		}
 
-		// route RTP packet to the server
-		err = c.WritePacketRTP(0, &pkt, true)
-		if err != nil {
-			panic(err)
+		// decode the Opus packet
+		opusPkt, pts, err := dec.Decode(&pkt)
+		if err == nil {
+			// encode the Opus packet into RTP packets
+			pkts, err := enc.Encode([][]byte{opusPkt}, pts)
+			if err != nil {
+				panic(err)
+			}
+
+			// send RTP packets to the server
+			for _, pkt := range pkts {
+				err = c.WritePacketRTP(0, pkt, true)
+				if err != nil {
+					panic(err)
+				}
+			}
 		}
 
 		// read another RTP packet from source
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+
+	"github.com/cobalt-robotics/gortsplib"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtph265"
+	"github.com/cobalt-robotics/gortsplib/pkg/url"
+)
+
+// This example shows how to
+// 1. connect to a RTSP server and read all tracks on a path
+// 2. check if there's an H265 track
+// 3. get H265 NALUs of that track
+
+func main() {
+	c := gortsplib.Client{}
+
+	// parse URL
+	u, err := url.Parse("rtsp://localhost:8554/mystream")
+	if err != nil {
+		panic(err)
+	}
+
+	// connect to the server
+	err = c.Start(u.Scheme, u.Host)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	// find published tracks
+	tracks, baseURL, _, err := c.Describe(u)
+	if err != nil {
+		panic(err)
+	}
+
+	// find the H265 track
+	h265Track, h265TrackID := func() (*gortsplib.TrackH265, int) {
+		for i, track := range tracks {
+			if tt, ok := track.(*gortsplib.TrackH265); ok {
+				return tt, i
+			}
+		}
+		return nil, -1
+	}()
+	if h265Track == nil {
+		panic("H265 track not found")
+	}
+
+	// setup decoder
+	dec := &rtph265.Decoder{
+		MaxDONDiff: h265Track.MaxDONDiff,
+	}
+	dec.Init()
+
+	// called when a RTP packet arrives
+	c.OnPacketRTP = func(ctx *gortsplib.ClientOnPacketRTPCtx) {
+		if ctx.TrackID != h265TrackID {
+			return
+		}
+
+		// decode H265 NALUs from the RTP packet
+		nalus, pts, err := dec.DecodeUntilMarker(ctx.Packet)
+		if err != nil {
+			return
+		}
+
+		// print NALUs
+		for _, nalu := range nalus {
+			log.Printf("received H265 NALU with PTS %v and size %d\n", pts, len(nalu))
+		}
+	}
+
+	// setup and read all tracks
+	err = c.SetupAndPlay(tracks, baseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	// wait until a fatal error
+	panic(c.Wait())
+}
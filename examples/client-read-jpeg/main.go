@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"image/jpeg"
+	"log"
+
+	"github.com/cobalt-robotics/gortsplib"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpjpeg"
+	"github.com/cobalt-robotics/gortsplib/pkg/url"
+)
+
+// This example shows how to
+// 1. connect to a RTSP server and read all tracks on a path
+// 2. check if there's a JPEG track
+// 3. get JPEG images of that track and decode them
+
+func main() {
+	c := gortsplib.Client{}
+
+	// parse URL
+	u, err := url.Parse("rtsp://localhost:8554/mystream")
+	if err != nil {
+		panic(err)
+	}
+
+	// connect to the server
+	err = c.Start(u.Scheme, u.Host)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	// find published tracks
+	tracks, baseURL, _, err := c.Describe(u)
+	if err != nil {
+		panic(err)
+	}
+
+	// find the JPEG track
+	jpegTrackID := func() int {
+		for i, track := range tracks {
+			if _, ok := track.(*gortsplib.TrackJPEG); ok {
+				return i
+			}
+		}
+		return -1
+	}()
+	if jpegTrackID < 0 {
+		panic("JPEG track not found")
+	}
+
+	// setup decoder
+	dec := &rtpjpeg.Decoder{}
+	dec.Init()
+
+	// called when a RTP packet arrives
+	c.OnPacketRTP = func(ctx *gortsplib.ClientOnPacketRTPCtx) {
+		if ctx.TrackID != jpegTrackID {
+			return
+		}
+
+		// decode a JPEG image from the RTP packet
+		byts, pts, err := dec.Decode(ctx.Packet)
+		if err != nil {
+			return
+		}
+
+		// convert the JPEG image into a Go image
+		img, err := jpeg.Decode(bytes.NewReader(byts))
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("received image with PTS %v and size %v\n", pts, img.Bounds().Max)
+	}
+
+	// setup and read all tracks
+	err = c.SetupAndPlay(tracks, baseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	// wait until a fatal error
+	panic(c.Wait())
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+
+	"github.com/cobalt-robotics/gortsplib"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpvp9"
+	"github.com/cobalt-robotics/gortsplib/pkg/url"
+)
+
+// This example shows how to
+// 1. connect to a RTSP server and read all tracks on a path
+// 2. check if there's an VP9 track
+// 3. get VP9 frames of that track, starting from a key frame
+
+func main() {
+	c := gortsplib.Client{}
+
+	// parse URL
+	u, err := url.Parse("rtsp://localhost:8554/mystream")
+	if err != nil {
+		panic(err)
+	}
+
+	// connect to the server
+	err = c.Start(u.Scheme, u.Host)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	// find published tracks
+	tracks, baseURL, _, err := c.Describe(u)
+	if err != nil {
+		panic(err)
+	}
+
+	// find the VP9 track
+	vp9Track, vp9TrackID := func() (*gortsplib.TrackVP9, int) {
+		for i, track := range tracks {
+			if tt, ok := track.(*gortsplib.TrackVP9); ok {
+				return tt, i
+			}
+		}
+		return nil, -1
+	}()
+	if vp9Track == nil {
+		panic("VP9 track not found")
+	}
+
+	// setup decoder
+	dec := &rtpvp9.Decoder{}
+	dec.Init()
+
+	keyFrameReceived := false
+
+	// called when a RTP packet arrives
+	c.OnPacketRTP = func(ctx *gortsplib.ClientOnPacketRTPCtx) {
+		if ctx.TrackID != vp9TrackID {
+			return
+		}
+
+		// decode a VP9 frame from the RTP packet
+		frame, pts, err := dec.Decode(ctx.Packet)
+		if err != nil {
+			return
+		}
+
+		// wait for a key frame
+		if !keyFrameReceived {
+			if !rtpvp9.IsKeyFrame(frame) {
+				return
+			}
+			keyFrameReceived = true
+		}
+
+		log.Printf("received VP9 frame with PTS %v and size %d\n", pts, len(frame))
+	}
+
+	// setup and read all tracks
+	err = c.SetupAndPlay(tracks, baseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	// wait until a fatal error
+	panic(c.Wait())
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+
+	"github.com/cobalt-robotics/gortsplib"
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// This example shows how to
+// 1. create a RTSP server that routes streams with the built-in router
+// 2. allow clients to publish streams to any path, with TCP or UDP
+// 3. allow clients to read those streams with TCP, UDP or UDP-multicast
+// 4. protect a path with a query-based policy
+
+func main() {
+	router := &gortsplib.ServerRouter{
+		PathConf: func(path string) *gortsplib.ServerRouterPathConf {
+			conf := &gortsplib.ServerRouterPathConf{
+				// a new publisher replaces the existing one
+				OverridePublisher: true,
+			}
+
+			// only clients that provide the right key can publish to the "private" path
+			if path == "private" {
+				conf.PublishPolicy = func(ctx *gortsplib.ServerRouterPolicyCtx) *base.Response {
+					if ctx.Query != "key=secret" {
+						return &base.Response{
+							StatusCode: base.StatusForbidden,
+						}
+					}
+					return nil
+				}
+			}
+
+			return conf
+		},
+		OnStreamReady: func(ctx *gortsplib.ServerRouterOnStreamReadyCtx) {
+			log.Printf("stream is available on path '%s'", ctx.Path)
+		},
+		OnStreamClose: func(ctx *gortsplib.ServerRouterOnStreamCloseCtx) {
+			log.Printf("stream is not available anymore on path '%s'", ctx.Path)
+		},
+	}
+
+	// configure server
+	s := &gortsplib.Server{
+		Handler:           router,
+		RTSPAddress:       ":8554",
+		UDPRTPAddress:     ":8000",
+		UDPRTCPAddress:    ":8001",
+		MulticastIPRange:  "224.1.0.0/16",
+		MulticastRTPPort:  8002,
+		MulticastRTCPPort: 8003,
+	}
+
+	// start server and wait until a fatal error
+	log.Printf("server is ready")
+	panic(s.StartAndWait())
+}
@@ -0,0 +1,110 @@
+package av1
+
+import (
+	"fmt"
+)
+
+// BitstreamUnmarshal extracts OBUs from a temporal unit in the low overhead bitstream format.
+// If removeSizeField is true, the obu_size field is removed from each OBU
+// and the obu_has_size_field flag is cleared.
+// Specification: AV1 Bitstream & Decoding Process, 5.2
+func BitstreamUnmarshal(bs []byte, removeSizeField bool) ([][]byte, error) {
+	var ret [][]byte
+
+	for {
+		var h OBUHeader
+		err := h.Unmarshal(bs)
+		if err != nil {
+			return nil, err
+		}
+
+		if !h.HasSize {
+			return nil, fmt.Errorf("OBU size not present")
+		}
+
+		hsize := h.MarshalSize()
+
+		size, sizeN, err := LEB128Unmarshal(bs[hsize:])
+		if err != nil {
+			return nil, err
+		}
+
+		obuLen := hsize + sizeN + int(size)
+		if len(bs) < obuLen {
+			return nil, fmt.Errorf("not enough bytes")
+		}
+
+		if len(ret) >= MaxOBUsPerTemporalUnit {
+			return nil, fmt.Errorf("OBU count exceeds maximum allowed (%d)", MaxOBUsPerTemporalUnit)
+		}
+
+		var obu []byte
+		if removeSizeField {
+			obu = make([]byte, hsize+int(size))
+			copy(obu, bs[:hsize])
+			obu[0] &^= 0x02
+			copy(obu[hsize:], bs[hsize+sizeN:obuLen])
+		} else {
+			obu = bs[:obuLen]
+		}
+
+		ret = append(ret, obu)
+		bs = bs[obuLen:]
+
+		if len(bs) == 0 {
+			break
+		}
+	}
+
+	return ret, nil
+}
+
+func bitstreamMarshalSize(tu [][]byte) int {
+	n := 0
+
+	for _, obu := range tu {
+		n += len(obu)
+
+		var h OBUHeader
+		err := h.Unmarshal(obu)
+		if err == nil && !h.HasSize {
+			n += LEB128MarshalSize(uint32(len(obu) - h.MarshalSize()))
+		}
+	}
+
+	return n
+}
+
+// BitstreamMarshal encodes a temporal unit into the low overhead bitstream format.
+// The obu_size field is added to OBUs that don't have it.
+func BitstreamMarshal(tu [][]byte) ([]byte, error) {
+	buf := make([]byte, bitstreamMarshalSize(tu))
+	n := 0
+
+	for _, obu := range tu {
+		var h OBUHeader
+		err := h.Unmarshal(obu)
+		if err != nil {
+			return nil, err
+		}
+
+		if h.HasSize {
+			n += copy(buf[n:], obu)
+			continue
+		}
+
+		hsize := h.MarshalSize()
+		if len(obu) < hsize {
+			return nil, fmt.Errorf("not enough bytes")
+		}
+
+		copy(buf[n:], obu[:hsize])
+		buf[n] |= 0x02
+		n += hsize
+
+		n += LEB128MarshalTo(uint32(len(obu)-hsize), buf[n:])
+		n += copy(buf[n:], obu[hsize:])
+	}
+
+	return buf, nil
+}
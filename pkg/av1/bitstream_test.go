@@ -0,0 +1,85 @@
+package av1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesBitstream = []struct {
+	name string
+	enc  []byte
+	dec  [][]byte
+}{
+	{
+		"standard",
+		[]byte{
+			0x12, 0x00, 0x0a, 0x0a, 0x00, 0x00, 0x00, 0x2c,
+			0xcf, 0xf7, 0xff, 0x01, 0x0c, 0x30, 0x32, 0x04,
+			0x10, 0x01, 0x02, 0x03,
+		},
+		[][]byte{
+			{0x10},
+			{
+				0x08, 0x00, 0x00, 0x00, 0x2c, 0xcf, 0xf7, 0xff,
+				0x01, 0x0c, 0x30,
+			},
+			{0x30, 0x10, 0x01, 0x02, 0x03},
+		},
+	},
+}
+
+func TestBitstreamUnmarshal(t *testing.T) {
+	for _, ca := range casesBitstream {
+		t.Run(ca.name, func(t *testing.T) {
+			dec, err := BitstreamUnmarshal(ca.enc, true)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, dec)
+		})
+	}
+}
+
+func TestBitstreamUnmarshalKeepSizeField(t *testing.T) {
+	dec, err := BitstreamUnmarshal([]byte{0x12, 0x00, 0x32, 0x02, 0x10, 0x01}, false)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x12, 0x00}, {0x32, 0x02, 0x10, 0x01}}, dec)
+}
+
+func TestBitstreamUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		enc  []byte
+		err  string
+	}{
+		{
+			"empty",
+			[]byte{},
+			"not enough bytes",
+		},
+		{
+			"missing size",
+			[]byte{0x10},
+			"OBU size not present",
+		},
+		{
+			"truncated",
+			[]byte{0x32, 0x04, 0x10},
+			"not enough bytes",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			_, err := BitstreamUnmarshal(ca.enc, true)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestBitstreamMarshal(t *testing.T) {
+	for _, ca := range casesBitstream {
+		t.Run(ca.name, func(t *testing.T) {
+			enc, err := BitstreamMarshal(ca.dec)
+			require.NoError(t, err)
+			require.Equal(t, ca.enc, enc)
+		})
+	}
+}
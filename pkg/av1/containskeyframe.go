@@ -0,0 +1,58 @@
+package av1
+
+// ContainsKeyFrame checks whether a temporal unit contains a key frame
+// that starts a new coded video sequence.
+func ContainsKeyFrame(tu [][]byte) bool {
+	sequenceHeaderFound := false
+	reducedStillPictureHeader := false
+
+	for _, obu := range tu {
+		var h OBUHeader
+		err := h.Unmarshal(obu)
+		if err != nil {
+			return false
+		}
+
+		payload := obu[h.MarshalSize():]
+
+		if h.HasSize {
+			_, n, err := LEB128Unmarshal(payload)
+			if err != nil {
+				return false
+			}
+			payload = payload[n:]
+		}
+
+		switch h.Type {
+		case OBUTypeSequenceHeader:
+			if len(payload) < 1 {
+				return false
+			}
+			sequenceHeaderFound = true
+
+			// seq_profile (3 bits), still_picture (1 bit), reduced_still_picture_header (1 bit)
+			reducedStillPictureHeader = ((payload[0] >> 3) & 0x01) != 0
+
+		case OBUTypeFrame, OBUTypeFrameHeader:
+			if !sequenceHeaderFound {
+				return false
+			}
+
+			// with reduced_still_picture_header, all frames are key frames
+			if reducedStillPictureHeader {
+				return true
+			}
+
+			if len(payload) < 1 {
+				return false
+			}
+
+			// show_existing_frame (1 bit), frame_type (2 bits)
+			showExistingFrame := (payload[0] >> 7) != 0
+			frameType := (payload[0] >> 5) & 0x03
+			return !showExistingFrame && frameType == 0
+		}
+	}
+
+	return false
+}
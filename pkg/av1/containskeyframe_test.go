@@ -0,0 +1,60 @@
+package av1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainsKeyFrame(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		tu   [][]byte
+		is   bool
+	}{
+		{
+			"key frame",
+			[][]byte{
+				{0x10},
+				{0x08, 0x00, 0x00, 0x00, 0x2c, 0xcf, 0xf7, 0xff},
+				{0x30, 0x10, 0x01, 0x02, 0x03},
+			},
+			true,
+		},
+		{
+			"key frame with size fields",
+			[][]byte{
+				{0x0a, 0x03, 0x00, 0x00, 0x00},
+				{0x32, 0x02, 0x10, 0x01},
+			},
+			true,
+		},
+		{
+			"inter frame",
+			[][]byte{
+				{0x08, 0x00, 0x00, 0x00, 0x2c, 0xcf, 0xf7, 0xff},
+				{0x30, 0x30, 0x01, 0x02, 0x03},
+			},
+			false,
+		},
+		{
+			"missing sequence header",
+			[][]byte{
+				{0x30, 0x10, 0x01, 0x02, 0x03},
+			},
+			false,
+		},
+		{
+			"reduced still picture header",
+			[][]byte{
+				{0x08, 0x18},
+				{0x30, 0xff},
+			},
+			true,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			require.Equal(t, ca.is, ContainsKeyFrame(ca.tu))
+		})
+	}
+}
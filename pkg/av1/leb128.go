@@ -0,0 +1,58 @@
+package av1
+
+import (
+	"fmt"
+)
+
+// LEB128Unmarshal decodes an unsigned integer from the LEB128 format.
+// It returns the value and the number of consumed bytes.
+// Specification: AV1 Bitstream & Decoding Process, 4.10.5
+func LEB128Unmarshal(buf []byte) (uint32, int, error) {
+	v := uint64(0)
+
+	for i := 0; i < 8; i++ {
+		if len(buf) <= i {
+			return 0, 0, fmt.Errorf("not enough bytes")
+		}
+
+		v |= uint64(buf[i]&0x7f) << (i * 7)
+
+		if (buf[i] & 0x80) == 0 {
+			if v > 0xFFFFFFFF {
+				return 0, 0, fmt.Errorf("value is too big")
+			}
+			return uint32(v), i + 1, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("value is too big")
+}
+
+// LEB128MarshalSize returns the size of an unsigned integer encoded with the LEB128 format.
+func LEB128MarshalSize(v uint32) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+// LEB128MarshalTo encodes an unsigned integer with the LEB128 format.
+// It returns the number of written bytes.
+func LEB128MarshalTo(v uint32, buf []byte) int {
+	n := 0
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+
+		if v != 0 {
+			buf[n] = b | 0x80
+			n++
+		} else {
+			buf[n] = b
+			n++
+			return n
+		}
+	}
+}
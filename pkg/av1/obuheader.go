@@ -0,0 +1,53 @@
+package av1
+
+import (
+	"fmt"
+)
+
+// OBUHeader is an OBU header.
+// Specification: AV1 Bitstream & Decoding Process, 5.3.2
+type OBUHeader struct {
+	Type         OBUType
+	HasExtension bool
+	HasSize      bool
+	TemporalID   uint8
+	SpatialID    uint8
+}
+
+// Unmarshal decodes an OBUHeader.
+func (h *OBUHeader) Unmarshal(buf []byte) error {
+	if len(buf) < 1 {
+		return fmt.Errorf("not enough bytes")
+	}
+
+	forbidden := (buf[0] >> 7) != 0
+	if forbidden {
+		return fmt.Errorf("forbidden bit is set")
+	}
+
+	h.Type = OBUType((buf[0] >> 3) & 0x0F)
+	h.HasExtension = ((buf[0] >> 2) & 0x01) != 0
+	h.HasSize = ((buf[0] >> 1) & 0x01) != 0
+
+	if h.HasExtension {
+		if len(buf) < 2 {
+			return fmt.Errorf("not enough bytes")
+		}
+
+		h.TemporalID = buf[1] >> 5
+		h.SpatialID = (buf[1] >> 3) & 0x03
+	} else {
+		h.TemporalID = 0
+		h.SpatialID = 0
+	}
+
+	return nil
+}
+
+// MarshalSize returns the size of an OBUHeader.
+func (h OBUHeader) MarshalSize() int {
+	if h.HasExtension {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,18 @@
+package av1
+
+// OBUType is an OBU type.
+// Specification: AV1 Bitstream & Decoding Process, 6.2.2
+type OBUType uint8
+
+// OBU types.
+const (
+	OBUTypeSequenceHeader       OBUType = 1
+	OBUTypeTemporalDelimiter    OBUType = 2
+	OBUTypeFrameHeader          OBUType = 3
+	OBUTypeTileGroup            OBUType = 4
+	OBUTypeMetadata             OBUType = 5
+	OBUTypeFrame                OBUType = 6
+	OBUTypeRedundantFrameHeader OBUType = 7
+	OBUTypeTileList             OBUType = 8
+	OBUTypePadding              OBUType = 15
+)
@@ -10,7 +10,6 @@ import (
 )
 
 const (
-	rtspProtocol10           = "RTSP/1.0"
 	requestMaxMethodLength   = 64
 	requestMaxURLLength      = 2048
 	requestMaxProtocolLength = 64
@@ -27,7 +26,9 @@ const (
 	Options      Method = "OPTIONS"
 	Pause        Method = "PAUSE"
 	Play         Method = "PLAY"
+	PlayNotify   Method = "PLAY_NOTIFY"
 	Record       Method = "RECORD"
+	Redirect     Method = "REDIRECT"
 	Setup        Method = "SETUP"
 	SetParameter Method = "SET_PARAMETER"
 	Teardown     Method = "TEARDOWN"
@@ -41,6 +42,10 @@ type Request struct {
 	// request url
 	URL *url.URL
 
+	// RTSP version.
+	// It defaults to RTSP 1.0.
+	Version Version
+
 	// map of header values
 	Header Header
 
@@ -76,10 +81,9 @@ func (req *Request) Read(rb *bufio.Reader) error {
 	if err != nil {
 		return err
 	}
-	proto := byts[:len(byts)-1]
-
-	if string(proto) != rtspProtocol10 {
-		return fmt.Errorf("expected '%s', got %v", rtspProtocol10, proto)
+	err = req.Version.unmarshal(byts[:len(byts)-1])
+	if err != nil {
+		return err
 	}
 
 	err = readByteEqual(rb, '\n')
@@ -105,7 +109,7 @@ func (req Request) MarshalSize() int {
 	n := 0
 
 	urStr := req.URL.CloneWithoutCredentials().String()
-	n += len([]byte(string(req.Method) + " " + urStr + " " + rtspProtocol10 + "\r\n"))
+	n += len([]byte(string(req.Method) + " " + urStr + " " + req.Version.String() + "\r\n"))
 
 	if len(req.Body) != 0 {
 		req.Header["Content-Length"] = HeaderValue{strconv.FormatInt(int64(len(req.Body)), 10)}
@@ -123,7 +127,7 @@ func (req Request) MarshalTo(buf []byte) (int, error) {
 	pos := 0
 
 	urStr := req.URL.CloneWithoutCredentials().String()
-	pos += copy(buf[pos:], []byte(string(req.Method)+" "+urStr+" "+rtspProtocol10+"\r\n"))
+	pos += copy(buf[pos:], []byte(string(req.Method)+" "+urStr+" "+req.Version.String()+"\r\n"))
 
 	if len(req.Body) != 0 {
 		req.Header["Content-Length"] = HeaderValue{strconv.FormatInt(int64(len(req.Body)), 10)}
@@ -138,6 +138,24 @@ var casesRequest = []struct {
 			),
 		},
 	},
+	{
+		"play notify rtsp 2.0",
+		[]byte("PLAY_NOTIFY rtsp://example.com/media.mp4 RTSP/2.0\r\n" +
+			"CSeq: 854\r\n" +
+			"Notify-Reason: end-of-stream\r\n" +
+			"Session: uZ3ci0K+Ld-M\r\n" +
+			"\r\n"),
+		Request{
+			Method:  "PLAY_NOTIFY",
+			URL:     mustParseURL("rtsp://example.com/media.mp4"),
+			Version: Version20,
+			Header: Header{
+				"CSeq":          HeaderValue{"854"},
+				"Notify-Reason": HeaderValue{"end-of-stream"},
+				"Session":       HeaderValue{"uZ3ci0K+Ld-M"},
+			},
+		},
+	},
 }
 
 func TestRequestRead(t *testing.T) {
@@ -197,7 +215,7 @@ func TestRequestReadErrors(t *testing.T) {
 		{
 			"empty protocol",
 			[]byte("GET rtsp://testing123 \r\n"),
-			"expected 'RTSP/1.0', got []",
+			"expected 'RTSP/1.0' or 'RTSP/2.0', got []",
 		},
 		{
 			"invalid URL",
@@ -206,8 +224,8 @@ func TestRequestReadErrors(t *testing.T) {
 		},
 		{
 			"invalid protocol",
-			[]byte("GET rtsp://testing123 RTSP/2.0\r\n"),
-			"expected 'RTSP/1.0', got [82 84 83 80 47 50 46 48]",
+			[]byte("GET rtsp://testing123 RTSP/3.0\r\n"),
+			"expected 'RTSP/1.0' or 'RTSP/2.0', got [82 84 83 80 47 51 46 48]",
 		},
 		{
 			"invalid header",
@@ -125,6 +125,10 @@ type Response struct {
 	// status message
 	StatusMessage string
 
+	// RTSP version.
+	// It defaults to RTSP 1.0.
+	Version Version
+
 	// map of header values
 	Header Header
 
@@ -138,10 +142,9 @@ func (res *Response) Read(rb *bufio.Reader) error {
 	if err != nil {
 		return err
 	}
-	proto := byts[:len(byts)-1]
-
-	if string(proto) != rtspProtocol10 {
-		return fmt.Errorf("expected '%s', got %v", rtspProtocol10, proto)
+	err = res.Version.unmarshal(byts[:len(byts)-1])
+	if err != nil {
+		return err
 	}
 
 	byts, err = readBytesLimited(rb, ' ', 4)
@@ -194,7 +197,7 @@ func (res Response) MarshalSize() int {
 		}
 	}
 
-	n += len([]byte(rtspProtocol10 + " " +
+	n += len([]byte(res.Version.String() + " " +
 		strconv.FormatInt(int64(res.StatusCode), 10) + " " +
 		res.StatusMessage + "\r\n"))
 
@@ -219,7 +222,7 @@ func (res Response) MarshalTo(buf []byte) (int, error) {
 
 	pos := 0
 
-	pos += copy(buf[pos:], []byte(rtspProtocol10+" "+
+	pos += copy(buf[pos:], []byte(res.Version.String()+" "+
 		strconv.FormatInt(int64(res.StatusCode), 10)+" "+
 		res.StatusMessage+"\r\n"))
 
@@ -90,6 +90,23 @@ var casesResponse = []struct {
 			),
 		},
 	},
+	{
+		"ok rtsp 2.0",
+		[]byte("RTSP/2.0 200 OK\r\n" +
+			"CSeq: 2\r\n" +
+			"Media-Properties: No-Seeking, Time-Progressing, Time-Duration=0.0\r\n" +
+			"\r\n",
+		),
+		Response{
+			StatusCode:    200,
+			StatusMessage: "OK",
+			Version:       Version20,
+			Header: Header{
+				"CSeq":             HeaderValue{"2"},
+				"Media-Properties": HeaderValue{"No-Seeking, Time-Progressing, Time-Duration=0.0"},
+			},
+		},
+	},
 }
 
 func TestResponseRead(t *testing.T) {
@@ -138,8 +155,8 @@ func TestResponseReadErrors(t *testing.T) {
 		},
 		{
 			"invalid protocol",
-			[]byte("RTSP/2.0 200 OK\r\n"),
-			"expected 'RTSP/1.0', got [82 84 83 80 47 50 46 48]",
+			[]byte("RTSP/3.0 200 OK\r\n"),
+			"expected 'RTSP/1.0' or 'RTSP/2.0', got [82 84 83 80 47 51 46 48]",
 		},
 		{
 			"code too long",
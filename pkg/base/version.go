@@ -0,0 +1,47 @@
+package base
+
+import (
+	"fmt"
+)
+
+const (
+	rtspProtocol10 = "RTSP/1.0"
+	rtspProtocol20 = "RTSP/2.0"
+)
+
+// Version is a RTSP version.
+type Version int
+
+// versions.
+const (
+	// Version10 is RTSP 1.0.
+	// Specification: https://datatracker.ietf.org/doc/html/rfc2326
+	Version10 Version = iota
+
+	// Version20 is RTSP 2.0.
+	// Specification: https://datatracker.ietf.org/doc/html/rfc7826
+	Version20
+)
+
+// String implements fmt.Stringer.
+func (v Version) String() string {
+	if v == Version20 {
+		return rtspProtocol20
+	}
+	return rtspProtocol10
+}
+
+func (v *Version) unmarshal(byts []byte) error {
+	switch string(byts) {
+	case rtspProtocol10:
+		*v = Version10
+
+	case rtspProtocol20:
+		*v = Version20
+
+	default:
+		return fmt.Errorf("expected '%s' or '%s', got %v", rtspProtocol10, rtspProtocol20, byts)
+	}
+
+	return nil
+}
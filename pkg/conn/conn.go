@@ -10,6 +10,7 @@ import (
 
 const (
 	readBufferSize = 4096
+	responsePrefix = "RTSP/"
 )
 
 // Conn is a RTSP TCP connection.
@@ -77,6 +78,35 @@ func (c *Conn) ReadInterleavedFrameOrResponse() (interface{}, error) {
 	return c.ReadResponse()
 }
 
+// ReadRequestOrResponse reads a Request or a Response.
+func (c *Conn) ReadRequestOrResponse() (interface{}, error) {
+	byts, err := c.br.Peek(len(responsePrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	if string(byts) == responsePrefix {
+		return c.ReadResponse()
+	}
+
+	return c.ReadRequest()
+}
+
+// ReadInterleavedFrameOrRequestOrResponse reads an InterleavedFrame, a Request or a Response.
+func (c *Conn) ReadInterleavedFrameOrRequestOrResponse() (interface{}, error) {
+	b, err := c.br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	c.br.UnreadByte()
+
+	if b == base.InterleavedFrameMagicByte {
+		return c.ReadInterleavedFrame()
+	}
+
+	return c.ReadRequestOrResponse()
+}
+
 // ReadRequestIgnoreFrames reads a Request and ignores frames in between.
 func (c *Conn) ReadRequestIgnoreFrames() (*base.Request, error) {
 	for {
@@ -0,0 +1,16 @@
+package h265
+
+import (
+	"github.com/cobalt-robotics/gortsplib/pkg/h264"
+)
+
+// AnnexBUnmarshal decodes NALUs from the Annex-B stream format.
+// The format is shared with H264.
+func AnnexBUnmarshal(byts []byte) ([][]byte, error) {
+	return h264.AnnexBUnmarshal(byts)
+}
+
+// AnnexBMarshal encodes NALUs into the Annex-B stream format.
+func AnnexBMarshal(nalus [][]byte) ([]byte, error) {
+	return h264.AnnexBMarshal(nalus)
+}
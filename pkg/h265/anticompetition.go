@@ -0,0 +1,34 @@
+package h265
+
+// AntiCompetitionRemove removes the anti-competition bytes from a NALU.
+func AntiCompetitionRemove(nalu []byte) []byte {
+	// 0x00 0x00 0x03 0x00 -> 0x00 0x00 0x00
+	// 0x00 0x00 0x03 0x01 -> 0x00 0x00 0x01
+	// 0x00 0x00 0x03 0x02 -> 0x00 0x00 0x02
+	// 0x00 0x00 0x03 0x03 -> 0x00 0x00 0x03
+
+	ret := make([]byte, len(nalu))
+	n := 0
+	zeroCount := 0
+	le := len(nalu)
+
+	for i, b := range nalu {
+		// the byte that follows the anti-competition byte
+		// can be part of the next sequence too.
+		if zeroCount >= 2 && b == 3 && i+1 < le && nalu[i+1] <= 3 {
+			zeroCount = 0
+			continue
+		}
+
+		if b == 0 {
+			zeroCount++
+		} else {
+			zeroCount = 0
+		}
+
+		ret[n] = b
+		n++
+	}
+
+	return ret[:n]
+}
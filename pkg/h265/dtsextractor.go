@@ -0,0 +1,265 @@
+package h265
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+func getPOC(buf []byte, sps *SPS, pps *PPS) (uint32, bool, error) {
+	if len(buf) < 3 {
+		return 0, false, fmt.Errorf("slice segment is too short")
+	}
+
+	// the POC is always contained inside the first bytes of the slice segment header
+	if len(buf) > 32 {
+		buf = buf[:32]
+	}
+
+	buf = AntiCompetitionRemove(buf)
+
+	typ := NALUType((buf[0] >> 1) & 0b111111)
+
+	buf = buf[2:]
+	pos := 0
+
+	firstSliceSegmentInPicFlag, err := bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if typ >= NALUTypeBLAWLP && typ <= NALUTypeReservedIRAP23 {
+		// no_output_of_prior_pics_flag
+		_, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	// slice_pic_parameter_set_id
+	_, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !firstSliceSegmentInPicFlag {
+		dependentSliceSegmentFlag := false
+		if pps.DependentSliceSegmentsEnabledFlag {
+			dependentSliceSegmentFlag, err = bits.ReadFlag(buf, &pos)
+			if err != nil {
+				return 0, false, err
+			}
+		}
+
+		// slice_segment_address
+		_, err = bits.ReadBits(buf, &pos, sliceSegmentAddressLength(sps))
+		if err != nil {
+			return 0, false, err
+		}
+
+		// dependent slice segments don't contain the POC
+		if dependentSliceSegmentFlag {
+			return 0, false, nil
+		}
+	}
+
+	// slice_reserved_flag
+	_, err = bits.ReadBits(buf, &pos, int(pps.NumExtraSliceHeaderBits))
+	if err != nil {
+		return 0, false, err
+	}
+
+	// slice_type
+	_, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if pps.OutputFlagPresentFlag {
+		// pic_output_flag
+		_, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	if sps.SeparateColourPlaneFlag {
+		// colour_plane_id
+		_, err = bits.ReadBits(buf, &pos, 2)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	if typ == NALUTypeIDRWRADL || typ == NALUTypeIDRNLP {
+		return 0, true, nil
+	}
+
+	picOrderCntLsb, err := bits.ReadBits(buf, &pos, int(sps.Log2MaxPicOrderCntLsbMinus4+4))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint32(picOrderCntLsb), true, nil
+}
+
+func sliceSegmentAddressLength(sps *SPS) int {
+	minCbLog2SizeY := sps.Log2MinLumaCodingBlockSizeMinus3 + 3
+	ctbLog2SizeY := minCbLog2SizeY + sps.Log2DiffMaxMinLumaCodingBlockSize
+	ctbSizeY := uint32(1) << ctbLog2SizeY
+	picWidthInCtbsY := (sps.PicWidthInLumaSamples + ctbSizeY - 1) / ctbSizeY
+	picHeightInCtbsY := (sps.PicHeightInLumaSamples + ctbSizeY - 1) / ctbSizeY
+	picSizeInCtbsY := picWidthInCtbsY * picHeightInCtbsY
+
+	// Ceil(Log2(PicSizeInCtbsY))
+	n := 0
+	for (uint32(1) << n) < picSizeInCtbsY {
+		n++
+	}
+	return n
+}
+
+func findPOC(nalus [][]byte, sps *SPS, pps *PPS) (uint32, error) {
+	for _, nalu := range nalus {
+		typ := NALUType((nalu[0] >> 1) & 0b111111)
+		if typ <= NALUTypeRASLR || (typ >= NALUTypeBLAWLP && typ <= NALUTypeCRA) {
+			poc, ok, err := getPOC(nalu, sps, pps)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				return poc, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("POC not found")
+}
+
+func getPOCDiff(poc1 uint32, poc2 uint32, sps *SPS) int32 {
+	diff := int32(poc1) - int32(poc2)
+	switch {
+	case diff < -((1 << (sps.Log2MaxPicOrderCntLsbMinus4 + 3)) - 1):
+		diff += (1 << (sps.Log2MaxPicOrderCntLsbMinus4 + 4))
+
+	case diff > ((1 << (sps.Log2MaxPicOrderCntLsbMinus4 + 3)) - 1):
+		diff -= (1 << (sps.Log2MaxPicOrderCntLsbMinus4 + 4))
+	}
+	return diff
+}
+
+// DTSExtractor is a utility that allows to extract NALU DTS from PTS.
+// It assumes that the POC is increased by one for each frame.
+type DTSExtractor struct {
+	sps           []byte
+	spsp          *SPS
+	pps           []byte
+	ppsp          *PPS
+	frameDuration time.Duration
+	ptsDTSOffset  time.Duration
+	expectedPOC   uint32
+	prevDTS       *time.Duration
+}
+
+// NewDTSExtractor allocates a DTSExtractor.
+func NewDTSExtractor() *DTSExtractor {
+	return &DTSExtractor{}
+}
+
+func (d *DTSExtractor) extractInner(nalus [][]byte, pts time.Duration) (time.Duration, error) {
+	idrPresent := false
+
+	for _, nalu := range nalus {
+		typ := NALUType((nalu[0] >> 1) & 0b111111)
+		switch typ {
+		case NALUTypeSPS:
+			if d.sps == nil || !bytes.Equal(d.sps, nalu) {
+				var spsp SPS
+				err := spsp.Unmarshal(nalu)
+				if err != nil {
+					return 0, fmt.Errorf("invalid SPS: %v", err)
+				}
+				d.sps = append([]byte(nil), nalu...)
+				d.spsp = &spsp
+
+				maxNumReorderPics := d.spsp.MaxNumReorderPics[len(d.spsp.MaxNumReorderPics)-1]
+
+				if d.spsp.VUI != nil && d.spsp.VUI.TimingInfo != nil && d.spsp.VUI.TimingInfo.TimeScale != 0 {
+					d.frameDuration = time.Duration(d.spsp.VUI.TimingInfo.NumUnitsInTick) * time.Second /
+						time.Duration(d.spsp.VUI.TimingInfo.TimeScale)
+					d.ptsDTSOffset = time.Duration(maxNumReorderPics) * d.frameDuration
+				} else {
+					d.frameDuration = 0
+					d.ptsDTSOffset = 0
+				}
+			}
+
+		case NALUTypePPS:
+			if d.pps == nil || !bytes.Equal(d.pps, nalu) {
+				var ppsp PPS
+				err := ppsp.Unmarshal(nalu)
+				if err != nil {
+					return 0, fmt.Errorf("invalid PPS: %v", err)
+				}
+				d.pps = append([]byte(nil), nalu...)
+				d.ppsp = &ppsp
+			}
+
+		case NALUTypeIDRWRADL, NALUTypeIDRNLP:
+			idrPresent = true
+		}
+	}
+
+	if d.spsp == nil {
+		return 0, fmt.Errorf("SPS not received yet")
+	}
+
+	if d.ppsp == nil {
+		return 0, fmt.Errorf("PPS not received yet")
+	}
+
+	// we assume PTS = DTS
+	if d.ptsDTSOffset == 0 {
+		return pts, nil
+	}
+
+	// DTS is computed by using POC, timing infos and max_num_reorder_pics
+	if idrPresent {
+		d.expectedPOC = 0
+		return pts - d.ptsDTSOffset, nil
+	}
+
+	// compute expectedPOC immediately in order to store it even in case of errors
+	d.expectedPOC++
+	d.expectedPOC &= ((1 << (d.spsp.Log2MaxPicOrderCntLsbMinus4 + 4)) - 1)
+
+	poc, err := findPOC(nalus, d.spsp, d.ppsp)
+	if err != nil {
+		return 0, err
+	}
+
+	pocDiff := getPOCDiff(poc, d.expectedPOC, d.spsp)
+
+	return pts - d.ptsDTSOffset - time.Duration(pocDiff)*d.frameDuration, nil
+}
+
+// Extract extracts the DTS of a group of NALUs.
+func (d *DTSExtractor) Extract(nalus [][]byte, pts time.Duration) (time.Duration, error) {
+	dts, err := d.extractInner(nalus, pts)
+	if err != nil {
+		return 0, err
+	}
+
+	if dts > pts {
+		return 0, fmt.Errorf("DTS is greater than PTS")
+	}
+
+	if d.prevDTS != nil && dts <= *d.prevDTS {
+		return 0, fmt.Errorf("DTS is not monotonically increasing, was %v, now is %v",
+			*d.prevDTS, dts)
+	}
+
+	d.prevDTS = &dts
+	return dts, err
+}
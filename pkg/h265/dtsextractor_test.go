@@ -0,0 +1,107 @@
+package h265
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDTSExtractor(t *testing.T) {
+	type sequenceSample struct {
+		nalus [][]byte
+		pts   time.Duration
+		dts   time.Duration
+	}
+
+	sps := []byte{
+		0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+		0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+		0x00, 0x78, 0xa0, 0x03, 0xc0, 0x80, 0x10, 0xe5,
+		0x96, 0x66, 0x69, 0x24, 0xca, 0xe0, 0x10, 0x00,
+		0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x03, 0x01,
+		0xe0, 0x80,
+	}
+
+	pps := []byte{0x44, 0x01, 0xc1, 0x72, 0xb4, 0x62, 0x40}
+
+	for _, ca := range []struct {
+		name     string
+		sequence []sequenceSample
+	}{
+		{
+			"max_num_reorder_pics-based",
+			[]sequenceSample{
+				{
+					[][]byte{
+						sps,
+						pps,
+						{0x26, 0x01, 0xae, 0xaa, 0xbb}, // IDR
+					},
+					1000000000 * time.Nanosecond,
+					933333334 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xd0, 0x24, 0xaa, 0xbb}}, // POC 4
+					1133333332 * time.Nanosecond,
+					966666667 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0x50, 0xaa, 0xbb}}, // POC 2
+					1066666666 * time.Nanosecond,
+					1000000000 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0x30, 0xaa, 0xbb}}, // POC 1
+					1033333333 * time.Nanosecond,
+					1033333333 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0x70, 0xaa, 0xbb}}, // POC 3
+					1099999999 * time.Nanosecond,
+					1066666666 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xd0, 0x44, 0xaa, 0xbb}}, // POC 8
+					1266666664 * time.Nanosecond,
+					1099999999 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0xd0, 0xaa, 0xbb}}, // POC 6
+					1199999998 * time.Nanosecond,
+					1133333332 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0xb0, 0xaa, 0xbb}}, // POC 5
+					1166666665 * time.Nanosecond,
+					1166666665 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x02, 0x01, 0xe0, 0xf0, 0xaa, 0xbb}}, // POC 7
+					1233333331 * time.Nanosecond,
+					1199999998 * time.Nanosecond,
+				},
+				{
+					[][]byte{{0x26, 0x01, 0xae, 0xaa, 0xbb}}, // IDR
+					1333333330 * time.Nanosecond,
+					1266666664 * time.Nanosecond,
+				},
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			ex := NewDTSExtractor()
+			for _, sample := range ca.sequence {
+				dts, err := ex.Extract(sample.nalus, sample.pts)
+				require.NoError(t, err)
+				require.Equal(t, sample.dts, dts)
+			}
+		})
+	}
+}
+
+func TestDTSExtractorErrors(t *testing.T) {
+	ex := NewDTSExtractor()
+	_, err := ex.Extract([][]byte{{0x26, 0x01, 0xae, 0xaa, 0xbb}}, 0)
+	require.EqualError(t, err, "SPS not received yet")
+}
@@ -0,0 +1,17 @@
+package h265
+
+import (
+	"github.com/cobalt-robotics/gortsplib/pkg/h264"
+)
+
+// HVCCUnmarshal decodes NALUs from the HVCC stream format,
+// in which every NALU is prefixed by its 4-byte length.
+// The format is shared with H264 (AVCC).
+func HVCCUnmarshal(buf []byte) ([][]byte, error) {
+	return h264.AVCCUnmarshal(buf)
+}
+
+// HVCCMarshal encodes NALUs into the HVCC stream format.
+func HVCCMarshal(nalus [][]byte) ([]byte, error) {
+	return h264.AVCCMarshal(nalus)
+}
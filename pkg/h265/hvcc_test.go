@@ -0,0 +1,25 @@
+package h265
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHVCC(t *testing.T) {
+	nalus := [][]byte{
+		{0x40, 0x01, 0xaa},
+		{0x42, 0x01, 0xbb},
+	}
+
+	enc, err := HVCCMarshal(nalus)
+	require.NoError(t, err)
+	require.Equal(t, []byte{
+		0x00, 0x00, 0x00, 0x03, 0x40, 0x01, 0xaa,
+		0x00, 0x00, 0x00, 0x03, 0x42, 0x01, 0xbb,
+	}, enc)
+
+	dec, err := HVCCUnmarshal(enc)
+	require.NoError(t, err)
+	require.Equal(t, nalus, dec)
+}
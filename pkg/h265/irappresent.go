@@ -0,0 +1,12 @@
+package h265
+
+// IRAPPresent check if there's an IRAP (intra random access point) inside provided NALUs.
+func IRAPPresent(nalus [][]byte) bool {
+	for _, nalu := range nalus {
+		typ := NALUType((nalu[0] >> 1) & 0b111111)
+		if typ >= NALUTypeBLAWLP && typ <= NALUTypeReservedIRAP23 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package h265
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNALUType(t *testing.T) {
+	require.NotEqual(t, true, strings.HasPrefix(NALUType(19).String(), "unknown"))
+	require.Equal(t, true, strings.HasPrefix(NALUType(60).String(), "unknown"))
+}
+
+func TestIRAPPresent(t *testing.T) {
+	require.Equal(t, true, IRAPPresent([][]byte{{0x40, 0x01}, {0x2a, 0x01}}))  // CRA
+	require.Equal(t, true, IRAPPresent([][]byte{{0x26, 0x01}}))                // IDR_W_RADL
+	require.Equal(t, false, IRAPPresent([][]byte{{0x02, 0x01}, {0x4e, 0x01}})) // TRAIL_R, SEI
+}
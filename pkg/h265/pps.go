@@ -0,0 +1,66 @@
+package h265
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+// PPS is a H265 picture parameter set.
+// Only the fields needed to parse slice headers are decoded.
+type PPS struct {
+	ID                                uint32
+	SPSID                             uint32
+	DependentSliceSegmentsEnabledFlag bool
+	OutputFlagPresentFlag             bool
+	NumExtraSliceHeaderBits           uint8
+}
+
+// Unmarshal decodes a PPS from bytes.
+func (p *PPS) Unmarshal(buf []byte) error {
+	// ref: ITU-T Rec. H.265 (02/2018)
+
+	buf = AntiCompetitionRemove(buf)
+
+	if len(buf) < 2 {
+		return fmt.Errorf("buffer too short")
+	}
+
+	typ := NALUType((buf[0] >> 1) & 0b111111)
+
+	if typ != NALUTypePPS {
+		return fmt.Errorf("not a PPS")
+	}
+
+	buf = buf[2:]
+	pos := 0
+
+	var err error
+	p.ID, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	p.SPSID, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	p.DependentSliceSegmentsEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	p.OutputFlagPresentFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := bits.ReadBits(buf, &pos, 3)
+	if err != nil {
+		return err
+	}
+	p.NumExtraSliceHeaderBits = uint8(tmp)
+
+	return nil
+}
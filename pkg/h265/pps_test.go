@@ -0,0 +1,38 @@
+package h265
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPPSUnmarshal(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		pps  PPS
+	}{
+		{
+			"default",
+			[]byte{0x44, 0x01, 0xc1, 0x72, 0xb4, 0x62, 0x40},
+			PPS{},
+		},
+		{
+			"dependent slices and output flag",
+			[]byte{0x44, 0x01, 0x5f, 0xe0},
+			PPS{
+				ID:                                1,
+				DependentSliceSegmentsEnabledFlag: true,
+				OutputFlagPresentFlag:             true,
+				NumExtraSliceHeaderBits:           7,
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var pps PPS
+			err := pps.Unmarshal(ca.byts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pps, pps)
+		})
+	}
+}
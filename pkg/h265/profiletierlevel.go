@@ -0,0 +1,132 @@
+package h265
+
+import (
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+// ProfileTierLevel is a profile, tier and level syntax structure.
+// It is contained inside a VPS and a SPS.
+type ProfileTierLevel struct {
+	GeneralProfileSpace             uint8
+	GeneralTierFlag                 uint8
+	GeneralProfileIdc               uint8
+	GeneralProfileCompatibilityFlag [32]bool
+	GeneralProgressiveSourceFlag    bool
+	GeneralInterlacedSourceFlag     bool
+	GeneralNonPackedConstraintFlag  bool
+	GeneralFrameOnlyConstraintFlag  bool
+	GeneralLevelIdc                 uint8
+	SubLayerProfilePresentFlag      []bool
+	SubLayerLevelPresentFlag        []bool
+}
+
+func (p *ProfileTierLevel) unmarshal(buf []byte, pos *int, maxSubLayersMinus1 uint8) error {
+	tmp, err := bits.ReadBits(buf, pos, 2)
+	if err != nil {
+		return err
+	}
+	p.GeneralProfileSpace = uint8(tmp)
+
+	tmp, err = bits.ReadBits(buf, pos, 1)
+	if err != nil {
+		return err
+	}
+	p.GeneralTierFlag = uint8(tmp)
+
+	tmp, err = bits.ReadBits(buf, pos, 5)
+	if err != nil {
+		return err
+	}
+	p.GeneralProfileIdc = uint8(tmp)
+
+	for j := 0; j < 32; j++ {
+		p.GeneralProfileCompatibilityFlag[j], err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.GeneralProgressiveSourceFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	p.GeneralInterlacedSourceFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	p.GeneralNonPackedConstraintFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	p.GeneralFrameOnlyConstraintFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	// general_reserved_zero_43bits + general_inbld_flag
+	_, err = bits.ReadBits(buf, pos, 44)
+	if err != nil {
+		return err
+	}
+
+	p.GeneralLevelIdc, err = bits.ReadUint8(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if maxSubLayersMinus1 > 0 {
+		p.SubLayerProfilePresentFlag = make([]bool, maxSubLayersMinus1)
+		p.SubLayerLevelPresentFlag = make([]bool, maxSubLayersMinus1)
+	} else {
+		p.SubLayerProfilePresentFlag = nil
+		p.SubLayerLevelPresentFlag = nil
+	}
+
+	for j := uint8(0); j < maxSubLayersMinus1; j++ {
+		p.SubLayerProfilePresentFlag[j], err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		p.SubLayerLevelPresentFlag[j], err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	if maxSubLayersMinus1 > 0 {
+		// reserved_zero_2bits
+		_, err := bits.ReadBits(buf, pos, int(8-maxSubLayersMinus1)*2)
+		if err != nil {
+			return err
+		}
+	}
+
+	for j := uint8(0); j < maxSubLayersMinus1; j++ {
+		if p.SubLayerProfilePresentFlag[j] {
+			// sub-layer profile informations (88 bits)
+			_, err := bits.ReadBits(buf, pos, 44)
+			if err != nil {
+				return err
+			}
+
+			_, err = bits.ReadBits(buf, pos, 44)
+			if err != nil {
+				return err
+			}
+		}
+
+		if p.SubLayerLevelPresentFlag[j] {
+			// sub_layer_level_idc
+			_, err := bits.ReadBits(buf, pos, 8)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
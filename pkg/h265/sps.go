@@ -0,0 +1,1114 @@
+package h265
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+const (
+	maxShortTermRefPicSets = 64
+	maxLongTermRefPics     = 32
+	maxNegativePics        = 16
+	maxPositivePics        = 16
+)
+
+func skipScalingListData(buf []byte, pos *int) error {
+	for sizeID := 0; sizeID < 4; sizeID++ {
+		step := 1
+		if sizeID == 3 {
+			step = 3
+		}
+
+		for matrixID := 0; matrixID < 6; matrixID += step {
+			scalingListPredModeFlag, err := bits.ReadFlag(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			if !scalingListPredModeFlag {
+				// scaling_list_pred_matrix_id_delta
+				_, err := bits.ReadGolombUnsigned(buf, pos)
+				if err != nil {
+					return err
+				}
+			} else {
+				coefNum := 1 << (4 + (sizeID << 1))
+				if coefNum > 64 {
+					coefNum = 64
+				}
+
+				if sizeID > 1 {
+					// scaling_list_dc_coef_minus8
+					_, err := bits.ReadGolombSigned(buf, pos)
+					if err != nil {
+						return err
+					}
+				}
+
+				for i := 0; i < coefNum; i++ {
+					// scaling_list_delta_coef
+					_, err := bits.ReadGolombSigned(buf, pos)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// SPS_ConformanceWindow is a conformance window of a SPS.
+type SPS_ConformanceWindow struct { //nolint:revive
+	LeftOffset   uint32
+	RightOffset  uint32
+	TopOffset    uint32
+	BottomOffset uint32
+}
+
+func (c *SPS_ConformanceWindow) unmarshal(buf []byte, pos *int) error {
+	var err error
+	c.LeftOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	c.RightOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	c.TopOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	c.BottomOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SPS_PCM contains PCM parameters of a SPS.
+type SPS_PCM struct { //nolint:revive
+	SampleBitDepthLumaMinus1             uint8
+	SampleBitDepthChromaMinus1           uint8
+	Log2MinPCMLumaCodingBlockSizeMinus3  uint32
+	Log2DiffMaxMinPCMLumaCodingBlockSize uint32
+	LoopFilterDisabledFlag               bool
+}
+
+func (p *SPS_PCM) unmarshal(buf []byte, pos *int) error {
+	tmp, err := bits.ReadBits(buf, pos, 4)
+	if err != nil {
+		return err
+	}
+	p.SampleBitDepthLumaMinus1 = uint8(tmp)
+
+	tmp, err = bits.ReadBits(buf, pos, 4)
+	if err != nil {
+		return err
+	}
+	p.SampleBitDepthChromaMinus1 = uint8(tmp)
+
+	p.Log2MinPCMLumaCodingBlockSizeMinus3, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	p.Log2DiffMaxMinPCMLumaCodingBlockSize, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	p.LoopFilterDisabledFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SPS_ShortTermRefPicSet is a short-term reference picture set.
+type SPS_ShortTermRefPicSet struct { //nolint:revive
+	InterRefPicSetPredictionFlag bool
+
+	// InterRefPicSetPredictionFlag == true
+	DeltaRPSSign      bool
+	AbsDeltaRPSMinus1 uint32
+	UsedByCurrPicFlag []bool
+	UseDeltaFlag      []bool
+
+	// InterRefPicSetPredictionFlag == false
+	NumNegativePics     uint32
+	NumPositivePics     uint32
+	DeltaPocS0Minus1    []uint32
+	UsedByCurrPicS0Flag []bool
+	DeltaPocS1Minus1    []uint32
+	UsedByCurrPicS1Flag []bool
+}
+
+// numDeltaPocs returns the number of delta POCs of the set.
+func (r *SPS_ShortTermRefPicSet) numDeltaPocs() uint32 {
+	if r.InterRefPicSetPredictionFlag {
+		n := uint32(0)
+		for _, v := range r.UseDeltaFlag {
+			if v {
+				n++
+			}
+		}
+		return n
+	}
+
+	return r.NumNegativePics + r.NumPositivePics
+}
+
+func (r *SPS_ShortTermRefPicSet) unmarshal(buf []byte, pos *int, stRpsIdx uint32, prev []*SPS_ShortTermRefPicSet) error {
+	if stRpsIdx != 0 {
+		var err error
+		r.InterRefPicSetPredictionFlag, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.InterRefPicSetPredictionFlag {
+		// delta_idx_minus1 is present in slice headers only,
+		// therefore the reference set is always the previous one.
+		ref := prev[stRpsIdx-1]
+
+		var err error
+		r.DeltaRPSSign, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		r.AbsDeltaRPSMinus1, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		n := ref.numDeltaPocs() + 1
+		r.UsedByCurrPicFlag = make([]bool, n)
+		r.UseDeltaFlag = make([]bool, n)
+
+		for j := uint32(0); j < n; j++ {
+			r.UsedByCurrPicFlag[j], err = bits.ReadFlag(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			if !r.UsedByCurrPicFlag[j] {
+				r.UseDeltaFlag[j], err = bits.ReadFlag(buf, pos)
+				if err != nil {
+					return err
+				}
+			} else {
+				r.UseDeltaFlag[j] = true
+			}
+		}
+
+		return nil
+	}
+
+	var err error
+	r.NumNegativePics, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if r.NumNegativePics > maxNegativePics {
+		return fmt.Errorf("num_negative_pics exceeds %d", maxNegativePics)
+	}
+
+	r.NumPositivePics, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if r.NumPositivePics > maxPositivePics {
+		return fmt.Errorf("num_positive_pics exceeds %d", maxPositivePics)
+	}
+
+	r.DeltaPocS0Minus1 = make([]uint32, r.NumNegativePics)
+	r.UsedByCurrPicS0Flag = make([]bool, r.NumNegativePics)
+
+	for i := uint32(0); i < r.NumNegativePics; i++ {
+		r.DeltaPocS0Minus1[i], err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		r.UsedByCurrPicS0Flag[i], err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.DeltaPocS1Minus1 = make([]uint32, r.NumPositivePics)
+	r.UsedByCurrPicS1Flag = make([]bool, r.NumPositivePics)
+
+	for i := uint32(0); i < r.NumPositivePics; i++ {
+		r.DeltaPocS1Minus1[i], err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		r.UsedByCurrPicS1Flag[i], err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SPS_DefaultDisplayWindow is a default display window.
+type SPS_DefaultDisplayWindow struct { //nolint:revive
+	LeftOffset   uint32
+	RightOffset  uint32
+	TopOffset    uint32
+	BottomOffset uint32
+}
+
+func (w *SPS_DefaultDisplayWindow) unmarshal(buf []byte, pos *int) error {
+	var err error
+	w.LeftOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	w.RightOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	w.TopOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	w.BottomOffset, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SPS_TimingInfo is a timing info.
+type SPS_TimingInfo struct { //nolint:revive
+	NumUnitsInTick              uint32
+	TimeScale                   uint32
+	POCProportionalToTimingFlag bool
+
+	// POCProportionalToTimingFlag == true
+	NumTicksPOCDiffOneMinus1 uint32
+}
+
+func (t *SPS_TimingInfo) unmarshal(buf []byte, pos *int) error {
+	var err error
+	t.NumUnitsInTick, err = bits.ReadUint32(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	t.TimeScale, err = bits.ReadUint32(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	t.POCProportionalToTimingFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if t.POCProportionalToTimingFlag {
+		t.NumTicksPOCDiffOneMinus1, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func skipSubLayerHRDParameters(buf []byte, pos *int, cpbCnt uint32, subPicHRDParamsPresentFlag bool) error {
+	for i := uint32(0); i < cpbCnt; i++ {
+		// bit_rate_value_minus1
+		_, err := bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		// cpb_size_value_minus1
+		_, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		if subPicHRDParamsPresentFlag {
+			// cpb_size_du_value_minus1
+			_, err = bits.ReadGolombUnsigned(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			// bit_rate_du_value_minus1
+			_, err = bits.ReadGolombUnsigned(buf, pos)
+			if err != nil {
+				return err
+			}
+		}
+
+		// cbr_flag
+		_, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func skipHRDParameters(buf []byte, pos *int, maxSubLayersMinus1 uint8) error {
+	// commonInfPresentFlag is always true in VUI
+	nalHRDParametersPresentFlag, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	vclHRDParametersPresentFlag, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	subPicHRDParamsPresentFlag := false
+
+	if nalHRDParametersPresentFlag || vclHRDParametersPresentFlag {
+		subPicHRDParamsPresentFlag, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		if subPicHRDParamsPresentFlag {
+			// tick_divisor_minus2, du_cpb_removal_delay_increment_length_minus1,
+			// sub_pic_cpb_params_in_pic_timing_sei_flag, dpb_output_delay_du_length_minus1
+			_, err = bits.ReadBits(buf, pos, 8+5+1+5)
+			if err != nil {
+				return err
+			}
+		}
+
+		// bit_rate_scale, cpb_size_scale
+		_, err = bits.ReadBits(buf, pos, 4+4)
+		if err != nil {
+			return err
+		}
+
+		if subPicHRDParamsPresentFlag {
+			// cpb_size_du_scale
+			_, err = bits.ReadBits(buf, pos, 4)
+			if err != nil {
+				return err
+			}
+		}
+
+		// initial_cpb_removal_delay_length_minus1, au_cpb_removal_delay_length_minus1,
+		// dpb_output_delay_length_minus1
+		_, err = bits.ReadBits(buf, pos, 5+5+5)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := uint8(0); i <= maxSubLayersMinus1; i++ {
+		fixedPicRateGeneralFlag, err := bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		fixedPicRateWithinCvsFlag := true
+		if !fixedPicRateGeneralFlag {
+			fixedPicRateWithinCvsFlag, err = bits.ReadFlag(buf, pos)
+			if err != nil {
+				return err
+			}
+		}
+
+		lowDelayHRDFlag := false
+		if fixedPicRateWithinCvsFlag {
+			// elemental_duration_in_tc_minus1
+			_, err = bits.ReadGolombUnsigned(buf, pos)
+			if err != nil {
+				return err
+			}
+		} else {
+			lowDelayHRDFlag, err = bits.ReadFlag(buf, pos)
+			if err != nil {
+				return err
+			}
+		}
+
+		cpbCntMinus1 := uint32(0)
+		if !lowDelayHRDFlag {
+			cpbCntMinus1, err = bits.ReadGolombUnsigned(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			if cpbCntMinus1 > 31 {
+				return fmt.Errorf("invalid cpb_cnt_minus1")
+			}
+		}
+
+		if nalHRDParametersPresentFlag {
+			err = skipSubLayerHRDParameters(buf, pos, cpbCntMinus1+1, subPicHRDParamsPresentFlag)
+			if err != nil {
+				return err
+			}
+		}
+
+		if vclHRDParametersPresentFlag {
+			err = skipSubLayerHRDParameters(buf, pos, cpbCntMinus1+1, subPicHRDParamsPresentFlag)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SPS_BitstreamRestriction are bitstream restriction infos.
+type SPS_BitstreamRestriction struct { //nolint:revive
+	TilesFixedStructureFlag            bool
+	MotionVectorsOverPicBoundariesFlag bool
+	RestrictedRefPicListsFlag          bool
+	MinSpatialSegmentationIdc          uint32
+	MaxBytesPerPicDenom                uint32
+	MaxBitsPerMinCuDenom               uint32
+	Log2MaxMvLengthHorizontal          uint32
+	Log2MaxMvLengthVertical            uint32
+}
+
+func (r *SPS_BitstreamRestriction) unmarshal(buf []byte, pos *int) error {
+	var err error
+	r.TilesFixedStructureFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.MotionVectorsOverPicBoundariesFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.RestrictedRefPicListsFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.MinSpatialSegmentationIdc, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.MaxBytesPerPicDenom, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.MaxBitsPerMinCuDenom, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.Log2MaxMvLengthHorizontal, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	r.Log2MaxMvLengthVertical, err = bits.ReadGolombUnsigned(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SPS_VUI is a video usability information.
+type SPS_VUI struct { //nolint:revive
+	AspectRatioInfoPresentFlag bool
+	AspectRatioIdc             uint8
+	SarWidth                   uint16
+	SarHeight                  uint16
+	OverscanInfoPresentFlag    bool
+	OverscanAppropriateFlag    bool
+	VideoSignalTypePresentFlag bool
+
+	// VideoSignalTypePresentFlag == true
+	VideoFormat                  uint8
+	VideoFullRangeFlag           bool
+	ColourDescriptionPresentFlag bool
+
+	// ColourDescriptionPresentFlag == true
+	ColourPrimaries         uint8
+	TransferCharacteristics uint8
+	MatrixCoefficients      uint8
+
+	ChromaLocInfoPresentFlag bool
+
+	// ChromaLocInfoPresentFlag == true
+	ChromaSampleLocTypeTopField    uint32
+	ChromaSampleLocTypeBottomField uint32
+
+	NeutralChromaIndicationFlag bool
+	FieldSeqFlag                bool
+	FrameFieldInfoPresentFlag   bool
+
+	// defaultDisplayWindowFlag == true
+	DefaultDisplayWindow *SPS_DefaultDisplayWindow
+
+	// timingInfoPresentFlag == true
+	TimingInfo *SPS_TimingInfo
+
+	// bitstreamRestrictionFlag == true
+	BitstreamRestriction *SPS_BitstreamRestriction
+}
+
+func (v *SPS_VUI) unmarshal(buf []byte, pos *int, maxSubLayersMinus1 uint8) error {
+	var err error
+	v.AspectRatioInfoPresentFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if v.AspectRatioInfoPresentFlag {
+		v.AspectRatioIdc, err = bits.ReadUint8(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		if v.AspectRatioIdc == 255 { // EXTENDED_SAR
+			v.SarWidth, err = bits.ReadUint16(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			v.SarHeight, err = bits.ReadUint16(buf, pos)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	v.OverscanInfoPresentFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if v.OverscanInfoPresentFlag {
+		v.OverscanAppropriateFlag, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	v.VideoSignalTypePresentFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if v.VideoSignalTypePresentFlag {
+		tmp, err := bits.ReadBits(buf, pos, 3)
+		if err != nil {
+			return err
+		}
+		v.VideoFormat = uint8(tmp)
+
+		v.VideoFullRangeFlag, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		v.ColourDescriptionPresentFlag, err = bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		if v.ColourDescriptionPresentFlag {
+			v.ColourPrimaries, err = bits.ReadUint8(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			v.TransferCharacteristics, err = bits.ReadUint8(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			v.MatrixCoefficients, err = bits.ReadUint8(buf, pos)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	v.ChromaLocInfoPresentFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if v.ChromaLocInfoPresentFlag {
+		v.ChromaSampleLocTypeTopField, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		v.ChromaSampleLocTypeBottomField, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	v.NeutralChromaIndicationFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	v.FieldSeqFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	v.FrameFieldInfoPresentFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	defaultDisplayWindowFlag, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if defaultDisplayWindowFlag {
+		v.DefaultDisplayWindow = &SPS_DefaultDisplayWindow{}
+		err := v.DefaultDisplayWindow.unmarshal(buf, pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		v.DefaultDisplayWindow = nil
+	}
+
+	timingInfoPresentFlag, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if timingInfoPresentFlag {
+		v.TimingInfo = &SPS_TimingInfo{}
+		err := v.TimingInfo.unmarshal(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		hrdParametersPresentFlag, err := bits.ReadFlag(buf, pos)
+		if err != nil {
+			return err
+		}
+
+		if hrdParametersPresentFlag {
+			err := skipHRDParameters(buf, pos, maxSubLayersMinus1)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		v.TimingInfo = nil
+	}
+
+	bitstreamRestrictionFlag, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if bitstreamRestrictionFlag {
+		v.BitstreamRestriction = &SPS_BitstreamRestriction{}
+		err := v.BitstreamRestriction.unmarshal(buf, pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		v.BitstreamRestriction = nil
+	}
+
+	return nil
+}
+
+// SPS is a H265 sequence parameter set.
+type SPS struct {
+	VPSID                                uint8
+	MaxSubLayersMinus1                   uint8
+	TemporalIDNestingFlag                bool
+	ProfileTierLevel                     ProfileTierLevel
+	ID                                   uint32
+	ChromaFormatIdc                      uint32
+	SeparateColourPlaneFlag              bool
+	PicWidthInLumaSamples                uint32
+	PicHeightInLumaSamples               uint32
+	ConformanceWindow                    *SPS_ConformanceWindow
+	BitDepthLumaMinus8                   uint32
+	BitDepthChromaMinus8                 uint32
+	Log2MaxPicOrderCntLsbMinus4          uint32
+	SubLayerOrderingInfoPresentFlag      bool
+	MaxDecPicBufferingMinus1             []uint32
+	MaxNumReorderPics                    []uint32
+	MaxLatencyIncreasePlus1              []uint32
+	Log2MinLumaCodingBlockSizeMinus3     uint32
+	Log2DiffMaxMinLumaCodingBlockSize    uint32
+	Log2MinLumaTransformBlockSizeMinus2  uint32
+	Log2DiffMaxMinLumaTransformBlockSize uint32
+	MaxTransformHierarchyDepthInter      uint32
+	MaxTransformHierarchyDepthIntra      uint32
+	ScalingListEnabledFlag               bool
+	ScalingListDataPresentFlag           bool
+	AmpEnabledFlag                       bool
+	SampleAdaptiveOffsetEnabledFlag      bool
+
+	// pcmEnabledFlag == true
+	PCM *SPS_PCM
+
+	ShortTermRefPicSets        []*SPS_ShortTermRefPicSet
+	LongTermRefPicsPresentFlag bool
+
+	// LongTermRefPicsPresentFlag == true
+	LtRefPicPocLsbSps      []uint32
+	UsedByCurrPicLtSpsFlag []bool
+
+	TemporalMvpEnabledFlag          bool
+	StrongIntraSmoothingEnabledFlag bool
+
+	// vuiParametersPresentFlag == true
+	VUI *SPS_VUI
+}
+
+// Unmarshal decodes a SPS from bytes.
+func (s *SPS) Unmarshal(buf []byte) error {
+	// ref: ITU-T Rec. H.265 (02/2018)
+
+	buf = AntiCompetitionRemove(buf)
+
+	if len(buf) < 2 {
+		return fmt.Errorf("buffer too short")
+	}
+
+	typ := NALUType((buf[0] >> 1) & 0b111111)
+
+	if typ != NALUTypeSPS {
+		return fmt.Errorf("not a SPS")
+	}
+
+	buf = buf[2:]
+	pos := 0
+
+	tmp, err := bits.ReadBits(buf, &pos, 4)
+	if err != nil {
+		return err
+	}
+	s.VPSID = uint8(tmp)
+
+	tmp, err = bits.ReadBits(buf, &pos, 3)
+	if err != nil {
+		return err
+	}
+	s.MaxSubLayersMinus1 = uint8(tmp)
+
+	s.TemporalIDNestingFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	err = s.ProfileTierLevel.unmarshal(buf, &pos, s.MaxSubLayersMinus1)
+	if err != nil {
+		return err
+	}
+
+	s.ID, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.ChromaFormatIdc, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if s.ChromaFormatIdc == 3 {
+		s.SeparateColourPlaneFlag, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.SeparateColourPlaneFlag = false
+	}
+
+	s.PicWidthInLumaSamples, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.PicHeightInLumaSamples, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	conformanceWindowFlag, err := bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if conformanceWindowFlag {
+		s.ConformanceWindow = &SPS_ConformanceWindow{}
+		err := s.ConformanceWindow.unmarshal(buf, &pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.ConformanceWindow = nil
+	}
+
+	s.BitDepthLumaMinus8, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.BitDepthChromaMinus8, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.Log2MaxPicOrderCntLsbMinus4, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if s.Log2MaxPicOrderCntLsbMinus4 > 12 {
+		return fmt.Errorf("invalid log2_max_pic_order_cnt_lsb_minus4")
+	}
+
+	s.SubLayerOrderingInfoPresentFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.MaxDecPicBufferingMinus1, s.MaxNumReorderPics, s.MaxLatencyIncreasePlus1, err = readSubLayerOrderingInfo(
+		buf, &pos, s.SubLayerOrderingInfoPresentFlag, s.MaxSubLayersMinus1)
+	if err != nil {
+		return err
+	}
+
+	s.Log2MinLumaCodingBlockSizeMinus3, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.Log2DiffMaxMinLumaCodingBlockSize, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.Log2MinLumaTransformBlockSizeMinus2, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.Log2DiffMaxMinLumaTransformBlockSize, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.MaxTransformHierarchyDepthInter, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.MaxTransformHierarchyDepthIntra, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.ScalingListEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if s.ScalingListEnabledFlag {
+		s.ScalingListDataPresentFlag, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return err
+		}
+
+		if s.ScalingListDataPresentFlag {
+			err := skipScalingListData(buf, &pos)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		s.ScalingListDataPresentFlag = false
+	}
+
+	s.AmpEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.SampleAdaptiveOffsetEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	pcmEnabledFlag, err := bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if pcmEnabledFlag {
+		s.PCM = &SPS_PCM{}
+		err := s.PCM.unmarshal(buf, &pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.PCM = nil
+	}
+
+	numShortTermRefPicSets, err := bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if numShortTermRefPicSets > maxShortTermRefPicSets {
+		return fmt.Errorf("num_short_term_ref_pic_sets exceeds %d", maxShortTermRefPicSets)
+	}
+
+	s.ShortTermRefPicSets = nil
+
+	for i := uint32(0); i < numShortTermRefPicSets; i++ {
+		rps := &SPS_ShortTermRefPicSet{}
+		err := rps.unmarshal(buf, &pos, i, s.ShortTermRefPicSets)
+		if err != nil {
+			return err
+		}
+		s.ShortTermRefPicSets = append(s.ShortTermRefPicSets, rps)
+	}
+
+	s.LongTermRefPicsPresentFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.LtRefPicPocLsbSps = nil
+	s.UsedByCurrPicLtSpsFlag = nil
+
+	if s.LongTermRefPicsPresentFlag {
+		numLongTermRefPicsSPS, err := bits.ReadGolombUnsigned(buf, &pos)
+		if err != nil {
+			return err
+		}
+
+		if numLongTermRefPicsSPS > maxLongTermRefPics {
+			return fmt.Errorf("num_long_term_ref_pics_sps exceeds %d", maxLongTermRefPics)
+		}
+
+		s.LtRefPicPocLsbSps = make([]uint32, numLongTermRefPicsSPS)
+		s.UsedByCurrPicLtSpsFlag = make([]bool, numLongTermRefPicsSPS)
+
+		for i := uint32(0); i < numLongTermRefPicsSPS; i++ {
+			tmp, err := bits.ReadBits(buf, &pos, int(s.Log2MaxPicOrderCntLsbMinus4+4))
+			if err != nil {
+				return err
+			}
+			s.LtRefPicPocLsbSps[i] = uint32(tmp)
+
+			s.UsedByCurrPicLtSpsFlag[i], err = bits.ReadFlag(buf, &pos)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.TemporalMvpEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	s.StrongIntraSmoothingEnabledFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	vuiParametersPresentFlag, err := bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if vuiParametersPresentFlag {
+		s.VUI = &SPS_VUI{}
+		err := s.VUI.unmarshal(buf, &pos, s.MaxSubLayersMinus1)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.VUI = nil
+	}
+
+	return nil
+}
+
+// Width returns the video width.
+func (s SPS) Width() int {
+	width := s.PicWidthInLumaSamples
+
+	if s.ConformanceWindow != nil {
+		subWidthC := uint32(1)
+		if s.ChromaFormatIdc == 1 || s.ChromaFormatIdc == 2 {
+			subWidthC = 2
+		}
+
+		width -= (s.ConformanceWindow.LeftOffset + s.ConformanceWindow.RightOffset) * subWidthC
+	}
+
+	return int(width)
+}
+
+// Height returns the video height.
+func (s SPS) Height() int {
+	height := s.PicHeightInLumaSamples
+
+	if s.ConformanceWindow != nil {
+		subHeightC := uint32(1)
+		if s.ChromaFormatIdc == 1 {
+			subHeightC = 2
+		}
+
+		height -= (s.ConformanceWindow.TopOffset + s.ConformanceWindow.BottomOffset) * subHeightC
+	}
+
+	return int(height)
+}
+
+// FPS returns the frames per second of the video.
+func (s SPS) FPS() float64 {
+	if s.VUI == nil || s.VUI.TimingInfo == nil {
+		return 0
+	}
+
+	return float64(s.VUI.TimingInfo.TimeScale) / float64(s.VUI.TimingInfo.NumUnitsInTick)
+}
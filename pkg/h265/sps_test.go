@@ -0,0 +1,134 @@
+package h265
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSPSUnmarshal(t *testing.T) {
+	for _, ca := range []struct {
+		name   string
+		byts   []byte
+		sps    SPS
+		width  int
+		height int
+		fps    float64
+	}{
+		{
+			"1920x1080",
+			[]byte{
+				0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+				0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+				0x00, 0x78, 0xa0, 0x03, 0xc0, 0x80, 0x10, 0xe5,
+				0x96, 0x66, 0x69, 0x24, 0xca, 0xe0, 0x10, 0x00,
+				0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x03, 0x01,
+				0xe0, 0x80,
+			},
+			SPS{
+				TemporalIDNestingFlag: true,
+				ProfileTierLevel: ProfileTierLevel{
+					GeneralProfileIdc: 1,
+					GeneralProfileCompatibilityFlag: [32]bool{
+						false, true, true, false, false, false, false, false,
+						false, false, false, false, false, false, false, false,
+						false, false, false, false, false, false, false, false,
+						false, false, false, false, false, false, false, false,
+					},
+					GeneralProgressiveSourceFlag:   true,
+					GeneralFrameOnlyConstraintFlag: true,
+					GeneralLevelIdc:                120,
+				},
+				ChromaFormatIdc:                      1,
+				PicWidthInLumaSamples:                1920,
+				PicHeightInLumaSamples:               1080,
+				Log2MaxPicOrderCntLsbMinus4:          4,
+				SubLayerOrderingInfoPresentFlag:      true,
+				MaxDecPicBufferingMinus1:             []uint32{5},
+				MaxNumReorderPics:                    []uint32{2},
+				MaxLatencyIncreasePlus1:              []uint32{5},
+				Log2DiffMaxMinLumaCodingBlockSize:    3,
+				Log2DiffMaxMinLumaTransformBlockSize: 3,
+				SampleAdaptiveOffsetEnabledFlag:      true,
+				TemporalMvpEnabledFlag:               true,
+				StrongIntraSmoothingEnabledFlag:      true,
+				VUI: &SPS_VUI{
+					TimingInfo: &SPS_TimingInfo{
+						NumUnitsInTick: 1,
+						TimeScale:      30,
+					},
+				},
+			},
+			1920,
+			1080,
+			30,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var sps SPS
+			err := sps.Unmarshal(ca.byts)
+			require.NoError(t, err)
+			require.Equal(t, ca.sps, sps)
+			require.Equal(t, ca.width, sps.Width())
+			require.Equal(t, ca.height, sps.Height())
+			require.Equal(t, ca.fps, sps.FPS())
+		})
+	}
+}
+
+func TestSPSWidthHeightConformanceWindow(t *testing.T) {
+	sps := SPS{
+		ChromaFormatIdc:        1,
+		PicWidthInLumaSamples:  1920,
+		PicHeightInLumaSamples: 1088,
+		ConformanceWindow: &SPS_ConformanceWindow{
+			BottomOffset: 4,
+		},
+	}
+	require.Equal(t, 1920, sps.Width())
+	require.Equal(t, 1080, sps.Height())
+}
+
+func TestSPSUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"too short",
+			[]byte{0x42},
+			"buffer too short",
+		},
+		{
+			"not a SPS",
+			[]byte{0x40, 0x01, 0x0c},
+			"not a SPS",
+		},
+		{
+			"truncated",
+			[]byte{0x42, 0x01, 0x01, 0x01, 0x60},
+			"not enough bits",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var sps SPS
+			err := sps.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func BenchmarkSPSUnmarshal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var sps SPS
+		sps.Unmarshal([]byte{
+			0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+			0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+			0x00, 0x78, 0xa0, 0x03, 0xc0, 0x80, 0x10, 0xe5,
+			0x96, 0x66, 0x69, 0x24, 0xca, 0xe0, 0x10, 0x00,
+			0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x03, 0x01,
+			0xe0, 0x80,
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package h265
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+// VPS_TimingInfo is a timing info.
+type VPS_TimingInfo struct { //nolint:revive
+	NumUnitsInTick              uint32
+	TimeScale                   uint32
+	POCProportionalToTimingFlag bool
+
+	// POCProportionalToTimingFlag == true
+	NumTicksPOCDiffOneMinus1 uint32
+}
+
+func (t *VPS_TimingInfo) unmarshal(buf []byte, pos *int) error {
+	var err error
+	t.NumUnitsInTick, err = bits.ReadUint32(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	t.TimeScale, err = bits.ReadUint32(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	t.POCProportionalToTimingFlag, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if t.POCProportionalToTimingFlag {
+		t.NumTicksPOCDiffOneMinus1, err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VPS is a H265 video parameter set.
+type VPS struct {
+	ID                              uint8
+	BaseLayerInternalFlag           bool
+	BaseLayerAvailableFlag          bool
+	MaxLayersMinus1                 uint8
+	MaxSubLayersMinus1              uint8
+	TemporalIDNestingFlag           bool
+	ProfileTierLevel                ProfileTierLevel
+	SubLayerOrderingInfoPresentFlag bool
+	MaxDecPicBufferingMinus1        []uint32
+	MaxNumReorderPics               []uint32
+	MaxLatencyIncreasePlus1         []uint32
+	MaxLayerID                      uint8
+	NumLayerSetsMinus1              uint32
+	LayerIDIncludedFlag             [][]bool
+
+	// timingInfoPresentFlag == true
+	TimingInfo *VPS_TimingInfo
+}
+
+// Unmarshal decodes a VPS from bytes.
+func (v *VPS) Unmarshal(buf []byte) error {
+	// ref: ITU-T Rec. H.265 (02/2018)
+
+	buf = AntiCompetitionRemove(buf)
+
+	if len(buf) < 2 {
+		return fmt.Errorf("buffer too short")
+	}
+
+	typ := NALUType((buf[0] >> 1) & 0b111111)
+
+	if typ != NALUTypeVPS {
+		return fmt.Errorf("not a VPS")
+	}
+
+	buf = buf[2:]
+	pos := 0
+
+	tmp, err := bits.ReadBits(buf, &pos, 4)
+	if err != nil {
+		return err
+	}
+	v.ID = uint8(tmp)
+
+	v.BaseLayerInternalFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	v.BaseLayerAvailableFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	tmp, err = bits.ReadBits(buf, &pos, 6)
+	if err != nil {
+		return err
+	}
+	v.MaxLayersMinus1 = uint8(tmp)
+
+	tmp, err = bits.ReadBits(buf, &pos, 3)
+	if err != nil {
+		return err
+	}
+	v.MaxSubLayersMinus1 = uint8(tmp)
+
+	v.TemporalIDNestingFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	// vps_reserved_0xffff_16bits
+	_, err = bits.ReadBits(buf, &pos, 16)
+	if err != nil {
+		return err
+	}
+
+	err = v.ProfileTierLevel.unmarshal(buf, &pos, v.MaxSubLayersMinus1)
+	if err != nil {
+		return err
+	}
+
+	v.SubLayerOrderingInfoPresentFlag, err = bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	v.MaxDecPicBufferingMinus1, v.MaxNumReorderPics, v.MaxLatencyIncreasePlus1, err = readSubLayerOrderingInfo(
+		buf, &pos, v.SubLayerOrderingInfoPresentFlag, v.MaxSubLayersMinus1)
+	if err != nil {
+		return err
+	}
+
+	tmp, err = bits.ReadBits(buf, &pos, 6)
+	if err != nil {
+		return err
+	}
+	v.MaxLayerID = uint8(tmp)
+
+	v.NumLayerSetsMinus1, err = bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if v.NumLayerSetsMinus1 > 1023 {
+		return fmt.Errorf("invalid vps_num_layer_sets_minus1")
+	}
+
+	v.LayerIDIncludedFlag = nil
+
+	for i := uint32(1); i <= v.NumLayerSetsMinus1; i++ {
+		flags := make([]bool, v.MaxLayerID+1)
+
+		for j := uint8(0); j <= v.MaxLayerID; j++ {
+			flags[j], err = bits.ReadFlag(buf, &pos)
+			if err != nil {
+				return err
+			}
+		}
+
+		v.LayerIDIncludedFlag = append(v.LayerIDIncludedFlag, flags)
+	}
+
+	timingInfoPresentFlag, err := bits.ReadFlag(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if timingInfoPresentFlag {
+		v.TimingInfo = &VPS_TimingInfo{}
+		err := v.TimingInfo.unmarshal(buf, &pos)
+		if err != nil {
+			return err
+		}
+	} else {
+		v.TimingInfo = nil
+	}
+
+	return nil
+}
+
+func readSubLayerOrderingInfo(
+	buf []byte,
+	pos *int,
+	presentFlag bool,
+	maxSubLayersMinus1 uint8,
+) ([]uint32, []uint32, []uint32, error) {
+	start := uint8(0)
+	if !presentFlag {
+		start = maxSubLayersMinus1
+	}
+
+	n := maxSubLayersMinus1 - start + 1
+	maxDecPicBufferingMinus1 := make([]uint32, n)
+	maxNumReorderPics := make([]uint32, n)
+	maxLatencyIncreasePlus1 := make([]uint32, n)
+
+	for i := uint8(0); i < n; i++ {
+		var err error
+		maxDecPicBufferingMinus1[i], err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		maxNumReorderPics[i], err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		maxLatencyIncreasePlus1[i], err = bits.ReadGolombUnsigned(buf, pos)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return maxDecPicBufferingMinus1, maxNumReorderPics, maxLatencyIncreasePlus1, nil
+}
@@ -0,0 +1,46 @@
+package h265
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVPSUnmarshal(t *testing.T) {
+	byts := []byte{
+		0x40, 0x01, 0x0c, 0x01, 0xff, 0xff, 0x01, 0x60,
+		0x00, 0x00, 0x03, 0x00, 0x90, 0x00, 0x00, 0x03,
+		0x00, 0x00, 0x03, 0x00, 0x78, 0x99, 0x98, 0x09,
+	}
+
+	var vps VPS
+	err := vps.Unmarshal(byts)
+	require.NoError(t, err)
+	require.Equal(t, VPS{
+		BaseLayerInternalFlag:  true,
+		BaseLayerAvailableFlag: true,
+		TemporalIDNestingFlag:  true,
+		ProfileTierLevel: ProfileTierLevel{
+			GeneralProfileIdc: 1,
+			GeneralProfileCompatibilityFlag: [32]bool{
+				false, true, true, false, false, false, false, false,
+				false, false, false, false, false, false, false, false,
+				false, false, false, false, false, false, false, false,
+				false, false, false, false, false, false, false, false,
+			},
+			GeneralProgressiveSourceFlag:   true,
+			GeneralFrameOnlyConstraintFlag: true,
+			GeneralLevelIdc:                120,
+		},
+		SubLayerOrderingInfoPresentFlag: true,
+		MaxDecPicBufferingMinus1:        []uint32{5},
+		MaxNumReorderPics:               []uint32{2},
+		MaxLatencyIncreasePlus1:         []uint32{5},
+	}, vps)
+}
+
+func TestVPSUnmarshalErrors(t *testing.T) {
+	var vps VPS
+	err := vps.Unmarshal([]byte{0x42, 0x01, 0x01})
+	require.EqualError(t, err, "not a VPS")
+}
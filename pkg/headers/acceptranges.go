@@ -0,0 +1,43 @@
+package headers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// AcceptRanges is an Accept-Ranges header.
+// It contains the range formats supported by the sender, like "npt", "clock" or "smpte".
+// Specification: https://datatracker.ietf.org/doc/html/rfc7826#section-18.5
+type AcceptRanges []string
+
+// Unmarshal decodes an Accept-Ranges header.
+func (h *AcceptRanges) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	*h = nil
+
+	for _, part := range strings.Split(v[0], ",") {
+		part = strings.Trim(part, " ")
+
+		if part == "" {
+			return fmt.Errorf("invalid value (%v)", v[0])
+		}
+
+		*h = append(*h, part)
+	}
+
+	return nil
+}
+
+// Marshal encodes an Accept-Ranges header.
+func (h AcceptRanges) Marshal() base.HeaderValue {
+	return base.HeaderValue{strings.Join(h, ", ")}
+}
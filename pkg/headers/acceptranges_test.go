@@ -0,0 +1,79 @@
+package headers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesAcceptRanges = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    AcceptRanges
+}{
+	{
+		"single",
+		base.HeaderValue{`npt`},
+		base.HeaderValue{`npt`},
+		AcceptRanges{"npt"},
+	},
+	{
+		"multiple",
+		base.HeaderValue{`npt,clock, smpte`},
+		base.HeaderValue{`npt, clock, smpte`},
+		AcceptRanges{"npt", "clock", "smpte"},
+	},
+}
+
+func TestAcceptRangesUnmarshal(t *testing.T) {
+	for _, ca := range casesAcceptRanges {
+		t.Run(ca.name, func(t *testing.T) {
+			var h AcceptRanges
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestAcceptRangesUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"empty format",
+			base.HeaderValue{"npt,,clock"},
+			"invalid value (npt,,clock)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h AcceptRanges
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestAcceptRangesMarshal(t *testing.T) {
+	for _, ca := range casesAcceptRanges {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
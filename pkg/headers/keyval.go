@@ -25,6 +25,12 @@ func readValue(origstr string, str string, separator byte) (string, string, erro
 			}
 
 			if str[i] == '"' {
+				// a quoted value followed by other characters (i.e. a list of
+				// quoted values) is returned as is
+				if (i+1) < len(str) && str[i+1] != separator {
+					return readRawValue(origstr, str, separator)
+				}
+
 				return str[1:i], str[i+1:], nil
 			}
 
@@ -41,6 +47,31 @@ func readValue(origstr string, str string, separator byte) (string, string, erro
 	}
 }
 
+func readRawValue(origstr string, str string, separator byte) (string, string, error) {
+	inQuotes := false
+	i := 0
+	for {
+		if i >= len(str) {
+			if inQuotes {
+				return "", "", fmt.Errorf("apexes not closed (%v)", origstr)
+			}
+			return str, "", nil
+		}
+
+		switch str[i] {
+		case '"':
+			inQuotes = !inQuotes
+
+		case separator:
+			if !inQuotes {
+				return str[:i], str[i:], nil
+			}
+		}
+
+		i++
+	}
+}
+
 func keyValParse(str string, separator byte) (map[string]string, error) {
 	ret := make(map[string]string)
 	origstr := str
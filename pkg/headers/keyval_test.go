@@ -52,6 +52,14 @@ func TestKeyValParse(t *testing.T) {
 				"key2": "v2",
 			},
 		},
+		{
+			"with list of apexes",
+			`key1="v1"/"v2",key2=v3`,
+			map[string]string{
+				"key1": `"v1"/"v2"`,
+				"key2": "v3",
+			},
+		},
 		{
 			"no val key1",
 			`key1, key2="v2"`,
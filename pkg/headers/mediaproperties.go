@@ -0,0 +1,267 @@
+package headers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// MediaPropertiesSeeking is the seeking capability of a media.
+type MediaPropertiesSeeking int
+
+// seeking capabilities.
+const (
+	MediaPropertiesSeekingRandomAccess MediaPropertiesSeeking = iota
+	MediaPropertiesSeekingBeginningOnly
+	MediaPropertiesSeekingNoSeeking
+)
+
+// MediaPropertiesContent describes how the content of a media changes over time.
+type MediaPropertiesContent int
+
+// content modifications.
+const (
+	MediaPropertiesContentImmutable MediaPropertiesContent = iota
+	MediaPropertiesContentDynamic
+	MediaPropertiesContentTimeProgressing
+)
+
+// MediaPropertiesRetention describes how long the content of a media is available.
+type MediaPropertiesRetention int
+
+// retentions.
+const (
+	MediaPropertiesRetentionUnlimited MediaPropertiesRetention = iota
+	MediaPropertiesRetentionTimeLimited
+	MediaPropertiesRetentionTimeDuration
+)
+
+// MediaProperties is a Media-Properties header.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7826#section-18.29
+type MediaProperties struct {
+	// (optional) seeking capability
+	Seeking *MediaPropertiesSeeking
+
+	// (optional) maximum interval between random access points.
+	// It is used only with MediaPropertiesSeekingRandomAccess.
+	RandomAccessMaxInterval *time.Duration
+
+	// (optional) content modifications
+	Content *MediaPropertiesContent
+
+	// (optional) retention
+	Retention *MediaPropertiesRetention
+
+	// (optional) time after which the content is not available anymore.
+	// It is used only with MediaPropertiesRetentionTimeLimited.
+	TimeLimit *RangeUTCTime
+
+	// (optional) duration of the content availability window.
+	// It is used only with MediaPropertiesRetentionTimeDuration.
+	TimeDuration *time.Duration
+
+	// (optional) supported scales.
+	// Each entry is either a value or a range in the format "min:max".
+	Scales []string
+}
+
+func parseDeltaSeconds(v string) (time.Duration, error) {
+	tmp, err := strconv.ParseFloat(v, 64)
+	if err != nil || tmp < 0 {
+		return 0, fmt.Errorf("invalid value (%v)", v)
+	}
+	return time.Duration(tmp * float64(time.Second)), nil
+}
+
+func marshalDeltaSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// splitQuoted splits a string by a separator, ignoring separators that are
+// inside quotes.
+func splitQuoted(str string, sep byte) []string {
+	var ret []string
+	inQuotes := false
+	start := 0
+
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '"':
+			inQuotes = !inQuotes
+
+		case sep:
+			if !inQuotes {
+				ret = append(ret, str[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(ret, str[start:])
+}
+
+// Unmarshal decodes a Media-Properties header.
+func (h *MediaProperties) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	for _, part := range splitQuoted(v[0], ',') {
+		part = strings.Trim(part, " ")
+
+		if part == "" {
+			continue
+		}
+
+		var key string
+		var val string
+		hasVal := false
+
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			key = strings.TrimRight(part[:i], " ")
+			val = strings.TrimLeft(part[i+1:], " ")
+			hasVal = true
+		} else {
+			key = part
+		}
+
+		switch key {
+		case "Random-Access":
+			v := MediaPropertiesSeekingRandomAccess
+			h.Seeking = &v
+
+			if hasVal {
+				d, err := parseDeltaSeconds(val)
+				if err != nil {
+					return fmt.Errorf("invalid Random-Access (%v)", val)
+				}
+				h.RandomAccessMaxInterval = &d
+			}
+
+		case "Beginning-Only":
+			v := MediaPropertiesSeekingBeginningOnly
+			h.Seeking = &v
+
+		case "No-Seeking":
+			v := MediaPropertiesSeekingNoSeeking
+			h.Seeking = &v
+
+		case "Immutable":
+			v := MediaPropertiesContentImmutable
+			h.Content = &v
+
+		case "Dynamic":
+			v := MediaPropertiesContentDynamic
+			h.Content = &v
+
+		case "Time-Progressing":
+			v := MediaPropertiesContentTimeProgressing
+			h.Content = &v
+
+		case "Unlimited":
+			v := MediaPropertiesRetentionUnlimited
+			h.Retention = &v
+
+		case "Time-Limited":
+			var t RangeUTCTime
+			err := t.unmarshal(val)
+			if err != nil {
+				return fmt.Errorf("invalid Time-Limited (%v)", val)
+			}
+
+			v := MediaPropertiesRetentionTimeLimited
+			h.Retention = &v
+			h.TimeLimit = &t
+
+		case "Time-Duration":
+			d, err := parseDeltaSeconds(val)
+			if err != nil {
+				return fmt.Errorf("invalid Time-Duration (%v)", val)
+			}
+
+			v := MediaPropertiesRetentionTimeDuration
+			h.Retention = &v
+			h.TimeDuration = &d
+
+		case "Scales":
+			if len(val) < 2 || val[0] != '"' || val[len(val)-1] != '"' {
+				return fmt.Errorf("invalid Scales (%v)", val)
+			}
+
+			h.Scales = nil
+			for _, s := range strings.Split(val[1:len(val)-1], ",") {
+				h.Scales = append(h.Scales, strings.Trim(s, " "))
+			}
+
+		default:
+			// ignore non-standard properties
+		}
+	}
+
+	return nil
+}
+
+// Marshal encodes a Media-Properties header.
+func (h MediaProperties) Marshal() base.HeaderValue {
+	var rets []string
+
+	if h.Seeking != nil {
+		switch *h.Seeking {
+		case MediaPropertiesSeekingRandomAccess:
+			if h.RandomAccessMaxInterval != nil {
+				rets = append(rets, "Random-Access="+marshalDeltaSeconds(*h.RandomAccessMaxInterval))
+			} else {
+				rets = append(rets, "Random-Access")
+			}
+
+		case MediaPropertiesSeekingBeginningOnly:
+			rets = append(rets, "Beginning-Only")
+
+		default:
+			rets = append(rets, "No-Seeking")
+		}
+	}
+
+	if h.Content != nil {
+		switch *h.Content {
+		case MediaPropertiesContentImmutable:
+			rets = append(rets, "Immutable")
+
+		case MediaPropertiesContentDynamic:
+			rets = append(rets, "Dynamic")
+
+		default:
+			rets = append(rets, "Time-Progressing")
+		}
+	}
+
+	if h.Retention != nil {
+		switch *h.Retention {
+		case MediaPropertiesRetentionUnlimited:
+			rets = append(rets, "Unlimited")
+
+		case MediaPropertiesRetentionTimeLimited:
+			if h.TimeLimit != nil {
+				rets = append(rets, "Time-Limited="+h.TimeLimit.marshal())
+			}
+
+		default:
+			if h.TimeDuration != nil {
+				rets = append(rets, "Time-Duration="+marshalDeltaSeconds(*h.TimeDuration))
+			}
+		}
+	}
+
+	if h.Scales != nil {
+		rets = append(rets, "Scales=\""+strings.Join(h.Scales, ", ")+"\"")
+	}
+
+	return base.HeaderValue{strings.Join(rets, ", ")}
+}
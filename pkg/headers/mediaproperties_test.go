@@ -0,0 +1,153 @@
+package headers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesMediaProperties = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    MediaProperties
+}{
+	{
+		"on demand",
+		base.HeaderValue{`Random-Access=2.5, Unlimited, Immutable, Scales="-20, -10, -4, 0.5:1.5, 4, 8, 10, 15, 20"`},
+		base.HeaderValue{`Random-Access=2.5, Immutable, Unlimited, Scales="-20, -10, -4, 0.5:1.5, 4, 8, 10, 15, 20"`},
+		MediaProperties{
+			Seeking: func() *MediaPropertiesSeeking {
+				v := MediaPropertiesSeekingRandomAccess
+				return &v
+			}(),
+			RandomAccessMaxInterval: func() *time.Duration {
+				v := 2500 * time.Millisecond
+				return &v
+			}(),
+			Content: func() *MediaPropertiesContent {
+				v := MediaPropertiesContentImmutable
+				return &v
+			}(),
+			Retention: func() *MediaPropertiesRetention {
+				v := MediaPropertiesRetentionUnlimited
+				return &v
+			}(),
+			Scales: []string{"-20", "-10", "-4", "0.5:1.5", "4", "8", "10", "15", "20"},
+		},
+	},
+	{
+		"live",
+		base.HeaderValue{`No-Seeking, Time-Progressing, Time-Duration=0.0`},
+		base.HeaderValue{`No-Seeking, Time-Progressing, Time-Duration=0`},
+		MediaProperties{
+			Seeking: func() *MediaPropertiesSeeking {
+				v := MediaPropertiesSeekingNoSeeking
+				return &v
+			}(),
+			Content: func() *MediaPropertiesContent {
+				v := MediaPropertiesContentTimeProgressing
+				return &v
+			}(),
+			Retention: func() *MediaPropertiesRetention {
+				v := MediaPropertiesRetentionTimeDuration
+				return &v
+			}(),
+			TimeDuration: func() *time.Duration {
+				v := time.Duration(0)
+				return &v
+			}(),
+		},
+	},
+	{
+		"live with recording",
+		base.HeaderValue{`Beginning-Only, Dynamic, Time-Limited=20081128T165000Z`},
+		base.HeaderValue{`Beginning-Only, Dynamic, Time-Limited=20081128T165000Z`},
+		MediaProperties{
+			Seeking: func() *MediaPropertiesSeeking {
+				v := MediaPropertiesSeekingBeginningOnly
+				return &v
+			}(),
+			Content: func() *MediaPropertiesContent {
+				v := MediaPropertiesContentDynamic
+				return &v
+			}(),
+			Retention: func() *MediaPropertiesRetention {
+				v := MediaPropertiesRetentionTimeLimited
+				return &v
+			}(),
+			TimeLimit: func() *RangeUTCTime {
+				v := RangeUTCTime(time.Date(2008, 11, 28, 16, 50, 0, 0, time.UTC))
+				return &v
+			}(),
+		},
+	},
+}
+
+func TestMediaPropertiesUnmarshal(t *testing.T) {
+	for _, ca := range casesMediaProperties {
+		t.Run(ca.name, func(t *testing.T) {
+			var h MediaProperties
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestMediaPropertiesUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"invalid random access",
+			base.HeaderValue{`Random-Access=aa`},
+			"invalid Random-Access (aa)",
+		},
+		{
+			"invalid time limited",
+			base.HeaderValue{`Time-Limited=aa`},
+			"invalid Time-Limited (aa)",
+		},
+		{
+			"invalid time duration",
+			base.HeaderValue{`Time-Duration=-1`},
+			"invalid Time-Duration (-1)",
+		},
+		{
+			"invalid scales",
+			base.HeaderValue{`Scales=1`},
+			"invalid Scales (1)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h MediaProperties
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestMediaPropertiesMarshal(t *testing.T) {
+	for _, ca := range casesMediaProperties {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package headers
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// NotifyReason is a Notify-Reason header.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7826#section-18.32
+type NotifyReason int
+
+// notify reasons.
+const (
+	NotifyReasonEndOfStream NotifyReason = iota
+	NotifyReasonMediaPropertiesUpdate
+	NotifyReasonScaleChange
+)
+
+var notifyReasonValues = map[NotifyReason]string{
+	NotifyReasonEndOfStream:           "end-of-stream",
+	NotifyReasonMediaPropertiesUpdate: "media-properties-update",
+	NotifyReasonScaleChange:           "scale-change",
+}
+
+// Unmarshal decodes a Notify-Reason header.
+func (h *NotifyReason) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	for k, s := range notifyReasonValues {
+		if s == v[0] {
+			*h = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid notify reason (%v)", v[0])
+}
+
+// Marshal encodes a Notify-Reason header.
+func (h NotifyReason) Marshal() base.HeaderValue {
+	return base.HeaderValue{notifyReasonValues[h]}
+}
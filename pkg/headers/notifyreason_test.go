@@ -0,0 +1,85 @@
+package headers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesNotifyReason = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    NotifyReason
+}{
+	{
+		"end of stream",
+		base.HeaderValue{`end-of-stream`},
+		base.HeaderValue{`end-of-stream`},
+		NotifyReasonEndOfStream,
+	},
+	{
+		"media properties update",
+		base.HeaderValue{`media-properties-update`},
+		base.HeaderValue{`media-properties-update`},
+		NotifyReasonMediaPropertiesUpdate,
+	},
+	{
+		"scale change",
+		base.HeaderValue{`scale-change`},
+		base.HeaderValue{`scale-change`},
+		NotifyReasonScaleChange,
+	},
+}
+
+func TestNotifyReasonUnmarshal(t *testing.T) {
+	for _, ca := range casesNotifyReason {
+		t.Run(ca.name, func(t *testing.T) {
+			var h NotifyReason
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestNotifyReasonUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"invalid value",
+			base.HeaderValue{"aa"},
+			"invalid notify reason (aa)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h NotifyReason
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestNotifyReasonMarshal(t *testing.T) {
+	for _, ca := range casesNotifyReason {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
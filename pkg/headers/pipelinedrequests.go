@@ -0,0 +1,38 @@
+package headers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// PipelinedRequests is a Pipelined-Requests header.
+// It contains an identifier that allows to group requests that are sent
+// before the session is established.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7826#section-18.33
+type PipelinedRequests uint32
+
+// Unmarshal decodes a Pipelined-Requests header.
+func (h *PipelinedRequests) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	tmp, err := strconv.ParseUint(v[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid value (%v)", v[0])
+	}
+
+	*h = PipelinedRequests(tmp)
+	return nil
+}
+
+// Marshal encodes a Pipelined-Requests header.
+func (h PipelinedRequests) Marshal() base.HeaderValue {
+	return base.HeaderValue{strconv.FormatUint(uint64(h), 10)}
+}
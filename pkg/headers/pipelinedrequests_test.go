@@ -0,0 +1,73 @@
+package headers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesPipelinedRequests = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    PipelinedRequests
+}{
+	{
+		"base",
+		base.HeaderValue{`7654`},
+		base.HeaderValue{`7654`},
+		PipelinedRequests(7654),
+	},
+}
+
+func TestPipelinedRequestsUnmarshal(t *testing.T) {
+	for _, ca := range casesPipelinedRequests {
+		t.Run(ca.name, func(t *testing.T) {
+			var h PipelinedRequests
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestPipelinedRequestsUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"invalid value",
+			base.HeaderValue{"aa"},
+			"invalid value (aa)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h PipelinedRequests
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestPipelinedRequestsMarshal(t *testing.T) {
+	for _, ca := range casesPipelinedRequests {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package headers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// Scale is a Scale header.
+// It contains the rate at which the stream is played, with respect to the normal rate.
+// Values greater than 1 mean fast forward, values between 0 and 1 mean slow motion,
+// negative values mean reverse playback.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2326#section-12.34
+type Scale float64
+
+// Unmarshal decodes a Scale header.
+func (h *Scale) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	tmp, err := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+	if err != nil || tmp == 0 {
+		return fmt.Errorf("invalid scale (%v)", v[0])
+	}
+
+	*h = Scale(tmp)
+	return nil
+}
+
+// Marshal encodes a Scale header.
+func (h Scale) Marshal() base.HeaderValue {
+	return base.HeaderValue{strconv.FormatFloat(float64(h), 'f', -1, 64)}
+}
@@ -0,0 +1,58 @@
+package headers
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// SeekStyle is a Seek-Style header.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7826#section-18.47
+type SeekStyle int
+
+// seek styles.
+const (
+	// SeekStyleRAP seeks to the closest random access point before the requested position.
+	SeekStyleRAP SeekStyle = iota
+
+	// SeekStyleCoRAP seeks to the closest random access point, before or after the requested position.
+	SeekStyleCoRAP
+
+	// SeekStyleFirstPrior seeks to the first unit that precedes the requested position.
+	SeekStyleFirstPrior
+
+	// SeekStyleNext seeks to the first unit that follows the requested position.
+	SeekStyleNext
+)
+
+var seekStyleValues = map[SeekStyle]string{
+	SeekStyleRAP:        "RAP",
+	SeekStyleCoRAP:      "CoRAP",
+	SeekStyleFirstPrior: "First-Prior",
+	SeekStyleNext:       "Next",
+}
+
+// Unmarshal decodes a Seek-Style header.
+func (h *SeekStyle) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	for k, s := range seekStyleValues {
+		if s == v[0] {
+			*h = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid seek style (%v)", v[0])
+}
+
+// Marshal encodes a Seek-Style header.
+func (h SeekStyle) Marshal() base.HeaderValue {
+	return base.HeaderValue{seekStyleValues[h]}
+}
@@ -0,0 +1,91 @@
+package headers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesSeekStyle = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    SeekStyle
+}{
+	{
+		"rap",
+		base.HeaderValue{`RAP`},
+		base.HeaderValue{`RAP`},
+		SeekStyleRAP,
+	},
+	{
+		"corap",
+		base.HeaderValue{`CoRAP`},
+		base.HeaderValue{`CoRAP`},
+		SeekStyleCoRAP,
+	},
+	{
+		"first prior",
+		base.HeaderValue{`First-Prior`},
+		base.HeaderValue{`First-Prior`},
+		SeekStyleFirstPrior,
+	},
+	{
+		"next",
+		base.HeaderValue{`Next`},
+		base.HeaderValue{`Next`},
+		SeekStyleNext,
+	},
+}
+
+func TestSeekStyleUnmarshal(t *testing.T) {
+	for _, ca := range casesSeekStyle {
+		t.Run(ca.name, func(t *testing.T) {
+			var h SeekStyle
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestSeekStyleUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"invalid value",
+			base.HeaderValue{"aa"},
+			"invalid seek style (aa)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h SeekStyle
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestSeekStyleMarshal(t *testing.T) {
+	for _, ca := range casesSeekStyle {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package headers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+// Speed is a Speed header.
+// It contains the rate at which the stream is delivered, with respect to the normal rate.
+// Unlike Scale, it doesn't change the rate at which the stream is played.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2326#section-12.35
+type Speed float64
+
+// Unmarshal decodes a Speed header.
+func (h *Speed) Unmarshal(v base.HeaderValue) error {
+	if len(v) == 0 {
+		return fmt.Errorf("value not provided")
+	}
+
+	if len(v) > 1 {
+		return fmt.Errorf("value provided multiple times (%v)", v)
+	}
+
+	tmp, err := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+	if err != nil || tmp <= 0 {
+		return fmt.Errorf("invalid speed (%v)", v[0])
+	}
+
+	*h = Speed(tmp)
+	return nil
+}
+
+// Marshal encodes a Speed header.
+func (h Speed) Marshal() base.HeaderValue {
+	return base.HeaderValue{strconv.FormatFloat(float64(h), 'f', -1, 64)}
+}
@@ -0,0 +1,95 @@
+package headers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+)
+
+var casesSpeed = []struct {
+	name string
+	vin  base.HeaderValue
+	vout base.HeaderValue
+	h    Speed
+}{
+	{
+		"normal",
+		base.HeaderValue{`1`},
+		base.HeaderValue{`1`},
+		1,
+	},
+	{
+		"fast",
+		base.HeaderValue{`2.0`},
+		base.HeaderValue{`2`},
+		2,
+	},
+	{
+		"slow",
+		base.HeaderValue{`0.5`},
+		base.HeaderValue{`0.5`},
+		0.5,
+	},
+}
+
+func TestSpeedUnmarshal(t *testing.T) {
+	for _, ca := range casesSpeed {
+		t.Run(ca.name, func(t *testing.T) {
+			var h Speed
+			err := h.Unmarshal(ca.vin)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestSpeedUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   base.HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			base.HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			base.HeaderValue{"a", "b"},
+			"value provided multiple times ([a b])",
+		},
+		{
+			"invalid value",
+			base.HeaderValue{"aa"},
+			"invalid speed (aa)",
+		},
+		{
+			"zero",
+			base.HeaderValue{"0"},
+			"invalid speed (0)",
+		},
+		{
+			"negative",
+			base.HeaderValue{"-1"},
+			"invalid speed (-1)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h Speed
+			err := h.Unmarshal(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestSpeedMarshal(t *testing.T) {
+	for _, ca := range casesSpeed {
+		t.Run(ca.name, func(t *testing.T) {
+			req := ca.h.Marshal()
+			require.Equal(t, ca.vout, req)
+		})
+	}
+}
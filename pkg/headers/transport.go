@@ -19,6 +19,51 @@ const (
 	TransportProtocolTCP
 )
 
+// TransportProfile is a transport profile.
+type TransportProfile int
+
+// transport profiles.
+const (
+	// TransportProfileAVP is the standard RTP profile (RTP/AVP).
+	TransportProfileAVP TransportProfile = iota
+
+	// TransportProfileSAVP is the secure RTP profile (RTP/SAVP), in which
+	// packets are encrypted with SRTP and SRTCP.
+	TransportProfileSAVP
+
+	// TransportProfileAVPF is the RTP profile with RTCP-based feedback (RTP/AVPF),
+	// in which receivers can request retransmissions and key frames.
+	TransportProfileAVPF
+
+	// TransportProfileSAVPF is the secure RTP profile with RTCP-based feedback (RTP/SAVPF).
+	TransportProfileSAVPF
+)
+
+var transportProfileLabels = map[TransportProfile]string{
+	TransportProfileAVP:   "RTP/AVP",
+	TransportProfileSAVP:  "RTP/SAVP",
+	TransportProfileAVPF:  "RTP/AVPF",
+	TransportProfileSAVPF: "RTP/SAVPF",
+}
+
+// String implements fmt.Stringer.
+func (p TransportProfile) String() string {
+	if l, ok := transportProfileLabels[p]; ok {
+		return l
+	}
+	return "unknown"
+}
+
+// IsSecure returns whether packets are encrypted with SRTP and SRTCP.
+func (p TransportProfile) IsSecure() bool {
+	return p == TransportProfileSAVP || p == TransportProfileSAVPF
+}
+
+// HasFeedback returns whether RTCP-based feedback is enabled.
+func (p TransportProfile) HasFeedback() bool {
+	return p == TransportProfileAVPF || p == TransportProfileSAVPF
+}
+
 // TransportDelivery is a delivery method.
 type TransportDelivery int
 
@@ -39,11 +84,87 @@ const (
 	TransportModeRecord
 )
 
+// TransportAddress is an address contained in the dest_addr or src_addr
+// parameters of a RTSP 2.0 Transport header.
+type TransportAddress struct {
+	// (optional) IP. When not provided, the address of the sender must be used.
+	IP net.IP
+
+	// port
+	Port int
+}
+
+func (a *TransportAddress) unmarshal(str string) error {
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return fmt.Errorf("invalid address (%v)", str)
+	}
+	str = str[1 : len(str)-1]
+
+	host, port, err := net.SplitHostPort(str)
+	if err != nil {
+		return fmt.Errorf("invalid address (%v)", str)
+	}
+
+	tmp, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid address (%v)", str)
+	}
+	a.Port = int(tmp)
+
+	if host != "" {
+		a.IP = net.ParseIP(host)
+		if a.IP == nil {
+			return fmt.Errorf("invalid address (%v)", str)
+		}
+	}
+
+	return nil
+}
+
+func (a TransportAddress) marshal() string {
+	host := ""
+	if a.IP != nil {
+		host = a.IP.String()
+	}
+	return "\"" + net.JoinHostPort(host, strconv.FormatInt(int64(a.Port), 10)) + "\""
+}
+
+func parseAddresses(val string) ([]TransportAddress, error) {
+	// when the list contains a single address, apexes have already been removed
+	if !strings.HasPrefix(val, "\"") {
+		val = "\"" + val + "\""
+	}
+
+	var ret []TransportAddress
+
+	for _, part := range strings.Split(val, "/") {
+		var addr TransportAddress
+		err := addr.unmarshal(part)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, addr)
+	}
+
+	return ret, nil
+}
+
+func marshalAddresses(addrs []TransportAddress) string {
+	tmp := make([]string, len(addrs))
+	for i, addr := range addrs {
+		tmp[i] = addr.marshal()
+	}
+	return strings.Join(tmp, "/")
+}
+
 // Transport is a Transport header.
 type Transport struct {
 	// protocol of the stream
 	Protocol TransportProtocol
 
+	// profile of the stream
+	Profile TransportProfile
+
 	// (optional) delivery method of the stream
 	Delivery *TransportDelivery
 
@@ -73,6 +194,12 @@ type Transport struct {
 
 	// (optional) mode
 	Mode *TransportMode
+
+	// (optional) destination addresses (RTSP 2.0)
+	DestinationAddresses []TransportAddress
+
+	// (optional) source addresses (RTSP 2.0)
+	SourceAddresses []TransportAddress
 }
 
 func parsePorts(val string) (*[2]int, error) {
@@ -128,10 +255,42 @@ func (h *Transport) Unmarshal(v base.HeaderValue) error {
 		switch k {
 		case "RTP/AVP", "RTP/AVP/UDP":
 			h.Protocol = TransportProtocolUDP
+			h.Profile = TransportProfileAVP
 			protocolFound = true
 
 		case "RTP/AVP/TCP":
 			h.Protocol = TransportProtocolTCP
+			h.Profile = TransportProfileAVP
+			protocolFound = true
+
+		case "RTP/SAVP", "RTP/SAVP/UDP":
+			h.Protocol = TransportProtocolUDP
+			h.Profile = TransportProfileSAVP
+			protocolFound = true
+
+		case "RTP/SAVP/TCP":
+			h.Protocol = TransportProtocolTCP
+			h.Profile = TransportProfileSAVP
+			protocolFound = true
+
+		case "RTP/AVPF", "RTP/AVPF/UDP":
+			h.Protocol = TransportProtocolUDP
+			h.Profile = TransportProfileAVPF
+			protocolFound = true
+
+		case "RTP/AVPF/TCP":
+			h.Protocol = TransportProtocolTCP
+			h.Profile = TransportProfileAVPF
+			protocolFound = true
+
+		case "RTP/SAVPF", "RTP/SAVPF/UDP":
+			h.Protocol = TransportProtocolUDP
+			h.Profile = TransportProfileSAVPF
+			protocolFound = true
+
+		case "RTP/SAVPF/TCP":
+			h.Protocol = TransportProtocolTCP
+			h.Profile = TransportProfileSAVPF
 			protocolFound = true
 
 		case "unicast":
@@ -217,6 +376,20 @@ func (h *Transport) Unmarshal(v base.HeaderValue) error {
 				h.SSRC = &v
 			}
 
+		case "dest_addr":
+			addrs, err := parseAddresses(v)
+			if err != nil {
+				return err
+			}
+			h.DestinationAddresses = addrs
+
+		case "src_addr":
+			addrs, err := parseAddresses(v)
+			if err != nil {
+				return err
+			}
+			h.SourceAddresses = addrs
+
 		case "mode":
 			str := strings.ToLower(v)
 			str = strings.TrimPrefix(str, "\"")
@@ -253,10 +426,12 @@ func (h *Transport) Unmarshal(v base.HeaderValue) error {
 func (h Transport) Marshal() base.HeaderValue {
 	var rets []string
 
+	profile := h.Profile.String()
+
 	if h.Protocol == TransportProtocolUDP {
-		rets = append(rets, "RTP/AVP")
+		rets = append(rets, profile)
 	} else {
-		rets = append(rets, "RTP/AVP/TCP")
+		rets = append(rets, profile+"/TCP")
 	}
 
 	if h.Delivery != nil {
@@ -316,5 +491,13 @@ func (h Transport) Marshal() base.HeaderValue {
 		}
 	}
 
+	if h.DestinationAddresses != nil {
+		rets = append(rets, "dest_addr="+marshalAddresses(h.DestinationAddresses))
+	}
+
+	if h.SourceAddresses != nil {
+		rets = append(rets, "src_addr="+marshalAddresses(h.SourceAddresses))
+	}
+
 	return base.HeaderValue{strings.Join(rets, ";")}
 }
@@ -15,6 +15,66 @@ var casesTransport = []struct {
 	vout base.HeaderValue
 	h    Transport
 }{
+	{
+		"srtp udp unicast play request",
+		base.HeaderValue{`RTP/SAVP;unicast;client_port=3456-3457;mode=play`},
+		base.HeaderValue{`RTP/SAVP;unicast;client_port=3456-3457;mode=play`},
+		Transport{
+			Protocol: TransportProtocolUDP,
+			Profile:  TransportProfileSAVP,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			ClientPorts: &[2]int{3456, 3457},
+			Mode: func() *TransportMode {
+				v := TransportModePlay
+				return &v
+			}(),
+		},
+	},
+	{
+		"srtp tcp play request",
+		base.HeaderValue{`RTP/SAVP/TCP;unicast;interleaved=0-1`},
+		base.HeaderValue{`RTP/SAVP/TCP;unicast;interleaved=0-1`},
+		Transport{
+			Protocol: TransportProtocolTCP,
+			Profile:  TransportProfileSAVP,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			InterleavedIDs: &[2]int{0, 1},
+		},
+	},
+	{
+		"avpf udp unicast play request",
+		base.HeaderValue{`RTP/AVPF/UDP;unicast;client_port=3456-3457`},
+		base.HeaderValue{`RTP/AVPF;unicast;client_port=3456-3457`},
+		Transport{
+			Protocol: TransportProtocolUDP,
+			Profile:  TransportProfileAVPF,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			ClientPorts: &[2]int{3456, 3457},
+		},
+	},
+	{
+		"savpf tcp play request",
+		base.HeaderValue{`RTP/SAVPF/TCP;unicast;interleaved=0-1`},
+		base.HeaderValue{`RTP/SAVPF/TCP;unicast;interleaved=0-1`},
+		Transport{
+			Protocol: TransportProtocolTCP,
+			Profile:  TransportProfileSAVPF,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			InterleavedIDs: &[2]int{0, 1},
+		},
+	},
 	{
 		"udp unicast play request",
 		base.HeaderValue{`RTP/AVP;unicast;client_port=3456-3457;mode="PLAY"`},
@@ -234,6 +294,68 @@ var casesTransport = []struct {
 			ServerPorts: &[2]int{56002, 56003},
 		},
 	},
+	{
+		"rtsp 2.0 udp unicast play request",
+		base.HeaderValue{`RTP/AVP/UDP;unicast;dest_addr=":3456"/":3457"`},
+		base.HeaderValue{`RTP/AVP;unicast;dest_addr=":3456"/":3457"`},
+		Transport{
+			Protocol: TransportProtocolUDP,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			DestinationAddresses: []TransportAddress{
+				{Port: 3456},
+				{Port: 3457},
+			},
+		},
+	},
+	{
+		"rtsp 2.0 udp unicast play response",
+		base.HeaderValue{`RTP/AVP/UDP;unicast;dest_addr="192.0.2.5:3456"/"192.0.2.5:3457";` +
+			`src_addr="198.51.100.1:6256"/"198.51.100.1:6257";ssrc=2A3F93ED`},
+		base.HeaderValue{`RTP/AVP;unicast;ssrc=2A3F93ED;dest_addr="192.0.2.5:3456"/"192.0.2.5:3457";` +
+			`src_addr="198.51.100.1:6256"/"198.51.100.1:6257"`},
+		Transport{
+			Protocol: TransportProtocolUDP,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryUnicast
+				return &v
+			}(),
+			SSRC: func() *uint32 {
+				v := uint32(0x2A3F93ED)
+				return &v
+			}(),
+			DestinationAddresses: []TransportAddress{
+				{IP: net.ParseIP("192.0.2.5"), Port: 3456},
+				{IP: net.ParseIP("192.0.2.5"), Port: 3457},
+			},
+			SourceAddresses: []TransportAddress{
+				{IP: net.ParseIP("198.51.100.1"), Port: 6256},
+				{IP: net.ParseIP("198.51.100.1"), Port: 6257},
+			},
+		},
+	},
+	{
+		"rtsp 2.0 udp multicast play response",
+		base.HeaderValue{`RTP/AVP/UDP;multicast;dest_addr="[ff15::1]:7000"/"[ff15::1]:7001";ttl=127`},
+		base.HeaderValue{`RTP/AVP;multicast;ttl=127;dest_addr="[ff15::1]:7000"/"[ff15::1]:7001"`},
+		Transport{
+			Protocol: TransportProtocolUDP,
+			Delivery: func() *TransportDelivery {
+				v := TransportDeliveryMulticast
+				return &v
+			}(),
+			TTL: func() *uint {
+				v := uint(127)
+				return &v
+			}(),
+			DestinationAddresses: []TransportAddress{
+				{IP: net.ParseIP("ff15::1"), Port: 7000},
+				{IP: net.ParseIP("ff15::1"), Port: 7001},
+			},
+		},
+	},
 }
 
 func TestTransportUnmarshal(t *testing.T) {
@@ -318,6 +440,21 @@ func TestTransportUnmarshalErrors(t *testing.T) {
 			base.HeaderValue{`RTP/AVP;unicast;server_port=aa-14187`},
 			"invalid ports (aa-14187)",
 		},
+		{
+			"invalid dest_addr 1",
+			base.HeaderValue{`RTP/AVP;unicast;dest_addr=aa`},
+			"invalid address (aa)",
+		},
+		{
+			"invalid dest_addr 2",
+			base.HeaderValue{`RTP/AVP;unicast;dest_addr=":aa"/":3457"`},
+			"invalid address (:aa)",
+		},
+		{
+			"invalid src_addr",
+			base.HeaderValue{`RTP/AVP;unicast;src_addr="aa:3456"`},
+			"invalid address (aa:3456)",
+		},
 		{
 			"invalid mode",
 			base.HeaderValue{`RTP/AVP;unicast;mode=aa`},
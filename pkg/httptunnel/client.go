@@ -0,0 +1,206 @@
+package httptunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+func newCookie() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func dialHalf(
+	ctx context.Context,
+	dialContext func(ctx context.Context, network, address string) (net.Conn, error),
+	address string,
+	tlsConfig *tls.Config,
+) (net.Conn, error) {
+	nconn, err := dialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		tconn := tls.Client(nconn, tlsConfig)
+		err := tconn.HandshakeContext(ctx)
+		if err != nil {
+			nconn.Close()
+			return nil, err
+		}
+		return tconn, nil
+	}
+
+	return nconn, nil
+}
+
+// dialWatcher aborts blocking operations of connections when a context is done.
+type dialWatcher struct {
+	mutex    sync.Mutex
+	conns    []net.Conn
+	canceled bool
+
+	terminate chan struct{}
+	done      chan struct{}
+}
+
+func newDialWatcher(ctx context.Context) *dialWatcher {
+	w := &dialWatcher{
+		terminate: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.done)
+		select {
+		case <-ctx.Done():
+			w.mutex.Lock()
+			defer w.mutex.Unlock()
+			w.canceled = true
+			for _, nconn := range w.conns {
+				nconn.SetDeadline(time.Now())
+			}
+		case <-w.terminate:
+		}
+	}()
+
+	return w
+}
+
+func (w *dialWatcher) add(nconn net.Conn) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.conns = append(w.conns, nconn)
+	if w.canceled {
+		nconn.SetDeadline(time.Now())
+	}
+}
+
+func (w *dialWatcher) close() {
+	close(w.terminate)
+	<-w.done
+}
+
+// Dial opens a tunnel with a server.
+// If tlsConfig is not nil, the tunnel uses HTTPS.
+func Dial(
+	ctx context.Context,
+	dialContext func(ctx context.Context, network, address string) (net.Conn, error),
+	address string,
+	path string,
+	tlsConfig *tls.Config,
+) (*Conn, error) {
+	conn, err := dial(ctx, dialContext, address, path, tlsConfig)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func dial(
+	ctx context.Context,
+	dialContext func(ctx context.Context, network, address string) (net.Conn, error),
+	address string,
+	path string,
+	tlsConfig *tls.Config,
+) (*Conn, error) {
+	cookie, err := newCookie()
+	if err != nil {
+		return nil, err
+	}
+
+	// abort the handshake of both halves when the context is done
+	watcher := newDialWatcher(ctx)
+	defer func() {
+		if watcher != nil {
+			watcher.close()
+		}
+	}()
+
+	getConn, err := dialHalf(ctx, dialContext, address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	watcher.add(getConn)
+
+	_, err = getConn.Write([]byte("GET " + path + " HTTP/1.0\r\n" +
+		"Host: " + address + "\r\n" +
+		CookieHeader + ": " + cookie + "\r\n" +
+		"Accept: " + ContentType + "\r\n" +
+		"Pragma: no-cache\r\n" +
+		"Cache-Control: no-cache\r\n" +
+		"\r\n"))
+	if err != nil {
+		getConn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(getConn, readBufferSize)
+
+	res, err := http.ReadResponse(br, nil)
+	if err != nil {
+		getConn.Close()
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		getConn.Close()
+		return nil, fmt.Errorf("bad status code: %d (%s)", res.StatusCode, res.Status)
+	}
+
+	postConn, err := dialHalf(ctx, dialContext, address, tlsConfig)
+	if err != nil {
+		getConn.Close()
+		return nil, err
+	}
+	watcher.add(postConn)
+
+	_, err = postConn.Write([]byte("POST " + path + " HTTP/1.0\r\n" +
+		"Host: " + address + "\r\n" +
+		CookieHeader + ": " + cookie + "\r\n" +
+		"Content-Type: " + ContentType + "\r\n" +
+		"Content-Length: 32767\r\n" +
+		"Expires: Sun, 9 Jan 1972 00:00:00 GMT\r\n" +
+		"Pragma: no-cache\r\n" +
+		"Cache-Control: no-cache\r\n" +
+		"\r\n"))
+	if err != nil {
+		getConn.Close()
+		postConn.Close()
+		return nil, err
+	}
+
+	watcher.close()
+	watcher = nil
+
+	if ctx.Err() != nil {
+		getConn.Close()
+		postConn.Close()
+		return nil, ctx.Err()
+	}
+
+	getConn.SetDeadline(time.Time{})
+	postConn.SetDeadline(time.Time{})
+
+	return &Conn{
+		getConn:  getConn,
+		postConn: postConn,
+		readConn: getConn,
+		r:        br,
+	}, nil
+}
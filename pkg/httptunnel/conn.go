@@ -0,0 +1,177 @@
+// Package httptunnel contains an implementation of RTSP-over-HTTP tunneling,
+// a technique introduced by Apple QuickTime that allows to pass through HTTP proxies and firewalls.
+//
+// A tunnel is made of two HTTP connections, tied together by a session cookie:
+// a GET connection that carries data from the server to the client, and a POST connection
+// that carries base64-encoded data from the client to the server.
+package httptunnel
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	// ContentType is the content type of tunneled data.
+	ContentType = "application/x-rtsp-tunnelled"
+
+	// CookieHeader is the header that ties together the GET and POST connections.
+	CookieHeader = "x-sessioncookie"
+
+	readBufferSize = 4096
+)
+
+// Conn is a RTSP-over-HTTP tunnel.
+// It implements net.Conn, therefore it can be used in place of a TCP connection.
+type Conn struct {
+	getConn  net.Conn
+	postConn net.Conn
+	readConn net.Conn
+	r        io.Reader
+	writeRaw bool
+}
+
+// Read implements net.Conn.
+func (c *Conn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// Write implements net.Conn.
+func (c *Conn) Write(p []byte) (int, error) {
+	// server to client: data is written as is
+	if c.writeRaw {
+		return c.getConn.Write(p)
+	}
+
+	// client to server: data is encoded in base64
+	buf := make([]byte, base64.StdEncoding.EncodedLen(len(p)))
+	base64.StdEncoding.Encode(buf, p)
+
+	_, err := c.postConn.Write(buf)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close implements net.Conn.
+func (c *Conn) Close() error {
+	err1 := c.getConn.Close()
+	err2 := c.postConn.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
+// LocalAddr implements net.Conn.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.getConn.LocalAddr()
+}
+
+// RemoteAddr implements net.Conn.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.getConn.RemoteAddr()
+}
+
+// SetDeadline implements net.Conn.
+func (c *Conn) SetDeadline(t time.Time) error {
+	err := c.SetReadDeadline(t)
+	if err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.readConn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	if c.writeRaw {
+		return c.getConn.SetWriteDeadline(t)
+	}
+	return c.postConn.SetWriteDeadline(t)
+}
+
+// base64Reader decodes a stream made of concatenated base64 chunks,
+// each one with its own padding.
+type base64Reader struct {
+	r   io.Reader
+	buf []byte
+	in  []byte
+	out []byte
+	err error
+}
+
+func newBase64Reader(r io.Reader) *base64Reader {
+	return &base64Reader{
+		r:   r,
+		buf: make([]byte, readBufferSize),
+	}
+}
+
+func (r *base64Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		n, err := r.r.Read(r.buf)
+		r.err = err
+
+		for _, b := range r.buf[:n] {
+			// some clients split chunks with newlines
+			if b != '\r' && b != '\n' && b != ' ' {
+				r.in = append(r.in, b)
+			}
+		}
+
+		l := len(r.in) - (len(r.in) % 4)
+		if l == 0 {
+			continue
+		}
+
+		out, err := decodeBase64Chunks(r.in[:l])
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+
+		r.out = out
+		r.in = append(r.in[:0], r.in[l:]...)
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func decodeBase64Chunks(src []byte) ([]byte, error) {
+	dst := make([]byte, base64.StdEncoding.DecodedLen(len(src)))
+	pos := 0
+
+	for len(src) > 0 {
+		// a chunk ends with the group that contains padding
+		end := len(src)
+		if i := bytes.IndexByte(src, '='); i >= 0 {
+			end = (i/4 + 1) * 4
+		}
+
+		n, err := base64.StdEncoding.Decode(dst[pos:], src[:end])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += n
+		src = src[end:]
+	}
+
+	return dst[:pos], nil
+}
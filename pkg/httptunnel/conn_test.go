@@ -0,0 +1,185 @@
+package httptunnel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64Reader(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			"single chunk",
+			"aGVsbG8=",
+			"hello",
+		},
+		{
+			"multiple chunks",
+			"aGVsbG8=d29ybGQ=YQ==YWJj",
+			"helloworldaabc",
+		},
+		{
+			"newlines",
+			"aGVs\r\nbG8=\r\nd29y\nbGQ=",
+			"helloworld",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			r := newBase64Reader(bytes.NewBufferString(ca.in))
+			out, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, ca.out, string(out))
+		})
+	}
+}
+
+func TestBase64ReaderError(t *testing.T) {
+	r := newBase64Reader(bytes.NewBufferString("a!!!"))
+	_, err := io.ReadAll(r)
+	require.EqualError(t, err, "illegal base64 data at input byte 1")
+}
+
+func TestTunnel(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8080")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		getConn, err := l.Accept()
+		require.NoError(t, err)
+		defer getConn.Close()
+
+		getBR := bufio.NewReader(getConn)
+
+		byts, err := getBR.Peek(5)
+		require.NoError(t, err)
+		require.Equal(t, true, IsTunnelRequest(byts))
+
+		req1, err := ReadRequest(getBR)
+		require.NoError(t, err)
+		require.Equal(t, http.MethodGet, req1.Method)
+		require.Equal(t, "/stream", req1.Path)
+
+		err = WriteResponse(getConn, http.StatusOK)
+		require.NoError(t, err)
+
+		postConn, err := l.Accept()
+		require.NoError(t, err)
+		defer postConn.Close()
+
+		postBR := bufio.NewReader(postConn)
+
+		req2, err := ReadRequest(postBR)
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPost, req2.Method)
+		require.Equal(t, "/stream", req2.Path)
+		require.Equal(t, req1.Cookie, req2.Cookie)
+
+		conn := NewServerConn(getConn, postConn, postBR)
+
+		buf := make([]byte, 1024)
+		n, err := io.ReadAtLeast(conn, buf, len("OPTIONS rtsp://localhost RTSP/1.0\r\n\r\n"))
+		require.NoError(t, err)
+		require.Equal(t, "OPTIONS rtsp://localhost RTSP/1.0\r\n\r\n", string(buf[:n]))
+
+		_, err = conn.Write([]byte("RTSP/1.0 200 OK\r\n\r\n"))
+		require.NoError(t, err)
+	}()
+
+	conn, err := Dial(context.Background(), (&net.Dialer{}).DialContext, "localhost:8080", "/stream", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("OPTIONS rtsp://localhost RTSP/1.0\r\n\r\n"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	n, err := io.ReadAtLeast(conn, buf, len("RTSP/1.0 200 OK\r\n\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "RTSP/1.0 200 OK\r\n\r\n", string(buf[:n]))
+}
+
+func TestDialErrorStatusCode(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8080")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		defer nconn.Close()
+
+		_, err = ReadRequest(bufio.NewReader(nconn))
+		require.NoError(t, err)
+
+		err = WriteResponse(nconn, http.StatusNotFound)
+		require.NoError(t, err)
+	}()
+
+	_, err = Dial(context.Background(), (&net.Dialer{}).DialContext, "localhost:8080", "/stream", nil)
+	require.EqualError(t, err, "bad status code: 404 (404 Not Found)")
+}
+
+func TestDialContextCanceledDuringPost(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8080")
+	require.NoError(t, err)
+	defer l.Close()
+
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err := l.Accept()
+		require.NoError(t, err)
+		defer nconn.Close()
+
+		_, err = ReadRequest(bufio.NewReader(nconn))
+		require.NoError(t, err)
+
+		err = WriteResponse(nconn, http.StatusOK)
+		require.NoError(t, err)
+	}()
+
+	// the POST half is a pipe that is never read, therefore writes block.
+	postConn, postServerConn := net.Pipe()
+	defer postServerConn.Close()
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	dialCount := 0
+
+	_, err = Dial(ctx, func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialCount++
+		if dialCount == 1 {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			ctxCancel()
+		}()
+		return postConn, nil
+	}, "localhost:8080", "/stream", nil)
+	require.EqualError(t, err, "context canceled")
+}
@@ -0,0 +1,73 @@
+package httptunnel
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// IsTunnelRequest checks whether the first bytes received by a connection
+// belong to the request that opens one of the two halves of a tunnel.
+func IsTunnelRequest(byts []byte) bool {
+	return bytes.HasPrefix(byts, []byte("GET ")) || bytes.HasPrefix(byts, []byte("POST "))
+}
+
+// Request is the request that opens one of the two halves of a tunnel.
+type Request struct {
+	// method (GET or POST).
+	Method string
+	// path, including the query.
+	Path string
+	// session cookie, that is shared by the two halves.
+	Cookie string
+}
+
+// ReadRequest reads the request that opens one of the two halves of a tunnel.
+func ReadRequest(br *bufio.Reader) (*Request, error) {
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method '%s'", req.Method)
+	}
+
+	cookie := req.Header.Get(CookieHeader)
+	if cookie == "" {
+		return nil, fmt.Errorf("%s header is missing", CookieHeader)
+	}
+
+	return &Request{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Cookie: cookie,
+	}, nil
+}
+
+// WriteResponse writes the response to a GET request.
+func WriteResponse(w io.Writer, statusCode int) error {
+	_, err := w.Write([]byte(fmt.Sprintf("HTTP/1.0 %d %s\r\n", statusCode, http.StatusText(statusCode)) +
+		"Content-Type: " + ContentType + "\r\n" +
+		"Cache-Control: no-store\r\n" +
+		"Pragma: no-cache\r\n" +
+		"Connection: close\r\n" +
+		"\r\n"))
+	return err
+}
+
+// NewServerConn allocates a server-side tunnel.
+// postReader is the reader of the POST connection, that may contain
+// data that has already been buffered.
+func NewServerConn(getConn net.Conn, postConn net.Conn, postReader io.Reader) *Conn {
+	return &Conn{
+		getConn:  getConn,
+		postConn: postConn,
+		readConn: postConn,
+		r:        newBase64Reader(postReader),
+		writeRaw: true,
+	}
+}
@@ -0,0 +1,18 @@
+package jpeg
+
+// DefineHuffmanTable is a DHT marker.
+type DefineHuffmanTable struct {
+	Codes       []byte
+	Symbols     []byte
+	TableNumber uint8
+	TableClass  uint8
+}
+
+// Marshal encodes the marker and appends it to buf.
+func (m DefineHuffmanTable) Marshal(buf []byte) []byte {
+	buf = appendMarkerHeader(buf, MarkerDefineHuffmanTable, 1+len(m.Codes)+len(m.Symbols))
+	buf = append(buf, (m.TableClass<<4)|m.TableNumber)
+	buf = append(buf, m.Codes...)
+	buf = append(buf, m.Symbols...)
+	return buf
+}
@@ -0,0 +1,70 @@
+package jpeg
+
+import (
+	"fmt"
+)
+
+// QuantizationTable is a quantization table.
+type QuantizationTable struct {
+	ID        uint8
+	Precision uint8
+	Data      []byte
+}
+
+// DefineQuantizationTable is a DQT marker.
+type DefineQuantizationTable struct {
+	Tables []QuantizationTable
+}
+
+// Unmarshal decodes the marker content (length excluded).
+func (m *DefineQuantizationTable) Unmarshal(buf []byte) error {
+	m.Tables = nil
+
+	for len(buf) != 0 {
+		id := buf[0] & 0x0F
+		precision := buf[0] >> 4
+		buf = buf[1:]
+
+		var size int
+		switch precision {
+		case 0:
+			size = 64
+
+		case 1:
+			size = 128
+
+		default:
+			return fmt.Errorf("invalid precision (%d)", precision)
+		}
+
+		if len(buf) < size {
+			return fmt.Errorf("image is too short")
+		}
+
+		m.Tables = append(m.Tables, QuantizationTable{
+			ID:        id,
+			Precision: precision,
+			Data:      buf[:size],
+		})
+		buf = buf[size:]
+	}
+
+	return nil
+}
+
+// Marshal encodes the marker and appends it to buf.
+func (m DefineQuantizationTable) Marshal(buf []byte) []byte {
+	s := 0
+	for _, t := range m.Tables {
+		s += 1 + len(t.Data)
+	}
+
+	buf = appendMarkerHeader(buf, MarkerDefineQuantizationTable, s)
+
+	for _, t := range m.Tables {
+		buf = append(buf, (t.Precision<<4)|t.ID)
+		buf = append(buf, t.Data...)
+	}
+
+	return buf
+}
@@ -0,0 +1,67 @@
+package jpeg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefineQuantizationTableUnmarshal(t *testing.T) {
+	buf := append([]byte{0x00}, bytes.Repeat([]byte{0x01}, 64)...)
+	buf = append(buf, 0x01)
+	buf = append(buf, bytes.Repeat([]byte{0x02}, 64)...)
+
+	var m DefineQuantizationTable
+	err := m.Unmarshal(buf)
+	require.NoError(t, err)
+	require.Equal(t, DefineQuantizationTable{
+		Tables: []QuantizationTable{
+			{
+				ID:   0,
+				Data: bytes.Repeat([]byte{0x01}, 64),
+			},
+			{
+				ID:   1,
+				Data: bytes.Repeat([]byte{0x02}, 64),
+			},
+		},
+	}, m)
+}
+
+func TestDefineQuantizationTableUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"invalid precision",
+			[]byte{0x20},
+			"invalid precision (2)",
+		},
+		{
+			"too short",
+			[]byte{0x00, 0x01, 0x02},
+			"image is too short",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var m DefineQuantizationTable
+			err := m.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestDefineQuantizationTableMarshal(t *testing.T) {
+	buf := DefineQuantizationTable{
+		Tables: []QuantizationTable{
+			{
+				ID:   1,
+				Data: bytes.Repeat([]byte{0x03}, 64),
+			},
+		},
+	}.Marshal(nil)
+	require.Equal(t, append([]byte{0xff, 0xdb, 0x00, 0x43, 0x01}, bytes.Repeat([]byte{0x03}, 64)...), buf)
+}
@@ -0,0 +1,26 @@
+package jpeg
+
+import (
+	"fmt"
+)
+
+// DefineRestartInterval is a DRI marker.
+type DefineRestartInterval struct {
+	Interval uint16
+}
+
+// Unmarshal decodes the marker content (length excluded).
+func (m *DefineRestartInterval) Unmarshal(buf []byte) error {
+	if len(buf) != 2 {
+		return fmt.Errorf("unsupported DRI size of %d", len(buf))
+	}
+
+	m.Interval = uint16(buf[0])<<8 | uint16(buf[1])
+	return nil
+}
+
+// Marshal encodes the marker and appends it to buf.
+func (m DefineRestartInterval) Marshal(buf []byte) []byte {
+	buf = appendMarkerHeader(buf, MarkerDefineRestartInterval, 2)
+	return append(buf, byte(m.Interval>>8), byte(m.Interval))
+}
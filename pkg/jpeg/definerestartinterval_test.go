@@ -0,0 +1,19 @@
+package jpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefineRestartInterval(t *testing.T) {
+	var m DefineRestartInterval
+	err := m.Unmarshal([]byte{0x01, 0x02})
+	require.NoError(t, err)
+	require.Equal(t, DefineRestartInterval{Interval: 0x0102}, m)
+
+	require.Equal(t, []byte{0xff, 0xdd, 0x00, 0x04, 0x01, 0x02}, m.Marshal(nil))
+
+	err = m.Unmarshal([]byte{0x01})
+	require.EqualError(t, err, "unsupported DRI size of 1")
+}
@@ -0,0 +1,9 @@
+package jpeg
+
+// EndOfImage is a EOI marker.
+type EndOfImage struct{}
+
+// Marshal encodes the marker and appends it to buf.
+func (EndOfImage) Marshal(buf []byte) []byte {
+	return append(buf, 0xFF, MarkerEndOfImage)
+}
@@ -0,0 +1,18 @@
+// Package jpeg contains utilities to work with the JPEG codec.
+package jpeg
+
+// JPEG markers.
+// Specification: ITU-T T.81, table B.1
+const (
+	MarkerStartOfFrame0           = 0xC0
+	MarkerDefineHuffmanTable      = 0xC4
+	MarkerStartOfImage            = 0xD8
+	MarkerEndOfImage              = 0xD9
+	MarkerStartOfScan             = 0xDA
+	MarkerDefineQuantizationTable = 0xDB
+	MarkerDefineRestartInterval   = 0xDD
+)
+
+func appendMarkerHeader(buf []byte, marker uint8, length int) []byte {
+	return append(buf, 0xFF, marker, byte((length+2)>>8), byte(length+2))
+}
@@ -0,0 +1,72 @@
+package jpeg
+
+import (
+	"fmt"
+)
+
+// FrameComponent is a component of a SOF marker.
+type FrameComponent struct {
+	ID                     uint8
+	HorizontalSampling     uint8
+	VerticalSampling       uint8
+	QuantizationTableIndex uint8
+}
+
+// StartOfFrame0 is a SOF0 marker (baseline DCT).
+type StartOfFrame0 struct {
+	Width      int
+	Height     int
+	Components []FrameComponent
+}
+
+// Unmarshal decodes the marker content (length excluded).
+func (m *StartOfFrame0) Unmarshal(buf []byte) error {
+	if len(buf) < 6 {
+		return fmt.Errorf("image is too short")
+	}
+
+	precision := buf[0]
+	if precision != 8 {
+		return fmt.Errorf("precision %d is not supported", precision)
+	}
+
+	m.Height = int(buf[1])<<8 | int(buf[2])
+	m.Width = int(buf[3])<<8 | int(buf[4])
+	count := int(buf[5])
+	buf = buf[6:]
+
+	if len(buf) != count*3 {
+		return fmt.Errorf("invalid SOF size")
+	}
+
+	m.Components = make([]FrameComponent, count)
+	for i := range m.Components {
+		m.Components[i] = FrameComponent{
+			ID:                     buf[i*3],
+			HorizontalSampling:     buf[i*3+1] >> 4,
+			VerticalSampling:       buf[i*3+1] & 0x0F,
+			QuantizationTableIndex: buf[i*3+2],
+		}
+	}
+
+	return nil
+}
+
+// Marshal encodes the marker and appends it to buf.
+func (m StartOfFrame0) Marshal(buf []byte) []byte {
+	buf = appendMarkerHeader(buf, MarkerStartOfFrame0, 6+len(m.Components)*3)
+	buf = append(buf,
+		8,
+		byte(m.Height>>8), byte(m.Height),
+		byte(m.Width>>8), byte(m.Width),
+		byte(len(m.Components)))
+
+	for _, c := range m.Components {
+		buf = append(buf,
+			c.ID,
+			(c.HorizontalSampling<<4)|c.VerticalSampling,
+			c.QuantizationTableIndex)
+	}
+
+	return buf
+}
@@ -0,0 +1,95 @@
+package jpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesStartOfFrame0 = []struct {
+	name string
+	byts []byte
+	m    StartOfFrame0
+}{
+	{
+		"ycbcr 4:2:0",
+		[]byte{
+			0xff, 0xc0, 0x00, 0x11, 0x08, 0x01, 0xe0, 0x02,
+			0x80, 0x03, 0x01, 0x22, 0x00, 0x02, 0x11, 0x01,
+			0x03, 0x11, 0x01,
+		},
+		StartOfFrame0{
+			Width:  640,
+			Height: 480,
+			Components: []FrameComponent{
+				{
+					ID:                 1,
+					HorizontalSampling: 2,
+					VerticalSampling:   2,
+				},
+				{
+					ID:                     2,
+					HorizontalSampling:     1,
+					VerticalSampling:       1,
+					QuantizationTableIndex: 1,
+				},
+				{
+					ID:                     3,
+					HorizontalSampling:     1,
+					VerticalSampling:       1,
+					QuantizationTableIndex: 1,
+				},
+			},
+		},
+	},
+}
+
+func TestStartOfFrame0Unmarshal(t *testing.T) {
+	for _, ca := range casesStartOfFrame0 {
+		t.Run(ca.name, func(t *testing.T) {
+			var m StartOfFrame0
+			err := m.Unmarshal(ca.byts[4:])
+			require.NoError(t, err)
+			require.Equal(t, ca.m, m)
+		})
+	}
+}
+
+func TestStartOfFrame0Marshal(t *testing.T) {
+	for _, ca := range casesStartOfFrame0 {
+		t.Run(ca.name, func(t *testing.T) {
+			buf := ca.m.Marshal(nil)
+			require.Equal(t, ca.byts, buf)
+		})
+	}
+}
+
+func TestStartOfFrame0UnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"too short",
+			[]byte{0x08, 0x01},
+			"image is too short",
+		},
+		{
+			"unsupported precision",
+			[]byte{0x0c, 0x01, 0xe0, 0x02, 0x80, 0x01},
+			"precision 12 is not supported",
+		},
+		{
+			"invalid size",
+			[]byte{0x08, 0x01, 0xe0, 0x02, 0x80, 0x02, 0x01, 0x22},
+			"invalid SOF size",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var m StartOfFrame0
+			err := m.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package jpeg
+
+// StartOfImage is a SOI marker.
+type StartOfImage struct{}
+
+// Marshal encodes the marker and appends it to buf.
+func (StartOfImage) Marshal(buf []byte) []byte {
+	return append(buf, 0xFF, MarkerStartOfImage)
+}
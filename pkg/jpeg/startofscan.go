@@ -0,0 +1,61 @@
+package jpeg
+
+import (
+	"fmt"
+)
+
+// ScanComponent is a component of a SOS marker.
+type ScanComponent struct {
+	ID      uint8
+	DCTable uint8
+	ACTable uint8
+}
+
+// StartOfScan is a SOS marker.
+type StartOfScan struct {
+	Components []ScanComponent
+}
+
+// Unmarshal decodes the marker content (length excluded).
+func (m *StartOfScan) Unmarshal(buf []byte) error {
+	if len(buf) < 1 {
+		return fmt.Errorf("image is too short")
+	}
+
+	count := int(buf[0])
+	buf = buf[1:]
+
+	if len(buf) != count*2+3 {
+		return fmt.Errorf("invalid SOS size")
+	}
+
+	m.Components = make([]ScanComponent, count)
+	for i := range m.Components {
+		m.Components[i] = ScanComponent{
+			ID:      buf[i*2],
+			DCTable: buf[i*2+1] >> 4,
+			ACTable: buf[i*2+1] & 0x0F,
+		}
+	}
+	buf = buf[count*2:]
+
+	// spectral selection and successive approximation must have
+	// baseline values
+	if buf[0] != 0 || buf[1] != 63 || buf[2] != 0 {
+		return fmt.Errorf("unsupported spectral selection or successive approximation")
+	}
+
+	return nil
+}
+
+// Marshal encodes the marker and appends it to buf.
+func (m StartOfScan) Marshal(buf []byte) []byte {
+	buf = appendMarkerHeader(buf, MarkerStartOfScan, 1+len(m.Components)*2+3)
+	buf = append(buf, byte(len(m.Components)))
+
+	for _, c := range m.Components {
+		buf = append(buf, c.ID, (c.DCTable<<4)|c.ACTable)
+	}
+
+	return append(buf, 0, 63, 0)
+}
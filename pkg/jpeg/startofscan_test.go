@@ -0,0 +1,88 @@
+package jpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesStartOfScan = []struct {
+	name string
+	byts []byte
+	m    StartOfScan
+}{
+	{
+		"ycbcr",
+		[]byte{
+			0xff, 0xda, 0x00, 0x0c, 0x03, 0x01, 0x00, 0x02,
+			0x11, 0x03, 0x11, 0x00, 0x3f, 0x00,
+		},
+		StartOfScan{
+			Components: []ScanComponent{
+				{
+					ID: 1,
+				},
+				{
+					ID:      2,
+					DCTable: 1,
+					ACTable: 1,
+				},
+				{
+					ID:      3,
+					DCTable: 1,
+					ACTable: 1,
+				},
+			},
+		},
+	},
+}
+
+func TestStartOfScanUnmarshal(t *testing.T) {
+	for _, ca := range casesStartOfScan {
+		t.Run(ca.name, func(t *testing.T) {
+			var m StartOfScan
+			err := m.Unmarshal(ca.byts[4:])
+			require.NoError(t, err)
+			require.Equal(t, ca.m, m)
+		})
+	}
+}
+
+func TestStartOfScanMarshal(t *testing.T) {
+	for _, ca := range casesStartOfScan {
+		t.Run(ca.name, func(t *testing.T) {
+			buf := ca.m.Marshal(nil)
+			require.Equal(t, ca.byts, buf)
+		})
+	}
+}
+
+func TestStartOfScanUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"too short",
+			[]byte{},
+			"image is too short",
+		},
+		{
+			"invalid size",
+			[]byte{0x01, 0x01, 0x00},
+			"invalid SOS size",
+		},
+		{
+			"progressive",
+			[]byte{0x01, 0x01, 0x00, 0x00, 0x05, 0x00},
+			"unsupported spectral selection or successive approximation",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var m StartOfScan
+			err := m.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
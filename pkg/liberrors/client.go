@@ -188,3 +188,74 @@ type ErrClientRTPInfoInvalid struct {
 func (e ErrClientRTPInfoInvalid) Error() string {
 	return fmt.Sprintf("invalid RTP-Info: %v", e.Err)
 }
+
+// ErrClientReconnectFailed is an error that can be returned by a client.
+type ErrClientReconnectFailed struct {
+	Attempts int
+	Err      error
+}
+
+// Error implements the error interface.
+func (e ErrClientReconnectFailed) Error() string {
+	return fmt.Sprintf("unable to reconnect after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// ErrClientReconnecting is an error that can be returned by a client.
+type ErrClientReconnecting struct{}
+
+// Error implements the error interface.
+func (e ErrClientReconnecting) Error() string {
+	return "the client is reconnecting to the server"
+}
+
+// ErrClientReconnectTrackMissing is an error that can be returned by a client.
+type ErrClientReconnectTrackMissing struct{}
+
+// Error implements the error interface.
+func (e ErrClientReconnectTrackMissing) Error() string {
+	return "a track that was being read is missing from the new SDP"
+}
+
+// ErrClientSessionNotFound is an error that can be returned by a client.
+type ErrClientSessionNotFound struct{}
+
+// Error implements the error interface.
+func (e ErrClientSessionNotFound) Error() string {
+	return "no session has been set up with the given base URL"
+}
+
+// ErrClientMultiSessionDisabled is an error that can be returned by a client.
+type ErrClientMultiSessionDisabled struct{}
+
+// Error implements the error interface.
+func (e ErrClientMultiSessionDisabled) Error() string {
+	return "sessions can be played or paused individually only when MultiSessionEnable is true"
+}
+
+// ErrClientRedirected is an error that can be returned by a client.
+type ErrClientRedirected struct {
+	Location string
+}
+
+// Error implements the error interface.
+func (e ErrClientRedirected) Error() string {
+	return fmt.Sprintf("redirected by the server to %s", e.Location)
+}
+
+// ErrClientRedirectInsecure is an error that can be returned by a client.
+type ErrClientRedirectInsecure struct {
+	Location string
+}
+
+// Error implements the error interface.
+func (e ErrClientRedirectInsecure) Error() string {
+	return fmt.Sprintf("refusing to be redirected from a secure connection to %s", e.Location)
+}
+
+// ErrClientKeyFrameRequestsNotSupported is an error that can be returned by a client.
+type ErrClientKeyFrameRequestsNotSupported struct{}
+
+// Error implements the error interface.
+func (e ErrClientKeyFrameRequestsNotSupported) Error() string {
+	return "the server doesn't support key frame requests on this track"
+}
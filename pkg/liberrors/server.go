@@ -106,6 +106,26 @@ func (e ErrServerTransportHeaderInvalid) Error() string {
 	return fmt.Sprintf("invalid transport header: %v", e.Err)
 }
 
+// ErrServerScaleHeaderInvalid is an error that can be returned by a server.
+type ErrServerScaleHeaderInvalid struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrServerScaleHeaderInvalid) Error() string {
+	return fmt.Sprintf("invalid scale header: %v", e.Err)
+}
+
+// ErrServerSpeedHeaderInvalid is an error that can be returned by a server.
+type ErrServerSpeedHeaderInvalid struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrServerSpeedHeaderInvalid) Error() string {
+	return fmt.Sprintf("invalid speed header: %v", e.Err)
+}
+
 // ErrServerTrackAlreadySetup is an error that can be returned by a server.
 type ErrServerTrackAlreadySetup struct {
 	TrackID int
@@ -253,3 +273,45 @@ type ErrServerSessionNotInUse struct{}
 func (e ErrServerSessionNotInUse) Error() string {
 	return "not in use"
 }
+
+// ErrServerSessionNotConnected is an error that can be returned by a server.
+type ErrServerSessionNotConnected struct{}
+
+// Error implements the error interface.
+func (e ErrServerSessionNotConnected) Error() string {
+	return "session is not associated with any connection"
+}
+
+// ErrServerRouterPublisherAlreadyPresent is an error that can be returned by a server router.
+type ErrServerRouterPublisherAlreadyPresent struct {
+	Path string
+}
+
+// Error implements the error interface.
+func (e ErrServerRouterPublisherAlreadyPresent) Error() string {
+	return fmt.Sprintf("someone is already publishing to path '%s'", e.Path)
+}
+
+// ErrServerSourceMulticast is an error that can be returned by a server.
+type ErrServerSourceMulticast struct{}
+
+// Error implements the error interface.
+func (e ErrServerSourceMulticast) Error() string {
+	return "sources can't be used with the UDP-multicast transport protocol"
+}
+
+// ErrServerSourceSRTP is an error that can be returned by a server.
+type ErrServerSourceSRTP struct{}
+
+// Error implements the error interface.
+func (e ErrServerSourceSRTP) Error() string {
+	return "sources can't be used with SRTP-encrypted streams"
+}
+
+// ErrServerReaderBehind is an error that can be returned by a server.
+type ErrServerReaderBehind struct{}
+
+// Error implements the error interface.
+func (e ErrServerReaderBehind) Error() string {
+	return "reader has been falling behind for too long"
+}
@@ -0,0 +1,499 @@
+// Package mikey contains a MIKEY message parser and encoder (RFC 3830).
+//
+// Only the subset of MIKEY that is used to transmit SRTP keys inside SDP (RFC 4567)
+// is supported, that is the pre-shared key mode with NULL encryption and NULL MAC.
+// Since keys are transmitted in plain text, the signaling channel must be
+// protected by other means (i.e. TLS).
+package mikey
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	version = 1
+
+	// data types
+	dataTypePSKInit = 0
+
+	// payload types
+	payloadLast    = 0
+	payloadKEMAC   = 1
+	payloadT       = 5
+	payloadSP      = 10
+	payloadRAND    = 11
+	payloadKeyData = 20
+
+	// CS ID map types
+	csIDMapTypeSRTPID = 0
+
+	// timestamp types
+	tsTypeNTPUTC = 0
+
+	// KEMAC algorithms
+	encrAlgNULL = 0
+	macAlgNULL  = 0
+
+	// key validity types
+	kvNULL = 0
+)
+
+// SRTPID is an entry of the SRTP-ID map of the header.
+type SRTPID struct {
+	// security policy number
+	PolicyNo uint8
+
+	// SSRC of the stream
+	SSRC uint32
+
+	// rollover counter of the stream
+	ROC uint32
+}
+
+// SRTP security policy parameter types (RFC 3830, section 6.10.1).
+const (
+	SPParamEncryptionAlgorithm  = 0
+	SPParamSessionEncrKeyLength = 1
+	SPParamAuthAlgorithm        = 2
+	SPParamSessionAuthKeyLength = 3
+	SPParamSessionSaltKeyLength = 4
+	SPParamSRTPPRF              = 5
+	SPParamKeyDerivationRate    = 6
+	SPParamSRTPEncryption       = 7
+	SPParamSRTCPEncryption      = 8
+	SPParamFECOrder             = 9
+	SPParamSRTPAuthentication   = 10
+	SPParamAuthTagLength        = 11
+	SPParamSRTPPrefixLength     = 12
+)
+
+// SecurityPolicyParam is a parameter of a security policy.
+type SecurityPolicyParam struct {
+	Type  uint8
+	Value []byte
+}
+
+// SecurityPolicy is a security policy (SP) payload.
+type SecurityPolicy struct {
+	// policy number
+	PolicyNo uint8
+
+	// parameters
+	Params []SecurityPolicyParam
+}
+
+// Param returns the value of a parameter.
+func (p SecurityPolicy) Param(typ uint8) ([]byte, bool) {
+	for _, param := range p.Params {
+		if param.Type == typ {
+			return param.Value, true
+		}
+	}
+	return nil, false
+}
+
+// KeyDataType is the type of a key data sub-payload.
+type KeyDataType uint8
+
+// key data types.
+const (
+	KeyDataTypeTGK     KeyDataType = 0
+	KeyDataTypeTGKSalt KeyDataType = 1
+	KeyDataTypeTEK     KeyDataType = 2
+	KeyDataTypeTEKSalt KeyDataType = 3
+)
+
+// KeyData is a key data sub-payload.
+type KeyData struct {
+	// type of key
+	Type KeyDataType
+
+	// key
+	Key []byte
+
+	// (optional) salt, available with the TGK+SALT and TEK+SALT types
+	Salt []byte
+}
+
+// Message is a MIKEY message.
+type Message struct {
+	// crypto session bundle ID
+	CSBID uint32
+
+	// SRTP-ID map
+	SRTPIDs []SRTPID
+
+	// NTP-UTC timestamp
+	Timestamp uint64
+
+	// random value
+	RAND []byte
+
+	// security policies
+	Policies []SecurityPolicy
+
+	// keys
+	KeyData []KeyData
+}
+
+func readUint16(byts []byte, pos *int) (uint16, error) {
+	if len(byts) < *pos+2 {
+		return 0, fmt.Errorf("buffer is too short")
+	}
+	v := binary.BigEndian.Uint16(byts[*pos:])
+	*pos += 2
+	return v, nil
+}
+
+func readBytes(byts []byte, pos *int, n int) ([]byte, error) {
+	if len(byts) < *pos+n {
+		return nil, fmt.Errorf("buffer is too short")
+	}
+	v := byts[*pos : *pos+n]
+	*pos += n
+	return v, nil
+}
+
+func appendUint16(byts []byte, v uint16) []byte {
+	return append(byts, byte(v>>8), byte(v))
+}
+
+func appendUint32(byts []byte, v uint32) []byte {
+	return append(byts, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(byts []byte, v uint64) []byte {
+	return appendUint32(appendUint32(byts, uint32(v>>32)), uint32(v))
+}
+
+// Unmarshal decodes a Message.
+func (m *Message) Unmarshal(byts []byte) error {
+	if len(byts) < 10 {
+		return fmt.Errorf("buffer is too short")
+	}
+
+	if byts[0] != version {
+		return fmt.Errorf("unsupported version: %d", byts[0])
+	}
+
+	if byts[1] != dataTypePSKInit {
+		return fmt.Errorf("unsupported data type: %d", byts[1])
+	}
+
+	nextPayload := byts[2]
+
+	if (byts[3] & 0x7F) != 0 {
+		return fmt.Errorf("unsupported PRF function: %d", byts[3]&0x7F)
+	}
+
+	m.CSBID = binary.BigEndian.Uint32(byts[4:])
+	csCount := int(byts[8])
+
+	if byts[9] != csIDMapTypeSRTPID {
+		return fmt.Errorf("unsupported CS ID map type: %d", byts[9])
+	}
+
+	pos := 10
+
+	m.SRTPIDs = nil
+	for i := 0; i < csCount; i++ {
+		buf, err := readBytes(byts, &pos, 9)
+		if err != nil {
+			return err
+		}
+
+		m.SRTPIDs = append(m.SRTPIDs, SRTPID{
+			PolicyNo: buf[0],
+			SSRC:     binary.BigEndian.Uint32(buf[1:]),
+			ROC:      binary.BigEndian.Uint32(buf[5:]),
+		})
+	}
+
+	m.Timestamp = 0
+	m.RAND = nil
+	m.Policies = nil
+	m.KeyData = nil
+
+	for nextPayload != payloadLast {
+		buf, err := readBytes(byts, &pos, 1)
+		if err != nil {
+			return err
+		}
+		curPayload := nextPayload
+		nextPayload = buf[0]
+
+		switch curPayload {
+		case payloadT:
+			buf, err := readBytes(byts, &pos, 1)
+			if err != nil {
+				return err
+			}
+
+			if buf[0] != tsTypeNTPUTC {
+				return fmt.Errorf("unsupported timestamp type: %d", buf[0])
+			}
+
+			buf, err = readBytes(byts, &pos, 8)
+			if err != nil {
+				return err
+			}
+			m.Timestamp = binary.BigEndian.Uint64(buf)
+
+		case payloadRAND:
+			buf, err := readBytes(byts, &pos, 1)
+			if err != nil {
+				return err
+			}
+
+			buf, err = readBytes(byts, &pos, int(buf[0]))
+			if err != nil {
+				return err
+			}
+			m.RAND = append([]byte(nil), buf...)
+
+		case payloadSP:
+			err := m.unmarshalSP(byts, &pos)
+			if err != nil {
+				return err
+			}
+
+		case payloadKEMAC:
+			err := m.unmarshalKEMAC(byts, &pos)
+			if err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("unsupported payload type: %d", curPayload)
+		}
+	}
+
+	if pos != len(byts) {
+		return fmt.Errorf("unexpected data after the last payload")
+	}
+
+	return nil
+}
+
+func (m *Message) unmarshalSP(byts []byte, pos *int) error {
+	buf, err := readBytes(byts, pos, 2)
+	if err != nil {
+		return err
+	}
+
+	sp := SecurityPolicy{
+		PolicyNo: buf[0],
+	}
+
+	if buf[1] != 0 {
+		return fmt.Errorf("unsupported protocol type: %d", buf[1])
+	}
+
+	paramsLen, err := readUint16(byts, pos)
+	if err != nil {
+		return err
+	}
+
+	params, err := readBytes(byts, pos, int(paramsLen))
+	if err != nil {
+		return err
+	}
+
+	ppos := 0
+	for ppos < len(params) {
+		buf, err := readBytes(params, &ppos, 2)
+		if err != nil {
+			return err
+		}
+
+		val, err := readBytes(params, &ppos, int(buf[1]))
+		if err != nil {
+			return err
+		}
+
+		sp.Params = append(sp.Params, SecurityPolicyParam{
+			Type:  buf[0],
+			Value: append([]byte(nil), val...),
+		})
+	}
+
+	m.Policies = append(m.Policies, sp)
+	return nil
+}
+
+func (m *Message) unmarshalKEMAC(byts []byte, pos *int) error {
+	buf, err := readBytes(byts, pos, 1)
+	if err != nil {
+		return err
+	}
+
+	if buf[0] != encrAlgNULL {
+		return fmt.Errorf("unsupported encryption algorithm: %d", buf[0])
+	}
+
+	encrLen, err := readUint16(byts, pos)
+	if err != nil {
+		return err
+	}
+
+	encr, err := readBytes(byts, pos, int(encrLen))
+	if err != nil {
+		return err
+	}
+
+	buf, err = readBytes(byts, pos, 1)
+	if err != nil {
+		return err
+	}
+
+	if buf[0] != macAlgNULL {
+		return fmt.Errorf("unsupported MAC algorithm: %d", buf[0])
+	}
+
+	// key data sub-payloads
+	epos := 0
+	nextPayload := byte(payloadKeyData)
+	for nextPayload != payloadLast {
+		if nextPayload != payloadKeyData {
+			return fmt.Errorf("unsupported sub-payload type: %d", nextPayload)
+		}
+
+		buf, err := readBytes(encr, &epos, 2)
+		if err != nil {
+			return err
+		}
+		nextPayload = buf[0]
+
+		kd := KeyData{
+			Type: KeyDataType(buf[1] >> 4),
+		}
+
+		if (buf[1] & 0x0F) != kvNULL {
+			return fmt.Errorf("unsupported key validity type: %d", buf[1]&0x0F)
+		}
+
+		keyLen, err := readUint16(encr, &epos)
+		if err != nil {
+			return err
+		}
+
+		key, err := readBytes(encr, &epos, int(keyLen))
+		if err != nil {
+			return err
+		}
+		kd.Key = append([]byte(nil), key...)
+
+		if kd.Type == KeyDataTypeTGKSalt || kd.Type == KeyDataTypeTEKSalt {
+			saltLen, err := readUint16(encr, &epos)
+			if err != nil {
+				return err
+			}
+
+			salt, err := readBytes(encr, &epos, int(saltLen))
+			if err != nil {
+				return err
+			}
+			kd.Salt = append([]byte(nil), salt...)
+		}
+
+		m.KeyData = append(m.KeyData, kd)
+	}
+
+	if epos != len(encr) {
+		return fmt.Errorf("unexpected data after the last key data")
+	}
+
+	return nil
+}
+
+// Marshal encodes a Message.
+func (m Message) Marshal() ([]byte, error) {
+	if len(m.SRTPIDs) > 255 {
+		return nil, fmt.Errorf("too many SRTP IDs")
+	}
+
+	if len(m.RAND) > 255 {
+		return nil, fmt.Errorf("RAND is too long")
+	}
+
+	// payloads are written in this order: T, RAND, SP, KEMAC
+	var types []byte
+	types = append(types, payloadT)
+	if m.RAND != nil {
+		types = append(types, payloadRAND)
+	}
+	for range m.Policies {
+		types = append(types, payloadSP)
+	}
+	types = append(types, payloadKEMAC)
+
+	next := func(i int) byte {
+		if i+1 < len(types) {
+			return types[i+1]
+		}
+		return payloadLast
+	}
+
+	byts := []byte{version, dataTypePSKInit, types[0], 0}
+	byts = appendUint32(byts, m.CSBID)
+	byts = append(byts, byte(len(m.SRTPIDs)), csIDMapTypeSRTPID)
+
+	for _, id := range m.SRTPIDs {
+		byts = append(byts, id.PolicyNo)
+		byts = appendUint32(byts, id.SSRC)
+		byts = appendUint32(byts, id.ROC)
+	}
+
+	i := 0
+
+	byts = append(byts, next(i), tsTypeNTPUTC)
+	byts = appendUint64(byts, m.Timestamp)
+	i++
+
+	if m.RAND != nil {
+		byts = append(byts, next(i), byte(len(m.RAND)))
+		byts = append(byts, m.RAND...)
+		i++
+	}
+
+	for _, sp := range m.Policies {
+		var params []byte
+		for _, param := range sp.Params {
+			if len(param.Value) > 255 {
+				return nil, fmt.Errorf("policy parameter is too long")
+			}
+			params = append(params, param.Type, byte(len(param.Value)))
+			params = append(params, param.Value...)
+		}
+
+		byts = append(byts, next(i), sp.PolicyNo, 0)
+		byts = appendUint16(byts, uint16(len(params)))
+		byts = append(byts, params...)
+		i++
+	}
+
+	var encr []byte
+	for j, kd := range m.KeyData {
+		nextSub := byte(payloadLast)
+		if j+1 < len(m.KeyData) {
+			nextSub = payloadKeyData
+		}
+
+		encr = append(encr, nextSub, byte(kd.Type)<<4|kvNULL)
+		encr = appendUint16(encr, uint16(len(kd.Key)))
+		encr = append(encr, kd.Key...)
+
+		if kd.Type == KeyDataTypeTGKSalt || kd.Type == KeyDataTypeTEKSalt {
+			encr = appendUint16(encr, uint16(len(kd.Salt)))
+			encr = append(encr, kd.Salt...)
+		}
+	}
+
+	byts = append(byts, next(i), encrAlgNULL)
+	byts = appendUint16(byts, uint16(len(encr)))
+	byts = append(byts, encr...)
+	byts = append(byts, macAlgNULL)
+
+	return byts, nil
+}
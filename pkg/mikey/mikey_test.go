@@ -0,0 +1,126 @@
+package mikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var cases = []struct {
+	name string
+	byts []byte
+	msg  Message
+}{
+	{
+		"srtp key",
+		[]byte{
+			// HDR
+			0x01, 0x00, 0x05, 0x00, 0x12, 0x34, 0x56, 0x78,
+			0x01, 0x00,
+			0x00, 0x38, 0xf2, 0x7a, 0x2f, 0x00, 0x00, 0x00, 0x00,
+			// T
+			0x0b, 0x00, 0xe6, 0x3d, 0x5a, 0x2b, 0x00, 0x00, 0x00, 0x00,
+			// RAND
+			0x0a, 0x04, 0x01, 0x02, 0x03, 0x04,
+			// SP
+			0x01, 0x00, 0x00, 0x00, 0x06,
+			0x00, 0x01, 0x01,
+			0x0b, 0x01, 0x0a,
+			// KEMAC
+			0x00, 0x00, 0x00, 0x0a,
+			0x00, 0x30, 0x00, 0x02, 0x01, 0x02, 0x00, 0x02, 0x03, 0x04,
+			0x00,
+		},
+		Message{
+			CSBID: 0x12345678,
+			SRTPIDs: []SRTPID{{
+				PolicyNo: 0,
+				SSRC:     0x38f27a2f,
+				ROC:      0,
+			}},
+			Timestamp: 0xe63d5a2b00000000,
+			RAND:      []byte{0x01, 0x02, 0x03, 0x04},
+			Policies: []SecurityPolicy{{
+				PolicyNo: 0,
+				Params: []SecurityPolicyParam{
+					{Type: SPParamEncryptionAlgorithm, Value: []byte{1}},
+					{Type: SPParamAuthTagLength, Value: []byte{10}},
+				},
+			}},
+			KeyData: []KeyData{{
+				Type: KeyDataTypeTEKSalt,
+				Key:  []byte{0x01, 0x02},
+				Salt: []byte{0x03, 0x04},
+			}},
+		},
+	},
+}
+
+func TestUnmarshal(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			var msg Message
+			err := msg.Unmarshal(ca.byts)
+			require.NoError(t, err)
+			require.Equal(t, ca.msg, msg)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			byts, err := ca.msg.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, ca.byts, byts)
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"empty",
+			[]byte{},
+			"buffer is too short",
+		},
+		{
+			"invalid version",
+			[]byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			"unsupported version: 2",
+		},
+		{
+			"invalid data type",
+			[]byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			"unsupported data type: 2",
+		},
+		{
+			"missing SRTP ID",
+			[]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+			"buffer is too short",
+		},
+		{
+			"unsupported payload",
+			[]byte{0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			"unsupported payload type: 2",
+		},
+		{
+			"encrypted KEMAC",
+			[]byte{
+				0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+			"unsupported encryption algorithm: 1",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var msg Message
+			err := msg.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package mpeg2audio
+
+import (
+	"fmt"
+)
+
+// bitrates in kbit/s
+var bitrates = [][][]int{
+	// MPEG-1
+	{
+		// layer 1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		// layer 2
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		// layer 3
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	// MPEG-2 and MPEG-2.5
+	{
+		// layer 1
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		// layer 2
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		// layer 3
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+var sampleRates = [][]int{
+	// MPEG-1
+	{44100, 48000, 32000},
+	// MPEG-2
+	{22050, 24000, 16000},
+	// MPEG-2.5
+	{11025, 12000, 8000},
+}
+
+// ChannelMode is a channel mode.
+type ChannelMode int
+
+// channel modes.
+const (
+	ChannelModeStereo      ChannelMode = 0
+	ChannelModeJointStereo ChannelMode = 1
+	ChannelModeDualChannel ChannelMode = 2
+	ChannelModeMono        ChannelMode = 3
+)
+
+// FrameHeader is the header of a MPEG-1/2 audio frame.
+// Specification: ISO 11172-3, section 2.4.1.3; ISO 13818-3, section 2.4.1.3
+type FrameHeader struct {
+	MPEG2       bool
+	MPEG25      bool
+	Layer       int
+	Bitrate     int
+	SampleRate  int
+	Padding     bool
+	ChannelMode ChannelMode
+}
+
+// Unmarshal decodes a FrameHeader.
+func (h *FrameHeader) Unmarshal(buf []byte) error {
+	if len(buf) < 4 {
+		return fmt.Errorf("not enough bytes")
+	}
+
+	syncWord := uint16(buf[0])<<4 | uint16(buf[1])>>4
+	if syncWord&0xFFE != 0xFFE {
+		return fmt.Errorf("sync word not found: %x", syncWord)
+	}
+
+	switch (buf[1] >> 3) & 0x03 {
+	case 0:
+		h.MPEG2 = true
+		h.MPEG25 = true
+
+	case 2:
+		h.MPEG2 = true
+		h.MPEG25 = false
+
+	case 3:
+		h.MPEG2 = false
+		h.MPEG25 = false
+
+	default:
+		return fmt.Errorf("invalid MPEG version")
+	}
+
+	layer := (buf[1] >> 1) & 0x03
+	if layer == 0 {
+		return fmt.Errorf("invalid layer")
+	}
+	h.Layer = 4 - int(layer)
+
+	bitrateIndex := buf[2] >> 4
+	if bitrateIndex == 0 {
+		return fmt.Errorf("free bitrate is not supported")
+	}
+	if bitrateIndex == 15 {
+		return fmt.Errorf("invalid bitrate")
+	}
+
+	versionIndex := 0
+	if h.MPEG2 {
+		versionIndex = 1
+	}
+	h.Bitrate = bitrates[versionIndex][h.Layer-1][bitrateIndex] * 1000
+
+	sampleRateIndex := (buf[2] >> 2) & 0x03
+	if sampleRateIndex == 3 {
+		return fmt.Errorf("invalid sample rate")
+	}
+
+	switch {
+	case h.MPEG25:
+		h.SampleRate = sampleRates[2][sampleRateIndex]
+
+	case h.MPEG2:
+		h.SampleRate = sampleRates[1][sampleRateIndex]
+
+	default:
+		h.SampleRate = sampleRates[0][sampleRateIndex]
+	}
+
+	h.Padding = ((buf[2] >> 1) & 0x01) != 0
+	h.ChannelMode = ChannelMode(buf[3] >> 6)
+
+	return nil
+}
+
+// ChannelCount returns the channel count.
+func (h FrameHeader) ChannelCount() int {
+	if h.ChannelMode == ChannelModeMono {
+		return 1
+	}
+	return 2
+}
+
+// SampleCount returns the number of samples contained into the frame.
+func (h FrameHeader) SampleCount() int {
+	switch {
+	case h.Layer == 1:
+		return 384
+
+	case h.Layer == 3 && h.MPEG2:
+		return 576
+
+	default:
+		return 1152
+	}
+}
+
+// FrameLen returns the length of the frame, header included.
+func (h FrameHeader) FrameLen() int {
+	if h.Layer == 1 {
+		n := 12 * h.Bitrate / h.SampleRate
+		if h.Padding {
+			n++
+		}
+		return n * 4
+	}
+
+	n := h.SampleCount() / 8 * h.Bitrate / h.SampleRate
+	if h.Padding {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,163 @@
+package mpeg2audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesFrameHeader = []struct {
+	name         string
+	byts         []byte
+	h            FrameHeader
+	frameLen     int
+	sampleCount  int
+	channelCount int
+}{
+	{
+		"mpeg-1 layer 3, 128kbit/s, 44.1khz, joint stereo",
+		[]byte{0xff, 0xfb, 0x90, 0x64},
+		FrameHeader{
+			Layer:       3,
+			Bitrate:     128000,
+			SampleRate:  44100,
+			ChannelMode: ChannelModeJointStereo,
+		},
+		417,
+		1152,
+		2,
+	},
+	{
+		"mpeg-1 layer 3, 128kbit/s, 44.1khz, padding",
+		[]byte{0xff, 0xfb, 0x92, 0x64},
+		FrameHeader{
+			Layer:       3,
+			Bitrate:     128000,
+			SampleRate:  44100,
+			Padding:     true,
+			ChannelMode: ChannelModeJointStereo,
+		},
+		418,
+		1152,
+		2,
+	},
+	{
+		"mpeg-1 layer 2, 192kbit/s, 48khz, stereo",
+		[]byte{0xff, 0xfd, 0xa4, 0x04},
+		FrameHeader{
+			Layer:       2,
+			Bitrate:     192000,
+			SampleRate:  48000,
+			ChannelMode: ChannelModeStereo,
+		},
+		576,
+		1152,
+		2,
+	},
+	{
+		"mpeg-1 layer 1, 384kbit/s, 32khz, mono",
+		[]byte{0xff, 0xff, 0xc8, 0xc4},
+		FrameHeader{
+			Layer:       1,
+			Bitrate:     384000,
+			SampleRate:  32000,
+			ChannelMode: ChannelModeMono,
+		},
+		576,
+		384,
+		1,
+	},
+	{
+		"mpeg-2 layer 3, 64kbit/s, 24khz, mono",
+		[]byte{0xff, 0xf3, 0x84, 0xc4},
+		FrameHeader{
+			MPEG2:       true,
+			Layer:       3,
+			Bitrate:     64000,
+			SampleRate:  24000,
+			ChannelMode: ChannelModeMono,
+		},
+		192,
+		576,
+		1,
+	},
+	{
+		"mpeg-2.5 layer 3, 8kbit/s, 8khz, mono",
+		[]byte{0xff, 0xe3, 0x18, 0xc4},
+		FrameHeader{
+			MPEG2:       true,
+			MPEG25:      true,
+			Layer:       3,
+			Bitrate:     8000,
+			SampleRate:  8000,
+			ChannelMode: ChannelModeMono,
+		},
+		72,
+		576,
+		1,
+	},
+}
+
+func TestFrameHeaderUnmarshal(t *testing.T) {
+	for _, ca := range casesFrameHeader {
+		t.Run(ca.name, func(t *testing.T) {
+			var h FrameHeader
+			err := h.Unmarshal(ca.byts)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+			require.Equal(t, ca.frameLen, h.FrameLen())
+			require.Equal(t, ca.sampleCount, h.SampleCount())
+			require.Equal(t, ca.channelCount, h.ChannelCount())
+		})
+	}
+}
+
+func TestFrameHeaderUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		byts []byte
+		err  string
+	}{
+		{
+			"too short",
+			[]byte{0xff, 0xfb},
+			"not enough bytes",
+		},
+		{
+			"invalid sync word",
+			[]byte{0xff, 0x1b, 0x90, 0x64},
+			"sync word not found: ff1",
+		},
+		{
+			"invalid version",
+			[]byte{0xff, 0xeb, 0x90, 0x64},
+			"invalid MPEG version",
+		},
+		{
+			"invalid layer",
+			[]byte{0xff, 0xf9, 0x90, 0x64},
+			"invalid layer",
+		},
+		{
+			"free bitrate",
+			[]byte{0xff, 0xfb, 0x00, 0x64},
+			"free bitrate is not supported",
+		},
+		{
+			"invalid bitrate",
+			[]byte{0xff, 0xfb, 0xf0, 0x64},
+			"invalid bitrate",
+		},
+		{
+			"invalid sample rate",
+			[]byte{0xff, 0xfb, 0x9c, 0x64},
+			"invalid sample rate",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h FrameHeader
+			err := h.Unmarshal(ca.byts)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
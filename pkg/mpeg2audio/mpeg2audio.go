@@ -0,0 +1,2 @@
+// Package mpeg2audio contains utilities to work with MPEG-1/2 audio codecs.
+package mpeg2audio
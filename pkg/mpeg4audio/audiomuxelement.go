@@ -0,0 +1,254 @@
+package mpeg4audio
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+// AudioMuxElement is a LATM AudioMuxElement.
+// Specification: ISO 14496-3, Table 1.41
+type AudioMuxElement struct {
+	// whether the StreamMuxConfig is transmitted in band.
+	MuxConfigPresent bool
+
+	// StreamMuxConfig of the element.
+	// When MuxConfigPresent is false, it must be filled before decoding.
+	// When MuxConfigPresent is true, it is filled by the decoder
+	// and it is preserved between elements that reuse the previous StreamMuxConfig.
+	StreamMuxConfig *StreamMuxConfig
+
+	// payloads, indexed by sub frame, program and layer.
+	Payloads [][][][]byte
+}
+
+func readPayloadLength(buf []byte, pos *int, l *StreamMuxConfigLayer) (int, error) {
+	switch l.FrameLengthType {
+	case 0:
+		n := 0
+		for {
+			tmp, err := bits.ReadBits(buf, pos, 8)
+			if err != nil {
+				return 0, err
+			}
+			n += int(tmp)
+
+			if tmp != 255 {
+				break
+			}
+		}
+		return n, nil
+
+	case 1:
+		return int(l.FrameLength) + 20, nil
+
+	default:
+		return 0, fmt.Errorf("unsupported frameLengthType (%d)", l.FrameLengthType)
+	}
+}
+
+func readBytes(buf []byte, pos *int, n int) ([]byte, error) {
+	if (len(buf)*8 - *pos) < n*8 {
+		return nil, fmt.Errorf("not enough bits")
+	}
+
+	// fast path: data is byte-aligned
+	if (*pos % 8) == 0 {
+		start := *pos / 8
+		*pos += n * 8
+		return buf[start : start+n], nil
+	}
+
+	ret := make([]byte, n)
+	for i := range ret {
+		tmp, _ := bits.ReadBits(buf, pos, 8)
+		ret[i] = byte(tmp)
+	}
+	return ret, nil
+}
+
+// Unmarshal decodes an AudioMuxElement.
+func (e *AudioMuxElement) Unmarshal(buf []byte) error {
+	pos := 0
+
+	if e.MuxConfigPresent {
+		useSameStreamMux, err := bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return err
+		}
+
+		if !useSameStreamMux {
+			// do not replace the previous StreamMuxConfig with a partially decoded one
+			var conf StreamMuxConfig
+			err = conf.unmarshalBits(buf, &pos)
+			if err != nil {
+				return err
+			}
+			e.StreamMuxConfig = &conf
+		}
+	}
+
+	if e.StreamMuxConfig == nil {
+		return fmt.Errorf("StreamMuxConfig is missing")
+	}
+
+	if len(e.StreamMuxConfig.Programs) == 0 {
+		return fmt.Errorf("invalid program count (0)")
+	}
+
+	for _, p := range e.StreamMuxConfig.Programs {
+		if p == nil || len(p.Layers) == 0 {
+			return fmt.Errorf("invalid layer count (0)")
+		}
+	}
+
+	e.Payloads = make([][][][]byte, e.StreamMuxConfig.NumSubFrames+1)
+
+	for i := range e.Payloads {
+		lengths := make([][]int, len(e.StreamMuxConfig.Programs))
+
+		for prog, p := range e.StreamMuxConfig.Programs {
+			lengths[prog] = make([]int, len(p.Layers))
+
+			for lay, l := range p.Layers {
+				var err error
+				lengths[prog][lay], err = readPayloadLength(buf, &pos, l)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		e.Payloads[i] = make([][][]byte, len(e.StreamMuxConfig.Programs))
+
+		for prog, p := range e.StreamMuxConfig.Programs {
+			e.Payloads[i][prog] = make([][]byte, len(p.Layers))
+
+			for lay := range p.Layers {
+				var err error
+				e.Payloads[i][prog][lay], err = readBytes(buf, &pos, lengths[prog][lay])
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if e.StreamMuxConfig.OtherDataPresent {
+		if (len(buf)*8 - pos) < int(e.StreamMuxConfig.OtherDataLenBits) {
+			return fmt.Errorf("not enough bits")
+		}
+		pos += int(e.StreamMuxConfig.OtherDataLenBits)
+	}
+
+	return nil
+}
+
+func (e AudioMuxElement) marshalSize() (int, error) {
+	if e.StreamMuxConfig == nil {
+		return 0, fmt.Errorf("StreamMuxConfig is missing")
+	}
+
+	n := 0
+
+	if e.MuxConfigPresent {
+		n += 1 + e.StreamMuxConfig.marshalSizeBits()
+	}
+
+	if len(e.Payloads) != int(e.StreamMuxConfig.NumSubFrames+1) {
+		return 0, fmt.Errorf("payload count (%d) does not match sub frame count (%d)",
+			len(e.Payloads), e.StreamMuxConfig.NumSubFrames+1)
+	}
+
+	for _, subFrame := range e.Payloads {
+		if len(subFrame) != len(e.StreamMuxConfig.Programs) {
+			return 0, fmt.Errorf("payloads do not match programs")
+		}
+
+		for prog, p := range e.StreamMuxConfig.Programs {
+			if len(subFrame[prog]) != len(p.Layers) {
+				return 0, fmt.Errorf("payloads do not match layers")
+			}
+
+			for lay, l := range p.Layers {
+				le := len(subFrame[prog][lay])
+
+				switch l.FrameLengthType {
+				case 0:
+					n += 8 * (le/255 + 1)
+
+				case 1:
+					if le != int(l.FrameLength)+20 {
+						return 0, fmt.Errorf("payload size (%d) does not match frame length (%d)",
+							le, int(l.FrameLength)+20)
+					}
+
+				default:
+					return 0, fmt.Errorf("unsupported frameLengthType (%d)", l.FrameLengthType)
+				}
+
+				n += 8 * le
+			}
+		}
+	}
+
+	if e.StreamMuxConfig.OtherDataPresent {
+		n += int(e.StreamMuxConfig.OtherDataLenBits)
+	}
+
+	ret := n / 8
+	if (n % 8) != 0 {
+		ret++
+	}
+
+	return ret, nil
+}
+
+// Marshal encodes an AudioMuxElement.
+func (e AudioMuxElement) Marshal() ([]byte, error) {
+	size, err := e.marshalSize()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	pos := 0
+
+	if e.MuxConfigPresent {
+		bits.WriteBits(buf, &pos, 0, 1) // useSameStreamMux
+
+		err := e.StreamMuxConfig.marshalTo(buf, &pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, subFrame := range e.Payloads {
+		for prog, p := range e.StreamMuxConfig.Programs {
+			for lay, l := range p.Layers {
+				if l.FrameLengthType == 0 {
+					le := len(subFrame[prog][lay])
+					for le >= 255 {
+						bits.WriteBits(buf, &pos, 255, 8)
+						le -= 255
+					}
+					bits.WriteBits(buf, &pos, uint64(le), 8)
+				}
+			}
+		}
+
+		for prog := range e.StreamMuxConfig.Programs {
+			for _, payload := range subFrame[prog] {
+				if (pos % 8) == 0 {
+					pos += 8 * copy(buf[pos/8:], payload)
+				} else {
+					for _, b := range payload {
+						bits.WriteBits(buf, &pos, uint64(b), 8)
+					}
+				}
+			}
+		}
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,167 @@
+package mpeg4audio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var audioMuxElementConfig = &StreamMuxConfig{
+	Programs: []*StreamMuxConfigProgram{{
+		Layers: []*StreamMuxConfigLayer{{
+			AudioSpecificConfig: &Config{
+				Type:         ObjectTypeAACLC,
+				SampleRate:   24000,
+				ChannelCount: 1,
+			},
+			LatmBufferFullness: 255,
+		}},
+	}},
+}
+
+var audioMuxElementCases = []struct {
+	name string
+	enc  []byte
+	dec  AudioMuxElement
+}{
+	{
+		"config not present",
+		[]byte{0x04, 0x01, 0x02, 0x03, 0x04},
+		AudioMuxElement{
+			StreamMuxConfig: audioMuxElementConfig,
+			Payloads:        [][][][]byte{{{{0x01, 0x02, 0x03, 0x04}}}},
+		},
+	},
+	{
+		"config not present, big payload",
+		append([]byte{0xff, 0x2d}, bytes.Repeat([]byte{0x01}, 300)...),
+		AudioMuxElement{
+			StreamMuxConfig: audioMuxElementConfig,
+			Payloads:        [][][][]byte{{{bytes.Repeat([]byte{0x01}, 300)}}},
+		},
+	},
+	{
+		"config present",
+		[]byte{0x20, 0x00, 0x13, 0x08, 0x1f, 0xe0, 0x20, 0x08, 0x10, 0x18, 0x20},
+		AudioMuxElement{
+			MuxConfigPresent: true,
+			StreamMuxConfig:  audioMuxElementConfig,
+			Payloads:         [][][][]byte{{{{0x01, 0x02, 0x03, 0x04}}}},
+		},
+	},
+}
+
+func TestAudioMuxElementUnmarshal(t *testing.T) {
+	for _, ca := range audioMuxElementCases {
+		t.Run(ca.name, func(t *testing.T) {
+			dec := AudioMuxElement{
+				MuxConfigPresent: ca.dec.MuxConfigPresent,
+			}
+			if !ca.dec.MuxConfigPresent {
+				dec.StreamMuxConfig = ca.dec.StreamMuxConfig
+			}
+
+			err := dec.Unmarshal(ca.enc)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, dec)
+		})
+	}
+}
+
+func TestAudioMuxElementUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		dec  AudioMuxElement
+		enc  []byte
+		err  string
+	}{
+		{
+			"missing config",
+			AudioMuxElement{},
+			[]byte{0x04, 0x01, 0x02, 0x03, 0x04},
+			"StreamMuxConfig is missing",
+		},
+		{
+			"payload too short",
+			AudioMuxElement{
+				StreamMuxConfig: audioMuxElementConfig,
+			},
+			[]byte{0x04, 0x01, 0x02},
+			"not enough bits",
+		},
+		{
+			"no programs",
+			AudioMuxElement{
+				StreamMuxConfig: &StreamMuxConfig{},
+			},
+			[]byte{0x04, 0x01, 0x02, 0x03, 0x04},
+			"invalid program count (0)",
+		},
+		{
+			"no layers",
+			AudioMuxElement{
+				StreamMuxConfig: &StreamMuxConfig{
+					Programs: []*StreamMuxConfigProgram{{}},
+				},
+			},
+			[]byte{0x04, 0x01, 0x02, 0x03, 0x04},
+			"invalid layer count (0)",
+		},
+		{
+			"invalid config after a valid one",
+			AudioMuxElement{
+				MuxConfigPresent: true,
+				StreamMuxConfig:  audioMuxElementConfig,
+			},
+			[]byte{0x40},
+			"audioMuxVersion = 1 is not supported",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			prevConfig := ca.dec.StreamMuxConfig
+			err := ca.dec.Unmarshal(ca.enc)
+			require.EqualError(t, err, ca.err)
+			require.Equal(t, prevConfig, ca.dec.StreamMuxConfig)
+		})
+	}
+}
+
+func TestAudioMuxElementMarshal(t *testing.T) {
+	for _, ca := range audioMuxElementCases {
+		t.Run(ca.name, func(t *testing.T) {
+			enc, err := ca.dec.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, ca.enc, enc)
+		})
+	}
+}
+
+func TestAudioMuxElementMarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		dec  AudioMuxElement
+		err  string
+	}{
+		{
+			"missing config",
+			AudioMuxElement{
+				Payloads: [][][][]byte{{{{0x01}}}},
+			},
+			"StreamMuxConfig is missing",
+		},
+		{
+			"wrong sub frame count",
+			AudioMuxElement{
+				StreamMuxConfig: audioMuxElementConfig,
+				Payloads:        [][][][]byte{{{{0x01}}}, {{{0x02}}}},
+			},
+			"payload count (2) does not match sub frame count (1)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			_, err := ca.dec.Marshal()
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
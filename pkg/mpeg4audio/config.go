@@ -23,11 +23,14 @@ type Config struct {
 
 // Unmarshal decodes a Config.
 func (c *Config) Unmarshal(buf []byte) error {
-	// ref: ISO 14496-3
-
 	pos := 0
+	return c.unmarshalBits(buf, &pos)
+}
 
-	tmp, err := bits.ReadBits(buf, &pos, 5)
+func (c *Config) unmarshalBits(buf []byte, pos *int) error {
+	// ref: ISO 14496-3
+
+	tmp, err := bits.ReadBits(buf, pos, 5)
 	if err != nil {
 		return err
 	}
@@ -40,7 +43,7 @@ func (c *Config) Unmarshal(buf []byte) error {
 		return fmt.Errorf("unsupported object type: %d", c.Type)
 	}
 
-	sampleRateIndex, err := bits.ReadBits(buf, &pos, 4)
+	sampleRateIndex, err := bits.ReadBits(buf, pos, 4)
 	if err != nil {
 		return err
 	}
@@ -50,7 +53,7 @@ func (c *Config) Unmarshal(buf []byte) error {
 		c.SampleRate = sampleRates[sampleRateIndex]
 
 	case sampleRateIndex == 0x0F:
-		tmp, err := bits.ReadBits(buf, &pos, 24)
+		tmp, err := bits.ReadBits(buf, pos, 24)
 		if err != nil {
 			return err
 		}
@@ -60,7 +63,7 @@ func (c *Config) Unmarshal(buf []byte) error {
 		return fmt.Errorf("invalid sample rate index (%d)", sampleRateIndex)
 	}
 
-	channelConfig, err := bits.ReadBits(buf, &pos, 4)
+	channelConfig, err := bits.ReadBits(buf, pos, 4)
 	if err != nil {
 		return err
 	}
@@ -80,7 +83,7 @@ func (c *Config) Unmarshal(buf []byte) error {
 	}
 
 	if c.Type == ObjectTypeSBR {
-		extensionSamplingFrequencyIndex, err := bits.ReadBits(buf, &pos, 4)
+		extensionSamplingFrequencyIndex, err := bits.ReadBits(buf, pos, 4)
 		if err != nil {
 			return err
 		}
@@ -90,7 +93,7 @@ func (c *Config) Unmarshal(buf []byte) error {
 			c.ExtensionSampleRate = sampleRates[extensionSamplingFrequencyIndex]
 
 		case extensionSamplingFrequencyIndex == 0x0F:
-			tmp, err := bits.ReadBits(buf, &pos, 24)
+			tmp, err := bits.ReadBits(buf, pos, 24)
 			if err != nil {
 				return err
 			}
@@ -100,25 +103,25 @@ func (c *Config) Unmarshal(buf []byte) error {
 			return fmt.Errorf("invalid extension sample rate index (%d)", extensionSamplingFrequencyIndex)
 		}
 	} else {
-		c.FrameLengthFlag, err = bits.ReadFlag(buf, &pos)
+		c.FrameLengthFlag, err = bits.ReadFlag(buf, pos)
 		if err != nil {
 			return err
 		}
 
-		c.DependsOnCoreCoder, err = bits.ReadFlag(buf, &pos)
+		c.DependsOnCoreCoder, err = bits.ReadFlag(buf, pos)
 		if err != nil {
 			return err
 		}
 
 		if c.DependsOnCoreCoder {
-			tmp, err := bits.ReadBits(buf, &pos, 14)
+			tmp, err := bits.ReadBits(buf, pos, 14)
 			if err != nil {
 				return err
 			}
 			c.CoreCoderDelay = uint16(tmp)
 		}
 
-		extensionFlag, err := bits.ReadFlag(buf, &pos)
+		extensionFlag, err := bits.ReadFlag(buf, pos)
 		if err != nil {
 			return err
 		}
@@ -131,8 +134,8 @@ func (c *Config) Unmarshal(buf []byte) error {
 	return nil
 }
 
-func (c Config) marshalSize() int {
-	n := 5 + 4 + 3
+func (c Config) marshalSizeBits() int {
+	n := 5 + 4
 
 	_, ok := reverseSampleRates[c.SampleRate]
 	if !ok {
@@ -148,10 +151,20 @@ func (c Config) marshalSize() int {
 		} else {
 			n += 4
 		}
-	} else if c.DependsOnCoreCoder {
-		n += 14
+	} else {
+		n += 3
+
+		if c.DependsOnCoreCoder {
+			n += 14
+		}
 	}
 
+	return n
+}
+
+func (c Config) marshalSize() int {
+	n := c.marshalSizeBits()
+
 	ret := n / 8
 	if (n % 8) != 0 {
 		ret++
@@ -165,14 +178,23 @@ func (c Config) Marshal() ([]byte, error) {
 	buf := make([]byte, c.marshalSize())
 	pos := 0
 
-	bits.WriteBits(buf, &pos, uint64(c.Type), 5)
+	err := c.marshalTo(buf, &pos)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (c Config) marshalTo(buf []byte, pos *int) error {
+	bits.WriteBits(buf, pos, uint64(c.Type), 5)
 
 	sampleRateIndex, ok := reverseSampleRates[c.SampleRate]
 	if !ok {
-		bits.WriteBits(buf, &pos, uint64(15), 4)
-		bits.WriteBits(buf, &pos, uint64(c.SampleRate), 24)
+		bits.WriteBits(buf, pos, uint64(15), 4)
+		bits.WriteBits(buf, pos, uint64(c.SampleRate), 24)
 	} else {
-		bits.WriteBits(buf, &pos, uint64(sampleRateIndex), 4)
+		bits.WriteBits(buf, pos, uint64(sampleRateIndex), 4)
 	}
 
 	var channelConfig int
@@ -184,35 +206,37 @@ func (c Config) Marshal() ([]byte, error) {
 		channelConfig = 7
 
 	default:
-		return nil, fmt.Errorf("invalid channel count (%d)", c.ChannelCount)
+		return fmt.Errorf("invalid channel count (%d)", c.ChannelCount)
 	}
-	bits.WriteBits(buf, &pos, uint64(channelConfig), 4)
+	bits.WriteBits(buf, pos, uint64(channelConfig), 4)
 
 	if c.Type == ObjectTypeSBR {
 		sampleRateIndex, ok := reverseSampleRates[c.ExtensionSampleRate]
 		if !ok {
-			bits.WriteBits(buf, &pos, uint64(0x0F), 4)
-			bits.WriteBits(buf, &pos, uint64(c.ExtensionSampleRate), 24)
+			bits.WriteBits(buf, pos, uint64(0x0F), 4)
+			bits.WriteBits(buf, pos, uint64(c.ExtensionSampleRate), 24)
 		} else {
-			bits.WriteBits(buf, &pos, uint64(sampleRateIndex), 4)
+			bits.WriteBits(buf, pos, uint64(sampleRateIndex), 4)
 		}
 	} else {
 		if c.FrameLengthFlag {
-			bits.WriteBits(buf, &pos, 1, 1)
+			bits.WriteBits(buf, pos, 1, 1)
 		} else {
-			bits.WriteBits(buf, &pos, 0, 1)
+			bits.WriteBits(buf, pos, 0, 1)
 		}
 
 		if c.DependsOnCoreCoder {
-			bits.WriteBits(buf, &pos, 1, 1)
+			bits.WriteBits(buf, pos, 1, 1)
 		} else {
-			bits.WriteBits(buf, &pos, 0, 1)
+			bits.WriteBits(buf, pos, 0, 1)
 		}
 
 		if c.DependsOnCoreCoder {
-			bits.WriteBits(buf, &pos, uint64(c.CoreCoderDelay), 14)
+			bits.WriteBits(buf, pos, uint64(c.CoreCoderDelay), 14)
 		}
+
+		bits.WriteBits(buf, pos, 0, 1) // extensionFlag
 	}
 
-	return buf, nil
+	return nil
 }
@@ -0,0 +1,380 @@
+package mpeg4audio
+
+import (
+	"fmt"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+// StreamMuxConfigLayer is a layer of a StreamMuxConfig.
+type StreamMuxConfigLayer struct {
+	AudioSpecificConfig       *Config
+	FrameLengthType           uint
+	LatmBufferFullness        uint
+	FrameLength               uint
+	CELPframeLengthTableIndex uint
+	HVXCframeLengthTableIndex bool
+}
+
+// StreamMuxConfigProgram is a program of a StreamMuxConfig.
+type StreamMuxConfigProgram struct {
+	Layers []*StreamMuxConfigLayer
+}
+
+// StreamMuxConfig is a LATM StreamMuxConfig.
+// Specification: ISO 14496-3, Table 1.42
+type StreamMuxConfig struct {
+	NumSubFrames     uint
+	Programs         []*StreamMuxConfigProgram
+	OtherDataPresent bool
+	OtherDataLenBits uint32
+	CRCCheckPresent  bool
+	CRCCheckSum      uint8
+}
+
+// Unmarshal decodes a StreamMuxConfig.
+func (c *StreamMuxConfig) Unmarshal(buf []byte) error {
+	pos := 0
+	return c.unmarshalBits(buf, &pos)
+}
+
+func (c *StreamMuxConfig) unmarshalBits(buf []byte, pos *int) error {
+	audioMuxVersion, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if audioMuxVersion {
+		return fmt.Errorf("audioMuxVersion = 1 is not supported")
+	}
+
+	allStreamsSameTimeFraming, err := bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if !allStreamsSameTimeFraming {
+		return fmt.Errorf("allStreamsSameTimeFraming = 0 is not supported")
+	}
+
+	tmp, err := bits.ReadBits(buf, pos, 6)
+	if err != nil {
+		return err
+	}
+	c.NumSubFrames = uint(tmp)
+
+	tmp, err = bits.ReadBits(buf, pos, 4)
+	if err != nil {
+		return err
+	}
+	numProgram := int(tmp)
+
+	c.Programs = make([]*StreamMuxConfigProgram, numProgram+1)
+	var prevConfig *Config
+
+	for prog := 0; prog <= numProgram; prog++ {
+		p := &StreamMuxConfigProgram{}
+		c.Programs[prog] = p
+
+		tmp, err = bits.ReadBits(buf, pos, 3)
+		if err != nil {
+			return err
+		}
+		numLayer := int(tmp)
+
+		p.Layers = make([]*StreamMuxConfigLayer, numLayer+1)
+
+		for lay := 0; lay <= numLayer; lay++ {
+			l := &StreamMuxConfigLayer{}
+			p.Layers[lay] = l
+
+			useSameConfig := false
+
+			if prog != 0 || lay != 0 {
+				useSameConfig, err = bits.ReadFlag(buf, pos)
+				if err != nil {
+					return err
+				}
+			}
+
+			if useSameConfig {
+				l.AudioSpecificConfig = prevConfig
+			} else {
+				l.AudioSpecificConfig = &Config{}
+				err = l.AudioSpecificConfig.unmarshalBits(buf, pos)
+				if err != nil {
+					return err
+				}
+				prevConfig = l.AudioSpecificConfig
+			}
+
+			tmp, err = bits.ReadBits(buf, pos, 3)
+			if err != nil {
+				return err
+			}
+			l.FrameLengthType = uint(tmp)
+
+			switch l.FrameLengthType {
+			case 0:
+				tmp, err = bits.ReadBits(buf, pos, 8)
+				if err != nil {
+					return err
+				}
+				l.LatmBufferFullness = uint(tmp)
+
+			case 1:
+				tmp, err = bits.ReadBits(buf, pos, 9)
+				if err != nil {
+					return err
+				}
+				l.FrameLength = uint(tmp)
+
+			case 3, 4, 5:
+				tmp, err = bits.ReadBits(buf, pos, 6)
+				if err != nil {
+					return err
+				}
+				l.CELPframeLengthTableIndex = uint(tmp)
+
+			case 6, 7:
+				l.HVXCframeLengthTableIndex, err = bits.ReadFlag(buf, pos)
+				if err != nil {
+					return err
+				}
+
+			default:
+				return fmt.Errorf("invalid frameLengthType (%d)", l.FrameLengthType)
+			}
+		}
+	}
+
+	c.OtherDataPresent, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if c.OtherDataPresent {
+		for {
+			c.OtherDataLenBits *= 256
+
+			otherDataLenEsc, err := bits.ReadFlag(buf, pos)
+			if err != nil {
+				return err
+			}
+
+			tmp, err = bits.ReadBits(buf, pos, 8)
+			if err != nil {
+				return err
+			}
+			c.OtherDataLenBits += uint32(tmp)
+
+			if !otherDataLenEsc {
+				break
+			}
+		}
+	}
+
+	c.CRCCheckPresent, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return err
+	}
+
+	if c.CRCCheckPresent {
+		tmp, err = bits.ReadBits(buf, pos, 8)
+		if err != nil {
+			return err
+		}
+		c.CRCCheckSum = uint8(tmp)
+	}
+
+	return nil
+}
+
+func (c StreamMuxConfig) otherDataLenBytes() int {
+	n := 1
+	for v := c.OtherDataLenBits >> 8; v != 0; v >>= 8 {
+		n++
+	}
+	return n
+}
+
+func (c StreamMuxConfig) marshalSizeBits() int {
+	n := 1 + 1 + 6 + 4
+
+	for prog, p := range c.Programs {
+		n += 3
+
+		for lay, l := range p.Layers {
+			if prog != 0 || lay != 0 {
+				n++
+			}
+
+			if l.AudioSpecificConfig != nil && (prog == 0 && lay == 0 || !c.usesSameConfig(prog, lay)) {
+				n += l.AudioSpecificConfig.marshalSizeBits()
+			}
+
+			n += 3
+
+			switch l.FrameLengthType {
+			case 0:
+				n += 8
+
+			case 1:
+				n += 9
+
+			case 3, 4, 5:
+				n += 6
+
+			case 6, 7:
+				n++
+			}
+		}
+	}
+
+	n++
+
+	if c.OtherDataPresent {
+		n += 9 * c.otherDataLenBytes()
+	}
+
+	n++
+
+	if c.CRCCheckPresent {
+		n += 8
+	}
+
+	return n
+}
+
+func (c StreamMuxConfig) marshalSize() int {
+	n := c.marshalSizeBits()
+
+	ret := n / 8
+	if (n % 8) != 0 {
+		ret++
+	}
+
+	return ret
+}
+
+// usesSameConfig checks whether a layer uses the same configuration of the previous one.
+func (c StreamMuxConfig) usesSameConfig(prog int, lay int) bool {
+	var prev *StreamMuxConfigLayer
+
+	if lay > 0 {
+		prev = c.Programs[prog].Layers[lay-1]
+	} else {
+		prevLayers := c.Programs[prog-1].Layers
+		prev = prevLayers[len(prevLayers)-1]
+	}
+
+	return prev.AudioSpecificConfig == c.Programs[prog].Layers[lay].AudioSpecificConfig
+}
+
+// Marshal encodes a StreamMuxConfig.
+func (c StreamMuxConfig) Marshal() ([]byte, error) {
+	buf := make([]byte, c.marshalSize())
+	pos := 0
+
+	err := c.marshalTo(buf, &pos)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (c StreamMuxConfig) marshalTo(buf []byte, pos *int) error {
+	if len(c.Programs) == 0 || len(c.Programs) > 16 {
+		return fmt.Errorf("invalid program count (%d)", len(c.Programs))
+	}
+
+	if c.NumSubFrames > 63 {
+		return fmt.Errorf("invalid NumSubFrames (%d)", c.NumSubFrames)
+	}
+
+	bits.WriteBits(buf, pos, 0, 1) // audioMuxVersion
+	bits.WriteBits(buf, pos, 1, 1) // allStreamsSameTimeFraming
+	bits.WriteBits(buf, pos, uint64(c.NumSubFrames), 6)
+	bits.WriteBits(buf, pos, uint64(len(c.Programs)-1), 4)
+
+	for prog, p := range c.Programs {
+		if len(p.Layers) == 0 || len(p.Layers) > 8 {
+			return fmt.Errorf("invalid layer count (%d)", len(p.Layers))
+		}
+
+		bits.WriteBits(buf, pos, uint64(len(p.Layers)-1), 3)
+
+		for lay, l := range p.Layers {
+			if l.AudioSpecificConfig == nil {
+				return fmt.Errorf("AudioSpecificConfig is missing")
+			}
+
+			useSameConfig := false
+
+			if prog != 0 || lay != 0 {
+				useSameConfig = c.usesSameConfig(prog, lay)
+				if useSameConfig {
+					bits.WriteBits(buf, pos, 1, 1)
+				} else {
+					bits.WriteBits(buf, pos, 0, 1)
+				}
+			}
+
+			if !useSameConfig {
+				err := l.AudioSpecificConfig.marshalTo(buf, pos)
+				if err != nil {
+					return err
+				}
+			}
+
+			bits.WriteBits(buf, pos, uint64(l.FrameLengthType), 3)
+
+			switch l.FrameLengthType {
+			case 0:
+				bits.WriteBits(buf, pos, uint64(l.LatmBufferFullness), 8)
+
+			case 1:
+				bits.WriteBits(buf, pos, uint64(l.FrameLength), 9)
+
+			case 3, 4, 5:
+				bits.WriteBits(buf, pos, uint64(l.CELPframeLengthTableIndex), 6)
+
+			case 6, 7:
+				if l.HVXCframeLengthTableIndex {
+					bits.WriteBits(buf, pos, 1, 1)
+				} else {
+					bits.WriteBits(buf, pos, 0, 1)
+				}
+
+			default:
+				return fmt.Errorf("invalid frameLengthType (%d)", l.FrameLengthType)
+			}
+		}
+	}
+
+	if c.OtherDataPresent {
+		bits.WriteBits(buf, pos, 1, 1)
+
+		n := c.otherDataLenBytes()
+		for i := n - 1; i >= 0; i-- {
+			if i > 0 {
+				bits.WriteBits(buf, pos, 1, 1)
+			} else {
+				bits.WriteBits(buf, pos, 0, 1)
+			}
+			bits.WriteBits(buf, pos, uint64(c.OtherDataLenBits>>(8*i))&0xFF, 8)
+		}
+	} else {
+		bits.WriteBits(buf, pos, 0, 1)
+	}
+
+	if c.CRCCheckPresent {
+		bits.WriteBits(buf, pos, 1, 1)
+		bits.WriteBits(buf, pos, uint64(c.CRCCheckSum), 8)
+	} else {
+		bits.WriteBits(buf, pos, 0, 1)
+	}
+
+	return nil
+}
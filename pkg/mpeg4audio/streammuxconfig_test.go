@@ -0,0 +1,110 @@
+package mpeg4audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var streamMuxConfigCases = []struct {
+	name string
+	enc  []byte
+	dec  StreamMuxConfig
+}{
+	{
+		"aac-lc 24khz mono",
+		[]byte{0x40, 0x00, 0x26, 0x10, 0x3f, 0xc0},
+		StreamMuxConfig{
+			Programs: []*StreamMuxConfigProgram{{
+				Layers: []*StreamMuxConfigLayer{{
+					AudioSpecificConfig: &Config{
+						Type:         ObjectTypeAACLC,
+						SampleRate:   24000,
+						ChannelCount: 1,
+					},
+					LatmBufferFullness: 255,
+				}},
+			}},
+		},
+	},
+	{
+		"other data and checksum",
+		[]byte{0x40, 0x00, 0x23, 0x20, 0x65, 0x98, 0x0a, 0x42, 0xaa},
+		StreamMuxConfig{
+			Programs: []*StreamMuxConfigProgram{{
+				Layers: []*StreamMuxConfigLayer{{
+					AudioSpecificConfig: &Config{
+						Type:         ObjectTypeAACLC,
+						SampleRate:   48000,
+						ChannelCount: 2,
+					},
+					FrameLengthType: 1,
+					FrameLength:     300,
+				}},
+			}},
+			OtherDataPresent: true,
+			OtherDataLenBits: 400,
+			CRCCheckPresent:  true,
+			CRCCheckSum:      0x55,
+		},
+	},
+	{
+		"multiple programs and layers",
+		[]byte{0x41, 0x12, 0x24, 0x20, 0x3f, 0xe3, 0xfc, 0x05, 0x02, 0x07, 0xf8},
+		func() StreamMuxConfig {
+			conf := &Config{
+				Type:         ObjectTypeAACLC,
+				SampleRate:   44100,
+				ChannelCount: 2,
+			}
+			return StreamMuxConfig{
+				NumSubFrames: 1,
+				Programs: []*StreamMuxConfigProgram{
+					{
+						Layers: []*StreamMuxConfigLayer{
+							{
+								AudioSpecificConfig: conf,
+								LatmBufferFullness:  255,
+							},
+							{
+								AudioSpecificConfig: conf,
+								LatmBufferFullness:  255,
+							},
+						},
+					},
+					{
+						Layers: []*StreamMuxConfigLayer{{
+							AudioSpecificConfig: &Config{
+								Type:         ObjectTypeAACLC,
+								SampleRate:   16000,
+								ChannelCount: 1,
+							},
+							LatmBufferFullness: 255,
+						}},
+					},
+				},
+			}
+		}(),
+	},
+}
+
+func TestStreamMuxConfigUnmarshal(t *testing.T) {
+	for _, ca := range streamMuxConfigCases {
+		t.Run(ca.name, func(t *testing.T) {
+			var dec StreamMuxConfig
+			err := dec.Unmarshal(ca.enc)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, dec)
+		})
+	}
+}
+
+func TestStreamMuxConfigMarshal(t *testing.T) {
+	for _, ca := range streamMuxConfigCases {
+		t.Run(ca.name, func(t *testing.T) {
+			enc, err := ca.dec.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, ca.enc, enc)
+		})
+	}
+}
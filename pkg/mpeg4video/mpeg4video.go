@@ -0,0 +1,55 @@
+// Package mpeg4video contains utilities to work with MPEG-4 part 2 video codecs.
+package mpeg4video
+
+const (
+	// MaxFrameSize is the maximum size of a frame.
+	MaxFrameSize = 1 * 1024 * 1024
+)
+
+// StartCode is a MPEG-4 video start code.
+// Specification: ISO 14496-2, Table 6-3
+type StartCode uint8
+
+// start codes.
+const (
+	VideoObjectStartCodeFirst        StartCode = 0x00
+	VideoObjectStartCodeLast         StartCode = 0x1F
+	VideoObjectLayerStartCodeFirst   StartCode = 0x20
+	VideoObjectLayerStartCodeLast    StartCode = 0x2F
+	VisualObjectSequenceStartCode    StartCode = 0xB0
+	VisualObjectSequenceEndCode      StartCode = 0xB1
+	UserDataStartCode                StartCode = 0xB2
+	GroupOfVideoObjectPlaneStartCode StartCode = 0xB3
+	VisualObjectStartCode            StartCode = 0xB5
+	VideoObjectPlaneStartCode        StartCode = 0xB6
+)
+
+// VOPCodingType is the coding type of a video object plane (VOP).
+type VOPCodingType uint8
+
+// VOP coding types.
+const (
+	VOPCodingTypeI VOPCodingType = 0
+	VOPCodingTypeP VOPCodingType = 1
+	VOPCodingTypeB VOPCodingType = 2
+	VOPCodingTypeS VOPCodingType = 3
+)
+
+// FindVOPCodingType finds the coding type of the first VOP contained in a frame.
+// It returns false if the frame does not contain any VOP.
+func FindVOPCodingType(frame []byte) (VOPCodingType, bool) {
+	for i := 0; i+4 < len(frame); i++ {
+		if frame[i] == 0 && frame[i+1] == 0 && frame[i+2] == 1 &&
+			StartCode(frame[i+3]) == VideoObjectPlaneStartCode {
+			return VOPCodingType(frame[i+4] >> 6), true
+		}
+	}
+
+	return 0, false
+}
+
+// IsIVOP checks whether a frame contains an intra-coded VOP (I-VOP).
+func IsIVOP(frame []byte) bool {
+	typ, ok := FindVOPCodingType(frame)
+	return ok && typ == VOPCodingTypeI
+}
@@ -0,0 +1,54 @@
+package mpeg4video
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindVOPCodingType(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		frame []byte
+		typ   VOPCodingType
+		ok    bool
+	}{
+		{
+			"i-vop with headers",
+			[]byte{
+				0x00, 0x00, 0x01, 0xb0, 0x01, 0x00, 0x00, 0x01,
+				0xb5, 0x89, 0x13, 0x00, 0x00, 0x01, 0x00, 0x00,
+				0x00, 0x01, 0x20, 0x00, 0xc4, 0x8d, 0x88, 0x00,
+				0xf5, 0x14, 0x04, 0x3c, 0x14, 0x63, 0x00, 0x00,
+				0x01, 0xb6, 0x10, 0x60, 0x91, 0x82, 0x3d, 0xb7,
+			},
+			VOPCodingTypeI,
+			true,
+		},
+		{
+			"p-vop",
+			[]byte{0x00, 0x00, 0x01, 0xb6, 0x50, 0x60, 0x91, 0x82},
+			VOPCodingTypeP,
+			true,
+		},
+		{
+			"b-vop",
+			[]byte{0x00, 0x00, 0x01, 0xb6, 0x90, 0x60, 0x91, 0x82},
+			VOPCodingTypeB,
+			true,
+		},
+		{
+			"no vop",
+			[]byte{0x00, 0x00, 0x01, 0xb0, 0x01},
+			0,
+			false,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			typ, ok := FindVOPCodingType(ca.frame)
+			require.Equal(t, ca.ok, ok)
+			require.Equal(t, ca.typ, typ)
+			require.Equal(t, ca.ok && ca.typ == VOPCodingTypeI, IsIVOP(ca.frame))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package opus
+
+import (
+	"time"
+)
+
+var frameDurations = [32]time.Duration{
+	// SILK-only, NB, MB, WB
+	10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond,
+	// hybrid, SWB, FB
+	10 * time.Millisecond, 20 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond,
+	// CELT-only, NB, WB, SWB, FB
+	2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond,
+	2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond,
+	2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond,
+	2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond,
+}
+
+// PacketDuration returns the duration of an Opus packet.
+// It returns zero if the packet is invalid.
+// Specification: RFC 6716, section 3.1
+func PacketDuration(pkt []byte) time.Duration {
+	if len(pkt) == 0 {
+		return 0
+	}
+
+	frameDuration := frameDurations[pkt[0]>>3]
+
+	var frameCount time.Duration
+	switch pkt[0] & 0x03 {
+	case 0:
+		frameCount = 1
+
+	case 1, 2:
+		frameCount = 2
+
+	case 3:
+		if len(pkt) < 2 {
+			return 0
+		}
+		frameCount = time.Duration(pkt[1] & 0x3F)
+	}
+
+	return frameDuration * frameCount
+}
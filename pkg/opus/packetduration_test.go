@@ -0,0 +1,71 @@
+package opus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketDuration(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkt  []byte
+		dur  time.Duration
+	}{
+		{
+			"silk nb 10ms",
+			[]byte{0x00},
+			10 * time.Millisecond,
+		},
+		{
+			"silk wb 60ms",
+			[]byte{0x58},
+			60 * time.Millisecond,
+		},
+		{
+			"hybrid fb 20ms",
+			[]byte{0x78},
+			20 * time.Millisecond,
+		},
+		{
+			"celt fb 20ms",
+			[]byte{0xf8, 0x01, 0x02},
+			20 * time.Millisecond,
+		},
+		{
+			"celt fb 2.5ms",
+			[]byte{0xe0},
+			2500 * time.Microsecond,
+		},
+		{
+			"two frames with equal size",
+			[]byte{0xf9},
+			40 * time.Millisecond,
+		},
+		{
+			"two frames with different size",
+			[]byte{0xfa},
+			40 * time.Millisecond,
+		},
+		{
+			"arbitrary number of frames",
+			[]byte{0xfb, 0x03},
+			60 * time.Millisecond,
+		},
+		{
+			"arbitrary number of frames, missing count",
+			[]byte{0xfb},
+			0,
+		},
+		{
+			"empty",
+			[]byte{},
+			0,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			require.Equal(t, ca.dur, PacketDuration(ca.pkt))
+		})
+	}
+}
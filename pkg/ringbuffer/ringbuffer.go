@@ -7,11 +7,17 @@ import (
 	"unsafe"
 )
 
+type entry struct {
+	index uint64 // write index of the entry
+	data  interface{}
+}
+
 // RingBuffer is a ring buffer.
 type RingBuffer struct {
 	size       uint64
 	readIndex  uint64
 	writeIndex uint64
+	count      uint64
 	closed     int64
 	buffer     []unsafe.Pointer
 	event      *event
@@ -46,24 +52,38 @@ func (r *RingBuffer) Reset() {
 		atomic.SwapPointer(&r.buffer[i], nil)
 	}
 	atomic.SwapUint64(&r.writeIndex, 0)
+	atomic.StoreUint64(&r.count, 0)
 	r.readIndex = 1
 	atomic.StoreInt64(&r.closed, 0)
 }
 
 // Push pushes some data at the end of the buffer.
+// When the buffer is full, the oldest entry is overwritten.
 func (r *RingBuffer) Push(data interface{}) {
 	writeIndex := atomic.AddUint64(&r.writeIndex, 1)
 	i := writeIndex % r.size
-	atomic.SwapPointer(&r.buffer[i], unsafe.Pointer(&data))
+
+	// increase count before the entry can be pulled, in order not to underflow it.
+	atomic.AddUint64(&r.count, 1)
+	if atomic.SwapPointer(&r.buffer[i], unsafe.Pointer(&entry{index: writeIndex, data: data})) != nil {
+		atomic.AddUint64(&r.count, ^uint64(0))
+	}
+
 	r.event.signal()
 }
 
 // Pull pulls some data from the beginning of the buffer.
 func (r *RingBuffer) Pull() (interface{}, bool) {
 	for {
+		// when the reader is overtaken by the writer, the oldest entries are overwritten.
+		// skip them and start from the oldest remaining one.
+		if writeIndex := atomic.LoadUint64(&r.writeIndex); writeIndex >= r.readIndex+r.size {
+			r.readIndex = writeIndex - r.size + 1
+		}
+
 		i := r.readIndex % r.size
-		res := (*interface{})(atomic.SwapPointer(&r.buffer[i], nil))
-		if res == nil {
+		ptr := atomic.LoadPointer(&r.buffer[i])
+		if ptr == nil {
 			if atomic.SwapInt64(&r.closed, 0) == 1 {
 				return nil, false
 			}
@@ -71,7 +91,41 @@ func (r *RingBuffer) Pull() (interface{}, bool) {
 			continue
 		}
 
+		e := (*entry)(ptr)
+
+		// the entry has been overwritten after the check above
+		if e.index > r.readIndex {
+			continue
+		}
+
+		if !atomic.CompareAndSwapPointer(&r.buffer[i], ptr, nil) {
+			continue
+		}
+		atomic.AddUint64(&r.count, ^uint64(0))
+
+		// the entry has been pushed after newer ones by a concurrent writer
+		if e.index < r.readIndex {
+			continue
+		}
+
 		r.readIndex++
-		return *res, true
+		return e.data, true
+	}
+}
+
+// Size returns the size of the buffer.
+func (r *RingBuffer) Size() uint64 {
+	return r.size
+}
+
+// Len returns the number of entries that are waiting to be pulled.
+// It can be used to measure the backpressure of the reader.
+func (r *RingBuffer) Len() uint64 {
+	n := atomic.LoadUint64(&r.count)
+
+	// count can briefly exceed size while an entry is being overwritten
+	if n > r.size {
+		return r.size
 	}
+	return n
 }
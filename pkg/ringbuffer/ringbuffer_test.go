@@ -13,6 +13,77 @@ func TestCreateError(t *testing.T) {
 	require.EqualError(t, err, "size must be a power of two")
 }
 
+func TestLen(t *testing.T) {
+	r, err := New(4)
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Equal(t, uint64(4), r.Size())
+	require.Equal(t, uint64(0), r.Len())
+
+	r.Push(1)
+	r.Push(2)
+	require.Equal(t, uint64(2), r.Len())
+
+	_, ok := r.Pull()
+	require.Equal(t, true, ok)
+	require.Equal(t, uint64(1), r.Len())
+
+	for i := 0; i < 10; i++ {
+		r.Push(i)
+	}
+	require.Equal(t, uint64(4), r.Len())
+
+	// after an overflow, the buffer can be drained, starting from the oldest entry
+	for i := 0; i < 4; i++ {
+		v, ok := r.Pull()
+		require.Equal(t, true, ok)
+		require.Equal(t, 6+i, v)
+		require.Equal(t, uint64(3-i), r.Len())
+	}
+
+	r.Push(10)
+	require.Equal(t, uint64(1), r.Len())
+
+	v, ok := r.Pull()
+	require.Equal(t, true, ok)
+	require.Equal(t, 10, v)
+
+	r.Push(11)
+
+	r.Reset()
+	require.Equal(t, uint64(0), r.Len())
+}
+
+func TestOverflowConcurrent(t *testing.T) {
+	r, err := New(16)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		prev := -1
+		for {
+			v, ok := r.Pull()
+			if !ok {
+				return
+			}
+
+			// entries can be lost, but never reordered
+			require.Greater(t, v.(int), prev)
+			prev = v.(int)
+		}
+	}()
+
+	for i := 0; i < 100000; i++ {
+		r.Push(i)
+	}
+
+	r.Close()
+	<-done
+}
+
 func TestPushBeforePull(t *testing.T) {
 	r, err := New(1024)
 	require.NoError(t, err)
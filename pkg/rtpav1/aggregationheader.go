@@ -0,0 +1,50 @@
+package rtpav1
+
+import (
+	"fmt"
+)
+
+// aggregation header.
+// Specification: https://aomediacodec.github.io/av1-rtp-spec/#44-av1-aggregation-header
+type aggregationHeader struct {
+	// first OBU element is the continuation of an OBU fragment from the previous packet.
+	Z bool
+
+	// last OBU element will continue in the next packet.
+	Y bool
+
+	// number of OBU elements. When zero, each element is preceded by a length field.
+	W uint8
+
+	// packet is the first packet of a coded video sequence.
+	N bool
+}
+
+func (h *aggregationHeader) unmarshal(buf []byte) error {
+	if len(buf) < 1 {
+		return fmt.Errorf("payload is too short")
+	}
+
+	h.Z = (buf[0] & 0x80) != 0
+	h.Y = (buf[0] & 0x40) != 0
+	h.W = (buf[0] >> 4) & 0x03
+	h.N = (buf[0] & 0x08) != 0
+
+	return nil
+}
+
+func (h aggregationHeader) marshal(buf []byte) []byte {
+	b := h.W << 4
+
+	if h.Z {
+		b |= 0x80
+	}
+	if h.Y {
+		b |= 0x40
+	}
+	if h.N {
+		b |= 0x08
+	}
+
+	return append(buf, b)
+}
@@ -0,0 +1,210 @@
+package rtpav1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/av1"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented OBU and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+// Decoder is a RTP/AV1 decoder.
+// Specification: https://aomediacodec.github.io/av1-rtp-spec/
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragments           [][]byte
+	fragmentsSize       int
+	frameBuffer         [][]byte
+	frameBufferSize     int
+	frameTimestamp      uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+}
+
+func (d *Decoder) resetFrameBuffer() {
+	d.frameBuffer = nil
+	d.frameBufferSize = 0
+}
+
+func (d *Decoder) addOBU(obu []byte) error {
+	if len(d.frameBuffer) >= av1.MaxOBUsPerTemporalUnit {
+		errCount := len(d.frameBuffer)
+		d.resetFrameBuffer()
+		return fmt.Errorf("OBU count (%d) exceeds maximum allowed (%d)",
+			errCount+1, av1.MaxOBUsPerTemporalUnit)
+	}
+
+	d.frameBufferSize += len(obu)
+	if d.frameBufferSize > av1.MaxTemporalUnitSize {
+		errSize := d.frameBufferSize
+		d.resetFrameBuffer()
+		return fmt.Errorf("temporal unit size (%d) is too big (maximum is %d)",
+			errSize, av1.MaxTemporalUnitSize)
+	}
+
+	d.frameBuffer = append(d.frameBuffer, obu)
+	return nil
+}
+
+// Decode decodes a temporal unit from a RTP/AV1 packet.
+// Temporal units are returned when the packet with the marker flag is received,
+// and are composed of OBUs without the obu_size field.
+// av1.ContainsKeyFrame() can be used to find the first key frame of a stream.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, time.Duration, error) {
+	var ah aggregationHeader
+	err := ah.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		d.resetFrameBuffer()
+		return nil, 0, err
+	}
+
+	payload := pkt.Payload[1:]
+
+	if len(d.frameBuffer) != 0 || len(d.fragments) != 0 {
+		if pkt.Timestamp != d.frameTimestamp {
+			d.resetFragments()
+			d.resetFrameBuffer()
+			return nil, 0, fmt.Errorf("received a packet with a different timestamp")
+		}
+	}
+	d.frameTimestamp = pkt.Timestamp
+
+	if ah.Z {
+		if len(d.fragments) == 0 {
+			d.resetFrameBuffer()
+			if !d.firstPacketReceived {
+				return nil, 0, ErrNonStartingPacketAndNoPrevious
+			}
+			return nil, 0, fmt.Errorf("received a non-starting fragment")
+		}
+	} else if len(d.fragments) != 0 {
+		d.resetFragments()
+		d.resetFrameBuffer()
+		return nil, 0, fmt.Errorf("received a starting fragment while a fragmented OBU is pending")
+	}
+
+	d.firstPacketReceived = true
+
+	// parse OBU elements
+	var elements [][]byte
+	for i := 0; len(payload) > 0; i++ {
+		var size int
+
+		if ah.W == 0 || i < int(ah.W-1) {
+			v, n, err := av1.LEB128Unmarshal(payload)
+			if err != nil {
+				d.resetFragments()
+				d.resetFrameBuffer()
+				return nil, 0, err
+			}
+			payload = payload[n:]
+
+			size = int(v)
+			if size > len(payload) {
+				d.resetFragments()
+				d.resetFrameBuffer()
+				return nil, 0, fmt.Errorf("invalid OBU element length")
+			}
+		} else {
+			size = len(payload)
+		}
+
+		elements = append(elements, payload[:size])
+		payload = payload[size:]
+
+		if ah.W != 0 && i == int(ah.W-1) {
+			break
+		}
+	}
+
+	if len(elements) == 0 {
+		d.resetFragments()
+		d.resetFrameBuffer()
+		return nil, 0, fmt.Errorf("payload does not contain any OBU element")
+	}
+
+	if ah.W != 0 && len(elements) != int(ah.W) {
+		d.resetFragments()
+		d.resetFrameBuffer()
+		return nil, 0, fmt.Errorf("OBU element count (%d) does not match W (%d)", len(elements), ah.W)
+	}
+
+	for i, el := range elements {
+		isFirst := (i == 0)
+		isLast := (i == len(elements)-1)
+
+		if (isFirst && ah.Z) || (isLast && ah.Y) {
+			d.fragmentsSize += len(el)
+			if d.fragmentsSize > av1.MaxTemporalUnitSize {
+				errSize := d.fragmentsSize
+				d.resetFragments()
+				d.resetFrameBuffer()
+				return nil, 0, fmt.Errorf("OBU size (%d) is too big (maximum is %d)",
+					errSize, av1.MaxTemporalUnitSize)
+			}
+
+			d.fragments = append(d.fragments, el)
+
+			if isLast && ah.Y {
+				continue
+			}
+
+			obu := make([]byte, d.fragmentsSize)
+			n := 0
+			for _, f := range d.fragments {
+				n += copy(obu[n:], f)
+			}
+			d.resetFragments()
+
+			err := d.addOBU(obu)
+			if err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		err := d.addOBU(el)
+		if err != nil {
+			d.resetFragments()
+			return nil, 0, err
+		}
+	}
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	if len(d.fragments) != 0 {
+		d.resetFragments()
+		d.resetFrameBuffer()
+		return nil, 0, fmt.Errorf("received the marker flag while a fragmented OBU is pending")
+	}
+
+	ret := d.frameBuffer
+	d.resetFrameBuffer()
+
+	return ret, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
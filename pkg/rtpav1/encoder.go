@@ -0,0 +1,186 @@
+package rtpav1
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/av1"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+type encoderPacket struct {
+	z        bool
+	y        bool
+	elements [][]byte
+	size     int
+}
+
+func (p *encoderPacket) marshal(n bool) []byte {
+	var w uint8
+	if len(p.elements) <= 3 {
+		w = uint8(len(p.elements))
+	}
+
+	buf := make([]byte, 0, p.size)
+	buf = aggregationHeader{
+		Z: p.z,
+		Y: p.y,
+		W: w,
+		N: n,
+	}.marshal(buf)
+
+	for i, el := range p.elements {
+		if w == 0 || i < int(w-1) {
+			var tmp [8]byte
+			le := av1.LEB128MarshalTo(uint32(len(el)), tmp[:])
+			buf = append(buf, tmp[:le]...)
+		}
+		buf = append(buf, el...)
+	}
+
+	return buf
+}
+
+// Encoder is a RTP/AV1 encoder.
+// Specification: https://aomediacodec.github.io/av1-rtp-spec/
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes a temporal unit into RTP/AV1 packets.
+// OBUs must not contain the obu_size field.
+// Temporal delimiters and tile lists are not transmitted, as required by the specification.
+func (e *Encoder) Encode(tu [][]byte, pts time.Duration) ([]*rtp.Packet, error) {
+	var packets []*encoderPacket
+	cur := &encoderPacket{size: 1}
+	newSequence := false
+
+	for _, obu := range tu {
+		var h av1.OBUHeader
+		err := h.Unmarshal(obu)
+		if err != nil {
+			return nil, err
+		}
+
+		if h.HasSize {
+			return nil, fmt.Errorf("OBUs must not contain the obu_size field")
+		}
+
+		switch h.Type {
+		case av1.OBUTypeTemporalDelimiter, av1.OBUTypeTileList:
+			continue
+
+		case av1.OBUTypeSequenceHeader:
+			newSequence = true
+		}
+
+		data := obu
+
+		for {
+			avail := e.PayloadMaxSize - cur.size - av1.LEB128MarshalSize(uint32(len(data)))
+
+			if avail <= 0 {
+				if len(cur.elements) == 0 {
+					return nil, fmt.Errorf("payload max size is too small")
+				}
+
+				packets = append(packets, cur)
+				cur = &encoderPacket{size: 1}
+				continue
+			}
+
+			if len(data) <= avail {
+				cur.elements = append(cur.elements, data)
+				cur.size += av1.LEB128MarshalSize(uint32(len(data))) + len(data)
+				break
+			}
+
+			// fragment OBU
+			cur.elements = append(cur.elements, data[:avail])
+			cur.y = true
+			packets = append(packets, cur)
+			cur = &encoderPacket{size: 1, z: true}
+			data = data[avail:]
+		}
+	}
+
+	if len(cur.elements) != 0 {
+		packets = append(packets, cur)
+	}
+
+	if len(packets) == 0 {
+		return nil, fmt.Errorf("temporal unit does not contain any OBU")
+	}
+
+	ret := make([]*rtp.Packet, len(packets))
+	encPTS := e.encodeTimestamp(pts)
+
+	for i, p := range packets {
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         i == (len(packets) - 1),
+			},
+			Payload: p.marshal(i == 0 && newSequence),
+		}
+		e.sequenceNumber++
+	}
+
+	return ret, nil
+}
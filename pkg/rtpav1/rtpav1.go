@@ -0,0 +1,7 @@
+// Package rtpav1 contains a RTP/AV1 decoder and encoder.
+package rtpav1
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // AV1 always uses 90khz
+)
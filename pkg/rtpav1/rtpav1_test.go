@@ -0,0 +1,443 @@
+package rtpav1
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var bigOBU = mergeBytes(
+	[]byte{0x30},
+	bytes.Repeat([]byte{0x01, 0x02, 0x03}, 1000),
+)
+
+var cases = []struct {
+	name string
+	tu   [][]byte
+	pts  time.Duration
+	pkts []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{
+			{0x08, 0x00, 0x00, 0x00, 0x2c, 0xcf, 0xf7, 0xff},
+			{0x30, 0x10, 0x01, 0x02, 0x03},
+		},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x28, 0x08, 0x08, 0x00, 0x00, 0x00, 0x2c, 0xcf,
+					0xf7, 0xff, 0x30, 0x10, 0x01, 0x02, 0x03,
+				},
+			},
+		},
+	},
+	{
+		"many obus",
+		[][]byte{
+			{0x28, 0x01},
+			{0x28, 0x02},
+			{0x28, 0x03},
+			{0x30, 0x30, 0x01},
+		},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x02, 0x28, 0x01, 0x02, 0x28, 0x02, 0x02,
+					0x28, 0x03, 0x03, 0x30, 0x30, 0x01,
+				},
+			},
+		},
+	},
+	{
+		"fragmented",
+		[][]byte{bigOBU},
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes([]byte{0x50}, bigOBU[:1457]),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes([]byte{0xd0}, bigOBU[1457:2914]),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes([]byte{0x90}, bigOBU[2914:]),
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x10, 0x30, 0x10},
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var tu [][]byte
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				var pts time.Duration
+				tu, pts, err = d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, ca.pts, pts)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.tu, tu)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"missing payload",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"no elements",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x00},
+				},
+			},
+			"payload does not contain any OBU element",
+		},
+		{
+			"invalid element length",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x00, 0x05, 0x30},
+				},
+			},
+			"invalid OBU element length",
+		},
+		{
+			"wrong element count",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x30, 0x01, 0x30},
+				},
+			},
+			"OBU element count (1) does not match W (3)",
+		},
+		{
+			"non-starting fragment",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x10, 0x30, 0x10},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527318,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x90, 0x01, 0x02},
+				},
+			},
+			"received a non-starting fragment",
+		},
+		{
+			"missing continuation",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x50, 0x30, 0x10},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x10, 0x30, 0x10},
+				},
+			},
+			"received a starting fragment while a fragmented OBU is pending",
+		},
+		{
+			"different timestamp",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x50, 0x30, 0x10},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527318,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x90, 0x01, 0x02},
+				},
+			},
+			"received a packet with a different timestamp",
+		},
+		{
+			"marker with pending fragment",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x50, 0x30, 0x10},
+				},
+			},
+			"received the marker flag while a fragmented OBU is pending",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestDecodeNonStartingPacketAndNoPrevious(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	_, _, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x90, 0x01, 0x02},
+	})
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType: 96,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.tu, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeSkipTemporalDelimiter(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+
+	pkts, err := e.Encode([][]byte{{0x10}, {0x30, 0x10}}, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pkts))
+	require.Equal(t, []byte{0x10, 0x30, 0x10}, pkts[0].Payload)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+
+	_, err := e.Encode([][]byte{{0x32, 0x01, 0x10}}, 0)
+	require.EqualError(t, err, "OBUs must not contain the obu_size field")
+
+	_, err = e.Encode([][]byte{{0x10}}, 0)
+	require.EqualError(t, err, "temporal unit does not contain any OBU")
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
@@ -0,0 +1,178 @@
+package rtpfec
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// number of media packets that are kept in the history.
+	// it must be a power of two.
+	historySize = 512
+
+	// maximum number of repair packets that are waiting for missing media packets.
+	maxPendingRepairs = 64
+)
+
+type historyEntry struct {
+	sequenceNumber uint16
+	byts           []byte
+}
+
+type pendingRepair struct {
+	header *header
+	ssrc   uint32
+}
+
+// Decoder is a utility that rebuilds lost RTP packets from repair packets.
+type Decoder struct {
+	scheme Scheme
+
+	mediaSSRC      uint32
+	mediaSSRCKnown bool
+	history        []*historyEntry
+	pending        []*pendingRepair
+}
+
+// NewDecoder allocates a Decoder.
+func NewDecoder(scheme Scheme) *Decoder {
+	return &Decoder{
+		scheme:  scheme,
+		history: make([]*historyEntry, historySize),
+	}
+}
+
+// ProcessMedia adds a media packet to the history.
+// It returns packets that have been recovered thanks to it, if any.
+func (d *Decoder) ProcessMedia(pkt *rtp.Packet) []*rtp.Packet {
+	byts, err := pkt.Marshal()
+	if err != nil {
+		return nil
+	}
+
+	d.mediaSSRC = pkt.SSRC
+	d.mediaSSRCKnown = true
+	d.store(pkt.SequenceNumber, byts)
+
+	if len(d.pending) == 0 {
+		return nil
+	}
+
+	return d.recover()
+}
+
+// ProcessRepair decodes a repair packet.
+// It returns packets that have been recovered thanks to it, if any.
+func (d *Decoder) ProcessRepair(pkt *rtp.Packet) ([]*rtp.Packet, error) {
+	var h header
+	var ssrc uint32
+
+	if d.scheme == SchemeULPFEC {
+		err := h.unmarshalULPFEC(pkt.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if !d.mediaSSRCKnown {
+			return nil, nil
+		}
+		ssrc = d.mediaSSRC
+	} else {
+		err := h.unmarshalFlexFEC(pkt.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pkt.CSRC) == 0 {
+			return nil, fmt.Errorf("SSRC of protected stream is missing")
+		}
+		ssrc = pkt.CSRC[0]
+	}
+
+	if len(h.offsets) == 0 {
+		return nil, nil
+	}
+
+	if len(d.pending) >= maxPendingRepairs {
+		d.pending = d.pending[1:]
+	}
+	d.pending = append(d.pending, &pendingRepair{
+		header: &h,
+		ssrc:   ssrc,
+	})
+
+	return d.recover(), nil
+}
+
+func (d *Decoder) store(sequenceNumber uint16, byts []byte) {
+	d.history[sequenceNumber&(historySize-1)] = &historyEntry{
+		sequenceNumber: sequenceNumber,
+		byts:           byts,
+	}
+}
+
+func (d *Decoder) lookup(sequenceNumber uint16) []byte {
+	entry := d.history[sequenceNumber&(historySize-1)]
+	if entry == nil || entry.sequenceNumber != sequenceNumber {
+		return nil
+	}
+	return entry.byts
+}
+
+// recover rebuilds media packets with pending repair packets.
+// A recovered packet may allow other repair packets to be used,
+// therefore the procedure is repeated until there are no changes.
+func (d *Decoder) recover() []*rtp.Packet {
+	var ret []*rtp.Packet
+
+	for {
+		recovered := false
+
+		for i := 0; i < len(d.pending); {
+			r := d.pending[i]
+
+			var missing []uint16
+			var others [][]byte
+			for _, o := range r.header.offsets {
+				seq := r.header.snBase + o
+				if byts := d.lookup(seq); byts != nil {
+					others = append(others, byts)
+				} else {
+					missing = append(missing, seq)
+				}
+			}
+
+			// repair packet can't be used yet
+			if len(missing) > 1 {
+				i++
+				continue
+			}
+
+			d.pending = append(d.pending[:i], d.pending[i+1:]...)
+
+			if len(missing) == 0 {
+				continue
+			}
+
+			byts, err := r.header.recover(others, missing[0], r.ssrc)
+			if err != nil {
+				continue
+			}
+
+			var pkt rtp.Packet
+			err = pkt.Unmarshal(byts)
+			if err != nil {
+				continue
+			}
+
+			d.store(missing[0], byts)
+			ret = append(ret, &pkt)
+			recovered = true
+		}
+
+		if !recovered {
+			return ret
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package rtpfec
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pion/rtp"
+)
+
+// Encoder is a utility that generates repair packets from outgoing RTP packets.
+// Repair packets are sent in a separate stream, with their own
+// payload type and SSRC.
+type Encoder struct {
+	scheme      Scheme
+	payloadType uint8
+	groupSize   int
+	ssrc        uint32
+	mutex       sync.Mutex
+
+	sequenceNumber uint16
+	group          [][]byte
+}
+
+// NewEncoder allocates an Encoder.
+// payloadType is the payload type of repair packets.
+// groupSize is the number of media packets protected by each repair packet;
+// lower values allow to recover more packets, at the cost of a higher bandwidth.
+func NewEncoder(scheme Scheme, payloadType uint8, groupSize int) (*Encoder, error) {
+	if _, ok := schemeLabels[scheme]; !ok {
+		return nil, fmt.Errorf("unsupported scheme: %d", scheme)
+	}
+
+	if groupSize < 1 || groupSize > MaxGroupSize {
+		return nil, fmt.Errorf("invalid group size: %d", groupSize)
+	}
+
+	return &Encoder{
+		scheme:         scheme,
+		payloadType:    payloadType,
+		groupSize:      groupSize,
+		ssrc:           randUint32(),
+		sequenceNumber: uint16(randUint32()),
+	}, nil
+}
+
+// PayloadType returns the payload type of repair packets.
+func (e *Encoder) PayloadType() uint8 {
+	return e.payloadType
+}
+
+// Encode adds a RTP packet to the current group.
+// byts is the marshaled packet; it is copied.
+// It returns a repair packet when the group is complete, otherwise nil.
+func (e *Encoder) Encode(byts []byte) *rtp.Packet {
+	if len(byts) < 12 {
+		return nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// start a new group when the packet is not consecutive to the previous ones
+	if len(e.group) != 0 {
+		first := e.group[0]
+		if sequenceNumber(byts) != sequenceNumber(first)+uint16(len(e.group)) ||
+			ssrc(byts) != ssrc(first) {
+			e.group = e.group[:0]
+		}
+	}
+
+	e.group = append(e.group, append([]byte(nil), byts...))
+
+	if len(e.group) < e.groupSize {
+		return nil
+	}
+
+	group := e.group
+	e.group = nil
+
+	var s xorState
+	offsets := make([]uint16, len(group))
+	for i, byts := range group {
+		s.add(byts)
+		offsets[i] = uint16(i)
+	}
+	h := s.header(sequenceNumber(group[0]), offsets)
+
+	last := group[len(group)-1]
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    e.payloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      uint32(last[4])<<24 | uint32(last[5])<<16 | uint32(last[6])<<8 | uint32(last[7]),
+			SSRC:           e.ssrc,
+		},
+	}
+	e.sequenceNumber++
+
+	if e.scheme == SchemeULPFEC {
+		pkt.Payload = h.marshalULPFEC()
+	} else {
+		// the CSRC list contains the SSRC of the protected stream
+		pkt.CSRC = []uint32{ssrc(group[0])}
+		pkt.Payload = h.marshalFlexFEC()
+	}
+
+	return pkt
+}
+
+func sequenceNumber(byts []byte) uint16 {
+	return uint16(byts[2])<<8 | uint16(byts[3])
+}
+
+func ssrc(byts []byte) uint32 {
+	return uint32(byts[8])<<24 | uint32(byts[9])<<16 | uint32(byts[10])<<8 | uint32(byts[11])
+}
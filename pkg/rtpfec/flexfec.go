@@ -0,0 +1,136 @@
+package rtpfec
+
+import (
+	"fmt"
+)
+
+// FlexFEC repair packets contain a FEC header with a flexible mask (RFC 8627, section 4.2.2.1).
+// The SSRC of the protected stream is stored in the CSRC list of the repair packet.
+//
+//  0                   1                   2                   3
+//  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |R|F|P|X|  CC   |M| PT recovery |        length recovery        |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |                          TS recovery                          |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |           SN base_i           |k|          Mask [0-14]        |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |k|                   Mask [15-45] (optional)                   |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |                     Mask [46-109] (optional)                  |
+// |                                                               |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+const (
+	flexfecHeaderLen = 10
+)
+
+// sizes of the mask, in bytes, and number of bits that they can contain.
+var flexfecMaskSizes = []struct {
+	bytes int
+	bits  int
+}{
+	{2, 15},
+	{6, 46},
+	{14, 110},
+}
+
+// flexfecMaskBitPos returns the position of a mask bit, skipping k bits.
+func flexfecMaskBitPos(i int) int {
+	if i < 15 {
+		return i + 1
+	}
+	return i + 2
+}
+
+func (h *header) marshalFlexFEC() []byte {
+	maskSize := 0
+	for _, o := range h.offsets {
+		for maskSize < len(flexfecMaskSizes)-1 && int(o) >= flexfecMaskSizes[maskSize].bits {
+			maskSize++
+		}
+	}
+	maskBytes := flexfecMaskSizes[maskSize].bytes
+
+	byts := make([]byte, flexfecHeaderLen+2+maskBytes+len(h.payload))
+
+	byts[0] = h.recovery0
+	byts[1] = h.recovery1
+	byts[2] = byte(h.lengthRecovery >> 8)
+	byts[3] = byte(h.lengthRecovery)
+	byts[4] = byte(h.tsRecovery >> 24)
+	byts[5] = byte(h.tsRecovery >> 16)
+	byts[6] = byte(h.tsRecovery >> 8)
+	byts[7] = byte(h.tsRecovery)
+	byts[8] = byte(h.snBase >> 8)
+	byts[9] = byte(h.snBase)
+
+	mask := byts[10 : 10+maskBytes]
+
+	// k bits are set in the last part of the mask
+	switch maskBytes {
+	case 2:
+		mask[0] |= 0x80
+	case 6:
+		mask[2] |= 0x80
+	}
+
+	for _, o := range h.offsets {
+		pos := flexfecMaskBitPos(int(o))
+		mask[pos/8] |= 1 << (7 - pos%8)
+	}
+
+	copy(byts[10+maskBytes:], h.payload)
+
+	return byts
+}
+
+func (h *header) unmarshalFlexFEC(byts []byte) error {
+	if len(byts) < flexfecHeaderLen+2 {
+		return fmt.Errorf("payload is too short")
+	}
+
+	if (byts[0] >> 6) != 0 {
+		return fmt.Errorf("retransmissions and fixed masks are not supported")
+	}
+
+	h.recovery0 = byts[0] & 0x3F
+	h.recovery1 = byts[1]
+	h.lengthRecovery = uint16(byts[2])<<8 | uint16(byts[3])
+	h.tsRecovery = uint32(byts[4])<<24 | uint32(byts[5])<<16 | uint32(byts[6])<<8 | uint32(byts[7])
+	h.snBase = uint16(byts[8])<<8 | uint16(byts[9])
+
+	maskBytes := 2
+	maskBits := 15
+	if (byts[10] >> 7) == 0 {
+		if len(byts) < flexfecHeaderLen+6 {
+			return fmt.Errorf("payload is too short")
+		}
+
+		maskBytes = 6
+		maskBits = 46
+		if (byts[12] >> 7) == 0 {
+			if len(byts) < flexfecHeaderLen+14 {
+				return fmt.Errorf("payload is too short")
+			}
+
+			maskBytes = 14
+			maskBits = 110
+		}
+	}
+
+	mask := byts[10 : 10+maskBytes]
+
+	h.offsets = nil
+	for o := 0; o < maskBits; o++ {
+		pos := flexfecMaskBitPos(o)
+		if (mask[pos/8] & (1 << (7 - pos%8))) != 0 {
+			h.offsets = append(h.offsets, uint16(o))
+		}
+	}
+
+	h.payload = byts[10+maskBytes:]
+
+	return nil
+}
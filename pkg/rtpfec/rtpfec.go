@@ -0,0 +1,136 @@
+// Package rtpfec contains utilities to protect RTP streams with
+// forward error correction (FEC), through the ULPFEC (RFC 5109)
+// and FlexFEC (RFC 8627) payload formats.
+//
+// Repair packets are generated by XOR-ing groups of consecutive media packets,
+// therefore a single lost packet of each group can be recovered.
+package rtpfec
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// MaxGroupSize is the maximum number of media packets that can be protected
+// by a single repair packet.
+const MaxGroupSize = 48
+
+// Scheme is a FEC scheme.
+type Scheme int
+
+// FEC schemes.
+const (
+	// SchemeULPFEC is the generic FEC scheme of RFC 5109, with a single protection level.
+	SchemeULPFEC Scheme = iota
+
+	// SchemeFlexFEC is the flexible FEC scheme of RFC 8627, with a flexible mask.
+	SchemeFlexFEC
+)
+
+var schemeLabels = map[Scheme]string{
+	SchemeULPFEC:  "ulpfec",
+	SchemeFlexFEC: "flexfec",
+}
+
+// String implements fmt.Stringer.
+// It returns the encoding name used in SDP.
+func (s Scheme) String() string {
+	if l, ok := schemeLabels[s]; ok {
+		return l
+	}
+	return "unknown"
+}
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// header contains the fields that are shared by ULPFEC and FlexFEC repair packets.
+type header struct {
+	recovery0      byte // P, X, CC recovery
+	recovery1      byte // M, PT recovery
+	snBase         uint16
+	offsets        []uint16
+	tsRecovery     uint32
+	lengthRecovery uint16
+	payload        []byte
+}
+
+// xorState accumulates the bit strings of some media packets.
+type xorState struct {
+	byte0   byte
+	byte1   byte
+	ts      uint32
+	length  uint16
+	payload []byte
+}
+
+// add adds a marshaled RTP packet to the state.
+func (s *xorState) add(byts []byte) {
+	s.byte0 ^= byts[0]
+	s.byte1 ^= byts[1]
+	s.ts ^= uint32(byts[4])<<24 | uint32(byts[5])<<16 | uint32(byts[6])<<8 | uint32(byts[7])
+	s.length ^= uint16(len(byts) - 12)
+
+	data := byts[12:]
+	if len(data) > len(s.payload) {
+		s.payload = append(s.payload, make([]byte, len(data)-len(s.payload))...)
+	}
+	for i, b := range data {
+		s.payload[i] ^= b
+	}
+}
+
+func (s *xorState) header(snBase uint16, offsets []uint16) *header {
+	return &header{
+		recovery0:      s.byte0 & 0x3F,
+		recovery1:      s.byte1,
+		snBase:         snBase,
+		offsets:        offsets,
+		tsRecovery:     s.ts,
+		lengthRecovery: s.length,
+		payload:        s.payload,
+	}
+}
+
+// recover rebuilds the missing packet of a group, given the other packets.
+func (h *header) recover(others [][]byte, seq uint16, ssrc uint32) ([]byte, error) {
+	s := xorState{
+		payload: make([]byte, len(h.payload)),
+	}
+	for _, byts := range others {
+		if len(byts)-12 > len(h.payload) {
+			return nil, fmt.Errorf("protected packet is bigger than protection length")
+		}
+		s.add(byts)
+	}
+
+	length := int(h.lengthRecovery ^ s.length)
+	if length > len(h.payload) {
+		return nil, fmt.Errorf("invalid recovered length")
+	}
+
+	ts := h.tsRecovery ^ s.ts
+
+	byts := make([]byte, 12+length)
+	byts[0] = 0x80 | ((h.recovery0 ^ s.byte0) & 0x3F)
+	byts[1] = h.recovery1 ^ s.byte1
+	byts[2] = byte(seq >> 8)
+	byts[3] = byte(seq)
+	byts[4] = byte(ts >> 24)
+	byts[5] = byte(ts >> 16)
+	byts[6] = byte(ts >> 8)
+	byts[7] = byte(ts)
+	byts[8] = byte(ssrc >> 24)
+	byts[9] = byte(ssrc >> 16)
+	byts[10] = byte(ssrc >> 8)
+	byts[11] = byte(ssrc)
+
+	for i := 0; i < length; i++ {
+		byts[12+i] = h.payload[i] ^ s.payload[i]
+	}
+
+	return byts, nil
+}
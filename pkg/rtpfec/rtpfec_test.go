@@ -0,0 +1,182 @@
+package rtpfec
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func testPacket(i int) *rtp.Packet {
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         i%3 == 0,
+			PayloadType:    96,
+			SequenceNumber: uint16(65530 + i),
+			Timestamp:      uint32(1234 + i*90),
+			SSRC:           0x38F27A2F,
+			CSRC:           []uint32{},
+		},
+		Payload: make([]byte, 1+i%7),
+	}
+	for j := range pkt.Payload {
+		pkt.Payload[j] = byte(i + j)
+	}
+	return pkt
+}
+
+func TestRecover(t *testing.T) {
+	for _, scheme := range []Scheme{SchemeULPFEC, SchemeFlexFEC} {
+		for _, groupSize := range []int{1, 4, 15, 16, 17, 47, 48} {
+			t.Run(scheme.String()+"_"+strconv.Itoa(groupSize), func(t *testing.T) {
+				e, err := NewEncoder(scheme, 98, groupSize)
+				require.NoError(t, err)
+				d := NewDecoder(scheme)
+
+				lost := groupSize / 2
+				var repair *rtp.Packet
+
+				for i := 0; i < groupSize; i++ {
+					pkt := testPacket(i)
+					byts, err := pkt.Marshal()
+					require.NoError(t, err)
+
+					repair = e.Encode(byts)
+					if i != groupSize-1 {
+						require.Nil(t, repair)
+					}
+
+					if i != lost {
+						require.Empty(t, d.ProcessMedia(pkt))
+					}
+				}
+
+				require.NotNil(t, repair)
+				require.Equal(t, uint8(98), repair.PayloadType)
+
+				// pass the repair packet through the network
+				byts, err := repair.Marshal()
+				require.NoError(t, err)
+				var dec rtp.Packet
+				err = dec.Unmarshal(byts)
+				require.NoError(t, err)
+
+				recovered, err := d.ProcessRepair(&dec)
+				if groupSize == 1 {
+					// the only packet is lost, therefore the SSRC is unknown with ULPFEC
+					if scheme == SchemeULPFEC {
+						require.NoError(t, err)
+						require.Empty(t, recovered)
+						return
+					}
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, []*rtp.Packet{testPacket(lost)}, recovered)
+			})
+		}
+	}
+}
+
+func TestRecoverLate(t *testing.T) {
+	for _, scheme := range []Scheme{SchemeULPFEC, SchemeFlexFEC} {
+		t.Run(scheme.String(), func(t *testing.T) {
+			e, err := NewEncoder(scheme, 98, 4)
+			require.NoError(t, err)
+			d := NewDecoder(scheme)
+
+			var repair *rtp.Packet
+			for i := 0; i < 4; i++ {
+				byts, err := testPacket(i).Marshal()
+				require.NoError(t, err)
+				repair = e.Encode(byts)
+			}
+
+			// packets 1 and 2 are missing when the repair packet is received
+			require.Empty(t, d.ProcessMedia(testPacket(0)))
+			require.Empty(t, d.ProcessMedia(testPacket(3)))
+
+			recovered, err := d.ProcessRepair(repair)
+			require.NoError(t, err)
+			require.Empty(t, recovered)
+
+			// packet 2 arrives late
+			require.Equal(t, []*rtp.Packet{testPacket(1)}, d.ProcessMedia(testPacket(2)))
+		})
+	}
+}
+
+func TestEncoderDiscontinuity(t *testing.T) {
+	e, err := NewEncoder(SchemeULPFEC, 98, 3)
+	require.NoError(t, err)
+
+	for _, i := range []int{0, 1, 5, 6} {
+		byts, err := testPacket(i).Marshal()
+		require.NoError(t, err)
+		require.Nil(t, e.Encode(byts))
+	}
+
+	byts, err := testPacket(7).Marshal()
+	require.NoError(t, err)
+	repair := e.Encode(byts)
+	require.NotNil(t, repair)
+
+	var h header
+	err = h.unmarshalULPFEC(repair.Payload)
+	require.NoError(t, err)
+	require.Equal(t, testPacket(5).SequenceNumber, h.snBase)
+	require.Equal(t, []uint16{0, 1, 2}, h.offsets)
+}
+
+func TestEncoderErrors(t *testing.T) {
+	_, err := NewEncoder(SchemeULPFEC, 98, 0)
+	require.EqualError(t, err, "invalid group size: 0")
+
+	_, err = NewEncoder(SchemeFlexFEC, 98, MaxGroupSize+1)
+	require.EqualError(t, err, "invalid group size: 49")
+
+	_, err = NewEncoder(Scheme(5), 98, 4)
+	require.EqualError(t, err, "unsupported scheme: 5")
+}
+
+func TestDecoderErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name   string
+		scheme Scheme
+		pkt    *rtp.Packet
+		err    string
+	}{
+		{
+			"ulpfec too short",
+			SchemeULPFEC,
+			&rtp.Packet{Payload: []byte{0x00, 0x01}},
+			"payload is too short",
+		},
+		{
+			"ulpfec extension",
+			SchemeULPFEC,
+			&rtp.Packet{Payload: []byte{0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80, 0}},
+			"extension flag is set",
+		},
+		{
+			"flexfec fixed mask",
+			SchemeFlexFEC,
+			&rtp.Packet{Payload: []byte{0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80, 0}},
+			"retransmissions and fixed masks are not supported",
+		},
+		{
+			"flexfec missing ssrc",
+			SchemeFlexFEC,
+			&rtp.Packet{Payload: []byte{0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xC0, 0}},
+			"SSRC of protected stream is missing",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := NewDecoder(ca.scheme)
+			_, err := d.ProcessRepair(ca.pkt)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
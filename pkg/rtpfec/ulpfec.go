@@ -0,0 +1,98 @@
+package rtpfec
+
+import (
+	"fmt"
+)
+
+// ULPFEC repair packets contain a FEC header and a single FEC level header (RFC 5109, section 7).
+//
+//  0                   1                   2                   3
+//  0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |E|L|P|X|  CC   |M| PT recovery |            SN base            |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |                          TS recovery                          |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |        length recovery        |       Protection Length       |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |             mask              | mask cont. (present if L = 1) |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+const (
+	ulpfecHeaderLen     = 10
+	ulpfecShortMaskBits = 16
+	ulpfecLongMaskBits  = 48
+)
+
+func (h *header) marshalULPFEC() []byte {
+	maskBits := ulpfecShortMaskBits
+	var l byte
+	for _, o := range h.offsets {
+		if o >= ulpfecShortMaskBits {
+			maskBits = ulpfecLongMaskBits
+			l = 1
+		}
+	}
+
+	byts := make([]byte, ulpfecHeaderLen+4+(maskBits/8-2)+len(h.payload))
+
+	byts[0] = l<<6 | h.recovery0
+	byts[1] = h.recovery1
+	byts[2] = byte(h.snBase >> 8)
+	byts[3] = byte(h.snBase)
+	byts[4] = byte(h.tsRecovery >> 24)
+	byts[5] = byte(h.tsRecovery >> 16)
+	byts[6] = byte(h.tsRecovery >> 8)
+	byts[7] = byte(h.tsRecovery)
+	byts[8] = byte(h.lengthRecovery >> 8)
+	byts[9] = byte(h.lengthRecovery)
+	byts[10] = byte(len(h.payload) >> 8)
+	byts[11] = byte(len(h.payload))
+
+	mask := byts[12 : 12+maskBits/8]
+	for _, o := range h.offsets {
+		mask[o/8] |= 1 << (7 - o%8)
+	}
+
+	copy(byts[12+maskBits/8:], h.payload)
+
+	return byts
+}
+
+func (h *header) unmarshalULPFEC(byts []byte) error {
+	if len(byts) < ulpfecHeaderLen+4 {
+		return fmt.Errorf("payload is too short")
+	}
+
+	if (byts[0] >> 7) != 0 {
+		return fmt.Errorf("extension flag is set")
+	}
+
+	maskBits := ulpfecShortMaskBits
+	if ((byts[0] >> 6) & 0x01) != 0 {
+		maskBits = ulpfecLongMaskBits
+	}
+
+	h.recovery0 = byts[0] & 0x3F
+	h.recovery1 = byts[1]
+	h.snBase = uint16(byts[2])<<8 | uint16(byts[3])
+	h.tsRecovery = uint32(byts[4])<<24 | uint32(byts[5])<<16 | uint32(byts[6])<<8 | uint32(byts[7])
+	h.lengthRecovery = uint16(byts[8])<<8 | uint16(byts[9])
+	protectionLength := int(byts[10])<<8 | int(byts[11])
+
+	n := 12 + maskBits/8
+	if len(byts) < n+protectionLength {
+		return fmt.Errorf("payload is too short")
+	}
+
+	h.offsets = nil
+	for o := 0; o < maskBits; o++ {
+		if (byts[12+o/8] & (1 << (7 - o%8))) != 0 {
+			h.offsets = append(h.offsets, uint16(o))
+		}
+	}
+
+	h.payload = byts[n : n+protectionLength]
+
+	return nil
+}
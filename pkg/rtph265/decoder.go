@@ -0,0 +1,241 @@
+package rtph265
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/h265"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented NALU and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragmentation unit without any previous starting fragmentation unit")
+
+// Decoder is a RTP/H265 decoder.
+type Decoder struct {
+	// indicates that NALUs have an additional field that specifies the decoding order.
+	// It must be set to the value of sprop-max-don-diff, if present.
+	// NALUs are returned in transmission order.
+	MaxDONDiff int
+
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragmentedMode      bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+
+	// for DecodeUntilMarker()
+	naluBuffer [][]byte
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedMode = false
+}
+
+// Decode decodes NALUs from a RTP/H265 packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, time.Duration, error) {
+	if !d.fragmentedMode {
+		if len(pkt.Payload) < 2 {
+			return nil, 0, fmt.Errorf("payload is too short")
+		}
+
+		typ := naluType((pkt.Payload[0] >> 1) & 0b111111)
+
+		switch typ {
+		case naluTypeAggregationUnit:
+			nalus, err := d.decodeAggregationUnit(pkt.Payload[2:])
+			if err != nil {
+				return nil, 0, err
+			}
+
+			d.firstPacketReceived = true
+			return nalus, d.timeDecoder.Decode(pkt.Timestamp), nil
+
+		case naluTypeFragmentationUnit: // first packet of a fragmented NALU
+			if len(pkt.Payload) < 3 {
+				return nil, 0, fmt.Errorf("invalid fragmentation unit (invalid size)")
+			}
+
+			start := pkt.Payload[2] >> 7
+			if start != 1 {
+				if !d.firstPacketReceived {
+					return nil, 0, ErrNonStartingPacketAndNoPrevious
+				}
+				return nil, 0, fmt.Errorf("invalid fragmentation unit (non-starting)")
+			}
+
+			end := (pkt.Payload[2] >> 6) & 0x01
+			if end != 0 {
+				return nil, 0, fmt.Errorf("invalid fragmentation unit (can't contain both a start and end bit)")
+			}
+
+			payload := pkt.Payload[3:]
+
+			if d.MaxDONDiff != 0 {
+				if len(payload) < 2 {
+					return nil, 0, fmt.Errorf("invalid fragmentation unit (invalid size)")
+				}
+				payload = payload[2:] // DONL
+			}
+
+			// rebuild the NALU header by using the fragmentation unit type
+			typ := pkt.Payload[2] & 0b111111
+			head := []byte{(pkt.Payload[0] & 0b10000001) | (typ << 1), pkt.Payload[1]}
+
+			d.fragmentedSize = len(head) + len(payload)
+			d.fragmentedParts = append(d.fragmentedParts, head, payload)
+			d.fragmentedMode = true
+
+			d.firstPacketReceived = true
+			return nil, 0, ErrMorePacketsNeeded
+
+		case naluTypePACI:
+			return nil, 0, fmt.Errorf("packet type not supported (%v)", typ)
+		}
+
+		nalu := pkt.Payload
+
+		if d.MaxDONDiff != 0 {
+			if len(nalu) < 4 {
+				return nil, 0, fmt.Errorf("payload is too short")
+			}
+
+			// remove DONL without modifying the input
+			tmp := make([]byte, len(nalu)-2)
+			copy(tmp, nalu[:2])
+			copy(tmp[2:], nalu[4:])
+			nalu = tmp
+		}
+
+		d.firstPacketReceived = true
+		return [][]byte{nalu}, d.timeDecoder.Decode(pkt.Timestamp), nil
+	}
+
+	// we are decoding a fragmented NALU
+
+	if len(pkt.Payload) < 3 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("invalid fragmentation unit (invalid size)")
+	}
+
+	typ := naluType((pkt.Payload[0] >> 1) & 0b111111)
+	if typ != naluTypeFragmentationUnit {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("expected FragmentationUnit packet, got %s packet", typ)
+	}
+
+	start := pkt.Payload[2] >> 7
+	if start == 1 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("invalid fragmentation unit (decoded two starting packets in a row)")
+	}
+
+	d.fragmentedSize += len(pkt.Payload[3:])
+	if d.fragmentedSize > h265.MaxNALUSize {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("NALU size (%d) is too big (maximum is %d)", d.fragmentedSize, h265.MaxNALUSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, pkt.Payload[3:])
+
+	end := (pkt.Payload[2] >> 6) & 0x01
+	if end != 1 {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := make([]byte, d.fragmentedSize)
+	n := 0
+	for _, p := range d.fragmentedParts {
+		n += copy(ret[n:], p)
+	}
+
+	d.resetFragments()
+
+	return [][]byte{ret}, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
+
+func (d *Decoder) decodeAggregationUnit(payload []byte) ([][]byte, error) {
+	var nalus [][]byte
+
+	for len(payload) > 0 {
+		if d.MaxDONDiff != 0 {
+			// DONL for the first NALU, DOND for the others
+			l := 2
+			if nalus != nil {
+				l = 1
+			}
+
+			if len(payload) < l {
+				return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+			}
+			payload = payload[l:]
+		}
+
+		if len(payload) < 2 {
+			return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+		}
+
+		size := uint16(payload[0])<<8 | uint16(payload[1])
+		payload = payload[2:]
+
+		// avoid final padding
+		if size == 0 {
+			break
+		}
+
+		if int(size) > len(payload) {
+			return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+		}
+
+		nalus = append(nalus, payload[:size])
+		payload = payload[size:]
+	}
+
+	if nalus == nil {
+		return nil, fmt.Errorf("aggregation unit doesn't contain any NALU")
+	}
+
+	return nalus, nil
+}
+
+// DecodeUntilMarker decodes NALUs from a RTP/H265 packet and puts them in a buffer.
+// When a packet has the marker flag (meaning that all the NALUs with the same PTS have
+// been received), the buffer is returned.
+func (d *Decoder) DecodeUntilMarker(pkt *rtp.Packet) ([][]byte, time.Duration, error) {
+	nalus, pts, err := d.Decode(pkt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if (len(d.naluBuffer) + len(nalus)) >= h265.MaxNALUsPerGroup {
+		return nil, 0, fmt.Errorf("number of NALUs contained inside a single group (%d) is too big (maximum is %d)",
+			len(d.naluBuffer)+len(nalus), h265.MaxNALUsPerGroup)
+	}
+
+	d.naluBuffer = append(d.naluBuffer, nalus...)
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := d.naluBuffer
+	d.naluBuffer = d.naluBuffer[:0]
+
+	return ret, pts, nil
+}
@@ -0,0 +1,310 @@
+package rtph265
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/H265 encoder.
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	// indicates that NALUs have an additional field that specifies the decoding order (optional).
+	// If not zero, it must be advertised in SDP with sprop-max-don-diff.
+	MaxDONDiff int
+
+	sequenceNumber uint16
+	don            uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes NALUs into RTP/H265 packets.
+func (e *Encoder) Encode(nalus [][]byte, pts time.Duration) ([]*rtp.Packet, error) {
+	var rets []*rtp.Packet
+	var batch [][]byte
+
+	// split NALUs into batches
+	for _, nalu := range nalus {
+		if e.lenAggregated(batch, nalu) <= e.PayloadMaxSize {
+			// add to existing batch
+			batch = append(batch, nalu)
+		} else {
+			// write batch
+			if batch != nil {
+				pkts, err := e.writeBatch(batch, pts, false)
+				if err != nil {
+					return nil, err
+				}
+				rets = append(rets, pkts...)
+			}
+
+			// initialize new batch
+			batch = [][]byte{nalu}
+		}
+	}
+
+	// write final batch
+	// marker is used to indicate when all NALUs with same PTS have been sent
+	pkts, err := e.writeBatch(batch, pts, true)
+	if err != nil {
+		return nil, err
+	}
+	rets = append(rets, pkts...)
+
+	return rets, nil
+}
+
+func (e *Encoder) writeBatch(nalus [][]byte, pts time.Duration, marker bool) ([]*rtp.Packet, error) {
+	if len(nalus) == 1 {
+		// the NALU fits into a single RTP packet
+		if e.lenSingle(nalus[0]) <= e.PayloadMaxSize {
+			return e.writeSingle(nalus[0], pts, marker)
+		}
+
+		// split the NALU into multiple fragmentation packet
+		return e.writeFragmented(nalus[0], pts, marker)
+	}
+
+	return e.writeAggregated(nalus, pts, marker)
+}
+
+func (e *Encoder) lenSingle(nalu []byte) int {
+	if e.MaxDONDiff != 0 {
+		return len(nalu) + 2 // DONL
+	}
+	return len(nalu)
+}
+
+func (e *Encoder) writeSingle(nalu []byte, pts time.Duration, marker bool) ([]*rtp.Packet, error) {
+	payload := nalu
+
+	if e.MaxDONDiff != 0 {
+		payload = make([]byte, len(nalu)+2)
+		copy(payload, nalu[:2])
+		payload[2] = byte(e.don >> 8)
+		payload[3] = byte(e.don)
+		copy(payload[4:], nalu[2:])
+		e.don++
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      e.encodeTimestamp(pts),
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return []*rtp.Packet{pkt}, nil
+}
+
+func (e *Encoder) writeFragmented(nalu []byte, pts time.Duration, marker bool) ([]*rtp.Packet, error) {
+	var ret []*rtp.Packet
+	encPTS := e.encodeTimestamp(pts)
+
+	head0 := nalu[0]
+	head1 := nalu[1]
+	typ := (head0 >> 1) & 0b111111
+	nalu = nalu[2:] // remove header
+
+	first := true
+
+	for len(nalu) > 0 {
+		overhead := 3 // payload header + FU header
+		if first && e.MaxDONDiff != 0 {
+			overhead += 2 // DONL
+		}
+
+		le := e.PayloadMaxSize - overhead
+		last := false
+		if len(nalu) <= le {
+			le = len(nalu)
+			last = true
+		}
+
+		data := make([]byte, overhead+le)
+		data[0] = (head0 & 0b10000001) | (uint8(naluTypeFragmentationUnit) << 1)
+		data[1] = head1
+		data[2] = typ
+		if first {
+			data[2] |= 1 << 7
+			if e.MaxDONDiff != 0 {
+				data[3] = byte(e.don >> 8)
+				data[4] = byte(e.don)
+				e.don++
+			}
+		}
+		if last {
+			data[2] |= 1 << 6
+		}
+		copy(data[overhead:], nalu[:le])
+		nalu = nalu[le:]
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         (last && marker),
+			},
+			Payload: data,
+		})
+
+		e.sequenceNumber++
+		first = false
+	}
+
+	return ret, nil
+}
+
+func (e *Encoder) lenAggregated(nalus [][]byte, addNALU []byte) int {
+	ret := 2 // header
+
+	for i, nalu := range nalus {
+		ret += e.lenAggregatedDON(i)
+		ret += 2         // size
+		ret += len(nalu) // nalu
+	}
+
+	if addNALU != nil {
+		ret += e.lenAggregatedDON(len(nalus))
+		ret += 2            // size
+		ret += len(addNALU) // nalu
+	}
+
+	return ret
+}
+
+func (e *Encoder) lenAggregatedDON(i int) int {
+	switch {
+	case e.MaxDONDiff == 0:
+		return 0
+
+	case i == 0:
+		return 2 // DONL
+
+	default:
+		return 1 // DOND
+	}
+}
+
+func (e *Encoder) writeAggregated(nalus [][]byte, pts time.Duration, marker bool) ([]*rtp.Packet, error) {
+	payload := make([]byte, e.lenAggregated(nalus, nil))
+
+	// header
+	// F is set if at least one of the NALUs has F set,
+	// LayerId and TID are the lowest among the ones of the NALUs
+	forbidden := uint8(0)
+	layerID := uint8(0b111111)
+	tid := uint8(0b111)
+	for _, nalu := range nalus {
+		forbidden |= nalu[0] >> 7
+		if v := ((nalu[0] & 0x01) << 5) | (nalu[1] >> 3); v < layerID {
+			layerID = v
+		}
+		if v := nalu[1] & 0b111; v < tid {
+			tid = v
+		}
+	}
+	payload[0] = (forbidden << 7) | (uint8(naluTypeAggregationUnit) << 1) | (layerID >> 5)
+	payload[1] = (layerID << 3) | tid
+	pos := 2
+
+	for i, nalu := range nalus {
+		if e.MaxDONDiff != 0 {
+			if i == 0 {
+				payload[pos] = byte(e.don >> 8)
+				payload[pos+1] = byte(e.don)
+				pos += 2
+			} else {
+				// NALUs are sent in decoding order, therefore DOND is always zero
+				payload[pos] = 0
+				pos++
+			}
+			e.don++
+		}
+
+		// size
+		naluLen := len(nalu)
+		payload[pos] = uint8(naluLen >> 8)
+		payload[pos+1] = uint8(naluLen)
+		pos += 2
+
+		// nalu
+		copy(payload[pos:], nalu)
+		pos += naluLen
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    e.PayloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      e.encodeTimestamp(pts),
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return []*rtp.Packet{pkt}, nil
+}
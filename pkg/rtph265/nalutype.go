@@ -0,0 +1,37 @@
+package rtph265
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/h265"
+)
+
+type naluType h265.NALUType
+
+// additional NALU types for RTP/H265.
+const (
+	naluTypeAggregationUnit   naluType = 48
+	naluTypeFragmentationUnit naluType = 49
+	naluTypePACI              naluType = 50
+)
+
+var naluLabels = map[naluType]string{
+	naluTypeAggregationUnit:   "AggregationUnit",
+	naluTypeFragmentationUnit: "FragmentationUnit",
+	naluTypePACI:              "PACI",
+}
+
+// String implements fmt.Stringer.
+func (nt naluType) String() string {
+	p := h265.NALUType(nt).String()
+	if !strings.HasPrefix(p, "unknown") {
+		return p
+	}
+
+	if l, ok := naluLabels[nt]; ok {
+		return l
+	}
+
+	return fmt.Sprintf("unknown (%d)", nt)
+}
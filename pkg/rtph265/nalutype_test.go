@@ -0,0 +1,14 @@
+package rtph265
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNALUType(t *testing.T) {
+	require.NotEqual(t, true, strings.HasPrefix(naluType(48).String(), "unknown"))
+	require.NotEqual(t, true, strings.HasPrefix(naluType(19).String(), "unknown"))
+	require.Equal(t, true, strings.HasPrefix(naluType(60).String(), "unknown"))
+}
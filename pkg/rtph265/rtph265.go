@@ -0,0 +1,7 @@
+// Package rtph265 contains a RTP/H265 decoder and encoder.
+package rtph265
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // h265 always uses 90khz
+)
@@ -0,0 +1,734 @@
+package rtph265
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var cases = []struct {
+	name       string
+	maxDONDiff int
+	nalus      [][]byte
+	pts        time.Duration
+	pkts       []*rtp.Packet
+}{
+	{
+		"single",
+		0,
+		[][]byte{
+			mergeBytes(
+				[]byte{0x26, 0x01},
+				bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 8),
+			),
+		},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x26, 0x01},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 8),
+				),
+			},
+		},
+	},
+	{
+		"fragmented",
+		0,
+		[][]byte{
+			mergeBytes(
+				[]byte{0x26, 0x01},
+				bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 512),
+			),
+		},
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x93},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 182),
+					[]byte{0x00},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x13},
+					[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 181),
+					[]byte{0x00, 0x01},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x53},
+					[]byte{0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 147),
+				),
+			},
+		},
+	},
+	{
+		"aggregated",
+		0,
+		[][]byte{
+			{0x40, 0x01, 0xaa},
+			{0x42, 0x01, 0xbb, 0xcc},
+		},
+		0,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x60, 0x01, 0x00, 0x03, 0x40, 0x01, 0xaa, 0x00,
+					0x04, 0x42, 0x01, 0xbb, 0xcc,
+				},
+			},
+		},
+	},
+	{
+		"aggregated followed by fragmented",
+		0,
+		[][]byte{
+			{0x40, 0x01, 0xaa},
+			{0x42, 0x01, 0xbb, 0xcc},
+			mergeBytes(
+				[]byte{0x26, 0x01},
+				bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 256),
+			),
+		},
+		0,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x60, 0x01, 0x00, 0x03, 0x40, 0x01, 0xaa, 0x00,
+					0x04, 0x42, 0x01, 0xbb, 0xcc,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x93},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 182),
+					[]byte{0x00},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x53},
+					[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 73),
+				),
+			},
+		},
+	},
+	{
+		"single with DONL",
+		2,
+		[][]byte{
+			{0x26, 0x01, 0xaa, 0xbb},
+		},
+		0,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x26, 0x01, 0x00, 0x00, 0xaa, 0xbb},
+			},
+		},
+	},
+	{
+		"fragmented with DONL",
+		2,
+		[][]byte{
+			mergeBytes(
+				[]byte{0x26, 0x01},
+				bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 256),
+			),
+		},
+		0,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x93, 0x00, 0x00},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 181),
+					[]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x62, 0x01, 0x53},
+					[]byte{0x07},
+					bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 74),
+				),
+			},
+		},
+	},
+	{
+		"aggregated with DONL",
+		2,
+		[][]byte{
+			{0x40, 0x01, 0xaa},
+			{0x42, 0x01, 0xbb, 0xcc},
+		},
+		0,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x60, 0x01, 0x00, 0x00, 0x00, 0x03, 0x40, 0x01,
+					0xaa, 0x00, 0x00, 0x04, 0x42, 0x01, 0xbb, 0xcc,
+				},
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				MaxDONDiff: ca.maxDONDiff,
+			}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x02, 0x01, 0x00, 0x00},
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var nalus [][]byte
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				addNALUs, pts, err := d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, ca.pts, pts)
+				nalus = append(nalus, addNALUs...)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.nalus, nalus)
+		})
+	}
+}
+
+func TestDecodeUntilMarker(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	for _, pkt := range cases[3].pkts {
+		nalus, pts, err := d.DecodeUntilMarker(pkt)
+		if err == ErrMorePacketsNeeded {
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), pts)
+		require.Equal(t, cases[3].nalus, nalus)
+	}
+}
+
+func TestDecodePartOfFragmentedBeforeSingle(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      2289531307,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: mergeBytes(
+			[]byte{0x62, 0x01, 0x53},
+			[]byte{0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+			bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 147),
+		),
+	}
+	_, _, err := d.Decode(&pkt)
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+
+	pkt = rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      2289534307,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: mergeBytes(
+			[]byte{0x26, 0x01},
+			bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}, 8),
+		),
+	}
+	_, _, err = d.Decode(&pkt)
+	require.NoError(t, err)
+}
+
+func TestDecodeAggregationUnitWithPadding(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289526357,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{
+			0x60, 0x01, 0x00, 0x02, 0xaa, 0xbb, 0x00, 0x02,
+			0xcc, 0xdd, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	}
+
+	nalus, _, err := d.Decode(&pkt)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{
+		{0xaa, 0xbb},
+		{0xcc, 0xdd},
+	}, nalus)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name       string
+		maxDONDiff int
+		pkts       []*rtp.Packet
+		err        string
+	}{
+		{
+			"missing payload",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"aggregation unit no size",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x60, 0x01, 0x01},
+				},
+			},
+			"invalid aggregation unit (invalid size)",
+		},
+		{
+			"aggregation unit invalid size",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x60, 0x01, 0x00, 0x05, 0x00},
+				},
+			},
+			"invalid aggregation unit (invalid size)",
+		},
+		{
+			"aggregation unit without NALUs",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x60, 0x01, 0x00, 0x00},
+				},
+			},
+			"aggregation unit doesn't contain any NALU",
+		},
+		{
+			"aggregation unit missing DONL",
+			2,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x60, 0x01, 0x00},
+				},
+			},
+			"invalid aggregation unit (invalid size)",
+		},
+		{
+			"single missing DONL",
+			2,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x26, 0x01, 0x00},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"fragmentation unit invalid size",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01},
+				},
+			},
+			"invalid fragmentation unit (invalid size)",
+		},
+		{
+			"fragmentation unit start and end",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01, 0xd3},
+				},
+			},
+			"invalid fragmentation unit (can't contain both a start and end bit)",
+		},
+		{
+			"fragmentation unit non-starting",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x26, 0x01, 0x00},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01, 0x13},
+				},
+			},
+			"invalid fragmentation unit (non-starting)",
+		},
+		{
+			"fragmentation unit expected",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01, 0x93, 0xaa},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x26, 0x01, 0x00},
+				},
+			},
+			"expected FragmentationUnit packet, got IDRWRADL packet",
+		},
+		{
+			"fragmentation unit two starting packets",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01, 0x93, 0xaa},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x62, 0x01, 0x93, 0xbb},
+				},
+			},
+			"invalid fragmentation unit (decoded two starting packets in a row)",
+		},
+		{
+			"PACI",
+			0,
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x64, 0x01},
+				},
+			},
+			"packet type not supported (PACI)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				MaxDONDiff: ca.maxDONDiff,
+			}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType: 96,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+				MaxDONDiff: ca.maxDONDiff,
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.nalus, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
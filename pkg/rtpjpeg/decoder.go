@@ -0,0 +1,269 @@
+package rtpjpeg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/jpeg"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented image and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+// Decoder is a RTP/JPEG decoder.
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+	fragmentedTimestamp uint32
+	firstJPEGHeader     headerJPEG
+	firstRestartHeader  *headerRestartMarker
+	firstTables         [][]byte
+
+	// quantization tables of static quantization values (128-254),
+	// that can be omitted by subsequent images.
+	staticTables map[uint8][][]byte
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+	d.staticTables = make(map[uint8][][]byte)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedSize = 0
+}
+
+// Decode decodes an image from a RTP/JPEG packet.
+// Images are returned in JFIF format, with all headers rebuilt.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	var jh headerJPEG
+	n, err := jh.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, 0, err
+	}
+	byts := pkt.Payload[n:]
+
+	var rh *headerRestartMarker
+	if jh.Type >= 64 {
+		rh = &headerRestartMarker{}
+		n, err := rh.unmarshal(byts)
+		if err != nil {
+			d.resetFragments()
+			return nil, 0, err
+		}
+		byts = byts[n:]
+	}
+
+	if jh.FragmentOffset == 0 {
+		// discard any incomplete image
+		d.resetFragments()
+
+		d.firstPacketReceived = true
+
+		var tables [][]byte
+
+		if jh.Quantization >= 128 {
+			var qh headerQuantizationTable
+			n, err := qh.unmarshal(byts)
+			if err != nil {
+				return nil, 0, err
+			}
+			byts = byts[n:]
+
+			switch {
+			case len(qh.Tables) != 0:
+				tables = qh.Tables
+				if jh.Quantization != 255 {
+					d.staticTables[jh.Quantization] = tables
+				}
+
+			case jh.Quantization != 255:
+				var ok bool
+				tables, ok = d.staticTables[jh.Quantization]
+				if !ok {
+					return nil, 0, fmt.Errorf("quantization tables of Q=%d not received yet", jh.Quantization)
+				}
+
+			default:
+				return nil, 0, fmt.Errorf("quantization tables are missing")
+			}
+		} else {
+			tables = makeTables(jh.Quantization)
+		}
+
+		d.firstJPEGHeader = jh
+		d.firstRestartHeader = rh
+		d.firstTables = tables
+		d.fragmentedTimestamp = pkt.Timestamp
+	} else {
+		if len(d.fragmentedParts) == 0 {
+			if !d.firstPacketReceived {
+				return nil, 0, ErrNonStartingPacketAndNoPrevious
+			}
+			return nil, 0, fmt.Errorf("received a non-starting fragment")
+		}
+
+		if int(jh.FragmentOffset) != d.fragmentedSize {
+			expected := d.fragmentedSize
+			d.resetFragments()
+			return nil, 0, fmt.Errorf("received wrong fragment offset (%d, expected %d)",
+				jh.FragmentOffset, expected)
+		}
+
+		if pkt.Timestamp != d.fragmentedTimestamp {
+			d.resetFragments()
+			return nil, 0, fmt.Errorf("received a fragment with a different timestamp")
+		}
+	}
+
+	d.fragmentedSize += len(byts)
+	if d.fragmentedSize > maxImageSize {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("image size (%d) is too big (maximum is %d)", d.fragmentedSize, maxImageSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, byts)
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	data := make([]byte, d.fragmentedSize)
+	n = 0
+	for _, p := range d.fragmentedParts {
+		n += copy(data[n:], p)
+	}
+
+	d.resetFragments()
+
+	return d.buildImage(data), d.timeDecoder.Decode(pkt.Timestamp), nil
+}
+
+func (d *Decoder) buildImage(data []byte) []byte {
+	jh := d.firstJPEGHeader
+
+	chmTable := uint8(0)
+	if len(d.firstTables) > 1 {
+		chmTable = 1
+	}
+
+	var lumSampling uint8
+	if (jh.Type & 0x3F) == 0 { // 4:2:2
+		lumSampling = 0x21
+	} else { // 4:2:0
+		lumSampling = 0x22
+	}
+
+	buf := make([]byte, 0, 1024+len(data))
+
+	buf = jpeg.StartOfImage{}.Marshal(buf)
+
+	var dqt jpeg.DefineQuantizationTable
+	for i, t := range d.firstTables {
+		dqt.Tables = append(dqt.Tables, jpeg.QuantizationTable{
+			ID:   uint8(i),
+			Data: t,
+		})
+	}
+	buf = dqt.Marshal(buf)
+
+	buf = jpeg.StartOfFrame0{
+		Width:  jh.Width,
+		Height: jh.Height,
+		Components: []jpeg.FrameComponent{
+			{
+				ID:                 1,
+				HorizontalSampling: lumSampling >> 4,
+				VerticalSampling:   lumSampling & 0x0F,
+			},
+			{
+				ID:                     2,
+				HorizontalSampling:     1,
+				VerticalSampling:       1,
+				QuantizationTableIndex: chmTable,
+			},
+			{
+				ID:                     3,
+				HorizontalSampling:     1,
+				VerticalSampling:       1,
+				QuantizationTableIndex: chmTable,
+			},
+		},
+	}.Marshal(buf)
+
+	if d.firstRestartHeader != nil {
+		buf = jpeg.DefineRestartInterval{
+			Interval: d.firstRestartHeader.Interval,
+		}.Marshal(buf)
+	}
+
+	buf = jpeg.DefineHuffmanTable{
+		Codes:       lumDCCodes,
+		Symbols:     lumDCSymbols,
+		TableNumber: 0,
+		TableClass:  0,
+	}.Marshal(buf)
+
+	buf = jpeg.DefineHuffmanTable{
+		Codes:       lumACCodes,
+		Symbols:     lumACSymbols,
+		TableNumber: 0,
+		TableClass:  1,
+	}.Marshal(buf)
+
+	buf = jpeg.DefineHuffmanTable{
+		Codes:       chmDCCodes,
+		Symbols:     chmDCSymbols,
+		TableNumber: 1,
+		TableClass:  0,
+	}.Marshal(buf)
+
+	buf = jpeg.DefineHuffmanTable{
+		Codes:       chmACCodes,
+		Symbols:     chmACSymbols,
+		TableNumber: 1,
+		TableClass:  1,
+	}.Marshal(buf)
+
+	buf = jpeg.StartOfScan{
+		Components: []jpeg.ScanComponent{
+			{
+				ID: 1,
+			},
+			{
+				ID:      2,
+				DCTable: 1,
+				ACTable: 1,
+			},
+			{
+				ID:      3,
+				DCTable: 1,
+				ACTable: 1,
+			},
+		},
+	}.Marshal(buf)
+
+	buf = append(buf, data...)
+
+	if len(data) < 2 || data[len(data)-2] != 0xFF || data[len(data)-1] != jpeg.MarkerEndOfImage {
+		buf = jpeg.EndOfImage{}.Marshal(buf)
+	}
+
+	return buf
+}
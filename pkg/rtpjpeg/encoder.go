@@ -0,0 +1,261 @@
+package rtpjpeg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/jpeg"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/JPEG encoder.
+type Encoder struct {
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes an image into RTP/JPEG packets.
+// The image must be a baseline JPEG image with YCbCr 4:2:0 or 4:2:2 subsampling
+// and standard Huffman tables, like the ones produced by image/jpeg.
+func (e *Encoder) Encode(image []byte, pts time.Duration) ([]*rtp.Packet, error) {
+	if len(image) < 2 || image[0] != 0xFF || image[1] != jpeg.MarkerStartOfImage {
+		return nil, fmt.Errorf("SOI not found")
+	}
+	image = image[2:]
+
+	var sof *jpeg.StartOfFrame0
+	var dri *jpeg.DefineRestartInterval
+	quantizationTables := make(map[uint8][]byte)
+	var data []byte
+
+outer:
+	for {
+		if len(image) < 4 {
+			return nil, fmt.Errorf("image is too short")
+		}
+
+		if image[0] != 0xFF {
+			return nil, fmt.Errorf("invalid marker")
+		}
+
+		marker := image[1]
+		mlen := int(image[2])<<8 | int(image[3])
+		if mlen < 2 || len(image) < (2+mlen) {
+			return nil, fmt.Errorf("image is too short")
+		}
+
+		mbuf := image[4 : 2+mlen]
+		image = image[2+mlen:]
+
+		switch marker {
+		case jpeg.MarkerDefineQuantizationTable:
+			var dqt jpeg.DefineQuantizationTable
+			err := dqt.Unmarshal(mbuf)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, t := range dqt.Tables {
+				if t.Precision != 0 {
+					return nil, fmt.Errorf("quantization table precision %d is not supported", t.Precision)
+				}
+				quantizationTables[t.ID] = t.Data
+			}
+
+		case jpeg.MarkerDefineRestartInterval:
+			dri = &jpeg.DefineRestartInterval{}
+			err := dri.Unmarshal(mbuf)
+			if err != nil {
+				return nil, err
+			}
+
+		case jpeg.MarkerStartOfFrame0:
+			sof = &jpeg.StartOfFrame0{}
+			err := sof.Unmarshal(mbuf)
+			if err != nil {
+				return nil, err
+			}
+
+		case 0xC1, 0xC2, 0xC3, 0xC5, 0xC6, 0xC7, 0xC9, 0xCA, 0xCB, 0xCD, 0xCE, 0xCF:
+			return nil, fmt.Errorf("JPEG type %d is not supported", marker-0xC0)
+
+		case jpeg.MarkerStartOfScan:
+			var sos jpeg.StartOfScan
+			err := sos.Unmarshal(mbuf)
+			if err != nil {
+				return nil, err
+			}
+
+			data = image
+			break outer
+		}
+	}
+
+	if sof == nil {
+		return nil, fmt.Errorf("SOF not found")
+	}
+
+	jh := headerJPEG{
+		Quantization: 255,
+		Width:        sof.Width,
+		Height:       sof.Height,
+	}
+
+	if jh.Width > 2040 || jh.Height > 2040 {
+		return nil, fmt.Errorf("image size (%dx%d) is too big (maximum is 2040x2040)", jh.Width, jh.Height)
+	}
+
+	// round up to the next multiple of 8
+	jh.Width = (jh.Width + 7) / 8 * 8
+	jh.Height = (jh.Height + 7) / 8 * 8
+
+	if len(sof.Components) != 3 ||
+		sof.Components[1].HorizontalSampling != 1 || sof.Components[1].VerticalSampling != 1 ||
+		sof.Components[2].HorizontalSampling != 1 || sof.Components[2].VerticalSampling != 1 ||
+		sof.Components[1].QuantizationTableIndex != sof.Components[2].QuantizationTableIndex {
+		return nil, fmt.Errorf("unsupported component configuration")
+	}
+
+	switch {
+	case sof.Components[0].HorizontalSampling == 2 && sof.Components[0].VerticalSampling == 1:
+		jh.Type = 0
+
+	case sof.Components[0].HorizontalSampling == 2 && sof.Components[0].VerticalSampling == 2:
+		jh.Type = 1
+
+	default:
+		return nil, fmt.Errorf("unsupported sampling factors (%dx%d)",
+			sof.Components[0].HorizontalSampling, sof.Components[0].VerticalSampling)
+	}
+
+	var qh headerQuantizationTable
+	for i, c := range sof.Components[:2] {
+		if i == 1 && c.QuantizationTableIndex == sof.Components[0].QuantizationTableIndex {
+			break
+		}
+
+		t, ok := quantizationTables[c.QuantizationTableIndex]
+		if !ok {
+			return nil, fmt.Errorf("quantization table %d not found", c.QuantizationTableIndex)
+		}
+		qh.Tables = append(qh.Tables, t)
+	}
+
+	var rh *headerRestartMarker
+	if dri != nil && dri.Interval != 0 {
+		jh.Type += 64
+		rh = &headerRestartMarker{
+			Interval: dri.Interval,
+			First:    true,
+			Last:     true,
+			Count:    0x3FFF,
+		}
+	}
+
+	// remove EOI
+	if len(data) >= 2 && data[len(data)-2] == 0xFF && data[len(data)-1] == jpeg.MarkerEndOfImage {
+		data = data[:len(data)-2]
+	}
+
+	var ret []*rtp.Packet
+	encPTS := e.encodeTimestamp(pts)
+	first := true
+	offset := 0
+
+	for {
+		var buf []byte
+
+		jh.FragmentOffset = uint32(offset)
+		buf = jh.marshal(buf)
+
+		if rh != nil {
+			buf = rh.marshal(buf)
+		}
+
+		if first {
+			first = false
+			buf = qh.marshal(buf)
+		}
+
+		avail := e.PayloadMaxSize - len(buf)
+		if avail <= 0 {
+			return nil, fmt.Errorf("payload max size is too small")
+		}
+
+		le := len(data)
+		if le > avail {
+			le = avail
+		}
+
+		buf = append(buf, data[:le]...)
+		data = data[le:]
+		offset += le
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    26,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         len(data) == 0,
+			},
+			Payload: buf,
+		})
+		e.sequenceNumber++
+
+		if len(data) == 0 {
+			break
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,58 @@
+package rtpjpeg
+
+import (
+	"fmt"
+)
+
+// headerJPEG is the main JPEG header.
+// Specification: RFC 2435, section 3.1
+type headerJPEG struct {
+	TypeSpecific   uint8
+	FragmentOffset uint32
+	Type           uint8
+	Quantization   uint8
+	Width          int
+	Height         int
+}
+
+func (h *headerJPEG) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 8 {
+		return 0, fmt.Errorf("buffer is too short")
+	}
+
+	h.TypeSpecific = buf[0]
+	h.FragmentOffset = uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3])
+
+	h.Type = buf[4]
+	if h.Type&0x3F > 1 {
+		return 0, fmt.Errorf("type %d is not supported", h.Type)
+	}
+
+	h.Quantization = buf[5]
+	if h.Quantization == 0 ||
+		(h.Quantization > 99 && h.Quantization < 128) {
+		return 0, fmt.Errorf("quantization %d is invalid", h.Quantization)
+	}
+
+	h.Width = int(buf[6]) * 8
+	if h.Width == 0 {
+		return 0, fmt.Errorf("invalid width")
+	}
+
+	h.Height = int(buf[7]) * 8
+	if h.Height == 0 {
+		return 0, fmt.Errorf("invalid height")
+	}
+
+	return 8, nil
+}
+
+func (h headerJPEG) marshal(buf []byte) []byte {
+	return append(buf,
+		h.TypeSpecific,
+		byte(h.FragmentOffset>>16), byte(h.FragmentOffset>>8), byte(h.FragmentOffset),
+		h.Type,
+		h.Quantization,
+		byte(h.Width/8),
+		byte(h.Height/8))
+}
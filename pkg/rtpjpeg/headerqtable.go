@@ -0,0 +1,54 @@
+package rtpjpeg
+
+import (
+	"fmt"
+)
+
+// headerQuantizationTable is the Quantization Table header.
+// Specification: RFC 2435, section 3.1.8
+type headerQuantizationTable struct {
+	MBZ       uint8
+	Precision uint8
+	Tables    [][]byte
+}
+
+func (h *headerQuantizationTable) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 4 {
+		return 0, fmt.Errorf("buffer is too short")
+	}
+
+	h.MBZ = buf[0]
+	h.Precision = buf[1]
+	if h.Precision != 0 {
+		return 0, fmt.Errorf("precision %d is not supported", h.Precision)
+	}
+
+	length := int(buf[2])<<8 | int(buf[3])
+	switch length {
+	case 0, 64, 128:
+	default:
+		return 0, fmt.Errorf("quantization table length %d is not supported", length)
+	}
+
+	if len(buf) < (4 + length) {
+		return 0, fmt.Errorf("buffer is too short")
+	}
+
+	h.Tables = nil
+	for i := 0; i < length; i += 64 {
+		h.Tables = append(h.Tables, buf[4+i:4+i+64])
+	}
+
+	return 4 + length, nil
+}
+
+func (h headerQuantizationTable) marshal(buf []byte) []byte {
+	length := len(h.Tables) * 64
+	buf = append(buf, h.MBZ, h.Precision, byte(length>>8), byte(length))
+
+	for _, t := range h.Tables {
+		buf = append(buf, t...)
+	}
+
+	return buf
+}
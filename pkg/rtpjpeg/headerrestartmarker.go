@@ -0,0 +1,39 @@
+package rtpjpeg
+
+import (
+	"fmt"
+)
+
+// headerRestartMarker is the Restart Marker header.
+// Specification: RFC 2435, section 3.1.7
+type headerRestartMarker struct {
+	Interval uint16
+	First    bool
+	Last     bool
+	Count    uint16
+}
+
+func (h *headerRestartMarker) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 4 {
+		return 0, fmt.Errorf("buffer is too short")
+	}
+
+	h.Interval = uint16(buf[0])<<8 | uint16(buf[1])
+	h.First = (buf[2] & 0x80) != 0
+	h.Last = (buf[2] & 0x40) != 0
+	h.Count = uint16(buf[2]&0x3F)<<8 | uint16(buf[3])
+
+	return 4, nil
+}
+
+func (h headerRestartMarker) marshal(buf []byte) []byte {
+	b2 := byte(h.Count>>8) & 0x3F
+	if h.First {
+		b2 |= 0x80
+	}
+	if h.Last {
+		b2 |= 0x40
+	}
+
+	return append(buf, byte(h.Interval>>8), byte(h.Interval), b2, byte(h.Count))
+}
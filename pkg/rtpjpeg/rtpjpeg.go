@@ -0,0 +1,8 @@
+// Package rtpjpeg contains a RTP/JPEG decoder and encoder.
+package rtpjpeg
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // JPEG always uses 90khz
+	maxImageSize = 3 * 1024 * 1024
+)
@@ -0,0 +1,368 @@
+package rtpjpeg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func generateImage(t *testing.T, width int, height int, quality int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func requireSameImage(t *testing.T, byts1 []byte, byts2 []byte) {
+	img1, err := jpeg.Decode(bytes.NewReader(byts1))
+	require.NoError(t, err)
+
+	img2, err := jpeg.Decode(bytes.NewReader(byts2))
+	require.NoError(t, err)
+
+	require.Equal(t, img1, img2)
+}
+
+var cases = []struct {
+	name    string
+	width   int
+	height  int
+	quality int
+	pts     time.Duration
+	pktSize []int
+}{
+	{
+		"single",
+		16,
+		16,
+		75,
+		25 * time.Millisecond,
+		[]int{153},
+	},
+	{
+		"fragmented",
+		320,
+		240,
+		90,
+		55 * time.Millisecond,
+		[]int{1460, 1460, 1460, 1460, 885},
+	},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			img := generateImage(t, ca.width, ca.height, ca.quality)
+
+			e := &Encoder{
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(img, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, len(ca.pktSize), len(pkts))
+
+			for i, pkt := range pkts {
+				require.Equal(t, rtp.Header{
+					Version:        2,
+					Marker:         i == len(pkts)-1,
+					PayloadType:    26,
+					SequenceNumber: 17645 + uint16(i),
+					Timestamp:      2289526357 + uint32(ca.pts.Seconds()*90000),
+					SSRC:           0x9dbb7812,
+				}, pkt.Header)
+				require.Equal(t, ca.pktSize[i], len(pkt.Payload))
+			}
+
+			d := &Decoder{}
+			d.Init()
+
+			var dec []byte
+
+			for _, pkt := range pkts {
+				clone := pkt.Clone()
+
+				dec, _, err = d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+				require.NoError(t, err)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			requireSameImage(t, img, dec)
+		})
+	}
+}
+
+func TestDecodeStandardQuantization(t *testing.T) {
+	require.Equal(t, [][]byte{lumQuantizer, chmQuantizer}, makeTables(50))
+
+	img := generateImage(t, 16, 16, 50)
+
+	e := &Encoder{}
+	e.Init()
+
+	pkts, err := e.Encode(img, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pkts))
+
+	// replace quantization tables with a quantization factor
+	pkt := pkts[0]
+	pkt.Payload = append([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 50, 2, 2}, pkt.Payload[8+4+128:]...)
+
+	d := &Decoder{}
+	d.Init()
+
+	dec, _, err := d.Decode(pkt)
+	require.NoError(t, err)
+
+	requireSameImage(t, img, dec)
+}
+
+func TestDecodeStaticQuantizationTables(t *testing.T) {
+	img := generateImage(t, 16, 16, 60)
+
+	e := &Encoder{}
+	e.Init()
+
+	pkts1, err := e.Encode(img, 0)
+	require.NoError(t, err)
+	pkts1[0].Payload[5] = 128
+
+	pkts2, err := e.Encode(img, 40*time.Millisecond)
+	require.NoError(t, err)
+	pkts2[0].Payload = append([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 128, 2, 2, 0, 0, 0, 0},
+		pkts2[0].Payload[8+4+128:]...)
+
+	d := &Decoder{}
+	d.Init()
+
+	dec, _, err := d.Decode(pkts1[0])
+	require.NoError(t, err)
+	requireSameImage(t, img, dec)
+
+	dec, pts, err := d.Decode(pkts2[0])
+	require.NoError(t, err)
+	require.Equal(t, 40*time.Millisecond, pts)
+	requireSameImage(t, img, dec)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"missing header",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00},
+				},
+			},
+			"buffer is too short",
+		},
+		{
+			"unsupported type",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x05, 50, 2, 2},
+				},
+			},
+			"type 5 is not supported",
+		},
+		{
+			"invalid quantization",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 110, 2, 2},
+				},
+			},
+			"quantization 110 is invalid",
+		},
+		{
+			"missing restart marker header",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x41, 50, 2, 2, 0x00},
+				},
+			},
+			"buffer is too short",
+		},
+		{
+			"missing quantization tables",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 255, 2, 2, 0x00, 0x00, 0x00, 0x00},
+				},
+			},
+			"quantization tables are missing",
+		},
+		{
+			"static quantization tables not received",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 130, 2, 2, 0x00, 0x00, 0x00, 0x00},
+				},
+			},
+			"quantization tables of Q=130 not received yet",
+		},
+		{
+			"non-starting",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x02, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+			},
+			"received a non-starting fragment",
+		},
+		{
+			"wrong fragment offset",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: false},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x03, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+			},
+			"received wrong fragment offset (3, expected 2)",
+		},
+		{
+			"different timestamp",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: false, Timestamp: 1},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+				{
+					Header:  rtp.Header{Marker: true, Timestamp: 2},
+					Payload: []byte{0x00, 0x00, 0x00, 0x02, 0x01, 50, 2, 2, 0x01, 0x02},
+				},
+			},
+			"received a fragment with a different timestamp",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestDecodeNonStartingPacketAndNoPrevious(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	_, _, err := d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: true},
+		Payload: []byte{0x00, 0x00, 0x00, 0x02, 0x01, 50, 2, 2, 0x01, 0x02},
+	})
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		img  []byte
+		err  string
+	}{
+		{
+			"missing SOI",
+			[]byte{0x01, 0x02},
+			"SOI not found",
+		},
+		{
+			"too short",
+			[]byte{0xff, 0xd8, 0xff},
+			"image is too short",
+		},
+		{
+			"progressive",
+			[]byte{0xff, 0xd8, 0xff, 0xc2, 0x00, 0x02},
+			"JPEG type 2 is not supported",
+		},
+		{
+			"missing SOF",
+			[]byte{
+				0xff, 0xd8, 0xff, 0xda, 0x00, 0x0c, 0x03, 0x01,
+				0x00, 0x02, 0x11, 0x03, 0x11, 0x00, 0x3f, 0x00,
+			},
+			"SOF not found",
+		},
+		{
+			"grayscale",
+			[]byte{
+				0xff, 0xd8, 0xff, 0xc0, 0x00, 0x0b, 0x08, 0x00,
+				0x10, 0x00, 0x10, 0x01, 0x01, 0x11, 0x00, 0xff,
+				0xda, 0x00, 0x08, 0x01, 0x01, 0x00, 0x00, 0x3f,
+				0x00,
+			},
+			"unsupported component configuration",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{}
+			e.Init()
+
+			_, err := e.Encode(ca.img, 0)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
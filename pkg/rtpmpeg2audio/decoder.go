@@ -0,0 +1,120 @@
+package rtpmpeg2audio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg2audio"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// Decoder is a RTP/MPEG-1/2 audio decoder.
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	fragmentedMode      bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+	fragmentedFrameSize int
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedMode = false
+}
+
+// Decode decodes frames from a RTP/MPEG-1/2 audio packet.
+// It returns the frames and the PTS of the first frame.
+// The PTS of subsequent frames can be calculated by using mpeg2audio.FrameHeader.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, time.Duration, error) {
+	if len(pkt.Payload) < 5 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("payload is too short")
+	}
+
+	mbz := uint16(pkt.Payload[0])<<8 | uint16(pkt.Payload[1])
+	if mbz != 0 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("invalid MBZ: %v", mbz)
+	}
+
+	offset := int(uint16(pkt.Payload[2])<<8 | uint16(pkt.Payload[3]))
+	buf := pkt.Payload[4:]
+
+	if offset == 0 {
+		// discard any incomplete frame
+		d.resetFragments()
+
+		var frames [][]byte
+
+		for len(buf) != 0 {
+			var h mpeg2audio.FrameHeader
+			err := h.Unmarshal(buf)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			fl := h.FrameLen()
+
+			if len(buf) < fl {
+				if len(frames) != 0 {
+					return nil, 0, fmt.Errorf("a fragmented packet can only contain one frame")
+				}
+
+				d.fragmentedMode = true
+				d.fragmentedFrameSize = fl
+				d.fragmentedSize = len(buf)
+				d.fragmentedParts = append(d.fragmentedParts, buf)
+				return nil, 0, ErrMorePacketsNeeded
+			}
+
+			frames = append(frames, buf[:fl])
+			buf = buf[fl:]
+		}
+
+		return frames, d.timeDecoder.Decode(pkt.Timestamp), nil
+	}
+
+	if !d.fragmentedMode {
+		return nil, 0, fmt.Errorf("received a non-starting fragment")
+	}
+
+	if offset != d.fragmentedSize {
+		expected := d.fragmentedSize
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("received wrong fragment offset (%d, expected %d)", offset, expected)
+	}
+
+	d.fragmentedSize += len(buf)
+	if d.fragmentedSize > d.fragmentedFrameSize {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("fragmented frame is bigger than declared (%d, expected %d)",
+			d.fragmentedSize, d.fragmentedFrameSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, buf)
+
+	if d.fragmentedSize != d.fragmentedFrameSize {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := make([]byte, d.fragmentedSize)
+	n := 0
+	for _, p := range d.fragmentedParts {
+		n += copy(ret[n:], p)
+	}
+
+	d.resetFragments()
+
+	return [][]byte{ret}, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
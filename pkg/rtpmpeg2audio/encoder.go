@@ -0,0 +1,195 @@
+package rtpmpeg2audio
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg2audio"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+func frameDuration(frame []byte) (time.Duration, error) {
+	var h mpeg2audio.FrameHeader
+	err := h.Unmarshal(frame)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(h.SampleCount()) * time.Second / time.Duration(h.SampleRate), nil
+}
+
+// Encoder is a RTP/MPEG-1/2 audio encoder.
+type Encoder struct {
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes frames into RTP/MPEG-1/2 audio packets.
+func (e *Encoder) Encode(frames [][]byte, firstPTS time.Duration) ([]*rtp.Packet, error) {
+	var rets []*rtp.Packet
+	var batch [][]byte
+	batchPTS := firstPTS
+	pts := firstPTS
+
+	// split frames into batches
+	for _, frame := range frames {
+		if e.lenAggregated(batch, frame) <= e.PayloadMaxSize {
+			// add to existing batch
+			batch = append(batch, frame)
+		} else {
+			// write last batch
+			if batch != nil {
+				pkts := e.writeBatch(batch, batchPTS)
+				rets = append(rets, pkts...)
+				batchPTS = pts
+			}
+
+			// initialize new batch
+			batch = [][]byte{frame}
+		}
+
+		dur, err := frameDuration(frame)
+		if err != nil {
+			return nil, err
+		}
+		pts += dur
+	}
+
+	// write last batch
+	pkts := e.writeBatch(batch, batchPTS)
+	rets = append(rets, pkts...)
+
+	return rets, nil
+}
+
+func (e *Encoder) writeBatch(frames [][]byte, pts time.Duration) []*rtp.Packet {
+	if len(frames) != 1 || e.lenAggregated(frames, nil) <= e.PayloadMaxSize {
+		return e.writeAggregated(frames, pts)
+	}
+
+	return e.writeFragmented(frames[0], pts)
+}
+
+func (e *Encoder) writeFragmented(frame []byte, pts time.Duration) []*rtp.Packet {
+	avail := e.PayloadMaxSize - 4
+	packetCount := len(frame) / avail
+	lastPacketSize := len(frame) % avail
+	if lastPacketSize > 0 {
+		packetCount++
+	}
+
+	ret := make([]*rtp.Packet, packetCount)
+	encPTS := e.encodeTimestamp(pts)
+
+	pos := 0
+	for i := range ret {
+		var le int
+		if i != (packetCount - 1) {
+			le = avail
+		} else {
+			le = lastPacketSize
+		}
+
+		payload := make([]byte, 4+le)
+		payload[2] = byte(pos >> 8)
+		payload[3] = byte(pos)
+		copy(payload[4:], frame[pos:pos+le])
+		pos += le
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    14,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         (i == (packetCount - 1)),
+			},
+			Payload: payload,
+		}
+
+		e.sequenceNumber++
+	}
+
+	return ret
+}
+
+func (e *Encoder) lenAggregated(frames [][]byte, addFrame []byte) int {
+	n := 4 + len(addFrame)
+	for _, frame := range frames {
+		n += len(frame)
+	}
+	return n
+}
+
+func (e *Encoder) writeAggregated(frames [][]byte, pts time.Duration) []*rtp.Packet {
+	payload := make([]byte, e.lenAggregated(frames, nil))
+
+	n := 4
+	for _, frame := range frames {
+		n += copy(payload[n:], frame)
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    14,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      e.encodeTimestamp(pts),
+			SSRC:           *e.SSRC,
+			Marker:         true,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return []*rtp.Packet{pkt}
+}
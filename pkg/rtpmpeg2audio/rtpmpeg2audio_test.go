@@ -0,0 +1,338 @@
+package rtpmpeg2audio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+// MPEG-1 layer 3, 128kbit/s, 44.1khz
+var smallFrame = mergeBytes(
+	[]byte{0xff, 0xfb, 0x90, 0x64},
+	bytes.Repeat([]byte{0x01}, 413),
+)
+
+// MPEG-2.5 layer 1, 256kbit/s, 8khz
+var bigFrame = mergeBytes(
+	[]byte{0xff, 0xe7, 0xe8, 0xc4},
+	bytes.Repeat([]byte{0x02}, 1532),
+)
+
+var cases = []struct {
+	name   string
+	frames [][]byte
+	pts    time.Duration
+	pkts   []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{smallFrame},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    14,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x00, 0x00},
+					smallFrame,
+				),
+			},
+		},
+	},
+	{
+		"aggregated",
+		[][]byte{smallFrame, smallFrame},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    14,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x00, 0x00},
+					smallFrame,
+					smallFrame,
+				),
+			},
+		},
+	},
+	{
+		"fragmented",
+		[][]byte{bigFrame},
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    14,
+					SequenceNumber: 17645,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x00, 0x00},
+					bigFrame[:1456],
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    14,
+					SequenceNumber: 17646,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x05, 0xb0},
+					bigFrame[1456:],
+				),
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    14,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x00, 0x00},
+					smallFrame,
+				),
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var frames [][]byte
+			expPTS := ca.pts
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				addFrames, pts, err := d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, expPTS, pts)
+				frames = append(frames, addFrames...)
+				expPTS += time.Duration(len(addFrames)) * 1152 * time.Second / 44100
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.frames, frames)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"missing payload",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"invalid MBZ",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x00, 0x01, 0x00, 0x00}, smallFrame),
+				},
+			},
+			"invalid MBZ: 1",
+		},
+		{
+			"invalid frame header",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04},
+				},
+			},
+			"sync word not found: 10",
+		},
+		{
+			"fragmented frame after aggregated frames",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x00, 0x00, 0x00, 0x00}, smallFrame, smallFrame[:100]),
+				},
+			},
+			"a fragmented packet can only contain one frame",
+		},
+		{
+			"non-starting",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x00, 0x00, 0x10, 0x01},
+				},
+			},
+			"received a non-starting fragment",
+		},
+		{
+			"wrong fragment offset",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: false},
+					Payload: mergeBytes([]byte{0x00, 0x00, 0x00, 0x00}, bigFrame[:1000]),
+				},
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x00, 0x00, 0x03, 0xe9}, bigFrame[1001:]),
+				},
+			},
+			"received wrong fragment offset (1001, expected 1000)",
+		},
+		{
+			"fragmented frame too big",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: false},
+					Payload: mergeBytes([]byte{0x00, 0x00, 0x00, 0x00}, bigFrame[:1000]),
+				},
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x00, 0x00, 0x03, 0xe8}, bigFrame[1000:], []byte{0x01}),
+				},
+			},
+			"fragmented frame is bigger than declared (1537, expected 1536)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.frames, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeSplitBatches(t *testing.T) {
+	e := &Encoder{
+		SSRC: func() *uint32 {
+			v := uint32(0x9dbb7812)
+			return &v
+		}(),
+		InitialSequenceNumber: func() *uint16 {
+			v := uint16(0x44ed)
+			return &v
+		}(),
+		InitialTimestamp: func() *uint32 {
+			v := uint32(0x88776655)
+			return &v
+		}(),
+	}
+	e.Init()
+
+	pkts, err := e.Encode([][]byte{smallFrame, smallFrame, bigFrame}, 25*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(pkts))
+
+	require.Equal(t, uint32(2289528607), pkts[0].Timestamp)
+	require.Equal(t, 4+2*len(smallFrame), len(pkts[0].Payload))
+
+	// 25ms + 2 * 1152 samples at 44.1khz
+	require.Equal(t, uint32(2289533309), pkts[1].Timestamp)
+	require.Equal(t, uint32(2289533309), pkts[2].Timestamp)
+	require.Equal(t, false, pkts[1].Marker)
+	require.Equal(t, true, pkts[2].Marker)
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
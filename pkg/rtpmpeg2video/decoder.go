@@ -0,0 +1,105 @@
+package rtpmpeg2video
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented frame and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+// Decoder is a RTP/MPEG-1/2 video decoder.
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragmentedMode      bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+	fragmentedTimestamp uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedMode = false
+}
+
+// Decode decodes a frame from a RTP/MPEG-1/2 video packet.
+// A frame contains a picture and any sequence and GOP header that precedes it.
+// Frames are returned when the packet with the marker flag is received.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	var h header
+	n, err := h.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, 0, err
+	}
+
+	payload := pkt.Payload[n:]
+	if len(payload) == 0 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("payload is too short")
+	}
+
+	if !d.fragmentedMode || pkt.Timestamp != d.fragmentedTimestamp {
+		// discard any incomplete frame
+		d.resetFragments()
+
+		if !h.BeginningOfSlice || !isStartCode(payload) {
+			if !d.firstPacketReceived {
+				return nil, 0, ErrNonStartingPacketAndNoPrevious
+			}
+			return nil, 0, fmt.Errorf("received a non-starting fragment")
+		}
+
+		d.firstPacketReceived = true
+
+		if pkt.Marker {
+			return payload, d.timeDecoder.Decode(pkt.Timestamp), nil
+		}
+
+		d.fragmentedMode = true
+		d.fragmentedParts = append(d.fragmentedParts, payload)
+		d.fragmentedSize = len(payload)
+		d.fragmentedTimestamp = pkt.Timestamp
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	d.fragmentedSize += len(payload)
+	if d.fragmentedSize > maxFrameSize {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("frame size (%d) is too big (maximum is %d)", d.fragmentedSize, maxFrameSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, payload)
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := make([]byte, d.fragmentedSize)
+	n = 0
+	for _, p := range d.fragmentedParts {
+		n += copy(ret[n:], p)
+	}
+
+	d.resetFragments()
+
+	return ret, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
@@ -0,0 +1,242 @@
+package rtpmpeg2video
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/bits"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/MPEG-1/2 video encoder.
+type Encoder struct {
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+func parsePictureHeader(buf []byte, h *header) error {
+	pos := 0
+
+	tmp, err := bits.ReadBits(buf, &pos, 10)
+	if err != nil {
+		return err
+	}
+	h.TemporalReference = uint16(tmp)
+
+	tmp, err = bits.ReadBits(buf, &pos, 3)
+	if err != nil {
+		return err
+	}
+	h.PictureType = uint8(tmp)
+
+	// vbv_delay
+	_, err = bits.ReadBits(buf, &pos, 16)
+	if err != nil {
+		return err
+	}
+
+	if h.PictureType == pictureTypeP || h.PictureType == pictureTypeB {
+		h.FullPelForward, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return err
+		}
+
+		tmp, err = bits.ReadBits(buf, &pos, 3)
+		if err != nil {
+			return err
+		}
+		h.ForwardFCode = uint8(tmp)
+	}
+
+	if h.PictureType == pictureTypeB {
+		h.FullPelBackward, err = bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return err
+		}
+
+		tmp, err = bits.ReadBits(buf, &pos, 3)
+		if err != nil {
+			return err
+		}
+		h.BackwardFCode = uint8(tmp)
+	}
+
+	return nil
+}
+
+// split a frame into units that begin with a start code.
+func splitUnits(frame []byte) [][]byte {
+	var ret [][]byte
+	start := 0
+
+	for i := 1; i < len(frame)-3; i++ {
+		if frame[i] == 0 && frame[i+1] == 0 && frame[i+2] == 1 {
+			ret = append(ret, frame[start:i])
+			start = i
+		}
+	}
+
+	return append(ret, frame[start:])
+}
+
+type chunk struct {
+	data           []byte
+	begin          bool
+	end            bool
+	sequenceHeader bool
+}
+
+// Encode encodes a frame into RTP/MPEG-1/2 video packets.
+// A frame contains a picture and any sequence and GOP header that precedes it.
+func (e *Encoder) Encode(frame []byte, pts time.Duration) ([]*rtp.Packet, error) {
+	if !isStartCode(frame) {
+		return nil, fmt.Errorf("frame must begin with a start code")
+	}
+
+	units := splitUnits(frame)
+	pictureFound := false
+	var h header
+
+	for _, unit := range units {
+		if unit[3] == startCodePicture {
+			err := parsePictureHeader(unit[4:], &h)
+			if err != nil {
+				return nil, fmt.Errorf("invalid picture header: %v", err)
+			}
+			pictureFound = true
+		}
+	}
+
+	if !pictureFound {
+		return nil, fmt.Errorf("picture header not found")
+	}
+
+	avail := e.PayloadMaxSize - 4
+
+	// group units into chunks.
+	// units are aggregated when they fit into a packet,
+	// otherwise they are fragmented.
+	var chunks []chunk
+	var cur *chunk
+
+	for _, unit := range units {
+		if cur != nil && (len(cur.data)+len(unit)) <= avail {
+			cur.data = append(cur.data, unit...)
+			cur.sequenceHeader = cur.sequenceHeader || unit[3] == startCodeSequenceHeader
+			continue
+		}
+
+		if cur != nil {
+			chunks = append(chunks, *cur)
+			cur = nil
+		}
+
+		if len(unit) <= avail {
+			cur = &chunk{
+				data:           append([]byte(nil), unit...),
+				begin:          true,
+				end:            true,
+				sequenceHeader: unit[3] == startCodeSequenceHeader,
+			}
+			continue
+		}
+
+		first := true
+		for len(unit) != 0 {
+			le := len(unit)
+			if le > avail {
+				le = avail
+			}
+
+			chunks = append(chunks, chunk{
+				data:           unit[:le],
+				begin:          first,
+				end:            le == len(unit),
+				sequenceHeader: first && unit[3] == startCodeSequenceHeader,
+			})
+			unit = unit[le:]
+			first = false
+		}
+	}
+
+	if cur != nil {
+		chunks = append(chunks, *cur)
+	}
+
+	ret := make([]*rtp.Packet, len(chunks))
+	encPTS := e.encodeTimestamp(pts)
+
+	for i, c := range chunks {
+		h.BeginningOfSlice = c.begin
+		h.EndOfSlice = c.end
+		h.SequenceHeader = c.sequenceHeader
+
+		payload := make([]byte, 0, 4+len(c.data))
+		payload = h.marshal(payload)
+		payload = append(payload, c.data...)
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    32,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         (i == len(chunks)-1),
+			},
+			Payload: payload,
+		}
+
+		e.sequenceNumber++
+	}
+
+	return ret, nil
+}
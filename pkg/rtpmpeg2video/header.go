@@ -0,0 +1,91 @@
+package rtpmpeg2video
+
+import (
+	"fmt"
+)
+
+// header is a MPEG video-specific header.
+// Specification: RFC 2250, section 3.4
+type header struct {
+	TemporalReference   uint16
+	ActiveN             bool
+	NewPictureHeader    bool
+	SequenceHeader      bool
+	BeginningOfSlice    bool
+	EndOfSlice          bool
+	PictureType         uint8
+	FullPelBackward     bool
+	BackwardFCode       uint8
+	FullPelForward      bool
+	ForwardFCode        uint8
+	MPEG2HeaderExtended bool
+}
+
+func (h *header) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 4 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	if (buf[0] >> 3) != 0 {
+		return 0, fmt.Errorf("invalid MBZ")
+	}
+
+	h.MPEG2HeaderExtended = (buf[0] & 0x04) != 0
+	h.TemporalReference = uint16(buf[0]&0x03)<<8 | uint16(buf[1])
+	h.ActiveN = (buf[2] & 0x80) != 0
+	h.NewPictureHeader = (buf[2] & 0x40) != 0
+	h.SequenceHeader = (buf[2] & 0x20) != 0
+	h.BeginningOfSlice = (buf[2] & 0x10) != 0
+	h.EndOfSlice = (buf[2] & 0x08) != 0
+	h.PictureType = buf[2] & 0x07
+	h.FullPelBackward = (buf[3] & 0x80) != 0
+	h.BackwardFCode = (buf[3] >> 4) & 0x07
+	h.FullPelForward = (buf[3] & 0x08) != 0
+	h.ForwardFCode = buf[3] & 0x07
+
+	n := 4
+
+	// skip the MPEG-2 video-specific header extension
+	if h.MPEG2HeaderExtended {
+		if len(buf) < 8 {
+			return 0, fmt.Errorf("payload is too short")
+		}
+		n += 4
+	}
+
+	return n, nil
+}
+
+func (h header) marshal(buf []byte) []byte {
+	b0 := byte(h.TemporalReference>>8) & 0x03
+	if h.MPEG2HeaderExtended {
+		b0 |= 0x04
+	}
+
+	b2 := h.PictureType & 0x07
+	if h.ActiveN {
+		b2 |= 0x80
+	}
+	if h.NewPictureHeader {
+		b2 |= 0x40
+	}
+	if h.SequenceHeader {
+		b2 |= 0x20
+	}
+	if h.BeginningOfSlice {
+		b2 |= 0x10
+	}
+	if h.EndOfSlice {
+		b2 |= 0x08
+	}
+
+	b3 := (h.BackwardFCode&0x07)<<4 | (h.ForwardFCode & 0x07)
+	if h.FullPelBackward {
+		b3 |= 0x80
+	}
+	if h.FullPelForward {
+		b3 |= 0x08
+	}
+
+	return append(buf, b0, byte(h.TemporalReference), b2, b3)
+}
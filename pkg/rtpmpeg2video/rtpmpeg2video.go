@@ -0,0 +1,25 @@
+// Package rtpmpeg2video contains a RTP/MPEG-1/2 video decoder and encoder.
+package rtpmpeg2video
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // MPEG-1/2 video always uses 90khz
+	maxFrameSize = 3 * 1024 * 1024
+)
+
+// start codes.
+// Specification: ISO 11172-2, section 2.4.4
+const (
+	startCodePicture        = 0x00
+	startCodeSequenceHeader = 0xB3
+)
+
+// picture types.
+const (
+	pictureTypeP = 2
+	pictureTypeB = 3
+)
+
+func isStartCode(buf []byte) bool {
+	return len(buf) >= 4 && buf[0] == 0 && buf[1] == 0 && buf[2] == 1
+}
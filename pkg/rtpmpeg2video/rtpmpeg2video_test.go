@@ -0,0 +1,340 @@
+package rtpmpeg2video
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var sequenceHeader = []byte{
+	0x00, 0x00, 0x01, 0xb3, 0x16, 0x00, 0xf0, 0x15,
+	0xff, 0xff, 0xe0, 0x28,
+}
+
+// P picture, temporal reference 5, forward_f_code 3
+var pictureHeader = []byte{
+	0x00, 0x00, 0x01, 0x00, 0x01, 0x57, 0xff, 0xf9,
+	0x80,
+}
+
+var smallSlice = mergeBytes(
+	[]byte{0x00, 0x00, 0x01, 0x01},
+	bytes.Repeat([]byte{0x02}, 10),
+)
+
+var bigSlice = mergeBytes(
+	[]byte{0x00, 0x00, 0x01, 0x01},
+	bytes.Repeat([]byte{0x02}, 2000),
+)
+
+var cases = []struct {
+	name  string
+	frame []byte
+	pts   time.Duration
+	pkts  []*rtp.Packet
+}{
+	{
+		"single",
+		mergeBytes(sequenceHeader, pictureHeader, smallSlice),
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    32,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x05, 0x3a, 0x03},
+					sequenceHeader,
+					pictureHeader,
+					smallSlice,
+				),
+			},
+		},
+	},
+	{
+		"fragmented",
+		mergeBytes(pictureHeader, bigSlice),
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    32,
+					SequenceNumber: 17645,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x05, 0x1a, 0x03},
+					pictureHeader,
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    32,
+					SequenceNumber: 17646,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x05, 0x12, 0x03},
+					bigSlice[:1456],
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    32,
+					SequenceNumber: 17647,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x05, 0x0a, 0x03},
+					bigSlice[1456:],
+				),
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    32,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x05, 0x1a, 0x03},
+					pictureHeader,
+				),
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var frame []byte
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				var pts time.Duration
+				frame, pts, err = d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, ca.pts, pts)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.frame, frame)
+		})
+	}
+}
+
+func TestDecodeMPEG2HeaderExtension(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	frame, _, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    32,
+			SequenceNumber: 17645,
+			Timestamp:      2289526357,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: mergeBytes(
+			[]byte{0x04, 0x05, 0x1a, 0x03},
+			[]byte{0x00, 0x00, 0x00, 0x00},
+			pictureHeader,
+		),
+	})
+	require.NoError(t, err)
+	require.Equal(t, pictureHeader, frame)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"missing header",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x05},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"invalid MBZ",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x08, 0x05, 0x1a, 0x03}, pictureHeader),
+				},
+			},
+			"invalid MBZ",
+		},
+		{
+			"missing payload",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: []byte{0x00, 0x05, 0x1a, 0x03},
+				},
+			},
+			"payload is too short",
+		},
+		{
+			"non-starting",
+			[]*rtp.Packet{
+				{
+					Header:  rtp.Header{Marker: true},
+					Payload: mergeBytes([]byte{0x00, 0x05, 0x1a, 0x03}, pictureHeader),
+				},
+				{
+					Header:  rtp.Header{Marker: true, Timestamp: 3000},
+					Payload: mergeBytes([]byte{0x00, 0x05, 0x0a, 0x03}, []byte{0x01, 0x02}),
+				},
+			},
+			"received a non-starting fragment",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestDecodeNonStartingPacketAndNoPrevious(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	_, _, err := d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: true},
+		Payload: mergeBytes([]byte{0x00, 0x05, 0x0a, 0x03}, []byte{0x01, 0x02}),
+	})
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.frame, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		frame []byte
+		err   string
+	}{
+		{
+			"missing start code",
+			[]byte{0x01, 0x02, 0x03, 0x04},
+			"frame must begin with a start code",
+		},
+		{
+			"missing picture header",
+			sequenceHeader,
+			"picture header not found",
+		},
+		{
+			"invalid picture header",
+			[]byte{0x00, 0x00, 0x01, 0x00, 0x01},
+			"invalid picture header: not enough bits",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{}
+			e.Init()
+
+			_, err := e.Encode(ca.frame, 0)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
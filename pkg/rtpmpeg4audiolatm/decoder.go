@@ -0,0 +1,100 @@
+package rtpmpeg4audiolatm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4audio"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// Decoder is a RTP/MPEG4-audio LATM decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-6
+type Decoder struct {
+	// sample rate of input packets.
+	SampleRate int
+
+	// whether the StreamMuxConfig is transmitted in band.
+	CPresent bool
+
+	// StreamMuxConfig.
+	// It is mandatory when CPresent is false.
+	Config *mpeg4audio.StreamMuxConfig
+
+	timeDecoder     *rtptimedec.Decoder
+	element         mpeg4audio.AudioMuxElement
+	fragmentedParts [][]byte
+	fragmentedSize  int
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(d.SampleRate)
+	d.element = mpeg4audio.AudioMuxElement{
+		MuxConfigPresent: d.CPresent,
+		StreamMuxConfig:  d.Config,
+	}
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedSize = 0
+}
+
+// Decode decodes AUs from a RTP/MPEG4-audio LATM packet.
+// It returns the AUs of the first program and layer, and the PTS of the first AU.
+// The PTS of subsequent AUs can be calculated by adding time.Second*mpeg4audio.SamplesPerAccessUnit/clockRate.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, time.Duration, error) {
+	if len(pkt.Payload) == 0 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("payload is empty")
+	}
+
+	d.fragmentedSize += len(pkt.Payload)
+	if d.fragmentedSize > mpeg4audio.MaxAccessUnitSize {
+		errSize := d.fragmentedSize
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("AudioMuxElement size (%d) is too big (maximum is %d)",
+			errSize, mpeg4audio.MaxAccessUnitSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, pkt.Payload)
+
+	if !pkt.Header.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	var buf []byte
+	if len(d.fragmentedParts) == 1 {
+		buf = d.fragmentedParts[0]
+	} else {
+		buf = make([]byte, d.fragmentedSize)
+		n := 0
+		for _, p := range d.fragmentedParts {
+			n += copy(buf[n:], p)
+		}
+	}
+
+	d.resetFragments()
+
+	err := d.element.Unmarshal(buf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	aus := make([][]byte, len(d.element.Payloads))
+	for i, subFrame := range d.element.Payloads {
+		if len(subFrame) == 0 || len(subFrame[0]) == 0 {
+			return nil, 0, fmt.Errorf("sub frame doesn't contain any program or layer")
+		}
+		aus[i] = subFrame[0][0]
+	}
+
+	return aus, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
@@ -0,0 +1,127 @@
+package rtpmpeg4audiolatm
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4audio"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/MPEG4-audio LATM encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-6
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	// sample rate of packets.
+	SampleRate int
+
+	// whether to transmit the StreamMuxConfig in band.
+	CPresent bool
+
+	// StreamMuxConfig.
+	// It must contain a single program with a single layer and a single sub frame.
+	Config *mpeg4audio.StreamMuxConfig
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*float64(e.SampleRate))
+}
+
+// Encode encodes AUs into RTP/MPEG4-audio LATM packets.
+// Each AU is put into a dedicated AudioMuxElement, that is fragmented if it is too big.
+func (e *Encoder) Encode(aus [][]byte, firstPTS time.Duration) ([]*rtp.Packet, error) {
+	var rets []*rtp.Packet
+	ts := e.encodeTimestamp(firstPTS)
+
+	for _, au := range aus {
+		if len(au) > mpeg4audio.MaxAccessUnitSize {
+			return nil, fmt.Errorf("AU size (%d) is too big (maximum is %d)", len(au), mpeg4audio.MaxAccessUnitSize)
+		}
+
+		el := mpeg4audio.AudioMuxElement{
+			MuxConfigPresent: e.CPresent,
+			StreamMuxConfig:  e.Config,
+			Payloads:         [][][][]byte{{{au}}},
+		}
+
+		buf, err := el.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		for len(buf) > 0 {
+			le := len(buf)
+			if le > e.PayloadMaxSize {
+				le = e.PayloadMaxSize
+			}
+
+			rets = append(rets, &rtp.Packet{
+				Header: rtp.Header{
+					Version:        rtpVersion,
+					PayloadType:    e.PayloadType,
+					SequenceNumber: e.sequenceNumber,
+					Timestamp:      ts,
+					SSRC:           *e.SSRC,
+					Marker:         le == len(buf),
+				},
+				Payload: buf[:le],
+			})
+
+			e.sequenceNumber++
+			buf = buf[le:]
+		}
+
+		ts += mpeg4audio.SamplesPerAccessUnit
+	}
+
+	return rets, nil
+}
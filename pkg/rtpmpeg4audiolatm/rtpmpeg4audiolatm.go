@@ -0,0 +1,6 @@
+// Package rtpmpeg4audiolatm contains a RTP/MPEG4-audio LATM decoder and encoder.
+package rtpmpeg4audiolatm
+
+const (
+	rtpVersion = 0x02
+)
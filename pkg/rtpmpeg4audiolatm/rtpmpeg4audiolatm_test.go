@@ -0,0 +1,404 @@
+package rtpmpeg4audiolatm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4audio"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var testConfig = &mpeg4audio.StreamMuxConfig{
+	Programs: []*mpeg4audio.StreamMuxConfigProgram{{
+		Layers: []*mpeg4audio.StreamMuxConfigLayer{{
+			AudioSpecificConfig: &mpeg4audio.Config{
+				Type:         mpeg4audio.ObjectTypeAACLC,
+				SampleRate:   48000,
+				ChannelCount: 2,
+			},
+			LatmBufferFullness: 255,
+		}},
+	}},
+}
+
+var cases = []struct {
+	name     string
+	cpresent bool
+	aus      [][]byte
+	pts      time.Duration
+	pkts     []*rtp.Packet
+}{
+	{
+		"single",
+		false,
+		[][]byte{{0x01, 0x02, 0x03, 0x04}},
+		20 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x04, 0x01, 0x02, 0x03, 0x04},
+			},
+		},
+	},
+	{
+		"aggregated",
+		false,
+		[][]byte{{0x01, 0x02}, {0x03, 0x04}},
+		20 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x02, 0x01, 0x02},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289528341,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x02, 0x03, 0x04},
+			},
+		},
+	},
+	{
+		"fragmented",
+		false,
+		[][]byte{bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 500)},
+		20 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					bytes.Repeat([]byte{0xff}, 11),
+					[]byte{0xc3},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 241),
+					[]byte{0x01, 0x02},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x03, 0x04, 0x05, 0x06},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 242),
+					[]byte{0x01, 0x02, 0x03, 0x04},
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x05, 0x06},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 15),
+				),
+			},
+		},
+	},
+	{
+		"config present",
+		true,
+		[][]byte{{0x01, 0x02, 0x03, 0x04}},
+		20 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x20, 0x00, 0x11, 0x90, 0x1f, 0xe0, 0x20, 0x08,
+					0x10, 0x18, 0x20,
+				},
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				SampleRate: 48000,
+				CPresent:   ca.cpresent,
+				Config: func() *mpeg4audio.StreamMuxConfig {
+					if ca.cpresent {
+						return nil
+					}
+					return testConfig
+				}(),
+			}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			_, _, err := d.Decode(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: func() []byte {
+					if ca.cpresent {
+						return []byte{
+							0x20, 0x00, 0x11, 0x90, 0x1f, 0xe0, 0x00, 0x08,
+						}
+					}
+					return []byte{0x01, 0x01}
+				}(),
+			})
+			require.NoError(t, err)
+
+			var aus [][]byte
+			expPTS := ca.pts
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				addAUs, pts, err := d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, expPTS, pts)
+				aus = append(aus, addAUs...)
+				expPTS += time.Duration(len(addAUs)) * mpeg4audio.SamplesPerAccessUnit * time.Second / 48000
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.aus, aus)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"empty payload",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289526357,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{},
+				},
+			},
+			"payload is empty",
+		},
+		{
+			"payload too short",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289526357,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x04, 0x01, 0x02},
+				},
+			},
+			"not enough bits",
+		},
+		{
+			"element too big",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289526357,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: bytes.Repeat([]byte{0x01}, 3000),
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289526357,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: bytes.Repeat([]byte{0x01}, 3000),
+				},
+			},
+			"AudioMuxElement size (6000) is too big (maximum is 5120)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				SampleRate: 48000,
+				Config:     testConfig,
+			}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestDecodeInvalidInBandConfig(t *testing.T) {
+	d := &Decoder{
+		SampleRate: 48000,
+		CPresent:   true,
+	}
+	d.Init()
+
+	// StreamMuxConfig with audioMuxVersion = 1
+	_, _, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289526357,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x40},
+	})
+	require.EqualError(t, err, "audioMuxVersion = 1 is not supported")
+
+	// element that reuses the previous StreamMuxConfig
+	payload, err := hex.DecodeString("fcb6c84703dd101ac77cf000e49b2a33f748a9d6993340fe25a5f5")
+	require.NoError(t, err)
+
+	_, _, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      2289526357,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: payload,
+	})
+	require.EqualError(t, err, "StreamMuxConfig is missing")
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType: 96,
+				SampleRate:  48000,
+				CPresent:    ca.cpresent,
+				Config:      testConfig,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.aus, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		SampleRate:  48000,
+		Config:      testConfig,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
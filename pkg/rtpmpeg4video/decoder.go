@@ -0,0 +1,106 @@
+package rtpmpeg4video
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4video"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented frame and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+func hasStartCode(payload []byte) bool {
+	return len(payload) >= 4 && payload[0] == 0 && payload[1] == 0 && payload[2] == 1
+}
+
+// Decoder is a RTP/MPEG4-video decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-5
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragmentedMode      bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+	fragmentedTimestamp uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedMode = false
+}
+
+// Decode decodes a MPEG-4 video frame from a RTP/MPEG4-video packet.
+// Frames are returned when the packet with the marker flag is received.
+// mpeg4video.IsIVOP() can be used to find the first I-VOP of a stream.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	if len(pkt.Payload) == 0 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("payload is empty")
+	}
+
+	if !d.fragmentedMode {
+		if !hasStartCode(pkt.Payload) {
+			if !d.firstPacketReceived {
+				return nil, 0, ErrNonStartingPacketAndNoPrevious
+			}
+			return nil, 0, fmt.Errorf("received a non-starting fragment")
+		}
+
+		d.firstPacketReceived = true
+
+		if pkt.Marker {
+			return pkt.Payload, d.timeDecoder.Decode(pkt.Timestamp), nil
+		}
+
+		d.fragmentedMode = true
+		d.fragmentedParts = append(d.fragmentedParts, pkt.Payload)
+		d.fragmentedSize = len(pkt.Payload)
+		d.fragmentedTimestamp = pkt.Timestamp
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	if pkt.Timestamp != d.fragmentedTimestamp {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("received a fragment with a different timestamp")
+	}
+
+	d.fragmentedSize += len(pkt.Payload)
+	if d.fragmentedSize > mpeg4video.MaxFrameSize {
+		errSize := d.fragmentedSize
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("frame size (%d) is too big (maximum is %d)", errSize, mpeg4video.MaxFrameSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, pkt.Payload)
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := make([]byte, d.fragmentedSize)
+	n := 0
+	for _, p := range d.fragmentedParts {
+		n += copy(ret[n:], p)
+	}
+
+	d.resetFragments()
+
+	return ret, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
@@ -0,0 +1,107 @@
+package rtpmpeg4video
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4video"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/MPEG4-video encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-5
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes a MPEG-4 video frame into RTP/MPEG4-video packets.
+// The frame must begin with a start code.
+func (e *Encoder) Encode(frame []byte, pts time.Duration) ([]*rtp.Packet, error) {
+	if !hasStartCode(frame) {
+		return nil, fmt.Errorf("frame does not begin with a start code")
+	}
+
+	if len(frame) > mpeg4video.MaxFrameSize {
+		return nil, fmt.Errorf("frame size (%d) is too big (maximum is %d)", len(frame), mpeg4video.MaxFrameSize)
+	}
+
+	packetCount := (len(frame) + e.PayloadMaxSize - 1) / e.PayloadMaxSize
+	ret := make([]*rtp.Packet, packetCount)
+	encPTS := e.encodeTimestamp(pts)
+
+	for i := range ret {
+		le := len(frame)
+		if le > e.PayloadMaxSize {
+			le = e.PayloadMaxSize
+		}
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         i == (packetCount - 1),
+			},
+			Payload: frame[:le],
+		}
+
+		e.sequenceNumber++
+		frame = frame[le:]
+	}
+
+	return ret, nil
+}
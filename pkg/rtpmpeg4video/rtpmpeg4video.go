@@ -0,0 +1,7 @@
+// Package rtpmpeg4video contains a RTP/MPEG4-video decoder and encoder.
+package rtpmpeg4video
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // MPEG-4 video always uses 90khz
+)
@@ -0,0 +1,334 @@
+package rtpmpeg4video
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBytes(vals ...[]byte) []byte {
+	size := 0
+	for _, v := range vals {
+		size += len(v)
+	}
+	res := make([]byte, size)
+
+	pos := 0
+	for _, v := range vals {
+		n := copy(res[pos:], v)
+		pos += n
+	}
+
+	return res
+}
+
+var cases = []struct {
+	name  string
+	frame []byte
+	pts   time.Duration
+	pkts  []*rtp.Packet
+}{
+	{
+		"single",
+		[]byte{0x00, 0x00, 0x01, 0xb6, 0x10, 0x60, 0x91, 0x82},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289528607,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x00, 0x00, 0x01, 0xb6, 0x10, 0x60, 0x91, 0x82},
+			},
+		},
+	},
+	{
+		"fragmented",
+		mergeBytes(
+			[]byte{0x00, 0x00, 0x01, 0xb6},
+			bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 750),
+		),
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0x00, 0x00, 0x01, 0xb6},
+					bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 364),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 365),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289531307,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 21),
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x00, 0x00, 0x01, 0xb6, 0x10},
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var frame []byte
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				var pts time.Duration
+				frame, pts, err = d.Decode(pkt)
+				if err == ErrMorePacketsNeeded {
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, ca.pts, pts)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.frame, frame)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts []*rtp.Packet
+		err  string
+	}{
+		{
+			"missing payload",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+				},
+			},
+			"payload is empty",
+		},
+		{
+			"non-starting fragment",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x00, 0x00, 0x01, 0xb6, 0x10},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527318,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x01, 0x02},
+				},
+			},
+			"received a non-starting fragment",
+		},
+		{
+			"different timestamp",
+			[]*rtp.Packet{
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x00, 0x00, 0x01, 0xb6, 0x10},
+				},
+				{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         true,
+						PayloadType:    96,
+						SequenceNumber: 17646,
+						Timestamp:      2289527318,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x01, 0x02},
+				},
+			},
+			"received a fragment with a different timestamp",
+		},
+		{
+			"frame too big",
+			func() []*rtp.Packet {
+				ret := []*rtp.Packet{{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         false,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: []byte{0x00, 0x00, 0x01, 0xb6},
+				}}
+
+				for i := 0; i < 1024; i++ {
+					ret = append(ret, &rtp.Packet{
+						Header: rtp.Header{
+							Version:        2,
+							Marker:         false,
+							PayloadType:    96,
+							SequenceNumber: uint16(17646 + i),
+							Timestamp:      2289527317,
+							SSRC:           0x9dbb7812,
+						},
+						Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 256),
+					})
+				}
+
+				return ret
+			}(),
+			"frame size (1048580) is too big (maximum is 1048576)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			var lastErr error
+			for _, pkt := range ca.pkts {
+				_, _, lastErr = d.Decode(pkt)
+			}
+			require.EqualError(t, lastErr, ca.err)
+		})
+	}
+}
+
+func TestDecodeNonStartingPacketAndNoPrevious(t *testing.T) {
+	d := &Decoder{}
+	d.Init()
+
+	_, _, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x01, 0x02},
+	})
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType: 96,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.frame, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+
+	_, err := e.Encode([]byte{0x01, 0x02, 0x03, 0x04}, 0)
+	require.EqualError(t, err, "frame does not begin with a start code")
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
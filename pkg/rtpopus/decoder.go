@@ -0,0 +1,51 @@
+package rtpopus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/opus"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// Decoder is a RTP/Opus decoder.
+type Decoder struct {
+	// called when the PTS of a packet is different from the one
+	// expected by using the PTS and duration of the previous packet (optional).
+	// This happens when packets are lost or when the transmitter
+	// is using discontinuous transmission (DTX).
+	OnTimestampGap func(expectedPTS time.Duration, pts time.Duration)
+
+	timeDecoder *rtptimedec.Decoder
+	nextPTS     *time.Duration
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+// Decode decodes an Opus packet from a RTP/Opus packet.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	if len(pkt.Payload) == 0 {
+		return nil, 0, fmt.Errorf("payload is too short")
+	}
+
+	dur := opus.PacketDuration(pkt.Payload)
+	if dur == 0 {
+		return nil, 0, fmt.Errorf("invalid TOC")
+	}
+
+	pts := d.timeDecoder.Decode(pkt.Timestamp)
+
+	if d.nextPTS != nil && pts != *d.nextPTS && d.OnTimestampGap != nil {
+		d.OnTimestampGap(*d.nextPTS, pts)
+	}
+
+	nextPTS := pts + dur
+	d.nextPTS = &nextPTS
+
+	return pkt.Payload, pts, nil
+}
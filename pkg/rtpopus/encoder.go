@@ -0,0 +1,101 @@
+package rtpopus
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/opus"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/Opus encoder.
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes Opus packets into RTP/Opus packets.
+// The PTS of subsequent packets is computed by using the duration of the previous ones.
+func (e *Encoder) Encode(packets [][]byte, firstPTS time.Duration) ([]*rtp.Packet, error) {
+	ret := make([]*rtp.Packet, len(packets))
+	pts := firstPTS
+
+	for i, packet := range packets {
+		dur := opus.PacketDuration(packet)
+		if dur == 0 {
+			return nil, fmt.Errorf("invalid Opus packet")
+		}
+
+		if len(packet) > e.PayloadMaxSize {
+			return nil, fmt.Errorf("packet size (%d) is too big (maximum is %d)", len(packet), e.PayloadMaxSize)
+		}
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      e.encodeTimestamp(pts),
+				SSRC:           *e.SSRC,
+				Marker:         false,
+			},
+			Payload: packet,
+		}
+
+		e.sequenceNumber++
+		pts += dur
+	}
+
+	return ret, nil
+}
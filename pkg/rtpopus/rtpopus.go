@@ -0,0 +1,7 @@
+// Package rtpopus contains a RTP/Opus decoder and encoder.
+package rtpopus
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 48000 // Opus always uses 48khz
+)
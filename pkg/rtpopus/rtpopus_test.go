@@ -0,0 +1,244 @@
+package rtpopus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+var cases = []struct {
+	name    string
+	packets [][]byte
+	pts     time.Duration
+	pkts    []*rtp.Packet
+}{
+	{
+		"single",
+		[][]byte{{0xfc, 0x01, 0x02}},
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527557,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0xfc, 0x01, 0x02},
+			},
+		},
+	},
+	{
+		"multiple",
+		[][]byte{
+			{0xfc, 0x01, 0x02},
+			{0xfd, 0x03, 0x04},
+			{0xf4, 0x05, 0x06},
+		},
+		55 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289528997,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0xfc, 0x01, 0x02},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					Timestamp:      2289529957,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0xfd, 0x03, 0x04},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					Timestamp:      2289531877,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0xf4, 0x05, 0x06},
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0xfc, 0x01, 0x02},
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			// the initial packet is not contiguous with the others
+			d.nextPTS = nil
+			d.OnTimestampGap = func(expectedPTS time.Duration, pts time.Duration) {
+				t.Errorf("unexpected gap")
+			}
+
+			var packets [][]byte
+			expPTS := ca.pts
+
+			for i, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				packet, pts, err := d.Decode(pkt)
+				require.NoError(t, err)
+				require.Equal(t, expPTS, pts)
+				packets = append(packets, packet)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+
+				expPTS += []time.Duration{
+					20 * time.Millisecond,
+					40 * time.Millisecond,
+					10 * time.Millisecond,
+				}[i]
+			}
+
+			require.Equal(t, ca.packets, packets)
+		})
+	}
+}
+
+func TestDecodeTimestampGap(t *testing.T) {
+	var gapExpected time.Duration
+	var gapPTS time.Duration
+
+	d := &Decoder{
+		OnTimestampGap: func(expectedPTS time.Duration, pts time.Duration) {
+			gapExpected = expectedPTS
+			gapPTS = pts
+		},
+	}
+	d.Init()
+
+	for _, ts := range []uint32{1000, 1960, 3880} {
+		_, _, err := d.Decode(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      ts,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0xfc, 0x01, 0x02},
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 40*time.Millisecond, gapExpected)
+	require.Equal(t, 60*time.Millisecond, gapPTS)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkt  *rtp.Packet
+		err  string
+	}{
+		{
+			"missing payload",
+			&rtp.Packet{},
+			"payload is too short",
+		},
+		{
+			"invalid TOC",
+			&rtp.Packet{
+				Payload: []byte{0xfb},
+			},
+			"invalid TOC",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			d.Init()
+
+			_, _, err := d.Decode(ca.pkt)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType: 96,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.packets, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	e := &Encoder{
+		PayloadType:    96,
+		PayloadMaxSize: 2,
+	}
+	e.Init()
+
+	_, err := e.Encode([][]byte{{}}, 0)
+	require.EqualError(t, err, "invalid Opus packet")
+
+	_, err = e.Encode([][]byte{{0xfc, 0x01, 0x02}}, 0)
+	require.EqualError(t, err, "packet size (3) is too big (maximum is 2)")
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
@@ -11,11 +11,14 @@ const (
 // Reorderer filters incoming RTP packets, in order to
 // - order packets
 // - remove duplicate packets
+// - detect missing packets
 type Reorderer struct {
 	initialized    bool
 	expectedSeqNum uint16
+	highestSeqNum  uint16
 	buffer         []*rtp.Packet
 	absPos         uint16
+	missing        []uint16
 }
 
 // New allocates a Reorderer.
@@ -30,6 +33,7 @@ func (r *Reorderer) Process(pkt *rtp.Packet) []*rtp.Packet {
 	if !r.initialized {
 		r.initialized = true
 		r.expectedSeqNum = pkt.SequenceNumber + 1
+		r.highestSeqNum = pkt.SequenceNumber
 		return []*rtp.Packet{pkt}
 	}
 
@@ -70,7 +74,11 @@ func (r *Reorderer) Process(pkt *rtp.Packet) []*rtp.Packet {
 			r.buffer[i] = nil
 		}
 
+		// missing packets are lost and can't be recovered anymore.
+		r.missing = nil
+
 		r.expectedSeqNum = pkt.SequenceNumber + 1
+		r.highestSeqNum = pkt.SequenceNumber
 		return ret
 	}
 
@@ -85,6 +93,15 @@ func (r *Reorderer) Process(pkt *rtp.Packet) []*rtp.Packet {
 
 		// put current packet in buffer
 		r.buffer[p] = pkt
+
+		// packets between the highest received packet and the current one are missing
+		if diff := pkt.SequenceNumber - r.highestSeqNum; diff > 0 && diff < 0x8000 {
+			for seq := r.highestSeqNum + 1; seq != pkt.SequenceNumber; seq++ {
+				r.missing = append(r.missing, seq)
+			}
+			r.highestSeqNum = pkt.SequenceNumber
+		}
+
 		return nil
 	}
 
@@ -114,5 +131,18 @@ func (r *Reorderer) Process(pkt *rtp.Packet) []*rtp.Packet {
 
 	r.expectedSeqNum = pkt.SequenceNumber + n
 
+	if diff := r.expectedSeqNum - 1 - r.highestSeqNum; diff < 0x8000 {
+		r.highestSeqNum = r.expectedSeqNum - 1
+	}
+
+	return ret
+}
+
+// Missing returns the sequence numbers of the packets that have been detected
+// as missing since the last call.
+// It is meant to be called after Process(), in order to request retransmissions.
+func (r *Reorderer) Missing() []uint16 {
+	ret := r.missing
+	r.missing = nil
 	return ret
 }
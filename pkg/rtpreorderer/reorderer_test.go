@@ -212,3 +212,29 @@ func TestBufferIsFull(t *testing.T) {
 
 	require.Equal(t, expected, out)
 }
+
+func TestMissing(t *testing.T) {
+	r := New()
+
+	for _, entry := range []struct {
+		seq     uint16
+		missing []uint16
+	}{
+		{65533, nil},
+		{65534, nil},
+		{1, []uint16{65535, 0}},
+		{65535, nil},
+		{4, []uint16{2, 3}},
+		{0, nil},
+		{2, nil},
+		{3, nil},
+		{5, nil},
+	} {
+		r.Process(&rtp.Packet{
+			Header: rtp.Header{
+				SequenceNumber: entry.seq,
+			},
+		})
+		require.Equal(t, entry.missing, r.Missing())
+	}
+}
@@ -0,0 +1,125 @@
+// Package rtpretransmitter contains a utility to retransmit lost RTP packets
+// with the RTP retransmission payload format (RFC 4588).
+package rtpretransmitter
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// number of packets that are kept in the history.
+	// it must be a power of two.
+	historySize = 512
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+type historyEntry struct {
+	sequenceNumber uint16
+	byts           []byte
+}
+
+// Retransmitter is a utility that stores outgoing RTP packets
+// and generates retransmission packets when they are requested by receivers.
+// Retransmission packets are sent in a separate stream, with their own
+// payload type and SSRC (session multiplexing).
+type Retransmitter struct {
+	payloadType uint8
+	ssrc        uint32
+	mutex       sync.Mutex
+
+	history        []*historyEntry
+	sequenceNumber uint16
+}
+
+// New allocates a Retransmitter.
+// payloadType is the payload type of retransmission packets.
+func New(payloadType uint8) *Retransmitter {
+	return &Retransmitter{
+		payloadType:    payloadType,
+		ssrc:           randUint32(),
+		history:        make([]*historyEntry, historySize),
+		sequenceNumber: uint16(randUint32()),
+	}
+}
+
+// PayloadType returns the payload type of retransmission packets.
+func (r *Retransmitter) PayloadType() uint8 {
+	return r.payloadType
+}
+
+// Add adds a RTP packet to the history.
+// byts is the marshaled packet; it is copied.
+func (r *Retransmitter) Add(sequenceNumber uint16, byts []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.history[sequenceNumber&(historySize-1)] = &historyEntry{
+		sequenceNumber: sequenceNumber,
+		byts:           append([]byte(nil), byts...),
+	}
+}
+
+// Retransmit returns a retransmission packet that contains the packet
+// with the given sequence number, or nil if the packet is not in the history anymore.
+func (r *Retransmitter) Retransmit(sequenceNumber uint16) *rtp.Packet {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry := r.history[sequenceNumber&(historySize-1)]
+	if entry == nil || entry.sequenceNumber != sequenceNumber {
+		return nil
+	}
+
+	var orig rtp.Packet
+	err := orig.Unmarshal(entry.byts)
+	if err != nil {
+		return nil
+	}
+
+	payload := make([]byte, 2+len(orig.Payload))
+	payload[0] = byte(orig.SequenceNumber >> 8)
+	payload[1] = byte(orig.SequenceNumber)
+	copy(payload[2:], orig.Payload)
+
+	pkt := &rtp.Packet{
+		Header:  orig.Header,
+		Payload: payload,
+	}
+	pkt.Padding = false
+	pkt.PaddingSize = 0
+	pkt.PayloadType = r.payloadType
+	pkt.SSRC = r.ssrc
+	pkt.SequenceNumber = r.sequenceNumber
+	r.sequenceNumber++
+
+	return pkt
+}
+
+// Decode decodes a retransmission packet into the original packet.
+// payloadType and ssrc are the ones of the original stream.
+func Decode(pkt *rtp.Packet, payloadType uint8, ssrc uint32) (*rtp.Packet, error) {
+	if len(pkt.Payload) < 2 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	out := &rtp.Packet{
+		Header:  pkt.Header,
+		Payload: pkt.Payload[2:],
+	}
+	out.Padding = false
+	out.PaddingSize = 0
+	out.PayloadType = payloadType
+	out.SSRC = ssrc
+	out.SequenceNumber = uint16(pkt.Payload[0])<<8 | uint16(pkt.Payload[1])
+
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package rtpretransmitter
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetransmit(t *testing.T) {
+	r := New(97)
+
+	for i := uint16(0); i < 10; i++ {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 65530 + i,
+				Timestamp:      1234,
+				SSRC:           0x38F27A2F,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, byte(i)},
+		}
+		byts, err := pkt.Marshal()
+		require.NoError(t, err)
+		r.Add(pkt.SequenceNumber, byts)
+	}
+
+	rtx1 := r.Retransmit(65532)
+	require.NotNil(t, rtx1)
+	require.Equal(t, uint8(97), rtx1.PayloadType)
+	require.Equal(t, []byte{0xff, 0xfc, 0x01, 0x02, 0x03, 0x02}, rtx1.Payload)
+
+	rtx2 := r.Retransmit(1)
+	require.NotNil(t, rtx2)
+	require.Equal(t, rtx1.SequenceNumber+1, rtx2.SequenceNumber)
+	require.Equal(t, rtx1.SSRC, rtx2.SSRC)
+
+	dec, err := Decode(rtx1, 96, 0x38F27A2F)
+	require.NoError(t, err)
+	require.Equal(t, &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 65532,
+			Timestamp:      1234,
+			SSRC:           0x38F27A2F,
+			CSRC:           []uint32{},
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x02},
+	}, dec)
+}
+
+func TestRetransmitNotInHistory(t *testing.T) {
+	r := New(97)
+
+	for i := 0; i < historySize+1; i++ {
+		byts, err := (&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				SequenceNumber: uint16(i),
+			},
+		}).Marshal()
+		require.NoError(t, err)
+		r.Add(uint16(i), byts)
+	}
+
+	require.Nil(t, r.Retransmit(0))
+	require.Nil(t, r.Retransmit(historySize+2))
+	require.NotNil(t, r.Retransmit(historySize))
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode(&rtp.Packet{Payload: []byte{0x01}}, 96, 1)
+	require.EqualError(t, err, "payload is too short")
+}
@@ -0,0 +1,55 @@
+package rtpsimpleaudio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// Decoder is a RTP decoder for audio codecs that use a fixed sample size.
+type Decoder struct {
+	// sample rate of input packets.
+	SampleRate int
+
+	// bit depth of samples (optional).
+	// It defaults to 8.
+	BitDepth int
+
+	// channel count of samples (optional).
+	// It defaults to 1.
+	ChannelCount int
+
+	timeDecoder *rtptimedec.Decoder
+	sampleSize  int
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	if d.BitDepth == 0 {
+		d.BitDepth = 8
+	}
+	if d.ChannelCount == 0 {
+		d.ChannelCount = 1
+	}
+
+	d.timeDecoder = rtptimedec.New(d.SampleRate)
+	d.sampleSize = d.BitDepth * d.ChannelCount / 8
+}
+
+// Decode decodes samples from a RTP packet.
+// It returns the samples and the PTS of the first sample.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	le := len(pkt.Payload)
+	if le == 0 {
+		return nil, 0, fmt.Errorf("payload is empty")
+	}
+
+	if (le % d.sampleSize) != 0 {
+		return nil, 0, fmt.Errorf("payload size (%d) is not a multiple of sample size (%d)", le, d.sampleSize)
+	}
+
+	return pkt.Payload, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
@@ -0,0 +1,146 @@
+package rtpsimpleaudio
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP encoder for audio codecs that use a fixed sample size.
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// sample rate of packets.
+	SampleRate int
+
+	// bit depth of samples (optional).
+	// It defaults to 8.
+	BitDepth int
+
+	// channel count of samples (optional).
+	// It defaults to 1.
+	ChannelCount int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	// maximum duration of packets (optional).
+	// It defaults to 20ms.
+	PacketMaxDuration time.Duration
+
+	sequenceNumber  uint16
+	sampleSize      int
+	maxSamplesCount int
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.BitDepth == 0 {
+		e.BitDepth = 8
+	}
+	if e.ChannelCount == 0 {
+		e.ChannelCount = 1
+	}
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+	if e.PacketMaxDuration == 0 {
+		e.PacketMaxDuration = 20 * time.Millisecond
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.sampleSize = e.BitDepth * e.ChannelCount / 8
+
+	e.maxSamplesCount = int(e.PacketMaxDuration * time.Duration(e.SampleRate) / time.Second)
+	if (e.maxSamplesCount * e.sampleSize) > e.PayloadMaxSize {
+		e.maxSamplesCount = e.PayloadMaxSize / e.sampleSize
+	}
+	if e.maxSamplesCount == 0 {
+		e.maxSamplesCount = 1
+	}
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*float64(e.SampleRate))
+}
+
+// Encode encodes samples into RTP packets.
+// Samples are split into packets whose duration does not exceed PacketMaxDuration.
+func (e *Encoder) Encode(samples []byte, pts time.Duration) ([]*rtp.Packet, error) {
+	le := len(samples)
+	if le == 0 {
+		return nil, fmt.Errorf("samples are empty")
+	}
+
+	if (le % e.sampleSize) != 0 {
+		return nil, fmt.Errorf("samples size (%d) is not a multiple of sample size (%d)", le, e.sampleSize)
+	}
+
+	maxPayloadSize := e.maxSamplesCount * e.sampleSize
+	packetCount := le / maxPayloadSize
+	if (le % maxPayloadSize) != 0 {
+		packetCount++
+	}
+
+	ret := make([]*rtp.Packet, packetCount)
+	encPTS := e.encodeTimestamp(pts)
+
+	for i := range ret {
+		n := maxPayloadSize
+		if n > len(samples) {
+			n = len(samples)
+		}
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         false,
+			},
+			Payload: samples[:n],
+		}
+
+		e.sequenceNumber++
+		encPTS += uint32(n / e.sampleSize)
+		samples = samples[n:]
+	}
+
+	return ret, nil
+}
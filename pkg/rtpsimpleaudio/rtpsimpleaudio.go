@@ -0,0 +1,7 @@
+// Package rtpsimpleaudio contains a RTP decoder and encoder for audio codecs
+// that use a fixed sample size, like G711, G722 and LPCM.
+package rtpsimpleaudio
+
+const (
+	rtpVersion = 0x02
+)
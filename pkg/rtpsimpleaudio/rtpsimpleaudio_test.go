@@ -0,0 +1,219 @@
+package rtpsimpleaudio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+var cases = []struct {
+	name         string
+	sampleRate   int
+	bitDepth     int
+	channelCount int
+	samples      []byte
+	pts          time.Duration
+	pkts         []*rtp.Packet
+}{
+	{
+		"g711",
+		8000,
+		8,
+		1,
+		bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 100),
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17645,
+					Timestamp:      2289526557,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 40),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17646,
+					Timestamp:      2289526717,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 40),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17647,
+					Timestamp:      2289526877,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 20),
+			},
+		},
+	},
+	{
+		"lpcm 24 bit stereo",
+		48000,
+		24,
+		2,
+		bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 300),
+		25 * time.Millisecond,
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17645,
+					Timestamp:      2289527557,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 243),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17646,
+					Timestamp:      2289527800,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 57),
+			},
+		},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				SampleRate:   ca.sampleRate,
+				BitDepth:     ca.bitDepth,
+				ChannelCount: ca.channelCount,
+			}
+			d.Init()
+
+			// send an initial packet downstream
+			// in order to compute the right timestamp,
+			// that is relative to the initial packet
+			pkt := rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    0,
+					SequenceNumber: 17645,
+					Timestamp:      2289526357,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: ca.samples[:6],
+			}
+			_, _, err := d.Decode(&pkt)
+			require.NoError(t, err)
+
+			var samples []byte
+			expPTS := ca.pts
+
+			for _, pkt := range ca.pkts {
+				clone := pkt.Clone()
+
+				partial, pts, err := d.Decode(pkt)
+				require.NoError(t, err)
+				require.Equal(t, expPTS, pts)
+				samples = append(samples, partial...)
+				expPTS += time.Duration(len(partial)/(ca.bitDepth*ca.channelCount/8)) *
+					time.Second / time.Duration(ca.sampleRate)
+
+				// test input integrity
+				require.Equal(t, clone, pkt)
+			}
+
+			require.Equal(t, ca.samples, samples)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	d := &Decoder{
+		SampleRate:   48000,
+		BitDepth:     16,
+		ChannelCount: 2,
+	}
+	d.Init()
+
+	_, _, err := d.Decode(&rtp.Packet{})
+	require.EqualError(t, err, "payload is empty")
+
+	_, _, err = d.Decode(&rtp.Packet{
+		Payload: []byte{0x01, 0x02, 0x03},
+	})
+	require.EqualError(t, err, "payload size (3) is not a multiple of sample size (4)")
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:  0,
+				SampleRate:   ca.sampleRate,
+				BitDepth:     ca.bitDepth,
+				ChannelCount: ca.channelCount,
+				SSRC: func() *uint32 {
+					v := uint32(0x9dbb7812)
+					return &v
+				}(),
+				InitialSequenceNumber: func() *uint16 {
+					v := uint16(0x44ed)
+					return &v
+				}(),
+				InitialTimestamp: func() *uint32 {
+					v := uint32(0x88776655)
+					return &v
+				}(),
+			}
+			e.Init()
+
+			pkts, err := e.Encode(ca.samples, ca.pts)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	e := &Encoder{
+		PayloadType:  0,
+		SampleRate:   48000,
+		BitDepth:     16,
+		ChannelCount: 2,
+	}
+	e.Init()
+
+	_, err := e.Encode([]byte{}, 0)
+	require.EqualError(t, err, "samples are empty")
+
+	_, err = e.Encode([]byte{0x01, 0x02, 0x03}, 0)
+	require.EqualError(t, err, "samples size (3) is not a multiple of sample size (4)")
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 0,
+		SampleRate:  8000,
+	}
+	e.Init()
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+	require.NotEqual(t, nil, e.InitialTimestamp)
+}
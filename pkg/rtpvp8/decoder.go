@@ -0,0 +1,110 @@
+package rtpvp8
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/rtptimedec"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a fragmented frame and we didn't received anything before.
+// It's normal to receive this when we are decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting fragment without any previous starting fragment")
+
+// Decoder is a RTP/VP8 decoder.
+type Decoder struct {
+	timeDecoder         *rtptimedec.Decoder
+	firstPacketReceived bool
+	fragmentedMode      bool
+	fragmentedParts     [][]byte
+	fragmentedSize      int
+	fragmentedTimestamp uint32
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() {
+	d.timeDecoder = rtptimedec.New(rtpClockRate)
+}
+
+func (d *Decoder) resetFragments() {
+	d.fragmentedParts = d.fragmentedParts[:0]
+	d.fragmentedMode = false
+}
+
+// Decode decodes a VP8 frame from a RTP/VP8 packet.
+// Frames are returned when the packet with the marker flag is received.
+// IsKeyFrame() can be used to find the first key frame of a stream.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, time.Duration, error) {
+	var desc payloadDescriptor
+	n, err := desc.unmarshal(pkt.Payload)
+	if err != nil {
+		d.resetFragments()
+		return nil, 0, err
+	}
+
+	payload := pkt.Payload[n:]
+	if len(payload) == 0 {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("payload is too short")
+	}
+
+	if desc.StartOfPartition && desc.PartitionID == 0 {
+		// discard any incomplete frame
+		d.resetFragments()
+
+		d.firstPacketReceived = true
+
+		if pkt.Marker {
+			return payload, d.timeDecoder.Decode(pkt.Timestamp), nil
+		}
+
+		d.fragmentedMode = true
+		d.fragmentedParts = append(d.fragmentedParts, payload)
+		d.fragmentedSize = len(payload)
+		d.fragmentedTimestamp = pkt.Timestamp
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	if !d.fragmentedMode {
+		if !d.firstPacketReceived {
+			return nil, 0, ErrNonStartingPacketAndNoPrevious
+		}
+		return nil, 0, fmt.Errorf("received a non-starting fragment")
+	}
+
+	if pkt.Timestamp != d.fragmentedTimestamp {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("received a fragment with a different timestamp")
+	}
+
+	d.fragmentedSize += len(payload)
+	if d.fragmentedSize > maxFrameSize {
+		d.resetFragments()
+		return nil, 0, fmt.Errorf("frame size (%d) is too big (maximum is %d)", d.fragmentedSize, maxFrameSize)
+	}
+
+	d.fragmentedParts = append(d.fragmentedParts, payload)
+
+	if !pkt.Marker {
+		return nil, 0, ErrMorePacketsNeeded
+	}
+
+	ret := make([]byte, d.fragmentedSize)
+	n = 0
+	for _, p := range d.fragmentedParts {
+		n += copy(ret[n:], p)
+	}
+
+	d.resetFragments()
+
+	return ret, d.timeDecoder.Decode(pkt.Timestamp), nil
+}
@@ -0,0 +1,107 @@
+package rtpvp8
+
+import (
+	"fmt"
+)
+
+// payloadDescriptor is a VP8 payload descriptor.
+// Specification: RFC 7741, section 4.2
+type payloadDescriptor struct {
+	NonReferenceFrame bool
+	StartOfPartition  bool
+	PartitionID       uint8
+
+	// I == 1
+	PictureID *uint16
+
+	// L == 1
+	TL0PICIDX *uint8
+
+	// T == 1
+	TID *uint8
+	Y   bool
+	// K == 1
+	KEYIDX *uint8
+}
+
+func (d *payloadDescriptor) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 1 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	x := (buf[0] & 0x80) != 0
+	d.NonReferenceFrame = (buf[0] & 0x20) != 0
+	d.StartOfPartition = (buf[0] & 0x10) != 0
+	d.PartitionID = buf[0] & 0x07
+	d.PictureID = nil
+	d.TL0PICIDX = nil
+	d.TID = nil
+	d.Y = false
+	d.KEYIDX = nil
+	pos := 1
+
+	if !x {
+		return pos, nil
+	}
+
+	if len(buf) < 2 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	i := (buf[1] & 0x80) != 0
+	l := (buf[1] & 0x40) != 0
+	t := (buf[1] & 0x20) != 0
+	k := (buf[1] & 0x10) != 0
+	pos++
+
+	if i {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		if (buf[pos] & 0x80) != 0 { // M
+			if len(buf) < (pos + 2) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			v := uint16(buf[pos]&0x7F)<<8 | uint16(buf[pos+1])
+			d.PictureID = &v
+			pos += 2
+		} else {
+			v := uint16(buf[pos])
+			d.PictureID = &v
+			pos++
+		}
+	}
+
+	if l {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		v := buf[pos]
+		d.TL0PICIDX = &v
+		pos++
+	}
+
+	if t || k {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		if t {
+			v := buf[pos] >> 6
+			d.TID = &v
+			d.Y = (buf[pos] & 0x20) != 0
+		}
+
+		if k {
+			v := buf[pos] & 0x1F
+			d.KEYIDX = &v
+		}
+
+		pos++
+	}
+
+	return pos, nil
+}
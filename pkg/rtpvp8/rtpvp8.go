@@ -0,0 +1,16 @@
+// Package rtpvp8 contains a RTP/VP8 decoder and encoder.
+package rtpvp8
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // VP8 always uses 90khz
+
+	// maximum size of a frame.
+	maxFrameSize = 3 * 1024 * 1024
+)
+
+// IsKeyFrame checks whether a VP8 frame is a key frame.
+func IsKeyFrame(frame []byte) bool {
+	// frame tag: the first bit is the inverse keyframe flag
+	return len(frame) >= 3 && (frame[0]&0x01) == 0
+}
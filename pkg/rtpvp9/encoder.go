@@ -0,0 +1,132 @@
+package rtpvp9
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+func randUint32() uint32 {
+	var b [4]byte
+	rand.Read(b[:])
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// Encoder is a RTP/VP9 encoder.
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// initial timestamp of packets (optional).
+	// It defaults to a random value.
+	InitialTimestamp *uint32
+
+	// initial picture ID of frames (optional).
+	// It defaults to a random value.
+	InitialPictureID *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	sequenceNumber uint16
+	pictureID      uint16
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() {
+	if e.SSRC == nil {
+		v := randUint32()
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v := uint16(randUint32())
+		e.InitialSequenceNumber = &v
+	}
+	if e.InitialTimestamp == nil {
+		v := randUint32()
+		e.InitialTimestamp = &v
+	}
+	if e.InitialPictureID == nil {
+		v := uint16(randUint32()) & 0x7FFF
+		e.InitialPictureID = &v
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.pictureID = *e.InitialPictureID & 0x7FFF
+}
+
+func (e *Encoder) encodeTimestamp(ts time.Duration) uint32 {
+	return *e.InitialTimestamp + uint32(ts.Seconds()*rtpClockRate)
+}
+
+// Encode encodes a VP9 frame into RTP/VP9 packets.
+func (e *Encoder) Encode(frame []byte, pts time.Duration) ([]*rtp.Packet, error) {
+	// flags + 15-bit picture ID
+	descSize := 3
+
+	flags := byte(0x80) // I
+	if !IsKeyFrame(frame) {
+		flags |= 0x40 // P
+	}
+
+	avail := e.PayloadMaxSize - descSize
+	packetCount := len(frame) / avail
+	lastPacketSize := len(frame) % avail
+	if lastPacketSize > 0 {
+		packetCount++
+	}
+
+	ret := make([]*rtp.Packet, packetCount)
+	encPTS := e.encodeTimestamp(pts)
+
+	for i := range ret {
+		le := avail
+		if i == (packetCount - 1) {
+			le = len(frame)
+		}
+
+		data := make([]byte, descSize+le)
+		data[0] = flags
+		if i == 0 {
+			data[0] |= 0x08 // B
+		}
+		if i == (packetCount - 1) {
+			data[0] |= 0x04 // E
+		}
+		data[1] = 0x80 | byte(e.pictureID>>8)
+		data[2] = byte(e.pictureID)
+		copy(data[descSize:], frame[:le])
+		frame = frame[le:]
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      encPTS,
+				SSRC:           *e.SSRC,
+				Marker:         (i == (packetCount - 1)),
+			},
+			Payload: data,
+		}
+
+		e.sequenceNumber++
+	}
+
+	e.pictureID = (e.pictureID + 1) & 0x7FFF
+
+	return ret, nil
+}
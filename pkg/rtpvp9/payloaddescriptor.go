@@ -0,0 +1,205 @@
+package rtpvp9
+
+import (
+	"fmt"
+)
+
+// payloadDescriptor is a VP9 payload descriptor.
+// Specification: RFC 9628, section 4.2
+type payloadDescriptor struct {
+	InterPicturePredicted bool
+	FlexibleMode          bool
+	StartOfFrame          bool
+	EndOfFrame            bool
+	NotReference          bool
+
+	// I == 1
+	PictureID *uint16
+
+	// L == 1
+	TID                  uint8
+	SwitchingUpPoint     bool
+	SID                  uint8
+	InterLayerDependency bool
+	// L == 1 and F == 0
+	TL0PICIDX *uint8
+
+	// F == 1 and P == 1
+	PDiffs []uint8
+
+	// V == 1
+	ScalabilityStructure *scalabilityStructure
+}
+
+type scalabilityStructureResolution struct {
+	Width  uint16
+	Height uint16
+}
+
+type scalabilityStructurePicture struct {
+	TID              uint8
+	SwitchingUpPoint bool
+	PDiffs           []uint8
+}
+
+// scalabilityStructure is a VP9 scalability structure.
+// Specification: RFC 9628, section 4.2.1
+type scalabilityStructure struct {
+	NumSpatialLayers uint8
+	Resolutions      []scalabilityStructureResolution
+	Pictures         []scalabilityStructurePicture
+}
+
+func (s *scalabilityStructure) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 1 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	s.NumSpatialLayers = (buf[0] >> 5) + 1
+	y := (buf[0] & 0x10) != 0
+	g := (buf[0] & 0x08) != 0
+	s.Resolutions = nil
+	s.Pictures = nil
+	pos := 1
+
+	if y {
+		if len(buf) < (pos + 4*int(s.NumSpatialLayers)) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		s.Resolutions = make([]scalabilityStructureResolution, s.NumSpatialLayers)
+		for i := range s.Resolutions {
+			s.Resolutions[i].Width = uint16(buf[pos])<<8 | uint16(buf[pos+1])
+			s.Resolutions[i].Height = uint16(buf[pos+2])<<8 | uint16(buf[pos+3])
+			pos += 4
+		}
+	}
+
+	if g {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		ng := int(buf[pos])
+		pos++
+
+		s.Pictures = make([]scalabilityStructurePicture, ng)
+		for i := range s.Pictures {
+			if len(buf) < (pos + 1) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			s.Pictures[i].TID = buf[pos] >> 5
+			s.Pictures[i].SwitchingUpPoint = (buf[pos] & 0x10) != 0
+			r := int((buf[pos] >> 2) & 0x03)
+			pos++
+
+			if len(buf) < (pos + r) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			s.Pictures[i].PDiffs = make([]uint8, r)
+			copy(s.Pictures[i].PDiffs, buf[pos:pos+r])
+			pos += r
+		}
+	}
+
+	return pos, nil
+}
+
+func (d *payloadDescriptor) unmarshal(buf []byte) (int, error) {
+	if len(buf) < 1 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	i := (buf[0] & 0x80) != 0
+	d.InterPicturePredicted = (buf[0] & 0x40) != 0
+	l := (buf[0] & 0x20) != 0
+	d.FlexibleMode = (buf[0] & 0x10) != 0
+	d.StartOfFrame = (buf[0] & 0x08) != 0
+	d.EndOfFrame = (buf[0] & 0x04) != 0
+	v := (buf[0] & 0x02) != 0
+	d.NotReference = (buf[0] & 0x01) != 0
+	d.PictureID = nil
+	d.TID = 0
+	d.SwitchingUpPoint = false
+	d.SID = 0
+	d.InterLayerDependency = false
+	d.TL0PICIDX = nil
+	d.PDiffs = nil
+	d.ScalabilityStructure = nil
+	pos := 1
+
+	if i {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		if (buf[pos] & 0x80) != 0 { // M
+			if len(buf) < (pos + 2) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			v := uint16(buf[pos]&0x7F)<<8 | uint16(buf[pos+1])
+			d.PictureID = &v
+			pos += 2
+		} else {
+			v := uint16(buf[pos])
+			d.PictureID = &v
+			pos++
+		}
+	}
+
+	if l {
+		if len(buf) < (pos + 1) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		d.TID = buf[pos] >> 5
+		d.SwitchingUpPoint = (buf[pos] & 0x10) != 0
+		d.SID = (buf[pos] >> 1) & 0x07
+		d.InterLayerDependency = (buf[pos] & 0x01) != 0
+		pos++
+
+		if !d.FlexibleMode {
+			if len(buf) < (pos + 1) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			v := buf[pos]
+			d.TL0PICIDX = &v
+			pos++
+		}
+	}
+
+	if d.FlexibleMode && d.InterPicturePredicted {
+		for {
+			if len(d.PDiffs) == 3 {
+				return 0, fmt.Errorf("too many reference indices")
+			}
+
+			if len(buf) < (pos + 1) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			d.PDiffs = append(d.PDiffs, buf[pos]>>1)
+			n := (buf[pos] & 0x01) != 0
+			pos++
+
+			if !n {
+				break
+			}
+		}
+	}
+
+	if v {
+		d.ScalabilityStructure = &scalabilityStructure{}
+		n, err := d.ScalabilityStructure.unmarshal(buf[pos:])
+		if err != nil {
+			return 0, err
+		}
+		pos += n
+	}
+
+	return pos, nil
+}
@@ -0,0 +1,42 @@
+// Package rtpvp9 contains a RTP/VP9 decoder and encoder.
+package rtpvp9
+
+const (
+	rtpVersion   = 0x02
+	rtpClockRate = 90000 // VP9 always uses 90khz
+	maxFrameSize = 3 * 1024 * 1024
+)
+
+// IsKeyFrame checks whether a VP9 frame is a key frame.
+// Specification: VP9 Bitstream Specification, section 6.2
+func IsKeyFrame(frame []byte) bool {
+	if len(frame) < 1 {
+		return false
+	}
+
+	// frame_marker
+	if (frame[0] >> 6) != 0x02 {
+		return false
+	}
+
+	profile := ((frame[0] >> 5) & 0x01) | ((frame[0] >> 3) & 0x02)
+	pos := 4
+
+	if profile == 3 {
+		// reserved_zero
+		pos++
+	}
+
+	if len(frame) < ((pos+2)/8 + 1) {
+		return false
+	}
+
+	showExistingFrame := (frame[pos/8] >> (7 - (pos % 8))) & 0x01
+	if showExistingFrame != 0 {
+		return false
+	}
+	pos++
+
+	frameType := (frame[pos/8] >> (7 - (pos % 8))) & 0x01
+	return frameType == 0
+}
@@ -0,0 +1,473 @@
+// Package srtp contains an implementation of SRTP and SRTCP (RFC 3711),
+// with the AES_CM_128_HMAC_SHA1_80 and AES_CM_128_HMAC_SHA1_32 protection profiles.
+package srtp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"sync"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// KeyLen is the length of a master key.
+	KeyLen = 16
+
+	// SaltLen is the length of a master salt.
+	SaltLen = 14
+
+	authKeyLen      = 20
+	srtcpIndexLen   = 4
+	srtcpTagLen     = 10
+	replayWindowLen = 64
+	maxSRTCPIndex   = 0x7FFFFFFF
+
+	// maximum number of SSRCs whose state is tracked when decrypting.
+	maxDecryptSSRCs = 64
+)
+
+// key derivation labels.
+const (
+	labelRTPEncryption  = 0x00
+	labelRTPAuth        = 0x01
+	labelRTPSalt        = 0x02
+	labelRTCPEncryption = 0x03
+	labelRTCPAuth       = 0x04
+	labelRTCPSalt       = 0x05
+)
+
+// ProtectionProfile is a SRTP protection profile.
+type ProtectionProfile int
+
+// protection profiles.
+const (
+	ProtectionProfileAES128CMHMACSHA180 ProtectionProfile = iota
+	ProtectionProfileAES128CMHMACSHA132
+)
+
+// String implements fmt.Stringer.
+// It returns the name of the profile used in SDP a=crypto attributes (RFC 4568).
+func (p ProtectionProfile) String() string {
+	if p == ProtectionProfileAES128CMHMACSHA132 {
+		return "AES_CM_128_HMAC_SHA1_32"
+	}
+	return "AES_CM_128_HMAC_SHA1_80"
+}
+
+func (p ProtectionProfile) rtpTagLen() int {
+	if p == ProtectionProfileAES128CMHMACSHA132 {
+		return 4
+	}
+	return 10
+}
+
+// GenerateMasterKey generates a random master key and master salt.
+func GenerateMasterKey() ([]byte, []byte, error) {
+	key := make([]byte, KeyLen)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	salt := make([]byte, SaltLen)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, salt, nil
+}
+
+// deriveKey derives a session key from the master key and salt (RFC 3711, section 4.3.1),
+// with a key derivation rate of zero.
+func deriveKey(masterBlock cipher.Block, masterSalt []byte, label byte, l int) []byte {
+	var iv [16]byte
+	copy(iv[:], masterSalt)
+	iv[7] ^= label
+
+	out := make([]byte, l)
+	cipher.NewCTR(masterBlock, iv[:]).XORKeyStream(out, out)
+	return out
+}
+
+type sessionKeys struct {
+	block cipher.Block
+	salt  []byte
+	auth  hash.Hash
+}
+
+func newSessionKeys(masterBlock cipher.Block, masterSalt []byte, labelEnc, labelAuth, labelSalt byte) (*sessionKeys, error) {
+	block, err := aes.NewCipher(deriveKey(masterBlock, masterSalt, labelEnc, KeyLen))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionKeys{
+		block: block,
+		salt:  deriveKey(masterBlock, masterSalt, labelSalt, SaltLen),
+		auth:  hmac.New(sha1.New, deriveKey(masterBlock, masterSalt, labelAuth, authKeyLen)),
+	}, nil
+}
+
+// xorKeyStream applies AES in counter mode (RFC 3711, section 4.1.1).
+func (k *sessionKeys) xorKeyStream(buf []byte, ssrc uint32, index uint64) {
+	var iv [16]byte
+	copy(iv[:], k.salt)
+
+	var tmp [4]byte
+	binary.BigEndian.PutUint32(tmp[:], ssrc)
+	for i := 0; i < 4; i++ {
+		iv[4+i] ^= tmp[i]
+	}
+
+	for i := 0; i < 6; i++ {
+		iv[8+i] ^= byte(index >> (8 * (5 - i)))
+	}
+
+	cipher.NewCTR(k.block, iv[:]).XORKeyStream(buf, buf)
+}
+
+func (k *sessionKeys) tag(parts ...[]byte) []byte {
+	k.auth.Reset()
+	for _, p := range parts {
+		k.auth.Write(p)
+	}
+	return k.auth.Sum(nil)
+}
+
+type rtpState struct {
+	initialized bool
+	roc         uint32
+	lastSeq     uint16
+	replay      replayWindow
+}
+
+// estimateROC estimates the rollover counter of a packet (RFC 3711, appendix A).
+func (s *rtpState) estimateROC(seq uint16) uint32 {
+	if !s.initialized {
+		return s.roc
+	}
+
+	if s.lastSeq < 0x8000 {
+		if seq > s.lastSeq && seq-s.lastSeq > 0x8000 {
+			return s.roc - 1
+		}
+		return s.roc
+	}
+
+	if s.lastSeq-0x8000 > seq {
+		return s.roc + 1
+	}
+	return s.roc
+}
+
+func (s *rtpState) update(seq uint16, roc uint32) {
+	switch {
+	case !s.initialized:
+		s.initialized = true
+		s.roc = roc
+		s.lastSeq = seq
+
+	case roc == s.roc && seq > s.lastSeq:
+		s.lastSeq = seq
+
+	case roc == s.roc+1:
+		s.roc = roc
+		s.lastSeq = seq
+	}
+}
+
+type rtcpState struct {
+	index  uint32
+	replay replayWindow
+}
+
+// replayWindow is a sliding window that detects replayed packets (RFC 3711, section 3.3.2).
+type replayWindow struct {
+	initialized bool
+	highest     uint64
+	mask        uint64
+}
+
+func (w *replayWindow) check(index uint64) bool {
+	if !w.initialized || index > w.highest {
+		return true
+	}
+
+	diff := w.highest - index
+	if diff >= replayWindowLen {
+		return false
+	}
+
+	return (w.mask & (1 << diff)) == 0
+}
+
+func (w *replayWindow) add(index uint64) {
+	switch {
+	case !w.initialized:
+		w.initialized = true
+		w.highest = index
+		w.mask = 1
+
+	case index > w.highest:
+		diff := index - w.highest
+		if diff >= replayWindowLen {
+			w.mask = 1
+		} else {
+			w.mask = (w.mask << diff) | 1
+		}
+		w.highest = index
+
+	default:
+		w.mask |= 1 << (w.highest - index)
+	}
+}
+
+// Context is a SRTP cryptographic context.
+// It can encrypt and decrypt packets of multiple SSRCs, and can be used
+// by multiple routines at once.
+// When decrypting, the state of at most 64 SSRCs is tracked, and packets
+// of additional SSRCs are discarded.
+type Context struct {
+	profile ProtectionProfile
+
+	mutex      sync.Mutex
+	rtpKeys    *sessionKeys
+	rtcpKeys   *sessionKeys
+	rtpStates  map[uint32]*rtpState
+	rtcpStates map[uint32]*rtcpState
+}
+
+// NewContext allocates a Context.
+func NewContext(profile ProtectionProfile, masterKey []byte, masterSalt []byte) (*Context, error) {
+	if profile != ProtectionProfileAES128CMHMACSHA180 &&
+		profile != ProtectionProfileAES128CMHMACSHA132 {
+		return nil, fmt.Errorf("unsupported protection profile")
+	}
+
+	if len(masterKey) != KeyLen {
+		return nil, fmt.Errorf("invalid master key length: %d", len(masterKey))
+	}
+
+	if len(masterSalt) != SaltLen {
+		return nil, fmt.Errorf("invalid master salt length: %d", len(masterSalt))
+	}
+
+	masterBlock, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpKeys, err := newSessionKeys(masterBlock, masterSalt,
+		labelRTPEncryption, labelRTPAuth, labelRTPSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	rtcpKeys, err := newSessionKeys(masterBlock, masterSalt,
+		labelRTCPEncryption, labelRTCPAuth, labelRTCPSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Context{
+		profile:    profile,
+		rtpKeys:    rtpKeys,
+		rtcpKeys:   rtcpKeys,
+		rtpStates:  make(map[uint32]*rtpState),
+		rtcpStates: make(map[uint32]*rtcpState),
+	}, nil
+}
+
+// Profile returns the protection profile of the context.
+func (c *Context) Profile() ProtectionProfile {
+	return c.profile
+}
+
+func (c *Context) rtpState(ssrc uint32) *rtpState {
+	s, ok := c.rtpStates[ssrc]
+	if !ok {
+		s = &rtpState{}
+		c.rtpStates[ssrc] = s
+	}
+	return s
+}
+
+func (c *Context) rtcpState(ssrc uint32) *rtcpState {
+	s, ok := c.rtcpStates[ssrc]
+	if !ok {
+		s = &rtcpState{}
+		c.rtcpStates[ssrc] = s
+	}
+	return s
+}
+
+// EncryptRTP encrypts a RTP packet. It returns a SRTP packet.
+func (c *Context) EncryptRTP(byts []byte) ([]byte, error) {
+	var h rtp.Header
+	headerLen, err := h.Unmarshal(byts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.rtpState(h.SSRC)
+	roc := s.estimateROC(h.SequenceNumber)
+	s.update(h.SequenceNumber, roc)
+
+	tagLen := c.profile.rtpTagLen()
+	out := make([]byte, len(byts), len(byts)+tagLen)
+	copy(out, byts)
+
+	c.rtpKeys.xorKeyStream(out[headerLen:], h.SSRC, uint64(roc)<<16|uint64(h.SequenceNumber))
+
+	var rocBuf [4]byte
+	binary.BigEndian.PutUint32(rocBuf[:], roc)
+	out = append(out, c.rtpKeys.tag(out, rocBuf[:])[:tagLen]...)
+
+	return out, nil
+}
+
+// DecryptRTP decrypts a SRTP packet. It returns a RTP packet.
+func (c *Context) DecryptRTP(byts []byte) ([]byte, error) {
+	tagLen := c.profile.rtpTagLen()
+
+	var h rtp.Header
+	headerLen, err := h.Unmarshal(byts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(byts) < headerLen+tagLen {
+		return nil, fmt.Errorf("packet is too short")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// do not store the state of a new SSRC until the packet is authenticated,
+	// otherwise anyone could fill the map with random SSRCs.
+	s, ok := c.rtpStates[h.SSRC]
+	if !ok {
+		if len(c.rtpStates) >= maxDecryptSSRCs {
+			return nil, fmt.Errorf("too many SSRCs")
+		}
+		s = &rtpState{}
+	}
+
+	roc := s.estimateROC(h.SequenceNumber)
+	index := uint64(roc)<<16 | uint64(h.SequenceNumber)
+
+	if !s.replay.check(index) {
+		return nil, fmt.Errorf("replayed packet")
+	}
+
+	authPortion := byts[:len(byts)-tagLen]
+
+	var rocBuf [4]byte
+	binary.BigEndian.PutUint32(rocBuf[:], roc)
+	tag := c.rtpKeys.tag(authPortion, rocBuf[:])[:tagLen]
+
+	if subtle.ConstantTimeCompare(tag, byts[len(byts)-tagLen:]) != 1 {
+		return nil, fmt.Errorf("authentication failed")
+	}
+
+	if !ok {
+		c.rtpStates[h.SSRC] = s
+	}
+
+	s.update(h.SequenceNumber, roc)
+	s.replay.add(index)
+
+	out := make([]byte, len(authPortion))
+	copy(out, authPortion)
+	c.rtpKeys.xorKeyStream(out[headerLen:], h.SSRC, index)
+
+	return out, nil
+}
+
+// EncryptRTCP encrypts a compound RTCP packet. It returns a SRTCP packet.
+func (c *Context) EncryptRTCP(byts []byte) ([]byte, error) {
+	if len(byts) < 8 {
+		return nil, fmt.Errorf("packet is too short")
+	}
+
+	ssrc := binary.BigEndian.Uint32(byts[4:8])
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.rtcpState(ssrc)
+	index := s.index
+	s.index = (s.index + 1) & maxSRTCPIndex
+
+	out := make([]byte, len(byts), len(byts)+srtcpIndexLen+srtcpTagLen)
+	copy(out, byts)
+
+	c.rtcpKeys.xorKeyStream(out[8:], ssrc, uint64(index))
+
+	// set the E flag
+	out = append(out, byte(index>>24)|0x80, byte(index>>16), byte(index>>8), byte(index))
+	out = append(out, c.rtcpKeys.tag(out)[:srtcpTagLen]...)
+
+	return out, nil
+}
+
+// DecryptRTCP decrypts a SRTCP packet. It returns a compound RTCP packet.
+func (c *Context) DecryptRTCP(byts []byte) ([]byte, error) {
+	if len(byts) < 8+srtcpIndexLen+srtcpTagLen {
+		return nil, fmt.Errorf("packet is too short")
+	}
+
+	ssrc := binary.BigEndian.Uint32(byts[4:8])
+
+	authPortion := byts[:len(byts)-srtcpTagLen]
+	indexPos := len(authPortion) - srtcpIndexLen
+	encrypted := (authPortion[indexPos] & 0x80) != 0
+	index := binary.BigEndian.Uint32(authPortion[indexPos:]) & maxSRTCPIndex
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, ok := c.rtcpStates[ssrc]
+	if !ok {
+		if len(c.rtcpStates) >= maxDecryptSSRCs {
+			return nil, fmt.Errorf("too many SSRCs")
+		}
+		s = &rtcpState{}
+	}
+
+	if !s.replay.check(uint64(index)) {
+		return nil, fmt.Errorf("replayed packet")
+	}
+
+	tag := c.rtcpKeys.tag(authPortion)[:srtcpTagLen]
+	if subtle.ConstantTimeCompare(tag, byts[len(byts)-srtcpTagLen:]) != 1 {
+		return nil, fmt.Errorf("authentication failed")
+	}
+
+	if !ok {
+		c.rtcpStates[ssrc] = s
+	}
+
+	s.replay.add(uint64(index))
+
+	out := make([]byte, indexPos)
+	copy(out, authPortion[:indexPos])
+
+	if encrypted {
+		c.rtcpKeys.xorKeyStream(out[8:], ssrc, uint64(index))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,248 @@
+package srtp
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecodeHex(s string) []byte {
+	byts, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return byts
+}
+
+func TestKeyDerivation(t *testing.T) {
+	// RFC 3711, appendix B.3
+	masterBlock, err := aes.NewCipher(mustDecodeHex("E1F97A0D3E018BE0D64FA32C06DE4139"))
+	require.NoError(t, err)
+	masterSalt := mustDecodeHex("0EC675AD498AFEEBB6960B3AABE6")
+
+	require.Equal(t,
+		mustDecodeHex("C61E7A93744F39EE10734AFE3FF7A087"),
+		deriveKey(masterBlock, masterSalt, labelRTPEncryption, KeyLen))
+
+	require.Equal(t,
+		mustDecodeHex("30CBBC08863D8C85D49DB34A9AE1"),
+		deriveKey(masterBlock, masterSalt, labelRTPSalt, SaltLen))
+
+	require.Equal(t,
+		mustDecodeHex("CEBE321F6FF7716B6FD4AB49AF256A156D38BAA4"),
+		deriveKey(masterBlock, masterSalt, labelRTPAuth, authKeyLen))
+}
+
+func TestKeyStream(t *testing.T) {
+	// RFC 3711, appendix B.2
+	block, err := aes.NewCipher(mustDecodeHex("2B7E151628AED2A6ABF7158809CF4F3C"))
+	require.NoError(t, err)
+
+	k := &sessionKeys{
+		block: block,
+		salt:  mustDecodeHex("F0F1F2F3F4F5F6F7F8F9FAFBFCFD"),
+	}
+
+	buf := make([]byte, 48)
+	k.xorKeyStream(buf, 0, 0)
+
+	require.Equal(t, mustDecodeHex("E03EAD0935C95E80E166B16DD92B4EB4"+
+		"D23513162B02D0F72A43A2FE4A5F97AB"+
+		"41E95B3BB0A2E8DD477901E4FCA894C0"), buf)
+}
+
+func newTestContexts(t *testing.T, profile ProtectionProfile) (*Context, *Context) {
+	key, salt, err := GenerateMasterKey()
+	require.NoError(t, err)
+
+	enc, err := NewContext(profile, key, salt)
+	require.NoError(t, err)
+
+	dec, err := NewContext(profile, key, salt)
+	require.NoError(t, err)
+
+	return enc, dec
+}
+
+func TestRTP(t *testing.T) {
+	for _, profile := range []ProtectionProfile{
+		ProtectionProfileAES128CMHMACSHA180,
+		ProtectionProfileAES128CMHMACSHA132,
+	} {
+		t.Run(profile.String(), func(t *testing.T) {
+			enc, dec := newTestContexts(t, profile)
+
+			// cross the sequence number boundary in order to test the rollover counter
+			for _, seq := range []uint16{65533, 65534, 65535, 0, 1, 2} {
+				pkt := rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: seq,
+						Timestamp:      123456,
+						SSRC:           0x38F27A2F,
+					},
+					Payload: []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+				}
+				plain, err := pkt.Marshal()
+				require.NoError(t, err)
+
+				encrypted, err := enc.EncryptRTP(plain)
+				require.NoError(t, err)
+				require.Equal(t, len(plain)+profile.rtpTagLen(), len(encrypted))
+				require.Equal(t, plain[:12], encrypted[:12])
+				require.NotEqual(t, plain[12:], encrypted[12:len(plain)])
+
+				decrypted, err := dec.DecryptRTP(encrypted)
+				require.NoError(t, err)
+				require.Equal(t, plain, decrypted)
+
+				_, err = dec.DecryptRTP(encrypted)
+				require.EqualError(t, err, "replayed packet")
+			}
+
+			require.Equal(t, uint32(1), dec.rtpStates[0x38F27A2F].roc)
+		})
+	}
+}
+
+func TestRTPErrors(t *testing.T) {
+	enc, dec := newTestContexts(t, ProtectionProfileAES128CMHMACSHA180)
+
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 946,
+			SSRC:           0x38F27A2F,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	}
+	plain, err := pkt.Marshal()
+	require.NoError(t, err)
+
+	encrypted, err := enc.EncryptRTP(plain)
+	require.NoError(t, err)
+
+	encrypted[13] ^= 0xFF
+	_, err = dec.DecryptRTP(encrypted)
+	require.EqualError(t, err, "authentication failed")
+
+	_, err = dec.DecryptRTP(encrypted[:14])
+	require.EqualError(t, err, "packet is too short")
+
+	_, err = NewContext(ProtectionProfileAES128CMHMACSHA180, []byte{1, 2}, make([]byte, SaltLen))
+	require.EqualError(t, err, "invalid master key length: 2")
+}
+
+func TestDecryptSSRCState(t *testing.T) {
+	enc, dec := newTestContexts(t, ProtectionProfileAES128CMHMACSHA180)
+
+	rtpPkt := func(ssrc uint32) []byte {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 946,
+				SSRC:           ssrc,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		}
+		plain, err := pkt.Marshal()
+		require.NoError(t, err)
+
+		encrypted, err := enc.EncryptRTP(plain)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	rtcpPkt := func(ssrc uint32) []byte {
+		plain, err := (&rtcp.ReceiverReport{SSRC: ssrc}).Marshal()
+		require.NoError(t, err)
+
+		encrypted, err := enc.EncryptRTCP(plain)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	// packets that fail authentication must not allocate any state
+	for i := uint32(0); i < 100; i++ {
+		encrypted := rtpPkt(i)
+		encrypted[len(encrypted)-1] ^= 0xFF
+		_, err := dec.DecryptRTP(encrypted)
+		require.EqualError(t, err, "authentication failed")
+
+		encrypted = rtcpPkt(i)
+		encrypted[len(encrypted)-1] ^= 0xFF
+		_, err = dec.DecryptRTCP(encrypted)
+		require.EqualError(t, err, "authentication failed")
+	}
+	require.Equal(t, 0, len(dec.rtpStates))
+	require.Equal(t, 0, len(dec.rtcpStates))
+
+	for i := uint32(0); i < maxDecryptSSRCs; i++ {
+		_, err := dec.DecryptRTP(rtpPkt(1000 + i))
+		require.NoError(t, err)
+
+		_, err = dec.DecryptRTCP(rtcpPkt(1000 + i))
+		require.NoError(t, err)
+	}
+
+	_, err := dec.DecryptRTP(rtpPkt(5000))
+	require.EqualError(t, err, "too many SSRCs")
+
+	_, err = dec.DecryptRTCP(rtcpPkt(5000))
+	require.EqualError(t, err, "too many SSRCs")
+}
+
+func TestRTCP(t *testing.T) {
+	enc, dec := newTestContexts(t, ProtectionProfileAES128CMHMACSHA180)
+
+	for i := 0; i < 3; i++ {
+		pkt := rtcp.ReceiverReport{
+			SSRC: 0x38F27A2F,
+			Reports: []rtcp.ReceptionReport{{
+				SSRC:               0x1234ABCD,
+				LastSequenceNumber: uint32(i),
+			}},
+		}
+		plain, err := pkt.Marshal()
+		require.NoError(t, err)
+
+		encrypted, err := enc.EncryptRTCP(plain)
+		require.NoError(t, err)
+		require.Equal(t, len(plain)+srtcpIndexLen+srtcpTagLen, len(encrypted))
+		require.Equal(t, plain[:8], encrypted[:8])
+
+		decrypted, err := dec.DecryptRTCP(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, plain, decrypted)
+
+		_, err = dec.DecryptRTCP(encrypted)
+		require.EqualError(t, err, "replayed packet")
+
+		encrypted[9] ^= 0xFF
+		_, err = dec.DecryptRTCP(encrypted)
+		require.Error(t, err)
+	}
+}
+
+func TestRTPKnownAnswer(t *testing.T) {
+	ctx, err := NewContext(ProtectionProfileAES128CMHMACSHA180,
+		mustDecodeHex("E1F97A0D3E018BE0D64FA32C06DE4139"),
+		mustDecodeHex("0EC675AD498AFEEBB6960B3AABE6"))
+	require.NoError(t, err)
+
+	plain := mustDecodeHex("800F1234DECAFBADCAFEBABE" +
+		"ABABABABABABABABABABABABABABABAB")
+
+	encrypted, err := ctx.EncryptRTP(plain)
+	require.NoError(t, err)
+	require.Equal(t, mustDecodeHex("800F1234DECAFBADCAFEBABE"+
+		"4E55DC4CE79978D88CA4D215949D2402"+
+		"B78D6ACC99EA179B8DBB"), encrypted)
+}
@@ -11,6 +11,8 @@ import (
 // URL is a RTSP URL.
 // This is basically an HTTP URL with some additional functions to handle
 // control attributes.
+// Supported schemes are rtsp, rtsps (RTSP over TLS), rtsph (RTSP over HTTP tunnel)
+// and rtsphs (RTSP over HTTPS tunnel).
 type URL url.URL
 
 var escapeRegexp = regexp.MustCompile(`^(.+?)://(.*?)@(.*?)/(.*?)$`)
@@ -30,7 +32,9 @@ func Parse(s string) (*URL, error) {
 		return nil, err
 	}
 
-	if u.Scheme != "rtsp" && u.Scheme != "rtsps" {
+	switch u.Scheme {
+	case "rtsp", "rtsps", "rtsph", "rtsphs":
+	default:
 		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
 	}
 
@@ -31,6 +31,15 @@ func TestURLParse(t *testing.T) {
 				User:   url.UserPassword("user", "pa#ss"),
 			},
 		},
+		{
+			"http tunnel",
+			`rtsph://localhost:8080/stream`,
+			&URL{
+				Scheme: "rtsph",
+				Host:   "localhost:8080",
+				Path:   "/stream",
+			},
+		},
 	} {
 		t.Run(ca.name, func(t *testing.T) {
 			u, err := Parse(ca.enc)
@@ -0,0 +1,115 @@
+package wsconn
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	gourl "net/url"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Dial opens a WebSocket connection with a server.
+// u is the URL of the WebSocket endpoint (ws:// or wss://).
+// tlsConfig is used with wss:// URLs; if nil, a default configuration is used.
+func Dial(
+	ctx context.Context,
+	dialContext func(ctx context.Context, network, address string) (net.Conn, error),
+	u string,
+	tlsConfig *tls.Config,
+) (*Conn, error) {
+	pu, err := gourl.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if pu.Scheme != "ws" && pu.Scheme != "wss" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", pu.Scheme)
+	}
+
+	address := pu.Host
+
+	// add default port
+	_, _, err = net.SplitHostPort(address)
+	if err != nil {
+		if pu.Scheme == "ws" {
+			address = net.JoinHostPort(address, "80")
+		} else {
+			address = net.JoinHostPort(address, "443")
+		}
+	}
+
+	nconn, err := dialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if pu.Scheme == "wss" {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = pu.Hostname()
+		}
+
+		tconn := tls.Client(nconn, tlsConfig)
+		err := tconn.HandshakeContext(ctx)
+		if err != nil {
+			nconn.Close()
+			return nil, err
+		}
+		nconn = tconn
+	}
+
+	// use the host of the URL, in order to match the Host header
+	origin := "http://" + pu.Host
+	if pu.Scheme == "wss" {
+		origin = "https://" + pu.Host
+	}
+
+	config, err := websocket.NewConfig(u, origin)
+	if err != nil {
+		nconn.Close()
+		return nil, err
+	}
+	config.Protocol = []string{Protocol}
+
+	// abort the handshake when the context is done
+	handshakeDone := make(chan struct{})
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		select {
+		case <-ctx.Done():
+			nconn.SetDeadline(time.Now())
+		case <-handshakeDone:
+		}
+	}()
+
+	ws, err := websocket.NewClient(config, nconn)
+
+	close(handshakeDone)
+	<-watcherDone
+
+	if err != nil {
+		nconn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		ws.Close()
+		return nil, ctx.Err()
+	}
+
+	nconn.SetDeadline(time.Time{})
+
+	return newConn(ws, nconn.LocalAddr(), nconn.RemoteAddr()), nil
+}
@@ -0,0 +1,79 @@
+package wsconn
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	gourl "net/url"
+	"strings"
+
+	"golang.org/x/net/websocket"
+)
+
+func resolveAddr(s string) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", s)
+	if err != nil {
+		return &net.TCPAddr{}
+	}
+	return addr
+}
+
+// sameOrigin accepts requests without an Origin header, that are not sent by browsers,
+// and requests whose Origin has the same host of the request.
+func sameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := gourl.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, req.Host)
+}
+
+// Handler returns a HTTP handler that upgrades requests to WebSocket connections
+// and passes them to onConn.
+// The connection is closed when onConn returns, therefore onConn must block
+// until the connection is not needed anymore.
+// checkOrigin validates the Origin header, in order to prevent web pages of other
+// sites from opening connections through the browser of their visitors.
+// If nil, only requests without an Origin header or with the same host are accepted.
+func Handler(onConn func(*Conn), checkOrigin func(*http.Request) bool) http.Handler {
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			if !checkOrigin(req) {
+				return fmt.Errorf("origin not allowed")
+			}
+
+			// use the RTSP subprotocol if the client asked for it
+			protocols := config.Protocol
+			config.Protocol = nil
+			for _, p := range protocols {
+				if p == Protocol {
+					config.Protocol = []string{Protocol}
+					break
+				}
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			req := ws.Request()
+
+			localAddr := func() net.Addr {
+				if addr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+					return addr
+				}
+				return &net.TCPAddr{}
+			}()
+
+			onConn(newConn(ws, localAddr, resolveAddr(req.RemoteAddr)))
+		},
+	}
+}
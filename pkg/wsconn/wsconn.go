@@ -0,0 +1,76 @@
+// Package wsconn contains an implementation of RTSP-over-WebSocket,
+// that allows to carry RTSP requests, responses and interleaved frames
+// inside WebSocket binary messages.
+package wsconn
+
+import (
+	"net"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Protocol is the WebSocket subprotocol used to carry RTSP.
+const Protocol = "rtsp"
+
+// Conn is a RTSP-over-WebSocket connection.
+// It implements net.Conn, therefore it can be used in place of a TCP connection.
+type Conn struct {
+	ws         *websocket.Conn
+	localAddr  net.Addr
+	remoteAddr net.Addr
+}
+
+func newConn(ws *websocket.Conn, localAddr net.Addr, remoteAddr net.Addr) *Conn {
+	ws.PayloadType = websocket.BinaryFrame
+
+	return &Conn{
+		ws:         ws,
+		localAddr:  localAddr,
+		remoteAddr: remoteAddr,
+	}
+}
+
+// Read implements net.Conn.
+// Message boundaries are not preserved.
+func (c *Conn) Read(p []byte) (int, error) {
+	return c.ws.Read(p)
+}
+
+// Write implements net.Conn.
+// Every call produces a binary message.
+func (c *Conn) Write(p []byte) (int, error) {
+	return c.ws.Write(p)
+}
+
+// Close implements net.Conn.
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+// LocalAddr implements net.Conn.
+// It returns the address of the underlying TCP connection.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+// RemoteAddr implements net.Conn.
+// It returns the address of the underlying TCP connection.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// SetDeadline implements net.Conn.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.ws.SetDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
@@ -0,0 +1,119 @@
+package wsconn
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn(t *testing.T) {
+	serverDone := make(chan struct{})
+
+	hs := httptest.NewServer(Handler(func(c *Conn) {
+		defer close(serverDone)
+
+		require.IsType(t, &net.TCPAddr{}, c.RemoteAddr())
+		require.IsType(t, &net.TCPAddr{}, c.LocalAddr())
+
+		buf := make([]byte, 10)
+		_, err := io.ReadFull(c, buf)
+		require.NoError(t, err)
+		require.Equal(t, []byte("0123456789"), buf)
+
+		_, err = c.Write([]byte("abcdef"))
+		require.NoError(t, err)
+	}, nil))
+	defer hs.Close()
+
+	c, err := Dial(context.Background(), (&net.Dialer{}).DialContext,
+		"ws"+strings.TrimPrefix(hs.URL, "http")+"/path", nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// message boundaries are not preserved
+	_, err = c.Write([]byte("01234"))
+	require.NoError(t, err)
+	_, err = c.Write([]byte("56789"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 6)
+	_, err = io.ReadFull(c, buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte("abcdef"), buf)
+
+	<-serverDone
+}
+
+func TestDialErrors(t *testing.T) {
+	_, err := Dial(context.Background(), (&net.Dialer{}).DialContext,
+		"http://localhost:8554", nil)
+	require.EqualError(t, err, "unsupported scheme 'http'")
+
+	hs := httptest.NewServer(http.NotFoundHandler())
+	defer hs.Close()
+
+	_, err = Dial(context.Background(), (&net.Dialer{}).DialContext,
+		"ws"+strings.TrimPrefix(hs.URL, "http")+"/path", nil)
+	require.Error(t, err)
+}
+
+func TestHandlerOrigin(t *testing.T) {
+	for _, ca := range []struct {
+		name        string
+		checkOrigin func(*http.Request) bool
+		origin      string
+		status      int
+	}{
+		{
+			"no origin",
+			nil,
+			"",
+			http.StatusSwitchingProtocols,
+		},
+		{
+			"same origin",
+			nil,
+			"http://{host}",
+			http.StatusSwitchingProtocols,
+		},
+		{
+			"cross origin",
+			nil,
+			"http://evil.example.com",
+			http.StatusForbidden,
+		},
+		{
+			"cross origin allowed",
+			func(*http.Request) bool { return true },
+			"http://evil.example.com",
+			http.StatusSwitchingProtocols,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			hs := httptest.NewServer(Handler(func(c *Conn) {}, ca.checkOrigin))
+			defer hs.Close()
+
+			req, err := http.NewRequest(http.MethodGet, hs.URL+"/path", nil)
+			require.NoError(t, err)
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			if ca.origin != "" {
+				req.Header.Set("Origin", strings.ReplaceAll(ca.origin, "{host}", req.Host))
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, ca.status, res.StatusCode)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"sync"
 	"time"
@@ -59,6 +60,11 @@ type sessionRequestReq struct {
 	res    chan sessionRequestRes
 }
 
+type serverConnNewReq struct {
+	nconn net.Conn
+	res   chan *ServerConn
+}
+
 type streamMulticastIPReq struct {
 	res chan net.IP
 }
@@ -97,6 +103,10 @@ type Server struct {
 	WriteTimeout time.Duration
 	// a TLS configuration to accept TLS (RTSPS) connections.
 	TLSConfig *tls.Config
+	// an address to accept RTSP-over-HTTP tunnels.
+	// If it is equal to RTSPAddress, tunnels and standard connections share the same port.
+	// If TLSConfig is filled, tunnels use HTTPS.
+	HTTPTunnelAddress string
 	// read buffer count.
 	// If greater than 1, allows to pass buffers to routines different than the one
 	// that is reading frames.
@@ -108,6 +118,14 @@ type Server struct {
 	// It allows to queue packets before sending them.
 	// It defaults to 256.
 	WriteBufferCount int
+	// policy applied when the write buffer of a session is full,
+	// that happens when a reader is slower than the stream.
+	// It defaults to ServerWriteQueuePolicyDropOldest.
+	WriteQueuePolicy ServerWriteQueuePolicy
+	// when WriteQueuePolicy is ServerWriteQueuePolicyDisconnect,
+	// time after which a reader that is falling behind is disconnected.
+	// It defaults to 5 seconds.
+	WriteQueueDisconnectTimeout time.Duration
 
 	//
 	// handler (optional)
@@ -124,6 +142,10 @@ type Server struct {
 	// function used to initialize UDP listeners.
 	// It defaults to net.ListenPacket.
 	ListenPacket func(network, address string) (net.PacketConn, error)
+	// function used to validate the Origin header of connections accepted by WebSocketHandler().
+	// It defaults to a function that accepts requests without an Origin header,
+	// that are not sent by browsers, and requests whose Origin has the same host of the request.
+	WebSocketCheckOrigin func(r *http.Request) bool
 
 	//
 	// private
@@ -140,6 +162,7 @@ type Server struct {
 	multicastNet       *net.IPNet
 	multicastNextIP    net.IP
 	tcpListener        net.Listener
+	httpTunnelListener net.Listener
 	udpRTPListener     *serverUDPListener
 	udpRTCPListener    *serverUDPListener
 	udpRTPPacketBuffer *rtpPacketMultiBuffer
@@ -148,6 +171,7 @@ type Server struct {
 	closeError         error
 
 	// in
+	connNewExternal   chan serverConnNewReq
 	connClose         chan *ServerConn
 	sessionRequest    chan sessionRequestReq
 	sessionClose      chan *ServerSession
@@ -172,6 +196,9 @@ func (s *Server) Start() error {
 	if (s.WriteBufferCount & (s.WriteBufferCount - 1)) != 0 {
 		return fmt.Errorf("WriteBufferCount must be a power of two")
 	}
+	if s.WriteQueueDisconnectTimeout == 0 {
+		s.WriteQueueDisconnectTimeout = 5 * time.Second
+	}
 
 	// system functions
 	if s.Listen == nil {
@@ -306,7 +333,22 @@ func (s *Server) Start() error {
 		return err
 	}
 
+	if s.HTTPTunnelAddress != "" && s.HTTPTunnelAddress != s.RTSPAddress {
+		s.httpTunnelListener, err = s.Listen("tcp", s.HTTPTunnelAddress)
+		if err != nil {
+			s.tcpListener.Close()
+			if s.udpRTPListener != nil {
+				s.udpRTPListener.close()
+			}
+			if s.udpRTCPListener != nil {
+				s.udpRTCPListener.close()
+			}
+			return err
+		}
+	}
+
 	s.ctx, s.ctxCancel = context.WithCancel(context.Background())
+	s.connNewExternal = make(chan serverConnNewReq)
 
 	s.wg.Add(1)
 	go s.run()
@@ -338,31 +380,25 @@ func (s *Server) run() {
 	s.sessionClose = make(chan *ServerSession)
 	s.streamMulticastIP = make(chan streamMulticastIPReq)
 
-	s.wg.Add(1)
 	connNew := make(chan net.Conn)
 	acceptErr := make(chan error)
-	go func() {
-		defer s.wg.Done()
-		err := func() error {
-			for {
-				nconn, err := s.tcpListener.Accept()
-				if err != nil {
-					return err
-				}
 
-				select {
-				case connNew <- nconn:
-				case <-s.ctx.Done():
-					nconn.Close()
-				}
-			}
-		}()
+	var httpTunnel *serverHTTPTunnel
+	if s.HTTPTunnelAddress != "" {
+		httpTunnel = newServerHTTPTunnel(s, connNew)
+	}
 
-		select {
-		case acceptErr <- err:
-		case <-s.ctx.Done():
-		}
-	}()
+	s.wg.Add(1)
+	if s.HTTPTunnelAddress == s.RTSPAddress {
+		go s.runAccept(s.tcpListener, connNew, acceptErr, httpTunnel, true)
+	} else {
+		go s.runAccept(s.tcpListener, connNew, acceptErr, nil, true)
+	}
+
+	if s.httpTunnelListener != nil {
+		s.wg.Add(1)
+		go s.runAccept(s.httpTunnelListener, connNew, acceptErr, httpTunnel, false)
+	}
 
 	s.closeError = func() error {
 		for {
@@ -374,6 +410,11 @@ func (s *Server) run() {
 				sc := newServerConn(s, nconn)
 				s.conns[sc] = struct{}{}
 
+			case req := <-s.connNewExternal:
+				sc := newServerConn(s, req.nconn)
+				s.conns[sc] = struct{}{}
+				req.res <- sc
+
 			case sc := <-s.connClose:
 				if _, ok := s.conns[sc]; !ok {
 					continue
@@ -479,6 +520,50 @@ func (s *Server) run() {
 	}
 
 	s.tcpListener.Close()
+
+	if s.httpTunnelListener != nil {
+		s.httpTunnelListener.Close()
+	}
+}
+
+func (s *Server) runAccept(
+	listener net.Listener,
+	connNew chan net.Conn,
+	acceptErr chan error,
+	httpTunnel *serverHTTPTunnel,
+	allowPlain bool,
+) {
+	defer s.wg.Done()
+
+	err := func() error {
+		for {
+			nconn, err := listener.Accept()
+			if err != nil {
+				return err
+			}
+
+			if s.TLSConfig != nil {
+				nconn = tls.Server(nconn, s.TLSConfig)
+			}
+
+			if httpTunnel != nil {
+				s.wg.Add(1)
+				go httpTunnel.handle(nconn, allowPlain)
+				continue
+			}
+
+			select {
+			case connNew <- nconn:
+			case <-s.ctx.Done():
+				nconn.Close()
+			}
+		}
+	}()
+
+	select {
+	case acceptErr <- err:
+	case <-s.ctx.Done():
+	}
 }
 
 // StartAndWait starts the server and waits until a fatal error.
@@ -1476,3 +1476,183 @@ func TestServerPublishUDPChangeConn(t *testing.T) {
 		require.Equal(t, base.StatusOK, res.StatusCode)
 	}()
 }
+
+func TestServerPublishSRTP(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			rtpReceived := make(chan struct{})
+			rtcpReceived := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onAnnounce: func(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil, nil
+					},
+					onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onPacketRTP: func(ctx *ServerHandlerOnPacketRTPCtx) {
+						require.Equal(t, 0, ctx.TrackID)
+						require.Equal(t, &testRTPPacket, ctx.Packet)
+						close(rtpReceived)
+						ctx.Session.WritePacketRTCP(0, &testRTCPPacket)
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			c := Client{
+				SRTPEnable: true,
+				Transport: func() *Transport {
+					if transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportTCP
+					return &v
+				}(),
+				OnPacketRTCP: func(ctx *ClientOnPacketRTCPCtx) {
+					// skip receiver reports
+					if _, ok := ctx.Packet.(*rtcp.SourceDescription); !ok {
+						return
+					}
+
+					require.Equal(t, 0, ctx.TrackID)
+					require.Equal(t, &testRTCPPacket, ctx.Packet)
+					close(rtcpReceived)
+				},
+			}
+
+			err = c.StartPublishing("rtsp://localhost:8554/teststream", Tracks{track})
+			require.NoError(t, err)
+			defer c.Close()
+
+			err = c.WritePacketRTP(0, &testRTPPacket, true)
+			require.NoError(t, err)
+
+			<-rtpReceived
+			<-rtcpReceived
+		})
+	}
+}
+
+func TestServerPublishRetransmission(t *testing.T) {
+	var received []uint16
+	allReceived := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPacketRTP: func(ctx *ServerHandlerOnPacketRTPCtx) {
+				require.Equal(t, uint8(96), ctx.Packet.PayloadType)
+				require.Equal(t, uint32(0x38F27A2F), ctx.Packet.SSRC)
+				received = append(received, ctx.Packet.SequenceNumber)
+				if len(received) == 4 {
+					close(allReceived)
+					ctx.Session.RequestKeyFrame(0)
+				}
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	keyFrameRequested := make(chan struct{})
+
+	c := Client{
+		RetransmissionEnable: true,
+		Transport: func() *Transport {
+			v := TransportUDP
+			return &v
+		}(),
+		OnPacketRTCP: func(ctx *ClientOnPacketRTCPCtx) {
+			if _, ok := ctx.Packet.(*rtcp.PictureLossIndication); ok {
+				close(keyFrameRequested)
+			}
+		},
+	}
+
+	err = c.StartPublishing("rtsp://localhost:8554/teststream", Tracks{track})
+	require.NoError(t, err)
+	defer c.Close()
+
+	for _, seq := range []uint16{1, 2, 3, 4} {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: seq,
+				SSRC:           0x38F27A2F,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		}
+
+		// simulate the loss of a packet
+		if seq == 3 {
+			byts, err := pkt.Marshal()
+			require.NoError(t, err)
+			c.tracks[0].retransmitter.Add(seq, byts)
+			continue
+		}
+
+		err = c.WritePacketRTP(0, &pkt, true)
+		require.NoError(t, err)
+	}
+
+	<-allReceived
+	require.Equal(t, []uint16{1, 2, 3, 4}, received)
+
+	<-keyFrameRequested
+}
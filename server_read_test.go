@@ -1,8 +1,11 @@
 package gortsplib
 
 import (
+	"bufio"
 	"crypto/tls"
+	"io"
 	"net"
+	"net/http"
 	"strconv"
 	"sync/atomic"
 	"testing"
@@ -17,6 +20,8 @@ import (
 	"github.com/cobalt-robotics/gortsplib/pkg/base"
 	"github.com/cobalt-robotics/gortsplib/pkg/conn"
 	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpfec"
 	"github.com/cobalt-robotics/gortsplib/pkg/url"
 )
 
@@ -1981,3 +1986,1636 @@ func TestServerReadErrorUDPSamePorts(t *testing.T) {
 		require.Equal(t, base.StatusBadRequest, res.StatusCode)
 	}()
 }
+
+func TestServerReadRTSP20(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	stream := NewServerStream(Tracks{track})
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Version: base.Version20,
+		Method:  base.Setup,
+		URL:     mustParseURL("rtsp://localhost:8554/teststream/trackID=0"),
+		Header: base.Header{
+			"CSeq":               base.HeaderValue{"1"},
+			"Pipelined-Requests": base.HeaderValue{"7654"},
+			"Transport": headers.Transport{
+				Protocol: headers.TransportProtocolUDP,
+				Delivery: func() *headers.TransportDelivery {
+					v := headers.TransportDeliveryUnicast
+					return &v
+				}(),
+				DestinationAddresses: []headers.TransportAddress{
+					{Port: 35466},
+					{Port: 35467},
+				},
+			}.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+	require.Equal(t, base.Version20, res.Version)
+	require.Equal(t, base.HeaderValue{"7654"}, res.Header["Pipelined-Requests"])
+	require.Equal(t, base.HeaderValue{"No-Seeking, Time-Progressing, Time-Duration=0"}, res.Header["Media-Properties"])
+	require.Equal(t, base.HeaderValue{"npt"}, res.Header["Accept-Ranges"])
+
+	var th headers.Transport
+	err = th.Unmarshal(res.Header["Transport"])
+	require.NoError(t, err)
+	require.Nil(t, th.ClientPorts)
+	require.Nil(t, th.ServerPorts)
+	require.Equal(t, []headers.TransportAddress{
+		{Port: 35466},
+		{Port: 35467},
+	}, th.DestinationAddresses)
+	require.Equal(t, []headers.TransportAddress{
+		{Port: 8000},
+		{Port: 8001},
+	}, th.SourceAddresses)
+
+	l1, err := net.ListenPacket("udp", "localhost:35466")
+	require.NoError(t, err)
+	defer l1.Close()
+
+	var sx headers.Session
+	err = sx.Unmarshal(res.Header["Session"])
+	require.NoError(t, err)
+
+	res, err = writeReqReadRes(conn, base.Request{
+		Version: base.Version20,
+		Method:  base.Play,
+		URL:     mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":    base.HeaderValue{"2"},
+			"Session": base.HeaderValue{sx.Session},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+	require.Equal(t, base.Version20, res.Version)
+
+	stream.WritePacketRTP(0, &testRTPPacket, true)
+
+	buf := make([]byte, 2048)
+	n, _, err := l1.ReadFrom(buf)
+	require.NoError(t, err)
+	require.Equal(t, testRTPPacketMarshaled, buf[:n])
+}
+
+func TestServerReadWriteRequest(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			stream := NewServerStream(Tracks{track})
+			defer stream.Close()
+
+			sessionPlaying := make(chan *ServerSession, 1)
+			clientResponses := make(chan base.StatusCode, 2)
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						sessionPlaying <- ctx.Session
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onClientResponse: func(ctx *ServerHandlerOnClientResponseCtx) {
+						require.NotNil(t, ctx.Session)
+						clientResponses <- ctx.Response.StatusCode
+					},
+				},
+				UDPRTPAddress:  "127.0.0.1:8000",
+				UDPRTCPAddress: "127.0.0.1:8001",
+				RTSPAddress:    "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			var methods []base.Method
+			var announcedTracks Tracks
+
+			c := Client{
+				Transport: func() *Transport {
+					if transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportTCP
+					return &v
+				}(),
+				OnServerRequest: func(ctx *ClientOnServerRequestCtx) {
+					methods = append(methods, ctx.Request.Method)
+					if ctx.Request.Method == base.Announce {
+						announcedTracks = ctx.Tracks
+					}
+				},
+			}
+
+			err = startReading(&c, "rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			ss := <-sessionPlaying
+
+			err = ss.Announce(Tracks{track, &TrackOpus{
+				PayloadType:  97,
+				SampleRate:   48000,
+				ChannelCount: 2,
+			}})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, <-clientResponses)
+
+			err = ss.Redirect(mustParseURL("rtsp://localhost:8555/teststream2"))
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, <-clientResponses)
+
+			err = c.Wait()
+			require.EqualError(t, err, "redirected by the server to rtsp://localhost:8555/teststream2")
+
+			require.Equal(t, []base.Method{base.Announce, base.Redirect}, methods)
+			require.Equal(t, 2, len(announcedTracks))
+		})
+	}
+}
+
+func TestServerReadHTTPTunnel(t *testing.T) {
+	for _, ca := range []string{
+		"shared port",
+		"separate port",
+		"tls",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			stream := NewServerStream(Tracks{track})
+			defer stream.Close()
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						require.Equal(t, "teststream", ctx.Path)
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			var ur string
+
+			switch ca {
+			case "shared port":
+				s.HTTPTunnelAddress = "localhost:8554"
+				ur = "rtsph://localhost:8554/teststream"
+
+			case "separate port":
+				s.HTTPTunnelAddress = "localhost:8080"
+				ur = "rtsph://localhost:8080/teststream"
+
+			case "tls":
+				cert, err := tls.X509KeyPair(serverCert, serverKey)
+				require.NoError(t, err)
+				s.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+				s.HTTPTunnelAddress = "localhost:8443"
+				ur = "rtsphs://localhost:8443/teststream"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			// standard connections must keep working
+			if ca == "shared port" {
+				nconn, err := net.Dial("tcp", "localhost:8554")
+				require.NoError(t, err)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				res, err := writeReqReadRes(conn, base.Request{
+					Method: base.Options,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+					Header: base.Header{
+						"CSeq": base.HeaderValue{"1"},
+					},
+				})
+				require.NoError(t, err)
+				require.Equal(t, base.StatusOK, res.StatusCode)
+			}
+
+			packetRecv := make(chan struct{})
+
+			c := Client{
+				TLSConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					require.Equal(t, 0, ctx.TrackID)
+					close(packetRecv)
+				},
+			}
+
+			err = startReading(&c, ur)
+			require.NoError(t, err)
+			defer c.Close()
+
+			stream.WritePacketRTP(0, &testRTPPacket, true)
+
+			<-packetRecv
+		})
+	}
+}
+
+func TestServerReadHTTPTunnelPathMismatch(t *testing.T) {
+	s := &Server{
+		Handler:           &testServerHandler{},
+		RTSPAddress:       "localhost:8554",
+		HTTPTunnelAddress: "localhost:8080",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	getConn, err := net.Dial("tcp", "localhost:8080")
+	require.NoError(t, err)
+	defer getConn.Close()
+
+	_, err = getConn.Write([]byte("GET /teststream HTTP/1.0\r\n" +
+		"x-sessioncookie: 0123456789\r\n" +
+		"\r\n"))
+	require.NoError(t, err)
+
+	res, err := http.ReadResponse(bufio.NewReader(getConn), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	postConn, err := net.Dial("tcp", "localhost:8080")
+	require.NoError(t, err)
+	defer postConn.Close()
+
+	_, err = postConn.Write([]byte("POST /otherstream HTTP/1.0\r\n" +
+		"x-sessioncookie: 0123456789\r\n" +
+		"Content-Length: 32767\r\n" +
+		"\r\n"))
+	require.NoError(t, err)
+
+	// the POST half is closed instead of being paired with the GET half
+	postConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = postConn.Read(make([]byte, 1))
+	require.Equal(t, io.EOF, err)
+}
+
+func TestServerReadWebSocket(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	stream := NewServerStream(Tracks{track})
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				require.Equal(t, TransportTCP, ctx.Transport)
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	l, err := net.Listen("tcp", "localhost:8080")
+	require.NoError(t, err)
+
+	hs := &http.Server{Handler: s.WebSocketHandler()}
+	go hs.Serve(l)
+	defer hs.Close()
+
+	packetRecv := make(chan struct{})
+
+	c := Client{
+		DialContext: WebSocketDialContext("ws://localhost:8080/rtsp", nil),
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			require.Equal(t, 0, ctx.TrackID)
+			close(packetRecv)
+		},
+	}
+
+	err = startReading(&c, "rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+	defer c.Close()
+
+	stream.WritePacketRTP(0, &testRTPPacket, true)
+
+	<-packetRecv
+}
+
+func TestServerReadSRTP(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"multicast",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			stream, err := NewServerStreamSRTP(Tracks{track})
+			require.NoError(t, err)
+			defer stream.Close()
+
+			rtcpReceived := make(chan struct{}, 10)
+
+			listenIP := "127.0.0.1"
+			if transport == "multicast" {
+				listenIP = multicastCapableIP(t)
+			}
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onPacketRTCP: func(ctx *ServerHandlerOnPacketRTCPCtx) {
+						require.Equal(t, 0, ctx.TrackID)
+						require.Equal(t, &testRTCPPacket, ctx.Packet)
+						rtcpReceived <- struct{}{}
+					},
+				},
+				RTSPAddress: listenIP + ":8554",
+			}
+
+			switch transport {
+			case "udp":
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+
+			case "multicast":
+				s.MulticastIPRange = "224.1.0.0/16"
+				s.MulticastRTPPort = 8000
+				s.MulticastRTCPPort = 8001
+			}
+
+			err = s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			// clients that don't support SRTP are rejected
+			func() {
+				nconn, err := net.Dial("tcp", listenIP+":8554")
+				require.NoError(t, err)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				res, err := writeReqReadRes(conn, base.Request{
+					Method: base.Setup,
+					URL:    mustParseURL("rtsp://" + listenIP + ":8554/teststream/trackID=0"),
+					Header: base.Header{
+						"CSeq": base.HeaderValue{"1"},
+						"Transport": headers.Transport{
+							Protocol: headers.TransportProtocolTCP,
+							Delivery: func() *headers.TransportDelivery {
+								v := headers.TransportDeliveryUnicast
+								return &v
+							}(),
+							InterleavedIDs: &[2]int{0, 1},
+						}.Marshal(),
+					},
+				})
+				require.NoError(t, err)
+				require.Equal(t, base.StatusUnsupportedTransport, res.StatusCode)
+			}()
+
+			rtpReceived := make(chan struct{})
+			streamRTCPReceived := make(chan struct{})
+
+			c := Client{
+				Transport: func() *Transport {
+					switch transport {
+					case "udp":
+						v := TransportUDP
+						return &v
+
+					case "multicast":
+						v := TransportUDPMulticast
+						return &v
+
+					default: // tcp
+						v := TransportTCP
+						return &v
+					}
+				}(),
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					require.Equal(t, 0, ctx.TrackID)
+					require.Equal(t, testRTPPacket.Payload, ctx.Packet.Payload)
+					select {
+					case <-rtpReceived:
+					default:
+						close(rtpReceived)
+					}
+				},
+				OnPacketRTCP: func(ctx *ClientOnPacketRTCPCtx) {
+					if _, ok := ctx.Packet.(*rtcp.SourceDescription); !ok {
+						return
+					}
+					require.Equal(t, 0, ctx.TrackID)
+					require.Equal(t, &testRTCPPacket, ctx.Packet)
+					select {
+					case <-streamRTCPReceived:
+					default:
+						close(streamRTCPReceived)
+					}
+				},
+			}
+
+			err = startReading(&c, "rtsp://"+listenIP+":8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			// write packets until they're received, since multicast
+			// packets may be lost while the group is being joined.
+			writerDone := make(chan struct{})
+			defer func() { <-writerDone }()
+			writerTerminate := make(chan struct{})
+			defer close(writerTerminate)
+
+			go func() {
+				defer close(writerDone)
+				t := time.NewTicker(100 * time.Millisecond)
+				defer t.Stop()
+
+				for {
+					select {
+					case <-t.C:
+						stream.WritePacketRTP(0, &testRTPPacket, true)
+						stream.WritePacketRTCP(0, &testRTCPPacket)
+					case <-writerTerminate:
+						return
+					}
+				}
+			}()
+
+			<-rtpReceived
+			<-streamRTCPReceived
+
+			err = c.WritePacketRTCP(0, &testRTCPPacket)
+			require.NoError(t, err)
+			<-rtcpReceived
+		})
+	}
+}
+
+func TestServerReadRetransmission(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	keyFrameRequested := make(chan struct{})
+
+	stream, err := NewServerStreamWithConf(Tracks{track}, ServerStreamConf{
+		RetransmissionEnable: true,
+		OnKeyFrameRequest: func(trackID int) {
+			require.Equal(t, 0, trackID)
+			close(keyFrameRequested)
+		},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	var received []uint16
+	allReceived := make(chan struct{})
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportUDP
+			return &v
+		}(),
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			require.Equal(t, uint8(96), ctx.Packet.PayloadType)
+			require.Equal(t, uint32(0x38F27A2F), ctx.Packet.SSRC)
+			received = append(received, ctx.Packet.SequenceNumber)
+			if len(received) == 4 {
+				close(allReceived)
+			}
+		},
+	}
+
+	err = startReading(&c, "rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+	defer c.Close()
+
+	for _, seq := range []uint16{1, 2, 3, 4} {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: seq,
+				SSRC:           0x38F27A2F,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		}
+
+		// simulate the loss of a packet
+		if seq == 3 {
+			byts, err := pkt.Marshal()
+			require.NoError(t, err)
+			stream.stTracks[0].retransmitter.Add(seq, byts)
+			continue
+		}
+
+		stream.WritePacketRTP(0, &pkt, true)
+	}
+
+	<-allReceived
+	require.Equal(t, []uint16{1, 2, 3, 4}, received)
+
+	err = c.RequestKeyFrame(0)
+	require.NoError(t, err)
+	<-keyFrameRequested
+}
+
+func TestServerReadFEC(t *testing.T) {
+	for _, ca := range []struct {
+		transport string
+		scheme    rtpfec.Scheme
+	}{
+		{"udp", rtpfec.SchemeULPFEC},
+		{"udp", rtpfec.SchemeFlexFEC},
+		{"multicast", rtpfec.SchemeULPFEC},
+		{"multicast", rtpfec.SchemeFlexFEC},
+	} {
+		t.Run(ca.transport+"_"+ca.scheme.String(), func(t *testing.T) {
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			stream, err := NewServerStreamWithConf(Tracks{track}, ServerStreamConf{
+				FEC: map[int]FECConf{
+					0: {
+						Scheme:    ca.scheme,
+						GroupSize: 2,
+					},
+				},
+			})
+			require.NoError(t, err)
+			defer stream.Close()
+
+			listenIP := "127.0.0.1"
+			if ca.transport == "multicast" {
+				listenIP = multicastCapableIP(t)
+			}
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: listenIP + ":8554",
+			}
+
+			switch ca.transport {
+			case "udp":
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+
+			case "multicast":
+				s.MulticastIPRange = "224.1.0.0/16"
+				s.MulticastRTPPort = 8000
+				s.MulticastRTCPPort = 8001
+			}
+
+			err = s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			var received []uint16
+			allReceived := make(chan struct{})
+
+			c := Client{
+				Transport: func() *Transport {
+					if ca.transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportUDPMulticast
+					return &v
+				}(),
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					require.Equal(t, uint8(96), ctx.Packet.PayloadType)
+					require.Equal(t, uint32(0x38F27A2F), ctx.Packet.SSRC)
+					require.Equal(t, []byte{0x01, 0x02, 0x03, byte(ctx.Packet.SequenceNumber)}, ctx.Packet.Payload)
+					received = append(received, ctx.Packet.SequenceNumber)
+					if len(received) == 4 {
+						close(allReceived)
+					}
+				},
+			}
+
+			err = startReading(&c, "rtsp://"+listenIP+":8554/teststream")
+			require.NoError(t, err)
+			defer c.Close()
+
+			for _, seq := range []uint16{1, 2, 3, 4} {
+				pkt := rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: seq,
+						Timestamp:      uint32(seq) * 3000,
+						SSRC:           0x38F27A2F,
+					},
+					Payload: []byte{0x01, 0x02, 0x03, byte(seq)},
+				}
+
+				// simulate the loss of a packet
+				if seq == 3 {
+					byts, err := pkt.Marshal()
+					require.NoError(t, err)
+					require.Nil(t, stream.stTracks[0].fecEncoder.Encode(byts))
+					continue
+				}
+
+				stream.WritePacketRTP(0, &pkt, true)
+			}
+
+			<-allReceived
+			require.Equal(t, []uint16{1, 2, 3, 4}, received)
+		})
+	}
+}
+
+type testServerSessionSource struct {
+	step time.Duration
+	pos  time.Duration
+
+	// when not zero, Seek() moves to the key frame that precedes the position
+	keyFrameInterval time.Duration
+}
+
+func (s *testServerSessionSource) Seek(v time.Duration) error {
+	if s.keyFrameInterval != 0 {
+		v = (v / s.keyFrameInterval) * s.keyFrameInterval
+	}
+	s.pos = v
+	return nil
+}
+
+func (s *testServerSessionSource) packet() *ServerSessionSourcePacket {
+	return &ServerSessionSourcePacket{
+		TrackID: 0,
+		Time:    s.pos,
+		Packet: &rtp.Packet{
+			Header: rtp.Header{
+				Version:     2,
+				PayloadType: 96,
+				SSRC:        0x38F27A2F,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+	}
+}
+
+func (s *testServerSessionSource) ReadPacket() (*ServerSessionSourcePacket, error) {
+	pkt := s.packet()
+	s.pos += s.step
+	return pkt, nil
+}
+
+func (s *testServerSessionSource) ReadPacketReverse() (*ServerSessionSourcePacket, error) {
+	s.pos -= s.step
+	if s.pos < 0 {
+		return nil, io.EOF
+	}
+	return s.packet(), nil
+}
+
+func TestServerReadSource(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			stream := NewServerStream(Tracks{track})
+			defer stream.Close()
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						err := ctx.Session.SetSource(&testServerSessionSource{step: 100 * time.Millisecond})
+						require.NoError(t, err)
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onPause: func(ctx *ServerHandlerOnPauseCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			packetRecv := make(chan *rtp.Packet, 100)
+
+			c := Client{
+				Transport: func() *Transport {
+					if transport == "udp" {
+						v := TransportUDP
+						return &v
+					}
+					v := TransportTCP
+					return &v
+				}(),
+				OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+					packetRecv <- ctx.Packet
+				},
+			}
+
+			u, err := url.Parse("rtsp://localhost:8554/teststream")
+			require.NoError(t, err)
+
+			err = c.Start(u.Scheme, u.Host)
+			require.NoError(t, err)
+			defer c.Close()
+
+			tracks, baseURL, _, err := c.Describe(u)
+			require.NoError(t, err)
+
+			for _, track := range tracks {
+				_, err = c.Setup(true, track, baseURL, 0, 0)
+				require.NoError(t, err)
+			}
+
+			res, err := c.Play(&headers.Range{
+				Value: &headers.RangeNPT{
+					Start: headers.RangeNPTTime(5 * time.Second),
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.HeaderValue{"npt=5-"}, res.Header["Range"])
+
+			var ri headers.RTPInfo
+			err = ri.Unmarshal(res.Header["RTP-Info"])
+			require.NoError(t, err)
+			require.Equal(t, 1, len(ri))
+			require.Equal(t, "rtsp://localhost:8554/teststream/trackID=0", ri[0].URL)
+
+			seqNum := *ri[0].SequenceNumber
+			ts := *ri[0].Timestamp
+
+			for i := 0; i < 3; i++ {
+				pkt := <-packetRecv
+				require.Equal(t, seqNum+uint16(i), pkt.SequenceNumber)
+				require.Equal(t, ts+uint32(i)*9000, pkt.Timestamp)
+			}
+
+			_, err = c.Pause()
+			require.NoError(t, err)
+
+			seqNum += 3
+			ts += 3 * 9000
+
+			for len(packetRecv) != 0 {
+				<-packetRecv
+				seqNum++
+				ts += 9000
+			}
+
+			// seek backwards.
+			// sequence numbers and timestamps are contiguous with the ones already sent.
+			res, err = c.Play(&headers.Range{
+				Value: &headers.RangeNPT{
+					Start: headers.RangeNPTTime(1 * time.Second),
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.HeaderValue{"npt=1-"}, res.Header["Range"])
+
+			var ri2 headers.RTPInfo
+			err = ri2.Unmarshal(res.Header["RTP-Info"])
+			require.NoError(t, err)
+			require.Equal(t, seqNum, *ri2[0].SequenceNumber)
+			require.Equal(t, ts-9000+1, *ri2[0].Timestamp)
+
+			pkt := <-packetRecv
+			require.Equal(t, seqNum, pkt.SequenceNumber)
+			require.Equal(t, ts-9000+1, pkt.Timestamp)
+		})
+	}
+}
+
+func TestServerReadSourceSeekKeyFrame(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	stream := NewServerStream(Tracks{track})
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				err := ctx.Session.SetSource(&testServerSessionSource{
+					step:             100 * time.Millisecond,
+					keyFrameInterval: 2 * time.Second,
+				})
+				require.NoError(t, err)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(ctx *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	packetRecv := make(chan *rtp.Packet, 100)
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			packetRecv <- ctx.Packet
+		},
+	}
+
+	u, err := url.Parse("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	tracks, baseURL, _, err := c.Describe(u)
+	require.NoError(t, err)
+
+	res, err := c.Setup(true, tracks[0], baseURL, 0, 0)
+	require.NoError(t, err)
+
+	var th headers.Transport
+	err = th.Unmarshal(res.Header["Transport"])
+	require.NoError(t, err)
+	require.NotNil(t, th.SSRC)
+
+	var lastTS uint32
+
+	for i, ca := range []struct {
+		seek  time.Duration
+		start base.HeaderValue
+	}{
+		{5500 * time.Millisecond, base.HeaderValue{"npt=4-"}},
+		{3 * time.Second, base.HeaderValue{"npt=2-"}},
+	} {
+		start := time.Now()
+
+		res, err := c.Play(&headers.Range{
+			Value: &headers.RangeNPT{
+				Start: headers.RangeNPTTime(ca.seek),
+			},
+		})
+		require.NoError(t, err)
+
+		// playback starts from the key frame that precedes the position
+		require.Equal(t, ca.start, res.Header["Range"])
+
+		var ri headers.RTPInfo
+		err = ri.Unmarshal(res.Header["RTP-Info"])
+		require.NoError(t, err)
+
+		// the key frame is sent without waiting
+		pkt := <-packetRecv
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+		require.Equal(t, *th.SSRC, pkt.SSRC)
+		require.Equal(t, *ri[0].SequenceNumber, pkt.SequenceNumber)
+		require.Equal(t, *ri[0].Timestamp, pkt.Timestamp)
+
+		// timestamps do not go backwards
+		if i != 0 {
+			require.Greater(t, pkt.Timestamp, lastTS)
+		}
+
+		next := <-packetRecv
+		require.Equal(t, pkt.Timestamp+9000, next.Timestamp)
+		lastTS = next.Timestamp
+
+		_, err = c.Pause()
+		require.NoError(t, err)
+
+		for len(packetRecv) != 0 {
+			pkt := <-packetRecv
+			lastTS = pkt.Timestamp
+		}
+	}
+}
+
+func TestServerReadSourceSRTP(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	stream, err := NewServerStreamSRTP(Tracks{track})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				err := ctx.Session.SetSource(&testServerSessionSource{step: 100 * time.Millisecond})
+				require.NoError(t, err)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	// packets of sources can't be encrypted with the crypto context of the stream
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream/trackID=0"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"1"},
+			"Transport": headers.Transport{
+				Profile:  headers.TransportProfileSAVP,
+				Protocol: headers.TransportProtocolTCP,
+				Delivery: func() *headers.TransportDelivery {
+					v := headers.TransportDeliveryUnicast
+					return &v
+				}(),
+				InterleavedIDs: &[2]int{0, 1},
+			}.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusUnsupportedTransport, res.StatusCode)
+}
+
+func TestServerReadSourceScale(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	stream := NewServerStream(Tracks{track})
+	defer stream.Close()
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				err := ctx.Session.SetSource(&testServerSessionSource{step: 100 * time.Millisecond})
+				require.NoError(t, err)
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				require.NotNil(t, ctx.Range)
+				require.NotNil(t, ctx.Scale)
+
+				res := &base.Response{
+					StatusCode: base.StatusOK,
+				}
+
+				// limit fast forward
+				if *ctx.Scale > 4 {
+					v := headers.Scale(4)
+					ctx.SetRate(res, &v, ctx.Speed)
+				} else {
+					ctx.AcceptRate(res)
+				}
+
+				return res, nil
+			},
+			onPause: func(ctx *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	packetRecv := make(chan *rtp.Packet, 100)
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			packetRecv <- ctx.Packet
+		},
+	}
+
+	u, err := url.Parse("rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+
+	err = c.Start(u.Scheme, u.Host)
+	require.NoError(t, err)
+	defer c.Close()
+
+	tracks, baseURL, _, err := c.Describe(u)
+	require.NoError(t, err)
+
+	for _, track := range tracks {
+		_, err = c.Setup(true, track, baseURL, 0, 0)
+		require.NoError(t, err)
+	}
+
+	for _, ca := range []struct {
+		name          string
+		scale         headers.Scale
+		speed         *headers.Speed
+		responseScale base.HeaderValue
+		tsDiff        uint32
+	}{
+		{
+			"fast forward",
+			2,
+			nil,
+			base.HeaderValue{"2"},
+			4500,
+		},
+		{
+			"fast forward limited",
+			8,
+			nil,
+			base.HeaderValue{"4"},
+			2250,
+		},
+		{
+			"slow motion with speed",
+			0.5,
+			func() *headers.Speed {
+				v := headers.Speed(4)
+				return &v
+			}(),
+			base.HeaderValue{"0.5"},
+			18000,
+		},
+		{
+			"reverse",
+			-1,
+			nil,
+			base.HeaderValue{"-1"},
+			9000,
+		},
+	} {
+		scale := ca.scale
+
+		res, err := c.PlayWithOptions(ClientPlayOptions{
+			Range: &headers.Range{
+				Value: &headers.RangeNPT{
+					Start: headers.RangeNPTTime(5 * time.Second),
+				},
+			},
+			Scale: &scale,
+			Speed: ca.speed,
+		})
+		require.NoError(t, err, ca.name)
+		require.Equal(t, ca.responseScale, res.Header["Scale"], ca.name)
+
+		if ca.speed != nil {
+			require.Equal(t, ca.speed.Marshal(), res.Header["Speed"], ca.name)
+		}
+
+		var ri headers.RTPInfo
+		err = ri.Unmarshal(res.Header["RTP-Info"])
+		require.NoError(t, err)
+
+		// skip packets sent before the PLAY request
+		var pkt *rtp.Packet
+		for {
+			pkt = <-packetRecv
+			if pkt.SequenceNumber == *ri[0].SequenceNumber {
+				break
+			}
+		}
+
+		require.Equal(t, *ri[0].Timestamp, pkt.Timestamp, ca.name)
+
+		for i := 0; i < 2; i++ {
+			next := <-packetRecv
+			require.Equal(t, pkt.SequenceNumber+1, next.SequenceNumber, ca.name)
+			require.Equal(t, pkt.Timestamp+ca.tsDiff, next.Timestamp, ca.name)
+			pkt = next
+		}
+
+		_, err = c.Pause()
+		require.NoError(t, err)
+	}
+}
+
+func TestServerReadWriteQueuePolicy(t *testing.T) {
+	for _, ca := range []string{
+		"drop oldest",
+		"drop until key frame",
+		"disconnect",
+		"disconnect after recovery",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			videoTrack := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			audioTrack := &TrackOpus{
+				PayloadType:  97,
+				SampleRate:   48000,
+				ChannelCount: 2,
+			}
+
+			stream := NewServerStream(Tracks{videoTrack, audioTrack})
+			defer stream.Close()
+
+			sessionPlay := make(chan *ServerSession, 1)
+			readerBehind := make(chan *ServerHandlerOnReaderBehindCtx, 10)
+			sessionClosed := make(chan error, 1)
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onSessionClose: func(ctx *ServerHandlerOnSessionCloseCtx) {
+						sessionClosed <- ctx.Error
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						sessionPlay <- ctx.Session
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onReaderBehind: func(ctx *ServerHandlerOnReaderBehindCtx) {
+						select {
+						case readerBehind <- ctx:
+						default:
+						}
+					},
+				},
+				WriteBufferCount: 16,
+				WriteQueuePolicy: func() ServerWriteQueuePolicy {
+					switch ca {
+					case "drop until key frame":
+						return ServerWriteQueuePolicyDropUntilKeyFrame
+
+					case "disconnect", "disconnect after recovery":
+						return ServerWriteQueuePolicyDisconnect
+					}
+					return ServerWriteQueuePolicyDropOldest
+				}(),
+				WriteQueueDisconnectTimeout: 500 * time.Millisecond,
+				RTSPAddress:                 "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			nconn.(*net.TCPConn).SetReadBuffer(1024)
+			conn := conn.NewConn(nconn)
+
+			var sx headers.Session
+
+			for trackID := 0; trackID < 2; trackID++ {
+				inTH := &headers.Transport{
+					Mode: func() *headers.TransportMode {
+						v := headers.TransportModePlay
+						return &v
+					}(),
+					Delivery: func() *headers.TransportDelivery {
+						v := headers.TransportDeliveryUnicast
+						return &v
+					}(),
+					Protocol:       headers.TransportProtocolTCP,
+					InterleavedIDs: &[2]int{trackID * 2, trackID*2 + 1},
+				}
+
+				header := base.Header{
+					"CSeq":      base.HeaderValue{strconv.FormatInt(int64(trackID)+1, 10)},
+					"Transport": inTH.Marshal(),
+				}
+				if trackID != 0 {
+					header["Session"] = base.HeaderValue{sx.Session}
+				}
+
+				res, err := writeReqReadRes(conn, base.Request{
+					Method: base.Setup,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream/trackID=" + strconv.FormatInt(int64(trackID), 10)),
+					Header: header,
+				})
+				require.NoError(t, err)
+				require.Equal(t, base.StatusOK, res.StatusCode)
+
+				err = sx.Unmarshal(res.Header["Session"])
+				require.NoError(t, err)
+			}
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.Play,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: base.Header{
+					"CSeq":    base.HeaderValue{"3"},
+					"Session": base.HeaderValue{sx.Session},
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+
+			session := <-sessionPlay
+
+			nonKeyFrame := append([]byte{0x41, 0x9a}, make([]byte, 1400)...)
+			keyFrame := append([]byte{0x65, 0x88}, make([]byte, 1400)...)
+
+			videoSeq := uint16(0)
+
+			writeVideo := func(payload []byte) {
+				stream.WritePacketRTP(0, &rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: videoSeq,
+						Timestamp:      uint32(videoSeq) * 3000,
+						SSRC:           0x38F27A2F,
+					},
+					Payload: payload,
+				}, true)
+				videoSeq++
+			}
+
+			// the reader is not reading, therefore it falls behind
+		outer:
+			for {
+				writeVideo(nonKeyFrame)
+
+				select {
+				case ctx := <-readerBehind:
+					require.Equal(t, session, ctx.Session)
+					require.NotZero(t, ctx.DroppedPackets)
+					break outer
+
+				default:
+				}
+			}
+
+			require.NotZero(t, session.DroppedPackets())
+
+			if ca == "disconnect" {
+				// keep the reader behind until it gets disconnected
+				for {
+					writeVideo(nonKeyFrame)
+
+					select {
+					case err := <-sessionClosed:
+						require.Equal(t, liberrors.ErrServerReaderBehind{}, err)
+						return
+
+					case <-time.After(time.Millisecond):
+					}
+				}
+			}
+
+			// the reader starts reading
+			videoPackets := make(chan *rtp.Packet, 65536)
+			audioPackets := make(chan *rtp.Packet, 16)
+
+			go func() {
+				for {
+					f, err := conn.ReadInterleavedFrame()
+					if err != nil {
+						return
+					}
+
+					// skip RTCP sender reports
+					if f.Channel != 0 && f.Channel != 2 {
+						continue
+					}
+
+					var pkt rtp.Packet
+					err = pkt.Unmarshal(f.Payload)
+					if err != nil {
+						return
+					}
+
+					if f.Channel == 0 {
+						videoPackets <- &pkt
+					} else {
+						audioPackets <- &pkt
+					}
+				}
+			}()
+
+			if ca == "drop until key frame" {
+				// packets of tracks with key frames are dropped until the next key frame
+				for i := 0; i < 5; i++ {
+					writeVideo(nonKeyFrame)
+				}
+			}
+
+			// the reader catches up
+			for {
+				_, behind := session.writeQueue.behind()
+				if !behind {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if ca == "drop until key frame" {
+				// packets of tracks without key frames are not dropped
+				stream.WritePacketRTP(1, &rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    97,
+						SequenceNumber: 1234,
+						SSRC:           0x12345678,
+					},
+					Payload: []byte{0x01, 0x02},
+				}, true)
+
+				pkt := <-audioPackets
+				require.Equal(t, uint16(1234), pkt.SequenceNumber)
+
+				writeVideo(nonKeyFrame)
+
+				keyFrameSeq := videoSeq
+				writeVideo(keyFrame)
+
+				expected := uint16(0)
+
+				for {
+					pkt := <-videoPackets
+
+					if pkt.SequenceNumber != expected {
+						require.Equal(t, keyFrameSeq, pkt.SequenceNumber)
+						require.Equal(t, keyFrame, pkt.Payload)
+						break
+					}
+
+					expected++
+				}
+			} else {
+				// the oldest packets have been overwritten, the remaining ones are in order
+				lastSeq := videoSeq - 1
+				prevSeq := -1
+
+				for {
+					pkt := <-videoPackets
+					require.Greater(t, int(pkt.SequenceNumber), prevSeq)
+					prevSeq = int(pkt.SequenceNumber)
+
+					if pkt.SequenceNumber == lastSeq {
+						break
+					}
+				}
+			}
+
+			// packets are not dropped anymore
+			dropped := session.DroppedPackets()
+
+			for i := 0; i < 60; i++ {
+				seq := videoSeq
+				writeVideo(nonKeyFrame)
+
+				pkt := <-videoPackets
+				require.Equal(t, seq, pkt.SequenceNumber)
+
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			require.Equal(t, dropped, session.DroppedPackets())
+
+			select {
+			case err := <-sessionClosed:
+				t.Errorf("unexpected session closure: %v", err)
+			default:
+			}
+		})
+	}
+}
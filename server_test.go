@@ -94,6 +94,9 @@ type testServerHandler struct {
 	onPacketRTCP   func(*ServerHandlerOnPacketRTCPCtx)
 	onSetParameter func(*ServerHandlerOnSetParameterCtx) (*base.Response, error)
 	onGetParameter func(*ServerHandlerOnGetParameterCtx) (*base.Response, error)
+	onReaderBehind func(*ServerHandlerOnReaderBehindCtx)
+
+	onClientResponse func(*ServerHandlerOnClientResponseCtx)
 }
 
 func (sh *testServerHandler) OnConnOpen(ctx *ServerHandlerOnConnOpenCtx) {
@@ -188,6 +191,18 @@ func (sh *testServerHandler) OnGetParameter(ctx *ServerHandlerOnGetParameterCtx)
 	return nil, fmt.Errorf("unimplemented")
 }
 
+func (sh *testServerHandler) OnReaderBehind(ctx *ServerHandlerOnReaderBehindCtx) {
+	if sh.onReaderBehind != nil {
+		sh.onReaderBehind(ctx)
+	}
+}
+
+func (sh *testServerHandler) OnClientResponse(ctx *ServerHandlerOnClientResponseCtx) {
+	if sh.onClientResponse != nil {
+		sh.onClientResponse(ctx)
+	}
+}
+
 func TestServerClose(t *testing.T) {
 	s := &Server{
 		Handler:     &testServerHandler{},
@@ -2,20 +2,23 @@ package gortsplib
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	gourl "net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pion/rtcp"
 
 	"github.com/cobalt-robotics/gortsplib/pkg/base"
 	"github.com/cobalt-robotics/gortsplib/pkg/conn"
+	"github.com/cobalt-robotics/gortsplib/pkg/httptunnel"
 	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
 	"github.com/cobalt-robotics/gortsplib/pkg/url"
+	"github.com/cobalt-robotics/gortsplib/pkg/wsconn"
 )
 
 func getSessionID(header base.Header) string {
@@ -28,6 +31,9 @@ func getSessionID(header base.Header) string {
 type readReq struct {
 	req *base.Request
 	res chan error
+
+	// filled when the client is answering to a request of the server
+	clientRes *base.Response
 }
 
 // ServerConn is a server-side RTSP connection.
@@ -42,6 +48,11 @@ type ServerConn struct {
 	session    *ServerSession
 	readFunc   func(readRequest chan readReq) error
 
+	// requests written by the server
+	writeRequestMutex sync.Mutex
+	writeRequestCSeq  int
+	version           base.Version
+
 	// in
 	sessionRemove chan *ServerSession
 
@@ -55,13 +66,6 @@ func newServerConn(
 ) *ServerConn {
 	ctx, ctxCancel := context.WithCancel(s.ctx)
 
-	nconn = func() net.Conn {
-		if s.TLSConfig != nil {
-			return tls.Server(nconn, s.TLSConfig)
-		}
-		return nconn
-	}()
-
 	sc := &ServerConn{
 		s:             s,
 		nconn:         nconn,
@@ -91,6 +95,15 @@ func (sc *ServerConn) NetConn() net.Conn {
 	return sc.nconn
 }
 
+// isTunneled returns whether the connection is carried by a HTTP tunnel or a WebSocket.
+func (sc *ServerConn) isTunneled() bool {
+	switch sc.nconn.(type) {
+	case *httptunnel.Conn, *wsconn.Conn:
+		return true
+	}
+	return false
+}
+
 func (sc *ServerConn) ip() net.IP {
 	return sc.remoteAddr.IP
 }
@@ -147,7 +160,12 @@ func (sc *ServerConn) runInner(readRequest chan readReq, readErr chan error) err
 	for {
 		select {
 		case req := <-readRequest:
-			req.res <- sc.handleRequestOuter(req.req)
+			if req.clientRes != nil {
+				sc.handleClientResponse(req.clientRes)
+				req.res <- nil
+			} else {
+				req.res <- sc.handleRequestOuter(req.req)
+			}
 
 		case err := <-readErr:
 			return err
@@ -188,14 +206,24 @@ func (sc *ServerConn) readFuncStandard(readRequest chan readReq) error {
 	sc.nconn.SetReadDeadline(time.Time{})
 
 	for {
-		req, err := sc.conn.ReadRequest()
+		what, err := sc.conn.ReadRequestOrResponse()
 		if err != nil {
 			return err
 		}
 
 		cres := make(chan error)
+		rreq := readReq{res: cres}
+
+		switch twhat := what.(type) {
+		case *base.Request:
+			rreq.req = twhat
+
+		case *base.Response:
+			rreq.clientRes = twhat
+		}
+
 		select {
-		case readRequest <- readReq{req: req, res: cres}:
+		case readRequest <- rreq:
 			err = <-cres
 			if err != nil {
 				return err
@@ -226,11 +254,20 @@ func (sc *ServerConn) readFuncTCP(readRequest chan readReq) error {
 						len(payload), maxPacketSize)
 				}
 
+				payload, ok := srtpDecryptRTCP(sc.session.setuppedTracks[trackID].srtpContext, payload)
+				if !ok {
+					return nil
+				}
+
 				packets, err := rtcp.Unmarshal(payload)
 				if err != nil {
 					return err
 				}
 
+				for _, pkt := range packets {
+					sc.session.setuppedStream.onReaderPacketRTCP(sc.session, trackID, pkt)
+				}
+
 				if h, ok := sc.s.Handler.(ServerHandlerOnPacketRTCP); ok {
 					for _, pkt := range packets {
 						h.OnPacketRTCP(&ServerHandlerOnPacketRTCPCtx{
@@ -249,12 +286,24 @@ func (sc *ServerConn) readFuncTCP(readRequest chan readReq) error {
 
 		processFunc = func(trackID int, isRTP bool, payload []byte) error {
 			if isRTP {
+				payload, ok := srtpDecryptRTP(sc.session.setuppedTracks[trackID].srtpContext, payload)
+				if !ok {
+					return nil
+				}
+
 				pkt := tcpRTPPacketBuffer.next()
 				err := pkt.Unmarshal(payload)
 				if err != nil {
 					return err
 				}
 
+				if fr := sc.session.setuppedTracks[trackID].feedbackReceiver; fr != nil {
+					pkt, ok = fr.processRTP(pkt)
+					if !ok {
+						return nil
+					}
+				}
+
 				out, err := sc.session.setuppedTracks[trackID].cleaner.Process(pkt)
 				if err != nil {
 					return err
@@ -278,6 +327,11 @@ func (sc *ServerConn) readFuncTCP(readRequest chan readReq) error {
 						len(payload), maxPacketSize)
 				}
 
+				payload, ok := srtpDecryptRTCP(sc.session.setuppedTracks[trackID].srtpContext, payload)
+				if !ok {
+					return nil
+				}
+
 				packets, err := rtcp.Unmarshal(payload)
 				if err != nil {
 					return err
@@ -297,7 +351,7 @@ func (sc *ServerConn) readFuncTCP(readRequest chan readReq) error {
 			sc.nconn.SetReadDeadline(time.Now().Add(sc.s.ReadTimeout))
 		}
 
-		what, err := sc.conn.ReadInterleavedFrameOrRequest()
+		what, err := sc.conn.ReadInterleavedFrameOrRequestOrResponse()
 		if err != nil {
 			return err
 		}
@@ -331,6 +385,16 @@ func (sc *ServerConn) readFuncTCP(readRequest chan readReq) error {
 			case <-sc.ctx.Done():
 				return liberrors.ErrServerTerminated{}
 			}
+
+		case *base.Response:
+			cres := make(chan error)
+			select {
+			case readRequest <- readReq{clientRes: twhat, res: cres}:
+				<-cres
+
+			case <-sc.ctx.Done():
+				return liberrors.ErrServerTerminated{}
+			}
 		}
 	}
 }
@@ -427,7 +491,13 @@ func (sc *ServerConn) handleRequest(req *base.Request) (*base.Response, error) {
 				}
 
 				if stream != nil {
-					res.Body = stream.Tracks().Marshal(multicast)
+					byts, err := stream.marshalSDP(multicast)
+					if err != nil {
+						return &base.Response{
+							StatusCode: base.StatusInternalServerError,
+						}, err
+					}
+					res.Body = byts
 				}
 			}
 
@@ -523,6 +593,20 @@ func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
 	// add server
 	res.Header["Server"] = base.HeaderValue{"gortsplib"}
 
+	// answer with the same protocol version of the request
+	res.Version = req.Version
+
+	sc.writeRequestMutex.Lock()
+	sc.version = req.Version
+	sc.writeRequestMutex.Unlock()
+
+	// RTSP 2.0 requires to echo the pipelined requests identifier
+	if req.Version == base.Version20 {
+		if v, ok := req.Header["Pipelined-Requests"]; ok {
+			res.Header["Pipelined-Requests"] = v
+		}
+	}
+
 	if h, ok := sc.s.Handler.(ServerHandlerOnResponse); ok {
 		h.OnResponse(sc, res)
 	}
@@ -533,6 +617,35 @@ func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
 	return err
 }
 
+func (sc *ServerConn) handleClientResponse(res *base.Response) {
+	if h, ok := sc.s.Handler.(ServerHandlerOnClientResponse); ok {
+		h.OnClientResponse(&ServerHandlerOnClientResponseCtx{
+			Session:  sc.session,
+			Conn:     sc,
+			Response: res,
+		})
+	}
+}
+
+// writeRequest writes a request to the client.
+// It can be called by any routine.
+func (sc *ServerConn) writeRequest(req *base.Request) error {
+	sc.writeRequestMutex.Lock()
+	defer sc.writeRequestMutex.Unlock()
+
+	if req.Header == nil {
+		req.Header = make(base.Header)
+	}
+
+	sc.writeRequestCSeq++
+	req.Header["CSeq"] = base.HeaderValue{strconv.FormatInt(int64(sc.writeRequestCSeq), 10)}
+
+	req.Version = sc.version
+
+	sc.nconn.SetWriteDeadline(time.Now().Add(sc.s.WriteTimeout))
+	return sc.conn.WriteRequest(req)
+}
+
 func (sc *ServerConn) handleRequestInSession(
 	sxID string,
 	req *base.Request,
@@ -7,6 +7,7 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/cobalt-robotics/gortsplib/pkg/base"
+	"github.com/cobalt-robotics/gortsplib/pkg/headers"
 )
 
 // ServerHandler is the interface implemented by all the server handlers.
@@ -55,6 +56,19 @@ type ServerHandlerOnSessionClose interface {
 	OnSessionClose(*ServerHandlerOnSessionCloseCtx)
 }
 
+// ServerHandlerOnReaderBehindCtx is the context of a reader that is falling behind.
+type ServerHandlerOnReaderBehindCtx struct {
+	Session        *ServerSession
+	DroppedPackets uint64
+}
+
+// ServerHandlerOnReaderBehind can be implemented by a ServerHandler.
+// It is called when the write buffer of a session becomes full,
+// that happens when a reader is slower than the stream.
+type ServerHandlerOnReaderBehind interface {
+	OnReaderBehind(*ServerHandlerOnReaderBehindCtx)
+}
+
 // ServerHandlerOnRequest can be implemented by a ServerHandler.
 type ServerHandlerOnRequest interface {
 	OnRequest(*ServerConn, *base.Request)
@@ -65,6 +79,19 @@ type ServerHandlerOnResponse interface {
 	OnResponse(*ServerConn, *base.Response)
 }
 
+// ServerHandlerOnClientResponseCtx is the context of a response sent by a client
+// to a request written with ServerSession.WriteRequest().
+type ServerHandlerOnClientResponseCtx struct {
+	Session  *ServerSession
+	Conn     *ServerConn
+	Response *base.Response
+}
+
+// ServerHandlerOnClientResponse can be implemented by a ServerHandler.
+type ServerHandlerOnClientResponse interface {
+	OnClientResponse(*ServerHandlerOnClientResponseCtx)
+}
+
 // ServerHandlerOnDescribeCtx is the context of a DESCRIBE request.
 type ServerHandlerOnDescribeCtx struct {
 	Conn    *ServerConn
@@ -122,6 +149,34 @@ type ServerHandlerOnPlayCtx struct {
 	Request *base.Request
 	Path    string
 	Query   string
+	Range   *headers.Range // (optional) requested range
+	Scale   *headers.Scale // (optional) requested playback rate
+	Speed   *headers.Speed // (optional) requested delivery rate
+}
+
+// SetRate sets the Scale and Speed headers of the response to a PLAY request,
+// in order to tell the client the rates that are actually used.
+// It can be used to adjust the rates requested by the client.
+// Nil values are not set.
+// When the session has a source, the source is played with these rates.
+func (ctx *ServerHandlerOnPlayCtx) SetRate(res *base.Response, scale *headers.Scale, speed *headers.Speed) {
+	if res.Header == nil {
+		res.Header = make(base.Header)
+	}
+
+	if scale != nil {
+		res.Header["Scale"] = scale.Marshal()
+	}
+
+	if speed != nil {
+		res.Header["Speed"] = speed.Marshal()
+	}
+}
+
+// AcceptRate sets the Scale and Speed headers of the response to a PLAY request
+// to the values requested by the client.
+func (ctx *ServerHandlerOnPlayCtx) AcceptRate(res *base.Response) {
+	ctx.SetRate(res, ctx.Scale, ctx.Speed)
 }
 
 // ServerHandlerOnPlay can be implemented by a ServerHandler.
@@ -0,0 +1,177 @@
+package gortsplib
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/httptunnel"
+)
+
+// serverBufferedConn is a net.Conn whose first bytes have already been
+// buffered in order to detect the protocol.
+type serverBufferedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c *serverBufferedConn) Read(p []byte) (int, error) {
+	return c.br.Read(p)
+}
+
+type serverHTTPTunnelPost struct {
+	nconn net.Conn
+	br    *bufio.Reader
+}
+
+type serverHTTPTunnelPending struct {
+	path string
+	ch   chan serverHTTPTunnelPost
+}
+
+type serverHTTPTunnel struct {
+	s       *Server
+	connNew chan net.Conn
+
+	mutex   sync.Mutex
+	pending map[string]serverHTTPTunnelPending
+}
+
+func newServerHTTPTunnel(s *Server, connNew chan net.Conn) *serverHTTPTunnel {
+	return &serverHTTPTunnel{
+		s:       s,
+		connNew: connNew,
+		pending: make(map[string]serverHTTPTunnelPending),
+	}
+}
+
+// handle reads the first bytes of a connection.
+// If they belong to a tunnel request, the connection is paired with its other half;
+// otherwise, if allowPlain is true, it is handled as a standard RTSP connection.
+func (t *serverHTTPTunnel) handle(nconn net.Conn, allowPlain bool) {
+	defer t.s.wg.Done()
+
+	// close the connection when the server is closed during the handshake
+	handshakeDone := make(chan struct{})
+	defer close(handshakeDone)
+	go func() {
+		select {
+		case <-t.s.ctx.Done():
+			nconn.Close()
+		case <-handshakeDone:
+		}
+	}()
+
+	nconn.SetReadDeadline(time.Now().Add(t.s.ReadTimeout))
+	br := bufio.NewReader(nconn)
+
+	byts, err := br.Peek(5)
+	if err != nil {
+		nconn.Close()
+		return
+	}
+
+	if !httptunnel.IsTunnelRequest(byts) {
+		if !allowPlain {
+			nconn.Close()
+			return
+		}
+
+		nconn.SetReadDeadline(time.Time{})
+		t.sendConn(&serverBufferedConn{
+			Conn: nconn,
+			br:   br,
+		})
+		return
+	}
+
+	req, err := httptunnel.ReadRequest(br)
+	if err != nil {
+		nconn.Close()
+		return
+	}
+
+	if req.Method == http.MethodPost {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+
+		// the two halves must share the cookie and the path
+		pending, ok := t.pending[req.Cookie]
+		if !ok || pending.path != req.Path {
+			nconn.Close()
+			return
+		}
+
+		// the channel is buffered and is removed from the map,
+		// therefore this never blocks.
+		delete(t.pending, req.Cookie)
+		pending.ch <- serverHTTPTunnelPost{
+			nconn: nconn,
+			br:    br,
+		}
+		return
+	}
+
+	nconn.SetWriteDeadline(time.Now().Add(t.s.WriteTimeout))
+	err = httptunnel.WriteResponse(nconn, http.StatusOK)
+	if err != nil {
+		nconn.Close()
+		return
+	}
+
+	ch := make(chan serverHTTPTunnelPost, 1)
+
+	t.mutex.Lock()
+	if _, ok := t.pending[req.Cookie]; ok {
+		t.mutex.Unlock()
+		nconn.Close()
+		return
+	}
+	t.pending[req.Cookie] = serverHTTPTunnelPending{
+		path: req.Path,
+		ch:   ch,
+	}
+	t.mutex.Unlock()
+
+	timer := time.NewTimer(t.s.ReadTimeout)
+	defer timer.Stop()
+
+	select {
+	case post := <-ch:
+		nconn.SetDeadline(time.Time{})
+		post.nconn.SetDeadline(time.Time{})
+		t.sendConn(httptunnel.NewServerConn(nconn, post.nconn, post.br))
+
+	case <-timer.C:
+		t.removePending(req.Cookie, ch, nconn)
+
+	case <-t.s.ctx.Done():
+		t.removePending(req.Cookie, ch, nconn)
+	}
+}
+
+func (t *serverHTTPTunnel) removePending(cookie string, ch chan serverHTTPTunnelPost, nconn net.Conn) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	nconn.Close()
+
+	if _, ok := t.pending[cookie]; ok {
+		delete(t.pending, cookie)
+		return
+	}
+
+	// the POST connection has been delivered in the meanwhile
+	post := <-ch
+	post.nconn.Close()
+}
+
+func (t *serverHTTPTunnel) sendConn(nconn net.Conn) {
+	select {
+	case t.connNew <- nconn:
+	case <-t.s.ctx.Done():
+		nconn.Close()
+	}
+}
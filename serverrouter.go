@@ -0,0 +1,421 @@
+package gortsplib
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
+)
+
+// ServerRouterPolicyCtx is the context of a policy check.
+type ServerRouterPolicyCtx struct {
+	Conn    *ServerConn
+	Session *ServerSession // nil in case of DESCRIBE requests
+	Request *base.Request
+	Path    string
+	Query   string
+}
+
+// ServerRouterPolicy decides whether a publisher or a reader can access a path.
+// It returns nil to allow the request, otherwise the response that is sent to the client,
+// for instance a 401 response with a WWW-Authenticate header.
+type ServerRouterPolicy func(*ServerRouterPolicyCtx) *base.Response
+
+// ServerRouterPathConf is the configuration of a path.
+type ServerRouterPathConf struct {
+	// policy of publishers.
+	// It defaults to nil, that means that anyone can publish.
+	PublishPolicy ServerRouterPolicy
+
+	// policy of readers.
+	// It is checked on DESCRIBE and SETUP requests.
+	// It defaults to nil, that means that anyone can read.
+	ReadPolicy ServerRouterPolicy
+
+	// allow a new publisher to replace the existing one.
+	// When the publisher is replaced, the previous publisher and the readers
+	// are disconnected.
+	// It defaults to false, that means that new publishers are rejected.
+	OverridePublisher bool
+
+	// configuration of the streams that are created when someone publishes to the path.
+	StreamConf ServerStreamConf
+}
+
+// ServerRouterOnStreamReadyCtx is the context of a stream that became available.
+type ServerRouterOnStreamReadyCtx struct {
+	Path      string
+	Publisher *ServerSession
+	Stream    *ServerStream
+}
+
+// ServerRouterOnStreamCloseCtx is the context of a stream that is not available anymore.
+type ServerRouterOnStreamCloseCtx struct {
+	Path      string
+	Publisher *ServerSession
+	Stream    *ServerStream
+	// whether the publisher has been replaced by another one.
+	Replaced bool
+}
+
+// ServerRouterPath contains the state of a path.
+type ServerRouterPath struct {
+	Name      string
+	Publisher *ServerSession
+	// stream of the path, available when the publisher is recording.
+	Stream  *ServerStream
+	Readers []*ServerSession
+}
+
+type serverRouterPath struct {
+	name      string
+	publisher *ServerSession
+	stream    *ServerStream
+	ready     bool
+	readers   map[*ServerSession]struct{}
+}
+
+// ServerRouter is a ServerHandler that routes streams from publishers to readers.
+// It keeps a registry of the publishers and readers of each path:
+// clients can publish a stream to a path with ANNOUNCE and RECORD,
+// and read it from the same path with DESCRIBE, SETUP and PLAY.
+//
+// It can be used directly as the Handler of a Server, or embedded into another
+// handler in order to add custom callbacks.
+type ServerRouter struct {
+	//
+	// paths
+	//
+	// function that returns the configuration of a path.
+	// It returns nil when the path is not available.
+	// It defaults to a function that allows anyone to publish and read any path.
+	PathConf func(path string) *ServerRouterPathConf
+
+	//
+	// callbacks
+	//
+	// called when a stream becomes available, i.e. when the publisher starts recording.
+	OnStreamReady func(*ServerRouterOnStreamReadyCtx)
+	// called when a stream is not available anymore.
+	OnStreamClose func(*ServerRouterOnStreamCloseCtx)
+
+	mutex      sync.RWMutex
+	paths      map[string]*serverRouterPath
+	publishers map[*ServerSession]*serverRouterPath
+	readers    map[*ServerSession]*serverRouterPath
+}
+
+func (r *ServerRouter) pathConf(path string) *ServerRouterPathConf {
+	if r.PathConf == nil {
+		return &ServerRouterPathConf{}
+	}
+	return r.PathConf(path)
+}
+
+func (r *ServerRouter) initialize() {
+	if r.paths == nil {
+		r.paths = make(map[string]*serverRouterPath)
+		r.publishers = make(map[*ServerSession]*serverRouterPath)
+		r.readers = make(map[*ServerSession]*serverRouterPath)
+	}
+}
+
+// removePathIfUnused removes a path when it has no publishers and readers.
+func (r *ServerRouter) removePathIfUnused(pa *serverRouterPath) {
+	if pa.publisher == nil && len(pa.readers) == 0 {
+		delete(r.paths, pa.name)
+	}
+}
+
+// closeStream closes the stream of a path and detaches the publisher.
+// It returns the context of the OnStreamClose callback, if the stream was ready.
+func (r *ServerRouter) closeStream(pa *serverRouterPath, replaced bool) *ServerRouterOnStreamCloseCtx {
+	var ctx *ServerRouterOnStreamCloseCtx
+	if pa.ready {
+		ctx = &ServerRouterOnStreamCloseCtx{
+			Path:      pa.name,
+			Publisher: pa.publisher,
+			Stream:    pa.stream,
+			Replaced:  replaced,
+		}
+	}
+
+	pa.stream.Close()
+	delete(r.publishers, pa.publisher)
+	pa.publisher = nil
+	pa.stream = nil
+	pa.ready = false
+
+	return ctx
+}
+
+func (r *ServerRouter) onStreamClose(ctx *ServerRouterOnStreamCloseCtx) {
+	if ctx != nil && r.OnStreamClose != nil {
+		r.OnStreamClose(ctx)
+	}
+}
+
+// Paths returns the state of all the paths that have a publisher or readers,
+// sorted by name.
+func (r *ServerRouter) Paths() []*ServerRouterPath {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ret := make([]*ServerRouterPath, 0, len(r.paths))
+
+	for _, pa := range r.paths {
+		p := &ServerRouterPath{
+			Name:      pa.name,
+			Publisher: pa.publisher,
+		}
+		if pa.ready {
+			p.Stream = pa.stream
+		}
+		for ss := range pa.readers {
+			p.Readers = append(p.Readers, ss)
+		}
+		ret = append(ret, p)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
+// Stream returns the stream of a path, or nil if no one is publishing to it.
+func (r *ServerRouter) Stream(path string) *ServerStream {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	pa, ok := r.paths[path]
+	if !ok || !pa.ready {
+		return nil
+	}
+	return pa.stream
+}
+
+// OnSessionClose implements ServerHandlerOnSessionClose.
+func (r *ServerRouter) OnSessionClose(ctx *ServerHandlerOnSessionCloseCtx) {
+	r.mutex.Lock()
+
+	var closeCtx *ServerRouterOnStreamCloseCtx
+
+	if pa, ok := r.publishers[ctx.Session]; ok {
+		closeCtx = r.closeStream(pa, false)
+		r.removePathIfUnused(pa)
+	}
+
+	if pa, ok := r.readers[ctx.Session]; ok {
+		delete(pa.readers, ctx.Session)
+		delete(r.readers, ctx.Session)
+		r.removePathIfUnused(pa)
+	}
+
+	r.mutex.Unlock()
+
+	r.onStreamClose(closeCtx)
+}
+
+// OnDescribe implements ServerHandlerOnDescribe.
+func (r *ServerRouter) OnDescribe(ctx *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+	conf := r.pathConf(ctx.Path)
+	if conf == nil {
+		return &base.Response{
+			StatusCode: base.StatusNotFound,
+		}, nil, nil
+	}
+
+	if conf.ReadPolicy != nil {
+		res := conf.ReadPolicy(&ServerRouterPolicyCtx{
+			Conn:    ctx.Conn,
+			Request: ctx.Request,
+			Path:    ctx.Path,
+			Query:   ctx.Query,
+		})
+		if res != nil {
+			return res, nil, nil
+		}
+	}
+
+	stream := r.Stream(ctx.Path)
+	if stream == nil {
+		return &base.Response{
+			StatusCode: base.StatusNotFound,
+		}, nil, nil
+	}
+
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, stream, nil
+}
+
+// OnAnnounce implements ServerHandlerOnAnnounce.
+func (r *ServerRouter) OnAnnounce(ctx *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+	conf := r.pathConf(ctx.Path)
+	if conf == nil {
+		return &base.Response{
+			StatusCode: base.StatusNotFound,
+		}, nil
+	}
+
+	if conf.PublishPolicy != nil {
+		res := conf.PublishPolicy(&ServerRouterPolicyCtx{
+			Conn:    ctx.Conn,
+			Session: ctx.Session,
+			Request: ctx.Request,
+			Path:    ctx.Path,
+			Query:   ctx.Query,
+		})
+		if res != nil {
+			return res, nil
+		}
+	}
+
+	stream, err := NewServerStreamWithConf(ctx.Tracks, conf.StreamConf)
+	if err != nil {
+		return &base.Response{
+			StatusCode: base.StatusInternalServerError,
+		}, err
+	}
+
+	r.mutex.Lock()
+
+	r.initialize()
+
+	var closeCtx *ServerRouterOnStreamCloseCtx
+
+	pa, ok := r.paths[ctx.Path]
+	if !ok {
+		pa = &serverRouterPath{
+			name:    ctx.Path,
+			readers: make(map[*ServerSession]struct{}),
+		}
+		r.paths[ctx.Path] = pa
+	}
+
+	if pa.publisher != nil {
+		if !conf.OverridePublisher {
+			r.mutex.Unlock()
+			stream.Close()
+			return &base.Response{
+				StatusCode: base.StatusBadRequest,
+			}, liberrors.ErrServerRouterPublisherAlreadyPresent{Path: ctx.Path}
+		}
+
+		// replace the publisher
+		prev := pa.publisher
+		closeCtx = r.closeStream(pa, true)
+		prev.Close()
+	}
+
+	pa.publisher = ctx.Session
+	pa.stream = stream
+	r.publishers[ctx.Session] = pa
+
+	r.mutex.Unlock()
+
+	r.onStreamClose(closeCtx)
+
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, nil
+}
+
+// OnSetup implements ServerHandlerOnSetup.
+func (r *ServerRouter) OnSetup(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+	// record
+	if ctx.Session.State() == ServerSessionStatePreRecord {
+		return &base.Response{
+			StatusCode: base.StatusOK,
+		}, nil, nil
+	}
+
+	// play
+	conf := r.pathConf(ctx.Path)
+	if conf == nil {
+		return &base.Response{
+			StatusCode: base.StatusNotFound,
+		}, nil, nil
+	}
+
+	if conf.ReadPolicy != nil {
+		res := conf.ReadPolicy(&ServerRouterPolicyCtx{
+			Conn:    ctx.Conn,
+			Session: ctx.Session,
+			Request: ctx.Request,
+			Path:    ctx.Path,
+			Query:   ctx.Query,
+		})
+		if res != nil {
+			return res, nil, nil
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pa, ok := r.paths[ctx.Path]
+	if !ok || !pa.ready {
+		return &base.Response{
+			StatusCode: base.StatusNotFound,
+		}, nil, nil
+	}
+
+	pa.readers[ctx.Session] = struct{}{}
+	r.readers[ctx.Session] = pa
+
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, pa.stream, nil
+}
+
+// OnPlay implements ServerHandlerOnPlay.
+func (r *ServerRouter) OnPlay(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, nil
+}
+
+// OnRecord implements ServerHandlerOnRecord.
+func (r *ServerRouter) OnRecord(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+	r.mutex.Lock()
+
+	pa, ok := r.publishers[ctx.Session]
+	if !ok {
+		r.mutex.Unlock()
+		return &base.Response{
+			StatusCode: base.StatusBadRequest,
+		}, nil
+	}
+
+	pa.ready = true
+	readyCtx := &ServerRouterOnStreamReadyCtx{
+		Path:      pa.name,
+		Publisher: pa.publisher,
+		Stream:    pa.stream,
+	}
+
+	r.mutex.Unlock()
+
+	if r.OnStreamReady != nil {
+		r.OnStreamReady(readyCtx)
+	}
+
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, nil
+}
+
+// OnPacketRTP implements ServerHandlerOnPacketRTP.
+func (r *ServerRouter) OnPacketRTP(ctx *ServerHandlerOnPacketRTPCtx) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// route packets from the publisher to the readers
+	if pa, ok := r.publishers[ctx.Session]; ok && pa.ready {
+		pa.stream.WritePacketRTP(ctx.TrackID, ctx.Packet, ctx.PTSEqualsDTS)
+	}
+}
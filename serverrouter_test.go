@@ -0,0 +1,260 @@
+package gortsplib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
+	"github.com/cobalt-robotics/gortsplib/pkg/url"
+)
+
+func TestServerRouter(t *testing.T) {
+	streamReady := make(chan *ServerRouterOnStreamReadyCtx, 1)
+	streamClosed := make(chan *ServerRouterOnStreamCloseCtx, 1)
+
+	router := &ServerRouter{
+		OnStreamReady: func(ctx *ServerRouterOnStreamReadyCtx) {
+			streamReady <- ctx
+		},
+		OnStreamClose: func(ctx *ServerRouterOnStreamCloseCtx) {
+			streamClosed <- ctx
+		},
+	}
+
+	s := &Server{
+		Handler:        router,
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	// no one is publishing yet
+	func() {
+		c := Client{}
+		err := startReading(&c, "rtsp://localhost:8554/teststream")
+		require.Equal(t, liberrors.ErrClientBadStatusCode{Code: base.StatusNotFound, Message: "Not Found"}, err)
+	}()
+
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	publisher := Client{}
+	err = publisher.StartPublishing("rtsp://localhost:8554/teststream", Tracks{track})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ready := <-streamReady
+	require.Equal(t, "teststream", ready.Path)
+	require.Equal(t, ready.Stream, router.Stream("teststream"))
+
+	packetRecv := make(chan struct{})
+
+	reader := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		OnPacketRTP: func(ctx *ClientOnPacketRTPCtx) {
+			require.Equal(t, 0, ctx.TrackID)
+			require.Equal(t, testRTPPacket.Payload, ctx.Packet.Payload)
+			close(packetRecv)
+		},
+	}
+	err = startReading(&reader, "rtsp://localhost:8554/teststream")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	paths := router.Paths()
+	require.Equal(t, 1, len(paths))
+	require.Equal(t, "teststream", paths[0].Name)
+	require.NotNil(t, paths[0].Publisher)
+	require.Equal(t, ready.Stream, paths[0].Stream)
+	require.Equal(t, 1, len(paths[0].Readers))
+
+	pkt := testRTPPacket
+	err = publisher.WritePacketRTP(0, &pkt, true)
+	require.NoError(t, err)
+
+	<-packetRecv
+
+	publisher.Close()
+
+	closed := <-streamClosed
+	require.Equal(t, "teststream", closed.Path)
+	require.Equal(t, false, closed.Replaced)
+
+	// readers are disconnected when the publisher goes away
+	err = reader.Wait()
+	require.Error(t, err)
+
+	require.Nil(t, router.Stream("teststream"))
+}
+
+func TestServerRouterPublisherAlreadyPresent(t *testing.T) {
+	for _, ca := range []string{
+		"reject",
+		"override",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			streamClosed := make(chan *ServerRouterOnStreamCloseCtx, 1)
+
+			router := &ServerRouter{
+				PathConf: func(path string) *ServerRouterPathConf {
+					return &ServerRouterPathConf{
+						OverridePublisher: (ca == "override"),
+					}
+				},
+				OnStreamClose: func(ctx *ServerRouterOnStreamCloseCtx) {
+					streamClosed <- ctx
+				},
+			}
+
+			s := &Server{
+				Handler:     router,
+				RTSPAddress: "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			track := &TrackH264{
+				PayloadType: 96,
+				SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+				PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+			}
+
+			transport := TransportTCP
+
+			publisher1 := Client{Transport: &transport}
+			err = publisher1.StartPublishing("rtsp://localhost:8554/teststream", Tracks{track})
+			require.NoError(t, err)
+			defer publisher1.Close()
+
+			publisher2 := Client{Transport: &transport}
+			err = publisher2.StartPublishing("rtsp://localhost:8554/teststream", Tracks{track})
+
+			if ca == "reject" {
+				require.Equal(t, liberrors.ErrClientBadStatusCode{Code: base.StatusBadRequest, Message: "Bad Request"}, err)
+				return
+			}
+
+			require.NoError(t, err)
+			defer publisher2.Close()
+
+			closed := <-streamClosed
+			require.Equal(t, true, closed.Replaced)
+
+			// the previous publisher is disconnected
+			err = publisher1.Wait()
+			require.Error(t, err)
+
+			paths := router.Paths()
+			require.Equal(t, 1, len(paths))
+			require.NotEqual(t, closed.Publisher, paths[0].Publisher)
+			require.NotEqual(t, closed.Stream, paths[0].Stream)
+		})
+	}
+}
+
+func TestServerRouterPolicies(t *testing.T) {
+	router := &ServerRouter{
+		PathConf: func(path string) *ServerRouterPathConf {
+			if path != "teststream" {
+				return nil
+			}
+
+			return &ServerRouterPathConf{
+				PublishPolicy: func(ctx *ServerRouterPolicyCtx) *base.Response {
+					require.NotNil(t, ctx.Session)
+					if ctx.Query != "user=publisher" {
+						return &base.Response{
+							StatusCode: base.StatusForbidden,
+						}
+					}
+					return nil
+				},
+				ReadPolicy: func(ctx *ServerRouterPolicyCtx) *base.Response {
+					if ctx.Query != "user=reader" {
+						return &base.Response{
+							StatusCode: base.StatusForbidden,
+						}
+					}
+					return nil
+				},
+			}
+		},
+	}
+
+	s := &Server{
+		Handler:     router,
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	transport := TransportTCP
+
+	forbidden := liberrors.ErrClientBadStatusCode{Code: base.StatusForbidden, Message: "Forbidden"}
+
+	func() {
+		c := Client{Transport: &transport}
+		err := c.StartPublishing("rtsp://localhost:8554/otherstream?user=publisher", Tracks{track})
+		require.Equal(t, liberrors.ErrClientBadStatusCode{Code: base.StatusNotFound, Message: "Not Found"}, err)
+	}()
+
+	func() {
+		c := Client{Transport: &transport}
+		err := c.StartPublishing("rtsp://localhost:8554/teststream?user=reader", Tracks{track})
+		require.Equal(t, forbidden, err)
+	}()
+
+	publisher := Client{Transport: &transport}
+	err = publisher.StartPublishing("rtsp://localhost:8554/teststream?user=publisher", Tracks{track})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	func() {
+		c := Client{Transport: &transport}
+		err := startReading(&c, "rtsp://localhost:8554/teststream?user=publisher")
+		require.Equal(t, forbidden, err)
+	}()
+
+	// SETUP without DESCRIBE
+	func() {
+		c := Client{Transport: &transport}
+		u, err := url.Parse("rtsp://localhost:8554/teststream?user=publisher")
+		require.NoError(t, err)
+		err = c.Start(u.Scheme, u.Host)
+		require.NoError(t, err)
+		defer c.Close()
+
+		setupTrack := track.clone()
+		setupTrack.SetControl("trackID=0")
+
+		_, err = c.Setup(true, setupTrack, u, 0, 0)
+		require.Equal(t, forbidden, err)
+	}()
+
+	reader := Client{Transport: &transport}
+	err = startReading(&reader, "rtsp://localhost:8554/teststream?user=reader")
+	require.NoError(t, err)
+	defer reader.Close()
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/cobalt-robotics/gortsplib/pkg/rtcpreceiver"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtpcleaner"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtpreorderer"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
 	"github.com/cobalt-robotics/gortsplib/pkg/url"
 )
 
@@ -112,6 +114,21 @@ func setupGetTransport(th headers.Transport) (Transport, bool) {
 	}
 }
 
+// liveMediaProperties returns the media properties of a live stream.
+func liveMediaProperties() headers.MediaProperties {
+	seeking := headers.MediaPropertiesSeekingNoSeeking
+	content := headers.MediaPropertiesContentTimeProgressing
+	retention := headers.MediaPropertiesRetentionTimeDuration
+	duration := time.Duration(0)
+
+	return headers.MediaProperties{
+		Seeking:      &seeking,
+		Content:      &content,
+		Retention:    &retention,
+		TimeDuration: &duration,
+	}
+}
+
 // ServerSessionState is a state of a ServerSession.
 type ServerSessionState int
 
@@ -150,10 +167,20 @@ type ServerSessionSetuppedTrack struct {
 	udpRTCPReadPort  int
 	udpRTCPWriteAddr *net.UDPAddr
 
+	// SSRC advertised to the client, if any
+	ssrc uint32
+
+	// SRTP crypto context, available when the RTP/SAVP profile is in use
+	srtpContext *srtp.Context
+
+	// feedback capabilities, available when the RTP/AVPF profile is in use
+	feedback *trackFeedback
+
 	// publish
-	udpRTCPReceiver *rtcpreceiver.RTCPReceiver
-	reorderer       *rtpreorderer.Reorderer
-	cleaner         *rtpcleaner.Cleaner
+	udpRTCPReceiver  *rtcpreceiver.RTCPReceiver
+	reorderer        *rtpreorderer.Reorderer
+	cleaner          *rtpcleaner.Cleaner
+	feedbackReceiver *trackFeedbackReceiver
 }
 
 // ServerSession is a server-side RTSP session.
@@ -169,17 +196,29 @@ type ServerSession struct {
 	setuppedTracks      map[int]*ServerSessionSetuppedTrack
 	tcpTracksByChannel  map[int]int
 	setuppedTransport   *Transport
-	setuppedBaseURL     *url.URL      // publish
-	setuppedStream      *ServerStream // read
+	setuppedBaseURL     *url.URL               // publish
+	setuppedStream      *ServerStream          // read
+	source              ServerSessionSource    // read
+	playback            *serverSessionPlayback // read
 	setuppedPath        *string
 	setuppedQuery       *string
 	lastRequestTime     time.Time
 	tcpConn             *ServerConn
-	announcedTracks     Tracks // publish
-	udpLastFrameTime    *int64 // publish
+	announcedTracks     Tracks           // publish
+	announcedSRTPCtxs   []*srtp.Context  // publish
+	announcedFeedbacks  []*trackFeedback // publish
+	udpLastFrameTime    *int64           // publish
 	udpCheckStreamTimer *time.Timer
 	writerRunning       bool
 	writeBuffer         *ringbuffer.RingBuffer
+	writeQueue          *serverSessionWriteQueue
+	writeQueueBehind    chan struct{}
+	writeQueueTimer     *time.Timer
+
+	// used by WriteRequest()
+	lastConnMutex sync.RWMutex
+	lastConn      *ServerConn
+	lastURL       *url.URL
 
 	// writer channels
 	writerDone chan struct{}
@@ -206,11 +245,15 @@ func newServerSession(
 		conns:               make(map[*ServerConn]struct{}),
 		lastRequestTime:     time.Now(),
 		udpCheckStreamTimer: emptyTimer(),
+		writeQueueBehind:    make(chan struct{}, 1),
+		writeQueueTimer:     emptyTimer(),
 		request:             make(chan sessionRequestReq),
 		connRemove:          make(chan *ServerConn),
 		startWriter:         make(chan struct{}),
 	}
 
+	ss.writeQueue = newServerSessionWriteQueue(ss)
+
 	s.wg.Add(1)
 	go ss.run()
 
@@ -243,6 +286,58 @@ func (ss *ServerSession) AnnouncedTracks() Tracks {
 	return ss.announcedTracks
 }
 
+// WriteRequest writes a request to the client, through the connection
+// that was used by the last request of the client.
+// If the request URL is nil, the URL of the stream is used.
+// Responses of the client are passed to ServerHandlerOnClientResponse.
+func (ss *ServerSession) WriteRequest(req *base.Request) error {
+	if ss.ctx.Err() != nil {
+		return liberrors.ErrServerTerminated{}
+	}
+
+	ss.lastConnMutex.RLock()
+	sc := ss.lastConn
+	u := ss.lastURL
+	ss.lastConnMutex.RUnlock()
+
+	if sc == nil || u == nil {
+		return liberrors.ErrServerSessionNotConnected{}
+	}
+
+	if req.URL == nil {
+		req.URL = u
+	}
+
+	if req.Header == nil {
+		req.Header = make(base.Header)
+	}
+
+	req.Header["Session"] = base.HeaderValue{ss.secretID}
+
+	return sc.writeRequest(req)
+}
+
+// Redirect asks the client to read or publish from another URL.
+func (ss *ServerSession) Redirect(location *url.URL) error {
+	return ss.WriteRequest(&base.Request{
+		Method: base.Redirect,
+		Header: base.Header{
+			"Location": base.HeaderValue{location.String()},
+		},
+	})
+}
+
+// Announce notifies the client that the tracks of the stream have changed.
+func (ss *ServerSession) Announce(tracks Tracks) error {
+	return ss.WriteRequest(&base.Request{
+		Method: base.Announce,
+		Header: base.Header{
+			"Content-Type": base.HeaderValue{"application/sdp"},
+		},
+		Body: tracks.Marshal(false),
+	})
+}
+
 func (ss *ServerSession) checkState(allowed map[ServerSessionState]struct{}) error {
 	if _, ok := allowed[ss.state]; ok {
 		return nil
@@ -271,6 +366,10 @@ func (ss *ServerSession) run() {
 
 	ss.ctxCancel()
 
+	if ss.playback != nil {
+		ss.playback.stop()
+	}
+
 	switch ss.state {
 	case ServerSessionStatePlay:
 		ss.setuppedStream.readerSetInactive(ss)
@@ -296,6 +395,12 @@ func (ss *ServerSession) run() {
 	}
 
 	if ss.writerRunning {
+		// the writer of a reader that is falling behind is stuck,
+		// close the connection in order not to wait for write timeouts.
+		if _, ok := err.(liberrors.ErrServerReaderBehind); ok && *ss.setuppedTransport == TransportTCP {
+			ss.tcpConn.Close()
+		}
+
 		ss.writeBuffer.Close()
 		<-ss.writerDone
 	}
@@ -339,6 +444,18 @@ func (ss *ServerSession) runInner() error {
 
 			res, err := ss.handleRequest(req.sc, req.req)
 
+			ss.lastConnMutex.Lock()
+			ss.lastConn = req.sc
+			if ss.setuppedPath != nil {
+				ss.lastURL = &url.URL{
+					Scheme:   req.req.URL.Scheme,
+					Host:     req.req.URL.Host,
+					Path:     "/" + *ss.setuppedPath,
+					RawQuery: *ss.setuppedQuery,
+				}
+			}
+			ss.lastConnMutex.Unlock()
+
 			returnedSession := ss
 
 			if err == nil || err == errSwitchReadFunc {
@@ -388,6 +505,12 @@ func (ss *ServerSession) runInner() error {
 		case sc := <-ss.connRemove:
 			delete(ss.conns, sc)
 
+			ss.lastConnMutex.Lock()
+			if ss.lastConn == sc {
+				ss.lastConn = nil
+			}
+			ss.lastConnMutex.Unlock()
+
 			// if session is not in state RECORD or PLAY, or transport is TCP,
 			// and there are no associated connections,
 			// close the session.
@@ -424,6 +547,30 @@ func (ss *ServerSession) runInner() error {
 
 			ss.udpCheckStreamTimer = time.NewTimer(ss.s.checkStreamPeriod)
 
+		case <-ss.writeQueueBehind:
+			if h, ok := ss.s.Handler.(ServerHandlerOnReaderBehind); ok {
+				h.OnReaderBehind(&ServerHandlerOnReaderBehindCtx{
+					Session:        ss,
+					DroppedPackets: ss.DroppedPackets(),
+				})
+			}
+
+			if ss.s.WriteQueuePolicy == ServerWriteQueuePolicyDisconnect {
+				ss.writeQueueTimer.Stop()
+				ss.writeQueueTimer = time.NewTimer(ss.s.WriteQueueDisconnectTimeout)
+			}
+
+		case <-ss.writeQueueTimer.C:
+			// the reader may have caught up and then fallen behind again.
+			if since, ok := ss.writeQueue.behind(); ok {
+				remaining := ss.s.WriteQueueDisconnectTimeout - time.Since(since)
+				if remaining <= 0 {
+					return liberrors.ErrServerReaderBehind{}
+				}
+
+				ss.writeQueueTimer = time.NewTimer(remaining)
+			}
+
 		case <-ss.ctx.Done():
 			return liberrors.ErrServerTerminated{}
 		}
@@ -514,7 +661,21 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 		}
 
 		var tracks Tracks
-		_, err = tracks.Unmarshal(req.Body, false)
+		sd, mds, err := tracks.unmarshal(req.Body, false)
+		if err != nil {
+			return &base.Response{
+				StatusCode: base.StatusBadRequest,
+			}, liberrors.ErrServerSDPInvalid{Err: err}
+		}
+
+		srtpKeys, err := srtpKeysFromMediaDescriptions(sd, mds)
+		if err != nil {
+			return &base.Response{
+				StatusCode: base.StatusBadRequest,
+			}, liberrors.ErrServerSDPInvalid{Err: err}
+		}
+
+		srtpCtxs, err := srtpContexts(srtpKeys)
 		if err != nil {
 			return &base.Response{
 				StatusCode: base.StatusBadRequest,
@@ -563,6 +724,8 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 		ss.setuppedQuery = &query
 		ss.setuppedBaseURL = req.URL
 		ss.announcedTracks = tracks
+		ss.announcedSRTPCtxs = srtpCtxs
+		ss.announcedFeedbacks = trackFeedbacksFromMediaDescriptions(mds)
 
 		v := time.Now().Unix()
 		ss.udpLastFrameTime = &v
@@ -588,6 +751,8 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			}, liberrors.ErrServerTransportHeaderInvalid{Err: err}
 		}
 
+		transportHeaderFromV20(&inTH, true)
+
 		trackID, path, query, err := setupGetTrackIDPathQuery(req.URL, inTH.Mode,
 			ss.announcedTracks, ss.setuppedPath, ss.setuppedQuery, ss.setuppedBaseURL)
 		if err != nil {
@@ -609,6 +774,13 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			}, nil
 		}
 
+		// tunneled connections can't be paired with UDP
+		if transport != TransportTCP && sc.isTunneled() {
+			return &base.Response{
+				StatusCode: base.StatusUnsupportedTransport,
+			}, nil
+		}
+
 		switch transport {
 		case TransportUDP:
 			if inTH.ClientPorts == nil {
@@ -704,6 +876,42 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			return res, err
 		}
 
+		var srtpCtx *srtp.Context
+		var feedback *trackFeedback
+		if ss.state == ServerSessionStatePreRecord {
+			if ss.announcedSRTPCtxs != nil {
+				srtpCtx = ss.announcedSRTPCtxs[trackID]
+			}
+			if ss.announcedFeedbacks != nil {
+				feedback = ss.announcedFeedbacks[trackID]
+			}
+		} else {
+			srtpCtx = stream.srtpContext(trackID)
+			feedback = stream.feedback(trackID)
+		}
+
+		// packets of sources are encrypted with the crypto context of the stream,
+		// that can't be shared between sessions.
+		if ss.source != nil && srtpCtx != nil {
+			return &base.Response{
+				StatusCode: base.StatusUnsupportedTransport,
+			}, liberrors.ErrServerSourceSRTP{}
+		}
+
+		// encrypted tracks must be set up with the RTP/SAVP profile, and vice versa.
+		// RTP/AVPF can be used only with tracks that support feedback,
+		// while RTP/AVP can always be used.
+		if inTH.Profile.IsSecure() != (srtpCtx != nil) ||
+			(inTH.Profile.HasFeedback() && feedback == nil) {
+			return &base.Response{
+				StatusCode: base.StatusUnsupportedTransport,
+			}, nil
+		}
+
+		if !inTH.Profile.HasFeedback() {
+			feedback = nil
+		}
+
 		if ss.state == ServerSessionStateInitial {
 			err := stream.readerAdd(ss,
 				transport,
@@ -721,10 +929,20 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			ss.setuppedStream = stream
 		}
 
-		th := headers.Transport{}
+		th := headers.Transport{
+			Profile: inTH.Profile,
+		}
+
+		var ssrc uint32
 
 		if ss.state == ServerSessionStatePrePlay {
-			ssrc := stream.ssrc(trackID)
+			// sessions with a source write packets with their own SSRC.
+			if ss.source != nil {
+				ssrc = randUint32()
+			} else {
+				ssrc = stream.ssrc(trackID)
+			}
+
 			if ssrc != 0 {
 				th.SSRC = &ssrc
 			}
@@ -737,7 +955,10 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 		}
 
 		sst := &ServerSessionSetuppedTrack{
-			id: trackID,
+			id:          trackID,
+			ssrc:        ssrc,
+			srtpContext: srtpCtx,
+			feedback:    feedback,
 		}
 
 		switch transport {
@@ -794,6 +1015,20 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 
 		ss.setuppedTracks[trackID] = sst
 
+		if req.Version == base.Version20 {
+			transportHeaderToV20(&th)
+
+			// RTSP 2.0 requires the server to describe the media in SETUP responses
+			if ss.state == ServerSessionStatePrePlay {
+				if _, ok := res.Header["Media-Properties"]; !ok {
+					res.Header["Media-Properties"] = liveMediaProperties().Marshal()
+				}
+				if _, ok := res.Header["Accept-Ranges"]; !ok {
+					res.Header["Accept-Ranges"] = headers.AcceptRanges{"npt"}.Marshal()
+				}
+			}
+		}
+
 		res.Header["Transport"] = th.Marshal()
 
 		return res, err
@@ -817,12 +1052,20 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			}, liberrors.ErrServerPathHasChanged{Prev: *ss.setuppedPath, Cur: path}
 		}
 
+		ra, scale, speed, err := readPlayHeaders(req)
+		if err != nil {
+			return &base.Response{
+				StatusCode: base.StatusBadRequest,
+			}, err
+		}
+
 		// allocate writeBuffer before calling OnPlay().
 		// in this way it's possible to call ServerSession.WritePacket*()
 		// inside the callback.
 		if ss.state != ServerSessionStatePlay &&
 			*ss.setuppedTransport != TransportUDPMulticast {
 			ss.writeBuffer, _ = ringbuffer.New(uint64(ss.s.WriteBufferCount))
+			ss.writeQueue.reset()
 		}
 
 		res, err := sc.s.Handler.(ServerHandlerOnPlay).OnPlay(&ServerHandlerOnPlayCtx{
@@ -831,6 +1074,9 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			Request: req,
 			Path:    path,
 			Query:   query,
+			Range:   ra,
+			Scale:   scale,
+			Speed:   speed,
 		})
 
 		if res.StatusCode != base.StatusOK {
@@ -840,6 +1086,23 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			return res, err
 		}
 
+		if ss.source != nil {
+			if *ss.setuppedTransport == TransportUDPMulticast {
+				return &base.Response{
+					StatusCode: base.StatusUnsupportedTransport,
+				}, liberrors.ErrServerSourceMulticast{}
+			}
+
+			var err2 error
+			res, err2 = ss.playSource(req, res, ra, scale, speed)
+			if res.StatusCode != base.StatusOK {
+				if ss.state != ServerSessionStatePlay {
+					ss.writeBuffer = nil
+				}
+				return res, err2
+			}
+		}
+
 		if ss.state == ServerSessionStatePlay {
 			return res, err
 		}
@@ -872,6 +1135,12 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			// runWriter() is called by ServerConn after the response has been sent
 		}
 
+		// sessions with a source don't receive packets from the stream,
+		// and RTP-Info is filled by playSource().
+		if ss.source != nil {
+			return res, err
+		}
+
 		ss.setuppedStream.readerSetActive(ss)
 
 		var trackIDs []int
@@ -963,6 +1232,9 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			if *ss.setuppedTransport == TransportUDP {
 				st.reorderer = rtpreorderer.New()
 			}
+			if st.feedback != nil {
+				st.feedbackReceiver = newTrackFeedbackReceiver(st.feedback)
+			}
 			_, isH264 := ss.announcedTracks[trackID].(*TrackH264)
 			st.cleaner = rtpcleaner.New(isH264, *ss.setuppedTransport == TransportTCP)
 		}
@@ -1029,12 +1301,19 @@ func (ss *ServerSession) handleRequest(sc *ServerConn, req *base.Request) (*base
 			return res, err
 		}
 
+		if ss.playback != nil {
+			ss.playback.stop()
+		}
+
 		if ss.writerRunning {
 			ss.writeBuffer.Close()
 			<-ss.writerDone
 			ss.writerRunning = false
 		}
 
+		ss.writeQueueTimer.Stop()
+		ss.writeQueueTimer = emptyTimer()
+
 		switch ss.state {
 		case ServerSessionStatePlay:
 			ss.setuppedStream.readerSetInactive(ss)
@@ -1183,6 +1462,8 @@ func (ss *ServerSession) runWriter() {
 		}
 		data := tmp.(trackTypePayload)
 
+		ss.writeQueue.onPull(ss.writeBuffer)
+
 		writeFunc(data.trackID, data.isRTP, data.payload)
 	}
 }
@@ -1197,16 +1478,16 @@ func (ss *ServerSession) onPacketRTCP(trackID int, pkt rtcp.Packet) {
 	}
 }
 
-func (ss *ServerSession) writePacketRTP(trackID int, byts []byte) {
+func (ss *ServerSession) writePacketRTP(trackID int, byts []byte, keyFrame bool) {
 	if _, ok := ss.setuppedTracks[trackID]; !ok {
 		return
 	}
 
-	ss.writeBuffer.Push(trackTypePayload{
+	ss.writeQueue.push(ss.writeBuffer, trackTypePayload{
 		trackID: trackID,
 		isRTP:   true,
 		payload: byts,
-	})
+	}, keyFrame)
 }
 
 // WritePacketRTP writes a RTP packet to the session.
@@ -1216,7 +1497,19 @@ func (ss *ServerSession) WritePacketRTP(trackID int, pkt *rtp.Packet) {
 		return
 	}
 
-	ss.writePacketRTP(trackID, byts)
+	if st, ok := ss.setuppedTracks[trackID]; ok && st.srtpContext != nil {
+		byts, err = st.srtpContext.EncryptRTP(byts)
+		if err != nil {
+			return
+		}
+	}
+
+	keyFrame := false
+	if ss.setuppedStream != nil && trackID < len(ss.setuppedStream.tracks) {
+		keyFrame, _ = rtpKeyFrameStart(ss.setuppedStream.tracks[trackID], pkt.Payload)
+	}
+
+	ss.writePacketRTP(trackID, byts, keyFrame)
 }
 
 func (ss *ServerSession) writePacketRTCP(trackID int, byts []byte) {
@@ -1224,11 +1517,11 @@ func (ss *ServerSession) writePacketRTCP(trackID int, byts []byte) {
 		return
 	}
 
-	ss.writeBuffer.Push(trackTypePayload{
+	ss.writeQueue.push(ss.writeBuffer, trackTypePayload{
 		trackID: trackID,
 		isRTP:   false,
 		payload: byts,
-	})
+	}, false)
 }
 
 // WritePacketRTCP writes a RTCP packet to the session.
@@ -1238,5 +1531,60 @@ func (ss *ServerSession) WritePacketRTCP(trackID int, pkt rtcp.Packet) {
 		return
 	}
 
+	if st, ok := ss.setuppedTracks[trackID]; ok && st.srtpContext != nil {
+		byts, err = st.srtpContext.EncryptRTCP(byts)
+		if err != nil {
+			return
+		}
+	}
+
 	ss.writePacketRTCP(trackID, byts)
 }
+
+// RequestKeyFrame asks the client that is publishing to send a key frame,
+// by sending a PLI or FIR message.
+// It can be used only after OnRecord, when the track has been set up
+// with the RTP/AVPF profile and the client supports those messages.
+func (ss *ServerSession) RequestKeyFrame(trackID int) {
+	st, ok := ss.setuppedTracks[trackID]
+	if !ok || st.feedbackReceiver == nil {
+		return
+	}
+
+	if pkt := st.feedbackReceiver.keyFrameRequest(); pkt != nil {
+		ss.WritePacketRTCP(trackID, pkt)
+	}
+}
+
+// readPlayHeaders reads the Range, Scale and Speed headers of a PLAY request.
+func readPlayHeaders(req *base.Request) (*headers.Range, *headers.Scale, *headers.Speed, error) {
+	// ranges that can't be parsed (e.g. npt=now-) are not considered errors,
+	// since they are sent by some clients when reading live streams.
+	var ra *headers.Range
+	if v, ok := req.Header["Range"]; ok {
+		var tmp headers.Range
+		if tmp.Unmarshal(v) == nil {
+			ra = &tmp
+		}
+	}
+
+	var scale *headers.Scale
+	if v, ok := req.Header["Scale"]; ok {
+		scale = new(headers.Scale)
+		err := scale.Unmarshal(v)
+		if err != nil {
+			return nil, nil, nil, liberrors.ErrServerScaleHeaderInvalid{Err: err}
+		}
+	}
+
+	var speed *headers.Speed
+	if v, ok := req.Header["Speed"]; ok {
+		speed = new(headers.Speed)
+		err := speed.Unmarshal(v)
+		if err != nil {
+			return nil, nil, nil, liberrors.ErrServerSpeedHeaderInvalid{Err: err}
+		}
+	}
+
+	return ra, scale, speed, nil
+}
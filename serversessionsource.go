@@ -0,0 +1,428 @@
+package gortsplib
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtcpsender"
+	"github.com/cobalt-robotics/gortsplib/pkg/url"
+)
+
+// ServerSessionSourcePacket is a packet returned by a ServerSessionSource.
+type ServerSessionSourcePacket struct {
+	TrackID int
+	// presentation time of the packet, relative to the beginning of the recording.
+	Time   time.Duration
+	Packet *rtp.Packet
+}
+
+// ServerSessionSource is a source of timestamped packets.
+// It can be used to serve recorded streams (video on demand),
+// since each session can seek, pause and resume its source independently.
+type ServerSessionSource interface {
+	// moves the source to the given position, relative to the beginning of the recording.
+	// The next packet returned by ReadPacket() should be the first one
+	// that is needed to decode the stream at the given position
+	// (i.e. a key frame that precedes the position).
+	Seek(time.Duration) error
+
+	// returns the next packet of the recording, ordered by time.
+	// It returns io.EOF when the recording ends.
+	ReadPacket() (*ServerSessionSourcePacket, error)
+}
+
+// ServerSessionSourceReverse can be implemented by a ServerSessionSource
+// in order to support reverse playback (negative Scale).
+type ServerSessionSourceReverse interface {
+	// returns the previous packet of the recording, ordered by reverse time.
+	// After Seek(), it should return the packets that precede the position.
+	// Since the stream must remain decodable, it usually returns key frames only.
+	// It returns io.EOF when the beginning of the recording is reached.
+	ReadPacketReverse() (*ServerSessionSourcePacket, error)
+}
+
+type serverSessionPlaybackTrack struct {
+	clockRate      int
+	ssrc           uint32
+	sequenceNumber uint16        // sequence number of the next packet
+	anchorTime     time.Duration // time that corresponds to anchorTimestamp
+	anchorTS       uint32
+	lastTS         uint32
+	rtcpSender     *rtcpsender.RTCPSender
+
+	// RTP-Info of the last start()
+	startSequenceNumber uint16
+	startTS             uint32
+}
+
+// timestamp returns the RTP timestamp that corresponds to a given time.
+// Timestamps are scaled in order to match the playback rate,
+// and they always increase, even in case of reverse playback.
+func (t *serverSessionPlaybackTrack) timestamp(v time.Duration, scale float64) uint32 {
+	d := time.Duration(float64(v-t.anchorTime) / scale)
+	return t.anchorTS + uint32(int64(d)*int64(t.clockRate)/int64(time.Second))
+}
+
+// serverSessionPlayback reads packets from a ServerSessionSource
+// and sends them to a session, at the pace of the recording.
+type serverSessionPlayback struct {
+	ss     *ServerSession
+	source ServerSessionSource
+	tracks map[int]*serverSessionPlaybackTrack
+
+	// position of the next packet to send
+	position      time.Duration
+	startPosition time.Duration
+	pending       *ServerSessionSourcePacket
+	sent          bool
+	scale         float64
+	speed         float64
+
+	ctxCancel func()
+	done      chan struct{}
+}
+
+func newServerSessionPlayback(ss *ServerSession) *serverSessionPlayback {
+	p := &serverSessionPlayback{
+		ss:     ss,
+		source: ss.source,
+		tracks: make(map[int]*serverSessionPlaybackTrack),
+	}
+
+	for trackID, sst := range ss.setuppedTracks {
+		// use the SSRC that has been advertised to the client, if any.
+		ssrc := sst.ssrc
+		if ssrc == 0 {
+			ssrc = randUint32()
+		}
+
+		p.tracks[trackID] = &serverSessionPlaybackTrack{
+			clockRate:      ss.setuppedStream.tracks[trackID].ClockRate(),
+			ssrc:           ssrc,
+			sequenceNumber: uint16(randUint32()),
+			anchorTS:       randUint32(),
+		}
+	}
+
+	return p
+}
+
+// start starts sending packets.
+// When seek is not nil, the source is moved to the given position,
+// otherwise playback is resumed from the current position.
+func (p *serverSessionPlayback) start(seek *time.Duration, scale float64, speed float64) error {
+	// the source has to be read in the opposite direction, starting from the current position.
+	if seek == nil && p.sent && (scale < 0) != (p.scale < 0) {
+		v := p.position
+		seek = &v
+	}
+
+	if seek != nil {
+		err := p.source.Seek(*seek)
+		if err != nil {
+			return err
+		}
+
+		p.position = *seek
+		p.pending = nil
+
+		// the source is moved to a key frame that precedes the position,
+		// therefore playback starts from the first packet that is returned.
+		pkt, err := p.readFunc(scale)()
+		if err == nil {
+			p.pending = pkt
+			p.position = pkt.Time
+		}
+	}
+
+	// timestamps restart from the position.
+	// In case of seeks, they restart after the last timestamp that has been sent.
+	for _, t := range p.tracks {
+		if p.sent {
+			if seek != nil {
+				t.anchorTS = t.lastTS + 1
+			} else {
+				t.anchorTS = t.timestamp(p.position, p.scale)
+			}
+		}
+		t.anchorTime = p.position
+	}
+
+	p.scale = scale
+	p.speed = speed
+	p.startPosition = p.position
+
+	for _, t := range p.tracks {
+		t.startSequenceNumber = t.sequenceNumber
+		t.startTS = t.anchorTS
+	}
+
+	for trackID, t := range p.tracks {
+		ctrackID := trackID
+		t.rtcpSender = rtcpsender.New(
+			p.ss.s.udpSenderReportPeriod,
+			t.clockRate,
+			func(pkt rtcp.Packet) {
+				p.ss.WritePacketRTCP(ctrackID, pkt)
+			},
+		)
+	}
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	p.ctxCancel = ctxCancel
+	p.done = make(chan struct{})
+
+	go p.run(ctx, time.Now(), p.startPosition)
+
+	return nil
+}
+
+// stop stops sending packets.
+func (p *serverSessionPlayback) stop() {
+	if p.ctxCancel != nil {
+		p.ctxCancel()
+		<-p.done
+		p.ctxCancel = nil
+
+		for _, t := range p.tracks {
+			t.rtcpSender.Close()
+		}
+	}
+}
+
+// readFunc returns the function that reads the source in the direction of the given scale.
+func (p *serverSessionPlayback) readFunc(scale float64) func() (*ServerSessionSourcePacket, error) {
+	if scale < 0 {
+		return p.source.(ServerSessionSourceReverse).ReadPacketReverse
+	}
+	return p.source.ReadPacket
+}
+
+func (p *serverSessionPlayback) run(ctx context.Context, startWall time.Time, startPos time.Duration) {
+	defer close(p.done)
+
+	readPacket := p.readFunc(p.scale)
+
+	rate := p.scale * p.speed
+	if rate < 0 {
+		rate = -rate
+	}
+
+	for {
+		if p.pending == nil {
+			pkt, err := readPacket()
+			if err != nil {
+				return
+			}
+
+			p.pending = pkt
+			p.position = pkt.Time
+		}
+
+		elapsed := p.pending.Time - startPos
+		if elapsed < 0 {
+			elapsed = -elapsed
+		}
+
+		wait := time.Until(startWall.Add(time.Duration(float64(elapsed) / rate)))
+		if wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return
+			}
+		}
+
+		pkt := p.pending
+		p.pending = nil
+
+		p.write(pkt)
+	}
+}
+
+func (p *serverSessionPlayback) write(in *ServerSessionSourcePacket) {
+	t, ok := p.tracks[in.TrackID]
+	if !ok {
+		return
+	}
+
+	pkt := &rtp.Packet{
+		Header:  in.Packet.Header,
+		Payload: in.Packet.Payload,
+	}
+	pkt.SSRC = t.ssrc
+	pkt.SequenceNumber = t.sequenceNumber
+	pkt.Timestamp = t.timestamp(in.Time, p.scale)
+
+	t.sequenceNumber++
+	t.lastTS = pkt.Timestamp
+	p.sent = true
+
+	t.rtcpSender.ProcessPacketRTP(time.Now(), pkt, true)
+	p.ss.WritePacketRTP(in.TrackID, pkt)
+}
+
+// rtpInfo returns the RTP-Info of the last start().
+func (p *serverSessionPlayback) rtpInfo(req *base.Request, setuppedPath string) headers.RTPInfo {
+	var trackIDs []int
+	for trackID := range p.tracks {
+		trackIDs = append(trackIDs, trackID)
+	}
+
+	sort.Ints(trackIDs)
+
+	var ri headers.RTPInfo
+
+	for _, trackID := range trackIDs {
+		t := p.tracks[trackID]
+
+		u := &url.URL{
+			Scheme: req.URL.Scheme,
+			User:   req.URL.User,
+			Host:   req.URL.Host,
+			Path:   "/" + setuppedPath + "/trackID=" + strconv.FormatInt(int64(trackID), 10),
+		}
+
+		seqNum := t.startSequenceNumber
+		ts := t.startTS
+
+		ri = append(ri, &headers.RTPInfoEntry{
+			URL:            u.String(),
+			SequenceNumber: &seqNum,
+			Timestamp:      &ts,
+		})
+	}
+
+	return ri
+}
+
+// playSource starts reading the source of the session.
+func (ss *ServerSession) playSource(
+	req *base.Request,
+	res *base.Response,
+	ra *headers.Range,
+	scale *headers.Scale,
+	speed *headers.Speed,
+) (*base.Response, error) {
+	var seek *time.Duration
+
+	if ra != nil {
+		npt, ok := ra.Value.(*headers.RangeNPT)
+		if !ok {
+			return &base.Response{
+				StatusCode: base.StatusInvalidRange,
+			}, nil
+		}
+
+		v := time.Duration(npt.Start)
+		seek = &v
+	}
+
+	// rates set by the handler have priority over the requested ones.
+	if v, ok := res.Header["Scale"]; ok {
+		var tmp headers.Scale
+		if tmp.Unmarshal(v) == nil {
+			scale = &tmp
+		}
+	}
+
+	if v, ok := res.Header["Speed"]; ok {
+		var tmp headers.Speed
+		if tmp.Unmarshal(v) == nil {
+			speed = &tmp
+		}
+	}
+
+	playScale := float64(1)
+	if scale != nil {
+		playScale = float64(*scale)
+	}
+
+	playSpeed := float64(1)
+	if speed != nil {
+		playSpeed = float64(*speed)
+	}
+
+	if playScale < 0 {
+		if _, ok := ss.source.(ServerSessionSourceReverse); !ok {
+			return &base.Response{
+				StatusCode: base.StatusHeaderFieldNotValidForResource,
+			}, nil
+		}
+	}
+
+	if ss.playback == nil {
+		ss.playback = newServerSessionPlayback(ss)
+	} else {
+		ss.playback.stop()
+	}
+
+	err := ss.playback.start(seek, playScale, playSpeed)
+	if err != nil {
+		return &base.Response{
+			StatusCode: base.StatusInvalidRange,
+		}, err
+	}
+
+	if res.Header == nil {
+		res.Header = make(base.Header)
+	}
+
+	res.Header["Range"] = headers.Range{
+		Value: &headers.RangeNPT{
+			Start: headers.RangeNPTTime(ss.playback.startPosition),
+		},
+	}.Marshal()
+
+	res.Header["RTP-Info"] = ss.playback.rtpInfo(req, *ss.setuppedPath).Marshal()
+
+	if scale != nil {
+		res.Header["Scale"] = scale.Marshal()
+	}
+
+	if speed != nil {
+		res.Header["Speed"] = speed.Marshal()
+	}
+
+	return res, nil
+}
+
+// SetSource sets a source of packets that is used to feed the session,
+// instead of the stream returned by OnSetup().
+// Each session reads its source independently, honoring the Range, Scale and Speed
+// headers of PLAY requests, therefore sources allow to serve recorded streams.
+// The stream returned by OnSetup() is still used to describe the tracks,
+// and can't be encrypted with SRTP, since each session writes packets with its own SSRC.
+// It must be called inside OnSetup() or OnPlay(), before the session starts playing.
+func (ss *ServerSession) SetSource(source ServerSessionSource) error {
+	err := ss.checkState(map[ServerSessionState]struct{}{
+		ServerSessionStateInitial: {},
+		ServerSessionStatePrePlay: {},
+	})
+	if err != nil {
+		return err
+	}
+
+	if ss.setuppedTransport != nil && *ss.setuppedTransport == TransportUDPMulticast {
+		return liberrors.ErrServerSourceMulticast{}
+	}
+
+	for _, sst := range ss.setuppedTracks {
+		if sst.srtpContext != nil {
+			return liberrors.ErrServerSourceSRTP{}
+		}
+	}
+
+	ss.source = source
+	return nil
+}
@@ -0,0 +1,155 @@
+package gortsplib
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/ringbuffer"
+)
+
+// ServerWriteQueuePolicy is the policy applied when the write queue of a session is full,
+// that happens when a reader is slower than the stream.
+type ServerWriteQueuePolicy int
+
+// write queue policies.
+const (
+	// ServerWriteQueuePolicyDropOldest drops the oldest packets of the queue.
+	ServerWriteQueuePolicyDropOldest ServerWriteQueuePolicy = iota
+
+	// ServerWriteQueuePolicyDropUntilKeyFrame drops incoming packets while the queue is full.
+	// Tracks whose key frames can be detected (H264, H265, VP8 and VP9) are resumed
+	// when the queue is half empty and a key frame is received, in order not to send corrupted frames.
+	ServerWriteQueuePolicyDropUntilKeyFrame
+
+	// ServerWriteQueuePolicyDisconnect drops the oldest packets of the queue
+	// and closes sessions that are behind for more than Server.WriteQueueDisconnectTimeout.
+	ServerWriteQueuePolicyDisconnect
+)
+
+// serverSessionWriteQueue applies the write queue policy of the server
+// and keeps track of sessions that are falling behind.
+type serverSessionWriteQueue struct {
+	ss *ServerSession
+
+	dropped    uint64 // atomic
+	behindFlag int32  // atomic
+
+	mutex           sync.Mutex
+	behindSince     time.Time
+	waitingKeyFrame map[int]struct{} // track ID -> waiting for a key frame
+	keyFrameTracks  map[int]bool     // track ID -> key frames can be detected
+}
+
+func newServerSessionWriteQueue(ss *ServerSession) *serverSessionWriteQueue {
+	return &serverSessionWriteQueue{
+		ss:              ss,
+		waitingKeyFrame: make(map[int]struct{}),
+		keyFrameTracks:  make(map[int]bool),
+	}
+}
+
+// reset resets the state of the queue, when a new buffer is allocated.
+func (q *serverSessionWriteQueue) reset() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	atomic.StoreInt32(&q.behindFlag, 0)
+	q.waitingKeyFrame = make(map[int]struct{})
+	q.keyFrameTracks = make(map[int]bool)
+}
+
+// hasKeyFrames checks whether key frames of a track can be detected.
+func (q *serverSessionWriteQueue) hasKeyFrames(trackID int) bool {
+	v, ok := q.keyFrameTracks[trackID]
+	if !ok {
+		if q.ss.setuppedStream != nil {
+			_, v = rtpKeyFrameStart(q.ss.setuppedStream.tracks[trackID], nil)
+		}
+		q.keyFrameTracks[trackID] = v
+	}
+
+	return v
+}
+
+// setBehind marks the session as behind.
+// It must be called with the mutex locked.
+func (q *serverSessionWriteQueue) setBehind() {
+	if atomic.LoadInt32(&q.behindFlag) == 1 {
+		return
+	}
+
+	atomic.StoreInt32(&q.behindFlag, 1)
+	q.behindSince = time.Now()
+
+	select {
+	case q.ss.writeQueueBehind <- struct{}{}:
+	default:
+	}
+}
+
+// push pushes an entry into the buffer, applying the policy.
+func (q *serverSessionWriteQueue) push(buffer *ringbuffer.RingBuffer, data trackTypePayload, keyFrame bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	full := buffer.Len() >= buffer.Size()
+
+	if q.ss.s.WriteQueuePolicy == ServerWriteQueuePolicyDropUntilKeyFrame {
+		if full {
+			q.setBehind()
+
+			// tracks without key frames are resumed as soon as there's space in the queue.
+			if data.isRTP && q.hasKeyFrames(data.trackID) {
+				q.waitingKeyFrame[data.trackID] = struct{}{}
+			}
+
+			atomic.AddUint64(&q.dropped, 1)
+			return
+		}
+
+		if _, ok := q.waitingKeyFrame[data.trackID]; ok && data.isRTP {
+			if !keyFrame || buffer.Len() > buffer.Size()/2 {
+				atomic.AddUint64(&q.dropped, 1)
+				return
+			}
+
+			delete(q.waitingKeyFrame, data.trackID)
+		}
+	} else if full {
+		// the oldest entry is overwritten
+		q.setBehind()
+		atomic.AddUint64(&q.dropped, 1)
+	}
+
+	buffer.Push(data)
+}
+
+// onPull is called by the writer after pulling an entry from the buffer.
+func (q *serverSessionWriteQueue) onPull(buffer *ringbuffer.RingBuffer) {
+	if atomic.LoadInt32(&q.behindFlag) == 0 {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if buffer.Len() == 0 {
+		atomic.StoreInt32(&q.behindFlag, 0)
+	}
+}
+
+// behind returns the time since the session is behind,
+// and whether the session is behind.
+func (q *serverSessionWriteQueue) behind() (time.Time, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.behindSince, atomic.LoadInt32(&q.behindFlag) == 1
+}
+
+// DroppedPackets returns the number of packets that have been dropped
+// since the session has been created, because the reader was slower than the stream.
+func (ss *ServerSession) DroppedPackets() uint64 {
+	return atomic.LoadUint64(&ss.writeQueue.dropped)
+}
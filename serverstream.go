@@ -10,6 +10,9 @@ import (
 
 	"github.com/cobalt-robotics/gortsplib/pkg/liberrors"
 	"github.com/cobalt-robotics/gortsplib/pkg/rtcpsender"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpfec"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpretransmitter"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
 )
 
 type serverStreamTrack struct {
@@ -19,6 +22,34 @@ type serverStreamTrack struct {
 	lastTimeRTP        uint32
 	lastTimeNTP        time.Time
 	udpRTCPSender      *rtcpsender.RTCPSender
+	retransmitter      *rtpretransmitter.Retransmitter
+	fecEncoder         *rtpfec.Encoder
+}
+
+// ServerStreamConf is the configuration of a ServerStream.
+type ServerStreamConf struct {
+	// encrypt packets with SRTP.
+	// Keys are generated randomly and sent to readers inside the SDP, through MIKEY,
+	// therefore the server should use TLS too.
+	// Readers must use the RTP/SAVP or RTP/SAVPF transport profile.
+	SRTPEnable bool
+
+	// advertise support for RTCP-based feedback (RTP/AVPF) and keep a history
+	// of recent packets, in order to retransmit lost packets to readers that
+	// request them with NACKs (UDP only).
+	// Retransmissions are sent with the RTP retransmission payload format (RFC 4588).
+	RetransmissionEnable bool
+
+	// called when a reader requests a key frame with a PLI or FIR message.
+	// It can be used to forward the request to the source of the stream.
+	OnKeyFrameRequest func(trackID int)
+
+	// protect tracks with forward error correction, by sending repair packets
+	// that allow readers to rebuild lost packets without requesting retransmissions.
+	// This is useful with UDP-multicast, where retransmissions are not available.
+	// Repair packets are sent to UDP and UDP-multicast readers only.
+	// Keys are track IDs.
+	FEC map[int]FECConf
 }
 
 // ServerStream represents a single stream.
@@ -35,6 +66,13 @@ type ServerStream struct {
 	readers                 map[*ServerSession]struct{}
 	serverMulticastHandlers []*serverMulticastHandler
 	stTracks                []*serverStreamTrack
+	srtpKeys                []*srtpKey
+	srtpContexts            []*srtp.Context
+	feedbacks               []*trackFeedback
+	fecs                    []*trackFEC
+	onKeyFrameRequest       func(trackID int)
+	selector                *ServerStreamSelector
+	selectorIndex           int
 }
 
 // NewServerStream allocates a ServerStream.
@@ -56,6 +94,82 @@ func NewServerStream(tracks Tracks) *ServerStream {
 	return st
 }
 
+// NewServerStreamSRTP allocates a ServerStream whose packets are encrypted with SRTP.
+// Keys are generated randomly and sent to readers inside the SDP, through MIKEY,
+// therefore the server should use TLS too.
+// Readers must use the RTP/SAVP transport profile.
+func NewServerStreamSRTP(tracks Tracks) (*ServerStream, error) {
+	return NewServerStreamWithConf(tracks, ServerStreamConf{
+		SRTPEnable: true,
+	})
+}
+
+// NewServerStreamWithConf allocates a ServerStream with the given configuration.
+func NewServerStreamWithConf(tracks Tracks, conf ServerStreamConf) (*ServerStream, error) {
+	st := NewServerStream(tracks)
+
+	if conf.SRTPEnable {
+		var err error
+		st.srtpKeys, err = newSRTPKeys(len(st.tracks))
+		if err != nil {
+			return nil, err
+		}
+
+		st.srtpContexts, err = srtpContexts(st.srtpKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.RetransmissionEnable {
+		st.feedbacks = newTrackFeedbacks(st.tracks)
+
+		for i, f := range st.feedbacks {
+			if f.rtxPayloadType != 0 {
+				st.stTracks[i].retransmitter = rtpretransmitter.New(f.rtxPayloadType)
+			}
+		}
+	}
+
+	for trackID, fecConf := range conf.FEC {
+		if trackID < 0 || trackID >= len(st.tracks) {
+			return nil, fmt.Errorf("invalid track ID: %d", trackID)
+		}
+
+		if st.fecs == nil {
+			st.fecs = make([]*trackFEC, len(st.tracks))
+		}
+
+		// the payload type must be different from the one of retransmissions.
+		var reserved []uint8
+		if f := st.feedback(trackID); f != nil && f.rtxPayloadType != 0 {
+			reserved = append(reserved, f.rtxPayloadType)
+		}
+
+		fec := &trackFEC{
+			scheme:      fecConf.Scheme,
+			payloadType: unusedPayloadType(st.tracks[trackID].MediaDescription(), reserved...),
+		}
+
+		groupSize := fecConf.GroupSize
+		if groupSize == 0 {
+			groupSize = 10
+		}
+
+		var err error
+		st.stTracks[trackID].fecEncoder, err = rtpfec.NewEncoder(fec.scheme, fec.payloadType, groupSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to setup FEC of track %d: %s", trackID, err)
+		}
+
+		st.fecs[trackID] = fec
+	}
+
+	st.onKeyFrameRequest = conf.OnKeyFrameRequest
+
+	return st, nil
+}
+
 // Close closes a ServerStream.
 func (st *ServerStream) Close() error {
 	st.mutex.Lock()
@@ -83,6 +197,83 @@ func (st *ServerStream) Tracks() Tracks {
 	return st.tracks
 }
 
+func (st *ServerStream) marshalSDP(multicast bool) ([]byte, error) {
+	return st.tracks.marshal(multicast, st.srtpKeys, st.feedbacks, st.fecs)
+}
+
+func (st *ServerStream) srtpContext(trackID int) *srtp.Context {
+	if st.srtpContexts == nil {
+		return nil
+	}
+	return st.srtpContexts[trackID]
+}
+
+func (st *ServerStream) feedback(trackID int) *trackFeedback {
+	if st.feedbacks == nil {
+		return nil
+	}
+	return st.feedbacks[trackID]
+}
+
+// onReaderPacketRTCP handles feedback messages sent by readers.
+func (st *ServerStream) onReaderPacketRTCP(ss *ServerSession, trackID int, pkt rtcp.Packet) {
+	st.mutex.RLock()
+	selector := st.selector
+	st.mutex.RUnlock()
+
+	if selector != nil {
+		selector.onReaderPacketRTCP(ss, trackID, pkt)
+		return
+	}
+
+	switch pkt := pkt.(type) {
+	case *rtcp.TransportLayerNack:
+		// retransmissions are sent only to readers that negotiated them.
+		if sst, ok := ss.setuppedTracks[trackID]; ok && sst.feedback != nil {
+			st.retransmit(ss, trackID, pkt)
+		}
+
+	case *rtcp.PictureLossIndication, *rtcp.FullIntraRequest:
+		if st.onKeyFrameRequest != nil {
+			st.onKeyFrameRequest(trackID)
+		}
+	}
+}
+
+func (st *ServerStream) retransmit(ss *ServerSession, trackID int, nack *rtcp.TransportLayerNack) {
+	if trackID >= len(st.stTracks) {
+		return
+	}
+
+	retransmitter := st.stTracks[trackID].retransmitter
+	if retransmitter == nil {
+		return
+	}
+
+	for _, pair := range nack.Nacks {
+		for _, seq := range pair.PacketList() {
+			pkt := retransmitter.Retransmit(seq)
+			if pkt == nil {
+				continue
+			}
+
+			byts, err := pkt.Marshal()
+			if err != nil {
+				continue
+			}
+
+			if ctx := st.srtpContext(trackID); ctx != nil {
+				byts, err = ctx.EncryptRTP(byts)
+				if err != nil {
+					continue
+				}
+			}
+
+			ss.writePacketRTP(trackID, byts, false)
+		}
+	}
+}
+
 func (st *ServerStream) ssrc(trackID int) uint32 {
 	st.mutex.Lock()
 	defer st.mutex.Unlock()
@@ -209,7 +400,11 @@ func (st *ServerStream) readerSetActive(ss *ServerSession) {
 
 	switch *ss.setuppedTransport {
 	case TransportUDP, TransportTCP:
-		st.readersUnicast[ss] = struct{}{}
+		if st.selector != nil {
+			st.selector.readerAdd(st, ss)
+		} else {
+			st.readersUnicast[ss] = struct{}{}
+		}
 
 	default: // UDPMulticast
 		for trackID, track := range ss.setuppedTracks {
@@ -225,6 +420,9 @@ func (st *ServerStream) readerSetInactive(ss *ServerSession) {
 
 	switch *ss.setuppedTransport {
 	case TransportUDP, TransportTCP:
+		if st.selector != nil {
+			st.selector.readerRemove(ss)
+		}
 		delete(st.readersUnicast, ss)
 
 	default: // UDPMulticast
@@ -245,6 +443,34 @@ func (st *ServerStream) WritePacketRTP(trackID int, pkt *rtp.Packet, ptsEqualsDT
 	}
 	byts = byts[:n]
 
+	if r := st.stTracks[trackID].retransmitter; r != nil {
+		r.Add(pkt.SequenceNumber, byts)
+	}
+
+	var repair []byte
+	if e := st.stTracks[trackID].fecEncoder; e != nil {
+		if repairPkt := e.Encode(byts); repairPkt != nil {
+			repair, err = repairPkt.Marshal()
+			if err != nil {
+				repair = nil
+			}
+		}
+	}
+
+	if ctx := st.srtpContext(trackID); ctx != nil {
+		byts, err = ctx.EncryptRTP(byts)
+		if err != nil {
+			return
+		}
+
+		if repair != nil {
+			repair, err = ctx.EncryptRTP(repair)
+			if err != nil {
+				repair = nil
+			}
+		}
+	}
+
 	st.mutex.RLock()
 	defer st.mutex.RUnlock()
 
@@ -265,13 +491,30 @@ func (st *ServerStream) WritePacketRTP(trackID int, pkt *rtp.Packet, ptsEqualsDT
 	}
 
 	// send unicast
-	for r := range st.readersUnicast {
-		r.writePacketRTP(trackID, byts)
+	if len(st.readersUnicast) != 0 {
+		keyFrame, _ := rtpKeyFrameStart(st.tracks[trackID], pkt.Payload)
+
+		for r := range st.readersUnicast {
+			r.writePacketRTP(trackID, byts, keyFrame)
+
+			// repair packets are useless with TCP, that is reliable.
+			if repair != nil && *r.setuppedTransport == TransportUDP {
+				r.writePacketRTP(trackID, repair, false)
+			}
+		}
+	}
+
+	if st.selector != nil {
+		st.selector.writePacketRTP(st.selectorIndex, trackID, pkt, ptsEqualsDTS)
 	}
 
 	// send multicast
 	if st.serverMulticastHandlers != nil {
 		st.serverMulticastHandlers[trackID].writePacketRTP(byts)
+
+		if repair != nil {
+			st.serverMulticastHandlers[trackID].writePacketRTP(repair)
+		}
 	}
 }
 
@@ -282,6 +525,13 @@ func (st *ServerStream) WritePacketRTCP(trackID int, pkt rtcp.Packet) {
 		return
 	}
 
+	if ctx := st.srtpContext(trackID); ctx != nil {
+		byts, err = ctx.EncryptRTCP(byts)
+		if err != nil {
+			return
+		}
+	}
+
 	st.mutex.RLock()
 	defer st.mutex.RUnlock()
 
@@ -0,0 +1,753 @@
+package gortsplib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/ringbuffer"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtcpsender"
+)
+
+// rtpKeyFrameStart checks whether a RTP packet is the first one of a key frame.
+// The second return value is false when the codec of the track is not supported.
+func rtpKeyFrameStart(track Track, payload []byte) (bool, bool) {
+	switch track.(type) {
+	case *TrackH264:
+		return rtpH264KeyFrameStart(payload), true
+
+	case *TrackH265:
+		return rtpH265KeyFrameStart(payload), true
+
+	case *TrackVP8:
+		return rtpVP8KeyFrameStart(payload), true
+
+	case *TrackVP9:
+		return rtpVP9KeyFrameStart(payload), true
+	}
+
+	return false, false
+}
+
+func rtpH264KeyFrameStart(payload []byte) bool {
+	if len(payload) < 2 {
+		return false
+	}
+
+	switch typ := payload[0] & 0x1F; typ {
+	case 7: // SPS
+		return true
+
+	case 5: // IDR, first_mb_in_slice must be zero
+		return (payload[1] & 0x80) != 0
+
+	case 24: // STAP-A
+		payload = payload[1:]
+		for len(payload) >= 3 {
+			size := int(payload[0])<<8 | int(payload[1])
+			if size == 0 || len(payload) < 2+size {
+				return false
+			}
+
+			if typ := payload[2] & 0x1F; typ == 7 || typ == 5 {
+				return true
+			}
+
+			payload = payload[2+size:]
+		}
+
+	case 28: // FU-A
+		if len(payload) < 3 {
+			return false
+		}
+		start := (payload[1] & 0x80) != 0
+		return start && (payload[1]&0x1F) == 5 && (payload[2]&0x80) != 0
+	}
+
+	return false
+}
+
+func rtpH265KeyFrameStart(payload []byte) bool {
+	if len(payload) < 3 {
+		return false
+	}
+
+	isIRAP := func(typ byte) bool {
+		return typ >= 16 && typ <= 21
+	}
+
+	switch typ := (payload[0] >> 1) & 0x3F; {
+	case typ == 32: // VPS
+		return true
+
+	case isIRAP(typ): // first_slice_segment_in_pic_flag must be one
+		return (payload[2] & 0x80) != 0
+
+	case typ == 48: // aggregation packet
+		payload = payload[2:]
+		for len(payload) >= 4 {
+			size := int(payload[0])<<8 | int(payload[1])
+			if size < 2 || len(payload) < 2+size {
+				return false
+			}
+
+			if typ := (payload[2] >> 1) & 0x3F; typ == 32 || isIRAP(typ) {
+				return true
+			}
+
+			payload = payload[2+size:]
+		}
+
+	case typ == 49: // fragmentation unit
+		if len(payload) < 4 {
+			return false
+		}
+		start := (payload[2] & 0x80) != 0
+		return start && isIRAP(payload[2]&0x3F) && (payload[3]&0x80) != 0
+	}
+
+	return false
+}
+
+// rtpVP8PayloadDescriptorSize returns the size of a VP8 payload descriptor.
+func rtpVP8PayloadDescriptorSize(payload []byte) int {
+	if len(payload) < 1 {
+		return -1
+	}
+
+	n := 1
+
+	if (payload[0] & 0x80) != 0 { // X
+		if len(payload) < 2 {
+			return -1
+		}
+		ext := payload[1]
+		n++
+
+		if (ext & 0x80) != 0 { // I
+			if len(payload) < n+1 {
+				return -1
+			}
+			if (payload[n] & 0x80) != 0 { // M
+				n += 2
+			} else {
+				n++
+			}
+		}
+
+		if (ext & 0x40) != 0 { // L
+			n++
+		}
+
+		if (ext&0x20) != 0 || (ext&0x10) != 0 { // T or K
+			n++
+		}
+	}
+
+	if len(payload) < n {
+		return -1
+	}
+
+	return n
+}
+
+func rtpVP8KeyFrameStart(payload []byte) bool {
+	n := rtpVP8PayloadDescriptorSize(payload)
+	if n < 0 || len(payload) < n+1 {
+		return false
+	}
+
+	start := (payload[0] & 0x10) != 0
+	partitionID := payload[0] & 0x07
+
+	// the P bit of the payload header is zero in key frames
+	return start && partitionID == 0 && (payload[n]&0x01) == 0
+}
+
+func rtpVP9KeyFrameStart(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+
+	interPredicted := (payload[0] & 0x40) != 0
+	start := (payload[0] & 0x08) != 0
+
+	if interPredicted || !start {
+		return false
+	}
+
+	// the key frame must belong to the base spatial layer
+	if (payload[0] & 0x20) != 0 { // L
+		n := 1
+		if (payload[0] & 0x80) != 0 { // I
+			if len(payload) < 2 {
+				return false
+			}
+			if (payload[1] & 0x80) != 0 { // M
+				n += 2
+			} else {
+				n++
+			}
+		}
+
+		if len(payload) < n+1 {
+			return false
+		}
+
+		if spatialID := (payload[n] >> 1) & 0x07; spatialID != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rtpNonReference checks whether a RTP packet belongs to a frame
+// that is not used as reference by other frames, and therefore can be dropped
+// without affecting the decoding of the stream.
+func rtpNonReference(track Track, payload []byte) bool {
+	switch track.(type) {
+	case *TrackH264:
+		// nal_ref_idc is zero
+		return len(payload) >= 1 && (payload[0]&0x60) == 0
+
+	case *TrackVP8:
+		// N bit is one
+		return len(payload) >= 1 && (payload[0]&0x20) != 0
+	}
+
+	return false
+}
+
+// ServerStreamSelectorOnSwitchCtx is the context of a stream switch.
+type ServerStreamSelectorOnSwitchCtx struct {
+	Session    *ServerSession
+	PrevStream *ServerStream
+	Stream     *ServerStream
+}
+
+// ServerStreamSelectorConf is the configuration of a ServerStreamSelector.
+type ServerStreamSelectorConf struct {
+	// fraction of lost packets, reported by a reader through RTCP receiver reports,
+	// above which the reader is considered congested.
+	// It defaults to 0.05.
+	MaxFractionLost float64
+
+	// interarrival jitter, reported by a reader through RTCP receiver reports,
+	// above which the reader is considered congested.
+	// It defaults to zero, that means that jitter is not considered.
+	MaxJitter time.Duration
+
+	// fill level of the write buffer of a reader, between 0 and 1,
+	// above which the reader is considered congested.
+	// It defaults to 0.5.
+	MaxWriteBufferUsage float64
+
+	// time that a reader must spend without congestion
+	// before being switched to a stream with an higher bitrate.
+	// It defaults to 10 seconds.
+	UpgradeDelay time.Duration
+
+	// period of the evaluation of readers.
+	// It defaults to 1 second.
+	CheckPeriod time.Duration
+
+	// drop frames that are not used as reference by other frames (H264 and VP8 only),
+	// when a reader is congested and is already reading the stream with the lowest bitrate.
+	DropNonReferenceFrames bool
+
+	// called after a reader has been switched to another stream.
+	OnSwitch func(*ServerStreamSelectorOnSwitchCtx)
+}
+
+type serverStreamSelectorReaderTrack struct {
+	clockRate  int
+	ssrc       uint32
+	ssrcSet    bool
+	resync     bool
+	seqOffset  uint16
+	tsOffset   uint32
+	sent       bool
+	lastSeq    uint16
+	lastTS     uint32
+	lastTime   time.Time
+	rtcpSender *rtcpsender.RTCPSender
+}
+
+// rewrite makes sequence numbers, timestamps and SSRCs of packets coming
+// from different streams look like they belong to a single stream.
+func (t *serverStreamSelectorReaderTrack) rewrite(pkt *rtp.Packet, drop bool, now time.Time) *rtp.Packet {
+	if !t.ssrcSet {
+		t.ssrc = pkt.SSRC
+		t.ssrcSet = true
+	}
+
+	if t.resync {
+		t.resync = false
+
+		if t.sent {
+			t.seqOffset = t.lastSeq + 1 - pkt.SequenceNumber
+			t.tsOffset = t.lastTS +
+				uint32(now.Sub(t.lastTime).Seconds()*float64(t.clockRate)) -
+				pkt.Timestamp
+		}
+	}
+
+	// skip a sequence number, in order to hide the dropped packet.
+	if drop {
+		t.seqOffset--
+		return nil
+	}
+
+	out := &rtp.Packet{
+		Header:  pkt.Header,
+		Payload: pkt.Payload,
+	}
+	out.SSRC = t.ssrc
+	out.SequenceNumber = pkt.SequenceNumber + t.seqOffset
+	out.Timestamp = pkt.Timestamp + t.tsOffset
+
+	if !t.sent || out.Timestamp != t.lastTS {
+		t.lastTS = out.Timestamp
+		t.lastTime = now
+	}
+	t.lastSeq = out.SequenceNumber
+	t.sent = true
+
+	return out
+}
+
+type serverStreamSelectorReader struct {
+	ss               *ServerSession
+	writeBuffer      *ringbuffer.RingBuffer
+	tracks           map[int]*serverStreamSelectorReaderTrack
+	cur              int // index of the stream that is being read
+	next             int // index of the stream that will be read after the next key frame, or -1
+	switchedFrom     int // index of the previous stream, or -1
+	dropNonReference bool
+	fractionLost     float64
+	jitter           time.Duration
+	lastCongestion   time.Time
+}
+
+// ServerStreamSelector switches each reader between alternative streams,
+// that contain the same tracks with different bitrates (e.g. main and sub profile),
+// depending on the network conditions of the reader,
+// that are measured through RTCP receiver reports (fraction lost and jitter)
+// and through the fill level of its write buffer.
+// Readers are switched when a key frame is received (H264, H265, VP8 and VP9 only),
+// and sequence numbers, timestamps and SSRCs are rewritten in order to be contiguous.
+// Readers can be setupped with any of the streams, and the streams can be
+// written independently.
+// Only UDP and TCP readers are switched; UDP-multicast readers always read
+// the stream they have been setupped with.
+type ServerStreamSelector struct {
+	streams             []*ServerStream
+	maxFractionLost     float64
+	maxJitter           time.Duration
+	maxWriteBufferUsage float64
+	upgradeDelay        time.Duration
+	checkPeriod         time.Duration
+	dropNonReference    bool
+	onSwitch            func(*ServerStreamSelectorOnSwitchCtx)
+	syncTrackID         int // track whose key frames trigger switches, or -1
+
+	mutex     sync.Mutex
+	readers   map[*ServerSession]*serverStreamSelectorReader
+	ctxCancel func()
+	done      chan struct{}
+}
+
+// NewServerStreamSelector allocates a ServerStreamSelector.
+// Streams must be ordered by decreasing bitrate and must contain the same tracks.
+// Streams with SRTP, retransmissions or FEC are not supported.
+func NewServerStreamSelector(streams []*ServerStream, conf ServerStreamSelectorConf) (*ServerStreamSelector, error) {
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("at least one stream must be provided")
+	}
+
+	for i, st := range streams {
+		if st.srtpKeys != nil || st.feedbacks != nil || st.fecs != nil {
+			return nil, fmt.Errorf("stream %d uses SRTP, retransmissions or FEC, that are not supported", i)
+		}
+
+		if len(st.tracks) != len(streams[0].tracks) {
+			return nil, fmt.Errorf("stream %d has a different number of tracks than stream 0", i)
+		}
+
+		for trackID, track := range st.tracks {
+			track0 := streams[0].tracks[trackID]
+			if track.MediaDescription().MediaName.Media != track0.MediaDescription().MediaName.Media ||
+				track.ClockRate() != track0.ClockRate() {
+				return nil, fmt.Errorf("track %d of stream %d is different than the one of stream 0", trackID, i)
+			}
+		}
+	}
+
+	sel := &ServerStreamSelector{
+		streams:             streams,
+		maxFractionLost:     conf.MaxFractionLost,
+		maxJitter:           conf.MaxJitter,
+		maxWriteBufferUsage: conf.MaxWriteBufferUsage,
+		upgradeDelay:        conf.UpgradeDelay,
+		checkPeriod:         conf.CheckPeriod,
+		dropNonReference:    conf.DropNonReferenceFrames,
+		onSwitch:            conf.OnSwitch,
+		syncTrackID:         -1,
+		readers:             make(map[*ServerSession]*serverStreamSelectorReader),
+	}
+
+	if sel.maxFractionLost == 0 {
+		sel.maxFractionLost = 0.05
+	}
+	if sel.maxWriteBufferUsage == 0 {
+		sel.maxWriteBufferUsage = 0.5
+	}
+	if sel.upgradeDelay == 0 {
+		sel.upgradeDelay = 10 * time.Second
+	}
+	if sel.checkPeriod == 0 {
+		sel.checkPeriod = 1 * time.Second
+	}
+
+	for trackID, track := range streams[0].tracks {
+		if _, ok := rtpKeyFrameStart(track, nil); ok {
+			sel.syncTrackID = trackID
+			break
+		}
+	}
+
+	for i, st := range streams {
+		st.mutex.Lock()
+		if st.selector != nil {
+			st.mutex.Unlock()
+			for _, st := range streams[:i] {
+				st.mutex.Lock()
+				st.selector = nil
+				st.mutex.Unlock()
+			}
+			return nil, fmt.Errorf("stream %d already belongs to a selector", i)
+		}
+		st.selector = sel
+		st.selectorIndex = i
+		st.mutex.Unlock()
+	}
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	sel.ctxCancel = ctxCancel
+	sel.done = make(chan struct{})
+
+	go sel.run(ctx)
+
+	return sel, nil
+}
+
+// Close closes a ServerStreamSelector.
+// Readers keep reading the stream they are currently reading.
+func (sel *ServerStreamSelector) Close() {
+	sel.ctxCancel()
+	<-sel.done
+
+	for _, st := range sel.streams {
+		st.mutex.Lock()
+		st.selector = nil
+
+		sel.mutex.Lock()
+		for ss, r := range sel.readers {
+			if r.cur == st.selectorIndex && st.readersUnicast != nil {
+				st.readersUnicast[ss] = struct{}{}
+			}
+		}
+		sel.mutex.Unlock()
+
+		st.mutex.Unlock()
+	}
+
+	sel.mutex.Lock()
+	defer sel.mutex.Unlock()
+
+	for _, r := range sel.readers {
+		for _, t := range r.tracks {
+			t.rtcpSender.Close()
+		}
+	}
+	sel.readers = nil
+}
+
+// ReaderStream returns the stream that is currently read by a reader.
+// It returns nil if the session is not reading any of the streams of the selector.
+func (sel *ServerStreamSelector) ReaderStream(ss *ServerSession) *ServerStream {
+	sel.mutex.Lock()
+	defer sel.mutex.Unlock()
+
+	r, ok := sel.readers[ss]
+	if !ok {
+		return nil
+	}
+	return sel.streams[r.cur]
+}
+
+// readerAdd is called by ServerStream.readerSetActive().
+func (sel *ServerStreamSelector) readerAdd(st *ServerStream, ss *ServerSession) {
+	sel.mutex.Lock()
+	defer sel.mutex.Unlock()
+
+	if sel.readers == nil {
+		return
+	}
+
+	r := &serverStreamSelectorReader{
+		ss:             ss,
+		writeBuffer:    ss.writeBuffer,
+		tracks:         make(map[int]*serverStreamSelectorReaderTrack),
+		cur:            st.selectorIndex,
+		next:           -1,
+		switchedFrom:   -1,
+		lastCongestion: time.Now(),
+	}
+
+	for trackID := range ss.setuppedTracks {
+		ctrackID := trackID
+		t := &serverStreamSelectorReaderTrack{
+			clockRate: st.tracks[trackID].ClockRate(),
+			rtcpSender: rtcpsender.New(
+				ss.s.udpSenderReportPeriod,
+				st.tracks[trackID].ClockRate(),
+				func(pkt rtcp.Packet) {
+					ss.WritePacketRTCP(ctrackID, pkt)
+				},
+			),
+		}
+
+		// keep the SSRC that has been sent to the reader in the SETUP response.
+		if stt := st.stTracks[trackID]; stt.firstPacketSent {
+			t.ssrc = stt.lastSSRC
+			t.ssrcSet = true
+		}
+
+		r.tracks[trackID] = t
+	}
+
+	sel.readers[ss] = r
+}
+
+// readerRemove is called by ServerStream.readerSetInactive().
+func (sel *ServerStreamSelector) readerRemove(ss *ServerSession) {
+	sel.mutex.Lock()
+	defer sel.mutex.Unlock()
+
+	r, ok := sel.readers[ss]
+	if !ok {
+		return
+	}
+
+	for _, t := range r.tracks {
+		t.rtcpSender.Close()
+	}
+
+	delete(sel.readers, ss)
+}
+
+// writePacketRTP is called by ServerStream.WritePacketRTP().
+func (sel *ServerStreamSelector) writePacketRTP(index int, trackID int, pkt *rtp.Packet, ptsEqualsDTS bool) {
+	sel.mutex.Lock()
+	defer sel.mutex.Unlock()
+
+	if len(sel.readers) == 0 {
+		return
+	}
+
+	track := sel.streams[index].tracks[trackID]
+	now := time.Now()
+
+	keyFrame, _ := rtpKeyFrameStart(track, pkt.Payload)
+
+	keyFrameStart := sel.syncTrackID < 0
+	if trackID == sel.syncTrackID {
+		keyFrameStart = keyFrame
+	}
+
+	var nonReference *bool
+
+	for _, r := range sel.readers {
+		t, ok := r.tracks[trackID]
+		if !ok {
+			continue
+		}
+
+		if r.next == index && keyFrameStart {
+			r.switchedFrom = r.cur
+			r.cur = index
+			r.next = -1
+
+			for _, t := range r.tracks {
+				t.resync = true
+			}
+		}
+
+		if r.cur != index {
+			continue
+		}
+
+		drop := false
+		if r.dropNonReference {
+			if nonReference == nil {
+				v := rtpNonReference(track, pkt.Payload)
+				nonReference = &v
+			}
+			drop = *nonReference
+		}
+
+		out := t.rewrite(pkt, drop, now)
+		if out == nil {
+			continue
+		}
+
+		byts, err := out.Marshal()
+		if err != nil {
+			continue
+		}
+
+		t.rtcpSender.ProcessPacketRTP(now, out, ptsEqualsDTS)
+		r.ss.writePacketRTP(trackID, byts, keyFrame)
+	}
+}
+
+// onReaderPacketRTCP is called by ServerStream.onReaderPacketRTCP().
+func (sel *ServerStreamSelector) onReaderPacketRTCP(ss *ServerSession, trackID int, pkt rtcp.Packet) {
+	var keyFrameRequestStream *ServerStream
+
+	func() {
+		sel.mutex.Lock()
+		defer sel.mutex.Unlock()
+
+		r, ok := sel.readers[ss]
+		if !ok {
+			return
+		}
+
+		switch pkt := pkt.(type) {
+		case *rtcp.ReceiverReport:
+			t, ok := r.tracks[trackID]
+			if !ok {
+				return
+			}
+
+			for _, report := range pkt.Reports {
+				fractionLost := float64(report.FractionLost) / 256
+				if fractionLost > r.fractionLost {
+					r.fractionLost = fractionLost
+				}
+
+				jitter := time.Duration(float64(report.Jitter) / float64(t.clockRate) * float64(time.Second))
+				if jitter > r.jitter {
+					r.jitter = jitter
+				}
+			}
+
+		case *rtcp.PictureLossIndication, *rtcp.FullIntraRequest:
+			keyFrameRequestStream = sel.streams[r.cur]
+		}
+	}()
+
+	if keyFrameRequestStream != nil && keyFrameRequestStream.onKeyFrameRequest != nil {
+		keyFrameRequestStream.onKeyFrameRequest(trackID)
+	}
+}
+
+func (sel *ServerStreamSelector) run(ctx context.Context) {
+	defer close(sel.done)
+
+	t := time.NewTicker(sel.checkPeriod)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			sel.check(time.Now())
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check evaluates the network conditions of readers
+// and decides whether to switch them to another stream.
+func (sel *ServerStreamSelector) check(now time.Time) {
+	var keyFrameRequests []*ServerStream
+	var switches []*ServerStreamSelectorOnSwitchCtx
+
+	func() {
+		sel.mutex.Lock()
+		defer sel.mutex.Unlock()
+
+		for _, r := range sel.readers {
+			if r.switchedFrom >= 0 {
+				switches = append(switches, &ServerStreamSelectorOnSwitchCtx{
+					Session:    r.ss,
+					PrevStream: sel.streams[r.switchedFrom],
+					Stream:     sel.streams[r.cur],
+				})
+				r.switchedFrom = -1
+			}
+
+			usage := float64(r.writeBuffer.Len()) / float64(r.writeBuffer.Size())
+
+			congested := r.fractionLost > sel.maxFractionLost ||
+				(sel.maxJitter != 0 && r.jitter > sel.maxJitter) ||
+				usage > sel.maxWriteBufferUsage
+
+			// each receiver report is evaluated once
+			r.fractionLost = 0
+			r.jitter = 0
+
+			if congested {
+				r.lastCongestion = now
+
+				switch {
+				case r.next >= 0 && r.next < r.cur:
+					// cancel the upgrade
+					r.next = -1
+
+				case r.next < 0 && r.cur < (len(sel.streams)-1):
+					r.next = r.cur + 1
+					keyFrameRequests = append(keyFrameRequests, sel.streams[r.next])
+
+				case r.next < 0 && sel.dropNonReference:
+					r.dropNonReference = true
+				}
+			} else if r.next < 0 && now.Sub(r.lastCongestion) >= sel.upgradeDelay {
+				switch {
+				case r.dropNonReference:
+					r.dropNonReference = false
+					r.lastCongestion = now
+
+				case r.cur > 0:
+					r.next = r.cur - 1
+					r.lastCongestion = now
+					keyFrameRequests = append(keyFrameRequests, sel.streams[r.next])
+				}
+			}
+		}
+	}()
+
+	// ask the source of the stream for a key frame, in order to switch readers faster.
+	if sel.syncTrackID >= 0 {
+		for _, st := range keyFrameRequests {
+			if st.onKeyFrameRequest != nil {
+				st.onKeyFrameRequest(sel.syncTrackID)
+			}
+		}
+	}
+
+	if sel.onSwitch != nil {
+		for _, ctx := range switches {
+			sel.onSwitch(ctx)
+		}
+	}
+}
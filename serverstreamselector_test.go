@@ -0,0 +1,355 @@
+package gortsplib
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/base"
+	"github.com/cobalt-robotics/gortsplib/pkg/conn"
+	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+)
+
+func TestRTPKeyFrameStart(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		track   Track
+		payload []byte
+		ok      bool
+	}{
+		{
+			"h264 idr",
+			&TrackH264{},
+			[]byte{0x65, 0x88, 0x84},
+			true,
+		},
+		{
+			"h264 idr second slice",
+			&TrackH264{},
+			[]byte{0x65, 0x40, 0x84},
+			false,
+		},
+		{
+			"h264 non-idr",
+			&TrackH264{},
+			[]byte{0x41, 0x9a, 0x02},
+			false,
+		},
+		{
+			"h264 stap-a with sps",
+			&TrackH264{},
+			[]byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce},
+			true,
+		},
+		{
+			"h264 fu-a idr start",
+			&TrackH264{},
+			[]byte{0x7c, 0x85, 0x88, 0x84},
+			true,
+		},
+		{
+			"h264 fu-a idr continuation",
+			&TrackH264{},
+			[]byte{0x7c, 0x05, 0x88, 0x84},
+			false,
+		},
+		{
+			"h265 idr",
+			&TrackH265{},
+			[]byte{0x26, 0x01, 0xaf, 0x08},
+			true,
+		},
+		{
+			"h265 fu idr start",
+			&TrackH265{},
+			[]byte{0x62, 0x01, 0x93, 0xaf},
+			true,
+		},
+		{
+			"h265 trail",
+			&TrackH265{},
+			[]byte{0x02, 0x01, 0xd0, 0x09},
+			false,
+		},
+		{
+			"vp8 key frame",
+			&TrackVP8{},
+			[]byte{0x10, 0x50, 0x01, 0x00},
+			true,
+		},
+		{
+			"vp8 inter frame",
+			&TrackVP8{},
+			[]byte{0x10, 0x51, 0x01, 0x00},
+			false,
+		},
+		{
+			"vp9 key frame",
+			&TrackVP9{},
+			[]byte{0x88, 0x01, 0x83},
+			true,
+		},
+		{
+			"vp9 inter frame",
+			&TrackVP9{},
+			[]byte{0xc8, 0x01, 0x83},
+			false,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			ok, supported := rtpKeyFrameStart(ca.track, ca.payload)
+			require.Equal(t, true, supported)
+			require.Equal(t, ca.ok, ok)
+		})
+	}
+
+	_, supported := rtpKeyFrameStart(&TrackOpus{}, []byte{0x01})
+	require.Equal(t, false, supported)
+}
+
+func TestServerStreamSelectorErrors(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	_, err := NewServerStreamSelector(nil, ServerStreamSelectorConf{})
+	require.EqualError(t, err, "at least one stream must be provided")
+
+	stream1 := NewServerStream(Tracks{track})
+	defer stream1.Close()
+
+	stream2, err := NewServerStreamSRTP(Tracks{track})
+	require.NoError(t, err)
+	defer stream2.Close()
+
+	_, err = NewServerStreamSelector([]*ServerStream{stream1, stream2}, ServerStreamSelectorConf{})
+	require.EqualError(t, err, "stream 1 uses SRTP, retransmissions or FEC, that are not supported")
+
+	stream3 := NewServerStream(Tracks{&TrackOpus{PayloadType: 97, ChannelCount: 2}})
+	defer stream3.Close()
+
+	_, err = NewServerStreamSelector([]*ServerStream{stream1, stream3}, ServerStreamSelectorConf{})
+	require.EqualError(t, err, "track 0 of stream 1 is different than the one of stream 0")
+
+	sel, err := NewServerStreamSelector([]*ServerStream{stream1}, ServerStreamSelectorConf{})
+	require.NoError(t, err)
+	defer sel.Close()
+
+	_, err = NewServerStreamSelector([]*ServerStream{stream1}, ServerStreamSelectorConf{})
+	require.EqualError(t, err, "stream 0 already belongs to a selector")
+}
+
+func TestServerStreamSelector(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	mainKeyFrameRequest := make(chan struct{}, 10)
+	subKeyFrameRequest := make(chan struct{}, 10)
+
+	mainStream, err := NewServerStreamWithConf(Tracks{track}, ServerStreamConf{
+		OnKeyFrameRequest: func(trackID int) {
+			mainKeyFrameRequest <- struct{}{}
+		},
+	})
+	require.NoError(t, err)
+	defer mainStream.Close()
+
+	subStream, err := NewServerStreamWithConf(Tracks{track}, ServerStreamConf{
+		OnKeyFrameRequest: func(trackID int) {
+			subKeyFrameRequest <- struct{}{}
+		},
+	})
+	require.NoError(t, err)
+	defer subStream.Close()
+
+	switched := make(chan *ServerStreamSelectorOnSwitchCtx, 10)
+
+	sel, err := NewServerStreamSelector([]*ServerStream{mainStream, subStream}, ServerStreamSelectorConf{
+		UpgradeDelay:           300 * time.Millisecond,
+		CheckPeriod:            50 * time.Millisecond,
+		DropNonReferenceFrames: true,
+		OnSwitch: func(ctx *ServerStreamSelectorOnSwitchCtx) {
+			switched <- ctx
+		},
+	})
+	require.NoError(t, err)
+	defer sel.Close()
+
+	var session *ServerSession
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, mainStream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				session = ctx.Session
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err = s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	inTH := &headers.Transport{
+		Mode: func() *headers.TransportMode {
+			v := headers.TransportModePlay
+			return &v
+		}(),
+		Delivery: func() *headers.TransportDelivery {
+			v := headers.TransportDeliveryUnicast
+			return &v
+		}(),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream/trackID=0"),
+		Header: base.Header{
+			"CSeq":      base.HeaderValue{"1"},
+			"Transport": inTH.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	var sx headers.Session
+	err = sx.Unmarshal(res.Header["Session"])
+	require.NoError(t, err)
+
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.Play,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":    base.HeaderValue{"2"},
+			"Session": base.HeaderValue{sx.Session},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	write := func(st *ServerStream, seq uint16, ssrc uint32, payload []byte) {
+		st.WritePacketRTP(0, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: seq,
+				Timestamp:      uint32(seq) * 3000,
+				SSRC:           ssrc,
+			},
+			Payload: payload,
+		}, true)
+	}
+
+	read := func() *rtp.Packet {
+		for {
+			f, err := conn.ReadInterleavedFrame()
+			require.NoError(t, err)
+
+			// skip RTCP sender reports
+			if f.Channel != 0 {
+				continue
+			}
+
+			var pkt rtp.Packet
+			err = pkt.Unmarshal(f.Payload)
+			require.NoError(t, err)
+			return &pkt
+		}
+	}
+
+	writeReceiverReport := func(fractionLost uint8) {
+		byts, _ := (&rtcp.ReceiverReport{
+			SSRC: 0x12345678,
+			Reports: []rtcp.ReceptionReport{{
+				SSRC:         0x38F27A2F,
+				FractionLost: fractionLost,
+			}},
+		}).Marshal()
+		err := conn.WriteInterleavedFrame(&base.InterleavedFrame{
+			Channel: 1,
+			Payload: byts,
+		}, make([]byte, 1024))
+		require.NoError(t, err)
+	}
+
+	idr := []byte{0x65, 0x88, 0x84, 0x00}
+	reference := []byte{0x61, 0x9a, 0x02, 0x00}
+	nonReference := []byte{0x01, 0x9a, 0x02, 0x00}
+
+	write(mainStream, 100, 0x38F27A2F, reference)
+	pkt := read()
+	require.Equal(t, uint16(100), pkt.SequenceNumber)
+	require.Equal(t, uint32(0x38F27A2F), pkt.SSRC)
+	lastTS := pkt.Timestamp
+
+	// congestion causes a switch to the sub stream
+	writeReceiverReport(128)
+	<-subKeyFrameRequest
+
+	// the switch happens at the next key frame
+	write(subStream, 5000, 0x11111111, reference)
+	write(mainStream, 101, 0x38F27A2F, reference)
+	pkt = read()
+	require.Equal(t, uint16(101), pkt.SequenceNumber)
+
+	write(subStream, 5001, 0x11111111, idr)
+	write(mainStream, 102, 0x38F27A2F, reference)
+	pkt = read()
+	require.Equal(t, uint16(102), pkt.SequenceNumber)
+	require.Equal(t, uint32(0x38F27A2F), pkt.SSRC)
+	require.Equal(t, idr, pkt.Payload)
+	require.GreaterOrEqual(t, pkt.Timestamp, lastTS)
+
+	ctx := <-switched
+	require.Equal(t, session, ctx.Session)
+	require.Equal(t, mainStream, ctx.PrevStream)
+	require.Equal(t, subStream, ctx.Stream)
+	require.Equal(t, subStream, sel.ReaderStream(session))
+
+	// congestion on the lowest stream causes non-reference frames to be dropped
+	writeReceiverReport(128)
+	time.Sleep(200 * time.Millisecond)
+
+	write(subStream, 5002, 0x11111111, nonReference)
+	write(subStream, 5003, 0x11111111, reference)
+	pkt = read()
+	require.Equal(t, uint16(103), pkt.SequenceNumber)
+	require.Equal(t, reference, pkt.Payload)
+
+	// the absence of congestion causes a switch to the main stream
+	<-mainKeyFrameRequest
+
+	write(mainStream, 103, 0x38F27A2F, idr)
+	pkt = read()
+	require.Equal(t, uint16(104), pkt.SequenceNumber)
+	require.Equal(t, uint32(0x38F27A2F), pkt.SSRC)
+
+	ctx = <-switched
+	require.Equal(t, subStream, ctx.PrevStream)
+	require.Equal(t, mainStream, ctx.Stream)
+	require.Equal(t, mainStream, sel.ReaderStream(session))
+}
@@ -192,14 +192,33 @@ func (u *serverUDPListener) runReader() {
 }
 
 func (u *serverUDPListener) processRTP(clientData *clientData, payload []byte) {
+	payload, ok := srtpDecryptRTP(clientData.track.srtpContext, payload)
+	if !ok {
+		return
+	}
+
 	pkt := u.s.udpRTPPacketBuffer.next()
 	err := pkt.Unmarshal(payload)
 	if err != nil {
 		return
 	}
 
+	fr := clientData.track.feedbackReceiver
+	if fr != nil {
+		pkt, ok = fr.processRTP(pkt)
+		if !ok {
+			return
+		}
+	}
+
 	packets := clientData.track.reorderer.Process(pkt)
 
+	if fr != nil {
+		if nack := fr.nack(clientData.track.reorderer.Missing()); nack != nil {
+			clientData.ss.WritePacketRTCP(clientData.track.id, nack)
+		}
+	}
+
 	for _, pkt := range packets {
 		now := time.Now()
 		atomic.StoreInt64(clientData.ss.udpLastFrameTime, now.Unix())
@@ -226,6 +245,11 @@ func (u *serverUDPListener) processRTP(clientData *clientData, payload []byte) {
 }
 
 func (u *serverUDPListener) processRTCP(clientData *clientData, payload []byte) {
+	payload, ok := srtpDecryptRTCP(clientData.track.srtpContext, payload)
+	if !ok {
+		return
+	}
+
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
 		return
@@ -241,6 +265,10 @@ func (u *serverUDPListener) processRTCP(clientData *clientData, payload []byte)
 	}
 
 	for _, pkt := range packets {
+		if !clientData.isPublishing {
+			clientData.ss.setuppedStream.onReaderPacketRTCP(clientData.ss, clientData.track.id, pkt)
+		}
+
 		clientData.ss.onPacketRTCP(clientData.track.id, pkt)
 	}
 }
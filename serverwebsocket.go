@@ -0,0 +1,33 @@
+package gortsplib
+
+import (
+	"net/http"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/wsconn"
+)
+
+// WebSocketHandler returns a HTTP handler that accepts RTSP-over-WebSocket connections.
+// Requests, responses and interleaved frames are carried inside binary messages,
+// therefore clients must use the TCP transport.
+// The handler can be mounted on any HTTP server and can be used only after Start().
+// Connections opened by web pages of other sites are rejected, unless
+// they are allowed by WebSocketCheckOrigin.
+func (s *Server) WebSocketHandler() http.Handler {
+	return wsconn.Handler(func(nconn *wsconn.Conn) {
+		res := make(chan *ServerConn, 1)
+
+		select {
+		case s.connNewExternal <- serverConnNewReq{
+			nconn: nconn,
+			res:   res,
+		}:
+		case <-s.ctx.Done():
+			return
+		}
+
+		sc := <-res
+
+		// the connection is closed when the handler returns
+		<-sc.done
+	}, s.WebSocketCheckOrigin)
+}
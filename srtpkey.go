@@ -0,0 +1,331 @@
+package gortsplib
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	psdp "github.com/pion/sdp/v3"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mikey"
+	"github.com/cobalt-robotics/gortsplib/pkg/sdp"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
+)
+
+// MIKEY values of SRTP security policy parameters.
+const (
+	mikeyEncrAlgAESCM     = 1
+	mikeyAuthAlgHMACSHA1  = 1
+	mikeyAuthKeyLen       = 20
+	mikeyOn               = 1
+	ntpEpochOffsetSeconds = 2208988800
+)
+
+// srtpKey is the master key of a SRTP crypto context.
+// It is exchanged through SDP, with the a=key-mgmt (RFC 4567) or a=crypto (RFC 4568) attributes.
+type srtpKey struct {
+	profile srtp.ProtectionProfile
+	key     []byte
+	salt    []byte
+}
+
+func newSRTPKey() (*srtpKey, error) {
+	key, salt, err := srtp.GenerateMasterKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &srtpKey{
+		profile: srtp.ProtectionProfileAES128CMHMACSHA180,
+		key:     key,
+		salt:    salt,
+	}, nil
+}
+
+func newSRTPKeys(count int) ([]*srtpKey, error) {
+	keys := make([]*srtpKey, count)
+	for i := range keys {
+		var err error
+		keys[i], err = newSRTPKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+func (k *srtpKey) context() (*srtp.Context, error) {
+	return srtp.NewContext(k.profile, k.key, k.salt)
+}
+
+func (k *srtpKey) mikeyMessage() ([]byte, error) {
+	csbID := make([]byte, 4)
+	_, err := rand.Read(csbID)
+	if err != nil {
+		return nil, err
+	}
+
+	rnd := make([]byte, 16)
+	_, err = rand.Read(rnd)
+	if err != nil {
+		return nil, err
+	}
+
+	tagLen := byte(10)
+	if k.profile == srtp.ProtectionProfileAES128CMHMACSHA132 {
+		tagLen = 4
+	}
+
+	now := time.Now()
+
+	msg := mikey.Message{
+		CSBID: binary.BigEndian.Uint32(csbID),
+		// the SSRC is not known in advance; it is learned from packets.
+		SRTPIDs: []mikey.SRTPID{{}},
+		Timestamp: uint64(now.Unix()+ntpEpochOffsetSeconds)<<32 |
+			uint64(now.Nanosecond())*(1<<32)/uint64(time.Second),
+		RAND: rnd,
+		Policies: []mikey.SecurityPolicy{{
+			Params: []mikey.SecurityPolicyParam{
+				{Type: mikey.SPParamEncryptionAlgorithm, Value: []byte{mikeyEncrAlgAESCM}},
+				{Type: mikey.SPParamSessionEncrKeyLength, Value: []byte{srtp.KeyLen}},
+				{Type: mikey.SPParamAuthAlgorithm, Value: []byte{mikeyAuthAlgHMACSHA1}},
+				{Type: mikey.SPParamSessionAuthKeyLength, Value: []byte{mikeyAuthKeyLen}},
+				{Type: mikey.SPParamSessionSaltKeyLength, Value: []byte{srtp.SaltLen}},
+				{Type: mikey.SPParamSRTPEncryption, Value: []byte{mikeyOn}},
+				{Type: mikey.SPParamSRTCPEncryption, Value: []byte{mikeyOn}},
+				{Type: mikey.SPParamSRTPAuthentication, Value: []byte{mikeyOn}},
+				{Type: mikey.SPParamAuthTagLength, Value: []byte{tagLen}},
+			},
+		}},
+		KeyData: []mikey.KeyData{{
+			Type: mikey.KeyDataTypeTEKSalt,
+			Key:  k.key,
+			Salt: k.salt,
+		}},
+	}
+
+	return msg.Marshal()
+}
+
+func (k *srtpKey) keyMgmtAttribute() (psdp.Attribute, error) {
+	byts, err := k.mikeyMessage()
+	if err != nil {
+		return psdp.Attribute{}, err
+	}
+
+	return psdp.Attribute{
+		Key:   "key-mgmt",
+		Value: "mikey " + base64.StdEncoding.EncodeToString(byts),
+	}, nil
+}
+
+func srtpKeyFromMIKEY(byts []byte) (*srtpKey, error) {
+	var msg mikey.Message
+	err := msg.Unmarshal(byts)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &srtpKey{
+		profile: srtp.ProtectionProfileAES128CMHMACSHA180,
+	}
+
+	for _, sp := range msg.Policies {
+		if v, ok := sp.Param(mikey.SPParamEncryptionAlgorithm); ok &&
+			(len(v) != 1 || v[0] != mikeyEncrAlgAESCM) {
+			return nil, fmt.Errorf("unsupported encryption algorithm")
+		}
+
+		if v, ok := sp.Param(mikey.SPParamAuthAlgorithm); ok &&
+			(len(v) != 1 || v[0] != mikeyAuthAlgHMACSHA1) {
+			return nil, fmt.Errorf("unsupported authentication algorithm")
+		}
+
+		if v, ok := sp.Param(mikey.SPParamAuthTagLength); ok && len(v) == 1 && v[0] == 4 {
+			k.profile = srtp.ProtectionProfileAES128CMHMACSHA132
+		}
+	}
+
+	for _, kd := range msg.KeyData {
+		switch kd.Type {
+		case mikey.KeyDataTypeTEKSalt:
+			k.key = kd.Key
+			k.salt = kd.Salt
+
+		case mikey.KeyDataTypeTEK:
+			if len(kd.Key) != srtp.KeyLen+srtp.SaltLen {
+				return nil, fmt.Errorf("invalid key length: %d", len(kd.Key))
+			}
+			k.key = kd.Key[:srtp.KeyLen]
+			k.salt = kd.Key[srtp.KeyLen:]
+
+		default:
+			return nil, fmt.Errorf("unsupported key data type: %d", kd.Type)
+		}
+
+		if len(k.key) != srtp.KeyLen || len(k.salt) != srtp.SaltLen {
+			return nil, fmt.Errorf("invalid key or salt length")
+		}
+
+		return k, nil
+	}
+
+	return nil, fmt.Errorf("key not found")
+}
+
+func srtpKeyFromKeyMgmt(value string) (*srtpKey, error) {
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) != 2 || parts[0] != "mikey" {
+		return nil, fmt.Errorf("unsupported key management protocol (%v)", value)
+	}
+
+	byts, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIKEY message: %v", err)
+	}
+
+	return srtpKeyFromMIKEY(byts)
+}
+
+// srtpKeyFromCrypto decodes a a=crypto attribute.
+// It returns nil when the crypto suite is not supported.
+func srtpKeyFromCrypto(value string) (*srtpKey, error) {
+	parts := strings.Fields(value)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid crypto attribute (%v)", value)
+	}
+
+	var profile srtp.ProtectionProfile
+	switch parts[1] {
+	case srtp.ProtectionProfileAES128CMHMACSHA180.String():
+		profile = srtp.ProtectionProfileAES128CMHMACSHA180
+
+	case srtp.ProtectionProfileAES128CMHMACSHA132.String():
+		profile = srtp.ProtectionProfileAES128CMHMACSHA132
+
+	default:
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(parts[2], "inline:") {
+		return nil, fmt.Errorf("invalid key parameters (%v)", parts[2])
+	}
+
+	// lifetime and MKI are not supported
+	keyParams := strings.Split(strings.TrimPrefix(parts[2], "inline:"), "|")
+	if len(keyParams) > 1 && strings.Contains(keyParams[len(keyParams)-1], ":") {
+		return nil, fmt.Errorf("MKI is not supported")
+	}
+
+	byts, err := base64.StdEncoding.DecodeString(keyParams[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameters (%v)", parts[2])
+	}
+
+	if len(byts) != srtp.KeyLen+srtp.SaltLen {
+		return nil, fmt.Errorf("invalid key length: %d", len(byts))
+	}
+
+	return &srtpKey{
+		profile: profile,
+		key:     byts[:srtp.KeyLen],
+		salt:    byts[srtp.KeyLen:],
+	}, nil
+}
+
+// srtpKeyFromMediaDescription returns the key of a media,
+// that can be placed in the media or in the session description.
+// It returns nil when the media is not encrypted.
+func srtpKeyFromMediaDescription(sd *sdp.SessionDescription, md *psdp.MediaDescription) (*srtpKey, error) {
+	for _, attr := range md.Attributes {
+		switch attr.Key {
+		case "key-mgmt":
+			return srtpKeyFromKeyMgmt(attr.Value)
+
+		case "crypto":
+			k, err := srtpKeyFromCrypto(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			if k != nil {
+				return k, nil
+			}
+		}
+	}
+
+	if v, ok := sd.Attribute("key-mgmt"); ok {
+		return srtpKeyFromKeyMgmt(v)
+	}
+
+	if len(md.MediaName.Protos) == 2 && (md.MediaName.Protos[1] == "SAVP" || md.MediaName.Protos[1] == "SAVPF") {
+		return nil, fmt.Errorf("media is encrypted but keys are not provided or not supported")
+	}
+
+	return nil, nil
+}
+
+// srtpKeysFromMediaDescriptions returns the keys of some medias.
+// It returns nil when no media is encrypted.
+func srtpKeysFromMediaDescriptions(sd *sdp.SessionDescription, mds []*psdp.MediaDescription) ([]*srtpKey, error) {
+	var keys []*srtpKey
+
+	for i, md := range mds {
+		k, err := srtpKeyFromMediaDescription(sd, md)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse keys of track %d: %s", i+1, err)
+		}
+
+		if k != nil {
+			if keys == nil {
+				keys = make([]*srtpKey, len(mds))
+			}
+			keys[i] = k
+		}
+	}
+
+	return keys, nil
+}
+
+func srtpContexts(keys []*srtpKey) ([]*srtp.Context, error) {
+	if keys == nil {
+		return nil, nil
+	}
+
+	contexts := make([]*srtp.Context, len(keys))
+	for i, k := range keys {
+		if k != nil {
+			var err error
+			contexts[i], err = k.context()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return contexts, nil
+}
+
+// srtpDecryptRTP decrypts a SRTP packet, if a crypto context is provided.
+// Packets that can't be authenticated are discarded.
+func srtpDecryptRTP(ctx *srtp.Context, payload []byte) ([]byte, bool) {
+	if ctx == nil {
+		return payload, true
+	}
+
+	payload, err := ctx.DecryptRTP(payload)
+	return payload, err == nil
+}
+
+// srtpDecryptRTCP decrypts a SRTCP packet, if a crypto context is provided.
+// Packets that can't be authenticated are discarded.
+func srtpDecryptRTCP(ctx *srtp.Context, payload []byte) ([]byte, bool) {
+	if ctx == nil {
+		return payload, true
+	}
+
+	payload, err := ctx.DecryptRTCP(payload)
+	return payload, err == nil
+}
@@ -0,0 +1,104 @@
+package gortsplib
+
+import (
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/sdp"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
+)
+
+func TestSRTPKeyFromCrypto(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		value string
+		key   *srtpKey
+	}{
+		{
+			"80 bit tag",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwd",
+			&srtpKey{
+				profile: srtp.ProtectionProfileAES128CMHMACSHA180,
+				key:     []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+				salt:    []byte{16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29},
+			},
+		},
+		{
+			"32 bit tag with lifetime",
+			"1 AES_CM_128_HMAC_SHA1_32 inline:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwd|2^20",
+			&srtpKey{
+				profile: srtp.ProtectionProfileAES128CMHMACSHA132,
+				key:     []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+				salt:    []byte{16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29},
+			},
+		},
+		{
+			"unsupported suite",
+			"1 F8_128_HMAC_SHA1_80 inline:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwd",
+			nil,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			key, err := srtpKeyFromCrypto(ca.value)
+			require.NoError(t, err)
+			require.Equal(t, ca.key, key)
+		})
+	}
+}
+
+func TestSRTPKeyFromCryptoErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{
+			"invalid attribute",
+			"1 AES_CM_128_HMAC_SHA1_80",
+			"invalid crypto attribute (1 AES_CM_128_HMAC_SHA1_80)",
+		},
+		{
+			"mki",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwd|2^20|1:4",
+			"MKI is not supported",
+		},
+		{
+			"invalid key length",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:AAECAwQFBgcICQoLDA0ODxAREhMUFRYX",
+			"invalid key length: 24",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			_, err := srtpKeyFromCrypto(ca.value)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestSRTPKeyMIKEY(t *testing.T) {
+	key, err := newSRTPKey()
+	require.NoError(t, err)
+
+	attr, err := key.keyMgmtAttribute()
+	require.NoError(t, err)
+	require.Equal(t, "key-mgmt", attr.Key)
+
+	dec, err := srtpKeyFromKeyMgmt(attr.Value)
+	require.NoError(t, err)
+	require.Equal(t, key, dec)
+}
+
+func TestSRTPKeyFromMediaDescriptionErrors(t *testing.T) {
+	md := &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "SAVP"},
+			Formats: []string{"96"},
+		},
+	}
+
+	_, err := srtpKeyFromMediaDescription(&sdp.SessionDescription{}, md)
+	require.EqualError(t, err, "media is encrypted but keys are not provided or not supported")
+}
@@ -75,11 +75,17 @@ func newTrackFromMediaDescription(md *psdp.MediaDescription) (Track, error) {
 			case rtpmapPart1 == "H265/90000":
 				return newTrackH265FromMediaDescription(control, payloadType, md)
 
+			case strings.ToUpper(rtpmapPart1) == "MP4V-ES/90000":
+				return newTrackMPEG4VideoFromMediaDescription(control, payloadType, md)
+
 			case rtpmapPart1 == "VP8/90000":
 				return newTrackVP8FromMediaDescription(control, payloadType, md)
 
 			case rtpmapPart1 == "VP9/90000":
 				return newTrackVP9FromMediaDescription(control, payloadType, md)
+
+			case rtpmapPart1 == "AV1/90000":
+				return newTrackAV1FromMediaDescription(control, payloadType, md)
 			}
 
 		case md.MediaName.Media == "audio":
@@ -90,14 +96,31 @@ func newTrackFromMediaDescription(md *psdp.MediaDescription) (Track, error) {
 			case md.MediaName.Formats[0] == "8":
 				return newTrackPCMAFromMediaDescription(control, rtpmapPart1)
 
+			case md.MediaName.Formats[0] == "9":
+				return newTrackG722FromMediaDescription(control, rtpmapPart1)
+
+			case md.MediaName.Formats[0] == "10":
+				return newTrackLPCMFromMediaDescription(control, 10, rtpmapPart1)
+
+			case md.MediaName.Formats[0] == "11":
+				return newTrackLPCMFromMediaDescription(control, 11, rtpmapPart1)
+
 			case md.MediaName.Formats[0] == "14":
 				return newTrackMPEG2AudioFromMediaDescription(control)
 
 			case strings.HasPrefix(strings.ToLower(rtpmapPart1), "mpeg4-generic/"):
 				return newTrackMPEG4AudioFromMediaDescription(control, payloadType, md)
 
+			case strings.HasPrefix(strings.ToLower(rtpmapPart1), "mp4a-latm/"):
+				return newTrackMPEG4AudioLATMFromMediaDescription(control, payloadType, rtpmapPart1, md)
+
 			case strings.HasPrefix(rtpmapPart1, "opus/"):
 				return newTrackOpusFromMediaDescription(control, payloadType, rtpmapPart1, md)
+
+			case strings.HasPrefix(strings.ToLower(rtpmapPart1), "l8/"),
+				strings.HasPrefix(strings.ToLower(rtpmapPart1), "l16/"),
+				strings.HasPrefix(strings.ToLower(rtpmapPart1), "l24/"):
+				return newTrackLPCMFromMediaDescription(control, payloadType, rtpmapPart1)
 			}
 		}
 	}
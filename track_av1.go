@@ -0,0 +1,147 @@
+package gortsplib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+// TrackAV1 is a AV1 track.
+// Specification: https://aomediacodec.github.io/av1-rtp-spec/#72-sdp-parameters
+type TrackAV1 struct {
+	trackBase
+	PayloadType uint8
+	LevelIdx    *int
+	Profile     *int
+	Tier        *int
+}
+
+func newTrackAV1FromMediaDescription(
+	control string,
+	payloadType uint8,
+	md *psdp.MediaDescription,
+) (*TrackAV1, error) {
+	t := &TrackAV1{
+		PayloadType: payloadType,
+		trackBase: trackBase{
+			control: control,
+		},
+	}
+
+	t.fillParamsFromMediaDescription(md)
+
+	return t, nil
+}
+
+func (t *TrackAV1) fillParamsFromMediaDescription(md *psdp.MediaDescription) error {
+	v, ok := md.Attribute("fmtp")
+	if !ok {
+		return fmt.Errorf("fmtp attribute is missing")
+	}
+
+	tmp := strings.SplitN(v, " ", 2)
+	if len(tmp) != 2 {
+		return fmt.Errorf("invalid fmtp attribute (%v)", v)
+	}
+
+	for _, kv := range strings.Split(tmp[1], ";") {
+		kv = strings.Trim(kv, " ")
+
+		if len(kv) == 0 {
+			continue
+		}
+
+		tmp := strings.SplitN(kv, "=", 2)
+		if len(tmp) != 2 {
+			return fmt.Errorf("invalid fmtp attribute (%v)", v)
+		}
+
+		switch tmp[0] {
+		case "level-idx":
+			val, err := strconv.ParseUint(tmp[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid level-idx (%v)", tmp[1])
+			}
+			v2 := int(val)
+			t.LevelIdx = &v2
+
+		case "profile":
+			val, err := strconv.ParseUint(tmp[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid profile (%v)", tmp[1])
+			}
+			v2 := int(val)
+			t.Profile = &v2
+
+		case "tier":
+			val, err := strconv.ParseUint(tmp[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid tier (%v)", tmp[1])
+			}
+			v2 := int(val)
+			t.Tier = &v2
+		}
+	}
+
+	return nil
+}
+
+// ClockRate returns the track clock rate.
+func (t *TrackAV1) ClockRate() int {
+	return 90000
+}
+
+func (t *TrackAV1) clone() Track {
+	return &TrackAV1{
+		trackBase:   t.trackBase,
+		PayloadType: t.PayloadType,
+		LevelIdx:    t.LevelIdx,
+		Profile:     t.Profile,
+		Tier:        t.Tier,
+	}
+}
+
+// MediaDescription returns the track media description in SDP format.
+func (t *TrackAV1) MediaDescription() *psdp.MediaDescription {
+	typ := strconv.FormatInt(int64(t.PayloadType), 10)
+
+	fmtp := typ
+
+	var tmp []string
+	if t.LevelIdx != nil {
+		tmp = append(tmp, "level-idx="+strconv.FormatInt(int64(*t.LevelIdx), 10))
+	}
+	if t.Profile != nil {
+		tmp = append(tmp, "profile="+strconv.FormatInt(int64(*t.Profile), 10))
+	}
+	if t.Tier != nil {
+		tmp = append(tmp, "tier="+strconv.FormatInt(int64(*t.Tier), 10))
+	}
+	if tmp != nil {
+		fmtp += " " + strings.Join(tmp, ";")
+	}
+
+	return &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{typ},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: typ + " AV1/90000",
+			},
+			{
+				Key:   "fmtp",
+				Value: fmtp,
+			},
+			{
+				Key:   "control",
+				Value: t.control,
+			},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package gortsplib
+
+import (
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackAV1Attributes(t *testing.T) {
+	track := &TrackAV1{}
+	require.Equal(t, 90000, track.ClockRate())
+	require.Equal(t, "", track.GetControl())
+}
+
+func TestTrackAV1Clone(t *testing.T) {
+	levelIdx := 8
+	profile := 1
+	tier := 0
+	track := &TrackAV1{
+		PayloadType: 96,
+		LevelIdx:    &levelIdx,
+		Profile:     &profile,
+		Tier:        &tier,
+	}
+
+	clone := track.clone()
+	require.NotSame(t, track, clone)
+	require.Equal(t, track, clone)
+}
+
+func TestTrackAV1MediaDescription(t *testing.T) {
+	levelIdx := 8
+	profile := 1
+	tier := 0
+	track := &TrackAV1{
+		PayloadType: 96,
+		LevelIdx:    &levelIdx,
+		Profile:     &profile,
+		Tier:        &tier,
+	}
+
+	require.Equal(t, &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{"96"},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: "96 AV1/90000",
+			},
+			{
+				Key:   "fmtp",
+				Value: "96 level-idx=8;profile=1;tier=0",
+			},
+			{
+				Key:   "control",
+				Value: "",
+			},
+		},
+	}, track.MediaDescription())
+}
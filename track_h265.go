@@ -16,6 +16,7 @@ type TrackH265 struct {
 	VPS         []byte
 	SPS         []byte
 	PPS         []byte
+	MaxDONDiff  int
 
 	trackBase
 	mutex sync.RWMutex
@@ -82,6 +83,13 @@ func (t *TrackH265) fillParamsFromMediaDescription(md *psdp.MediaDescription) er
 			if err != nil {
 				return fmt.Errorf("invalid sprop-pps (%v)", v)
 			}
+
+		case "sprop-max-don-diff":
+			tmp, err := strconv.ParseUint(tmp[1], 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-max-don-diff (%v)", v)
+			}
+			t.MaxDONDiff = int(tmp)
 		}
 	}
 
@@ -99,6 +107,7 @@ func (t *TrackH265) clone() Track {
 		VPS:         t.VPS,
 		SPS:         t.SPS,
 		PPS:         t.PPS,
+		MaxDONDiff:  t.MaxDONDiff,
 		trackBase:   t.trackBase,
 	}
 }
@@ -164,6 +173,9 @@ func (t *TrackH265) MediaDescription() *psdp.MediaDescription {
 	if t.PPS != nil {
 		tmp = append(tmp, "sprop-pps="+base64.StdEncoding.EncodeToString(t.PPS))
 	}
+	if t.MaxDONDiff != 0 {
+		tmp = append(tmp, "sprop-max-don-diff="+strconv.FormatInt(int64(t.MaxDONDiff), 10))
+	}
 	if tmp != nil {
 		fmtp += " " + strings.Join(tmp, "; ")
 	}
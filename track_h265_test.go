@@ -71,3 +71,33 @@ func TestTrackH265MediaDescription(t *testing.T) {
 		},
 	}, track.MediaDescription())
 }
+
+func TestTrackH265MediaDescriptionMaxDONDiff(t *testing.T) {
+	track := &TrackH265{
+		PayloadType: 96,
+		PPS:         []byte{0x05, 0x06},
+		MaxDONDiff:  2,
+	}
+
+	require.Equal(t, &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{"96"},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: "96 H265/90000",
+			},
+			{
+				Key:   "fmtp",
+				Value: "96 sprop-pps=BQY=; sprop-max-don-diff=2",
+			},
+			{
+				Key:   "control",
+				Value: "",
+			},
+		},
+	}, track.MediaDescription())
+}
@@ -0,0 +1,132 @@
+package gortsplib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+// TrackLPCM is an uncompressed, Linear PCM track.
+type TrackLPCM struct {
+	PayloadType  uint8
+	BitDepth     int
+	SampleRate   int
+	ChannelCount int
+
+	trackBase
+}
+
+func newTrackLPCMFromMediaDescription(
+	control string,
+	payloadType uint8,
+	rtpmapPart1 string,
+) (*TrackLPCM, error) {
+	// static payload types
+	if rtpmapPart1 == "" {
+		t := &TrackLPCM{
+			PayloadType: payloadType,
+			BitDepth:    16,
+			SampleRate:  44100,
+			trackBase: trackBase{
+				control: control,
+			},
+		}
+
+		if payloadType == 10 {
+			t.ChannelCount = 2
+		} else {
+			t.ChannelCount = 1
+		}
+
+		return t, nil
+	}
+
+	tmp := strings.SplitN(rtpmapPart1, "/", 3)
+	if len(tmp) < 2 {
+		return nil, fmt.Errorf("invalid rtpmap (%v)", rtpmapPart1)
+	}
+
+	var bitDepth int
+	switch strings.ToLower(tmp[0]) {
+	case "l8":
+		bitDepth = 8
+
+	case "l16":
+		bitDepth = 16
+
+	case "l24":
+		bitDepth = 24
+
+	default:
+		return nil, fmt.Errorf("invalid LPCM bit depth (%v)", tmp[0])
+	}
+
+	sampleRate, err := strconv.ParseInt(tmp[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	channelCount := int64(1)
+	if len(tmp) == 3 {
+		channelCount, err = strconv.ParseInt(tmp[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &TrackLPCM{
+		PayloadType:  payloadType,
+		BitDepth:     bitDepth,
+		SampleRate:   int(sampleRate),
+		ChannelCount: int(channelCount),
+		trackBase: trackBase{
+			control: control,
+		},
+	}, nil
+}
+
+// ClockRate returns the track clock rate.
+func (t *TrackLPCM) ClockRate() int {
+	return t.SampleRate
+}
+
+func (t *TrackLPCM) clone() Track {
+	return &TrackLPCM{
+		PayloadType:  t.PayloadType,
+		BitDepth:     t.BitDepth,
+		SampleRate:   t.SampleRate,
+		ChannelCount: t.ChannelCount,
+		trackBase:    t.trackBase,
+	}
+}
+
+// MediaDescription returns the track media description in SDP format.
+func (t *TrackLPCM) MediaDescription() *psdp.MediaDescription {
+	typ := strconv.FormatInt(int64(t.PayloadType), 10)
+
+	rtpmap := typ + " L" + strconv.FormatInt(int64(t.BitDepth), 10) +
+		"/" + strconv.FormatInt(int64(t.SampleRate), 10)
+	if t.ChannelCount != 1 {
+		rtpmap += "/" + strconv.FormatInt(int64(t.ChannelCount), 10)
+	}
+
+	return &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "audio",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{typ},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: rtpmap,
+			},
+			{
+				Key:   "control",
+				Value: t.control,
+			},
+		},
+	}
+}
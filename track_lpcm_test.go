@@ -0,0 +1,80 @@
+package gortsplib
+
+import (
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackLPCMAttributes(t *testing.T) {
+	track := &TrackLPCM{
+		PayloadType:  96,
+		BitDepth:     24,
+		SampleRate:   44100,
+		ChannelCount: 2,
+	}
+	require.Equal(t, 44100, track.ClockRate())
+	require.Equal(t, "", track.GetControl())
+}
+
+func TestTrackLPCMClone(t *testing.T) {
+	track := &TrackLPCM{
+		PayloadType:  96,
+		BitDepth:     24,
+		SampleRate:   44100,
+		ChannelCount: 2,
+	}
+
+	clone := track.clone()
+	require.NotSame(t, track, clone)
+	require.Equal(t, track, clone)
+}
+
+func TestTrackLPCMMediaDescription(t *testing.T) {
+	for _, ca := range []struct {
+		name   string
+		track  *TrackLPCM
+		rtpmap string
+	}{
+		{
+			"stereo",
+			&TrackLPCM{
+				PayloadType:  96,
+				BitDepth:     24,
+				SampleRate:   96000,
+				ChannelCount: 2,
+			},
+			"96 L24/96000/2",
+		},
+		{
+			"mono",
+			&TrackLPCM{
+				PayloadType:  97,
+				BitDepth:     16,
+				SampleRate:   8000,
+				ChannelCount: 1,
+			},
+			"97 L16/8000",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			md := ca.track.MediaDescription()
+			require.Equal(t, []psdp.Attribute{
+				{
+					Key:   "rtpmap",
+					Value: ca.rtpmap,
+				},
+				{
+					Key:   "control",
+					Value: "",
+				},
+			}, md.Attributes)
+
+			// test the parsing of the generated media description
+			track, err := newTrackFromMediaDescription(md)
+			require.NoError(t, err)
+			require.Equal(t, ca.track, track)
+		})
+	}
+}
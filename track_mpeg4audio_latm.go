@@ -0,0 +1,232 @@
+package gortsplib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4audio"
+)
+
+// TrackMPEG4AudioLATM is a MPEG-4 audio track that uses the LATM format.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416
+type TrackMPEG4AudioLATM struct {
+	PayloadType    uint8
+	SampleRate     int
+	ChannelCount   int
+	ProfileLevelID int
+	Bitrate        int
+	CPresent       bool
+	Object         int
+	SBREnabled     bool
+	Config         *mpeg4audio.StreamMuxConfig
+
+	trackBase
+}
+
+func newTrackMPEG4AudioLATMFromMediaDescription(
+	control string,
+	payloadType uint8,
+	rtpmapPart1 string,
+	md *psdp.MediaDescription,
+) (*TrackMPEG4AudioLATM, error) {
+	tmp := strings.SplitN(rtpmapPart1, "/", 3)
+	if len(tmp) < 2 {
+		return nil, fmt.Errorf("invalid rtpmap (%v)", rtpmapPart1)
+	}
+
+	sampleRate, err := strconv.ParseInt(tmp[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	channelCount := int64(1)
+	if len(tmp) == 3 {
+		channelCount, err = strconv.ParseInt(tmp[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := &TrackMPEG4AudioLATM{
+		PayloadType:    payloadType,
+		SampleRate:     int(sampleRate),
+		ChannelCount:   int(channelCount),
+		ProfileLevelID: 30, // default value defined by specification
+		CPresent:       true,
+		trackBase: trackBase{
+			control: control,
+		},
+	}
+
+	v, ok := md.Attribute("fmtp")
+	if ok {
+		tmp := strings.SplitN(v, " ", 2)
+		if len(tmp) != 2 {
+			return nil, fmt.Errorf("invalid fmtp (%v)", v)
+		}
+
+		for _, kv := range strings.Split(tmp[1], ";") {
+			kv = strings.Trim(kv, " ")
+
+			if len(kv) == 0 {
+				continue
+			}
+
+			tmp := strings.SplitN(kv, "=", 2)
+			if len(tmp) != 2 {
+				return nil, fmt.Errorf("invalid fmtp (%v)", v)
+			}
+
+			switch strings.ToLower(tmp[0]) {
+			case "profile-level-id":
+				val, err := strconv.ParseUint(tmp[1], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid profile-level-id (%v)", tmp[1])
+				}
+				t.ProfileLevelID = int(val)
+
+			case "bitrate":
+				val, err := strconv.ParseUint(tmp[1], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid bitrate (%v)", tmp[1])
+				}
+				t.Bitrate = int(val)
+
+			case "cpresent":
+				switch tmp[1] {
+				case "0":
+					t.CPresent = false
+
+				case "1":
+					t.CPresent = true
+
+				default:
+					return nil, fmt.Errorf("invalid cpresent (%v)", tmp[1])
+				}
+
+			case "object":
+				val, err := strconv.ParseUint(tmp[1], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid object (%v)", tmp[1])
+				}
+				t.Object = int(val)
+
+			case "sbr-enabled":
+				switch tmp[1] {
+				case "0":
+					t.SBREnabled = false
+
+				case "1":
+					t.SBREnabled = true
+
+				default:
+					return nil, fmt.Errorf("invalid SBR-enabled (%v)", tmp[1])
+				}
+
+			case "config":
+				enc, err := hex.DecodeString(tmp[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid LATM config (%v)", tmp[1])
+				}
+
+				t.Config = &mpeg4audio.StreamMuxConfig{}
+				err = t.Config.Unmarshal(enc)
+				if err != nil {
+					return nil, fmt.Errorf("invalid LATM config (%v)", tmp[1])
+				}
+			}
+		}
+	}
+
+	if !t.CPresent && t.Config == nil {
+		return nil, fmt.Errorf("config is missing")
+	}
+
+	return t, nil
+}
+
+// ClockRate returns the track clock rate.
+func (t *TrackMPEG4AudioLATM) ClockRate() int {
+	return t.SampleRate
+}
+
+func (t *TrackMPEG4AudioLATM) clone() Track {
+	return &TrackMPEG4AudioLATM{
+		PayloadType:    t.PayloadType,
+		SampleRate:     t.SampleRate,
+		ChannelCount:   t.ChannelCount,
+		ProfileLevelID: t.ProfileLevelID,
+		Bitrate:        t.Bitrate,
+		CPresent:       t.CPresent,
+		Object:         t.Object,
+		SBREnabled:     t.SBREnabled,
+		Config:         t.Config,
+		trackBase:      t.trackBase,
+	}
+}
+
+// MediaDescription returns the track media description in SDP format.
+func (t *TrackMPEG4AudioLATM) MediaDescription() *psdp.MediaDescription {
+	typ := strconv.FormatInt(int64(t.PayloadType), 10)
+
+	rtpmap := typ + " MP4A-LATM/" + strconv.FormatInt(int64(t.SampleRate), 10)
+	if t.ChannelCount != 1 {
+		rtpmap += "/" + strconv.FormatInt(int64(t.ChannelCount), 10)
+	}
+
+	fmtp := []string{
+		"profile-level-id=" + strconv.FormatInt(int64(t.ProfileLevelID), 10),
+	}
+
+	if t.Bitrate != 0 {
+		fmtp = append(fmtp, "bitrate="+strconv.FormatInt(int64(t.Bitrate), 10))
+	}
+
+	if t.CPresent {
+		fmtp = append(fmtp, "cpresent=1")
+	} else {
+		fmtp = append(fmtp, "cpresent=0")
+	}
+
+	if t.Object != 0 {
+		fmtp = append(fmtp, "object="+strconv.FormatInt(int64(t.Object), 10))
+	}
+
+	if t.SBREnabled {
+		fmtp = append(fmtp, "SBR-enabled=1")
+	}
+
+	if t.Config != nil {
+		enc, err := t.Config.Marshal()
+		if err != nil {
+			return nil
+		}
+		fmtp = append(fmtp, "config="+hex.EncodeToString(enc))
+	}
+
+	return &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "audio",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{typ},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: rtpmap,
+			},
+			{
+				Key:   "fmtp",
+				Value: typ + " " + strings.Join(fmtp, "; "),
+			},
+			{
+				Key:   "control",
+				Value: t.control,
+			},
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package gortsplib
+
+import (
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/mpeg4audio"
+)
+
+func TestTrackMPEG4AudioLATMAttributes(t *testing.T) {
+	track := &TrackMPEG4AudioLATM{
+		PayloadType:    96,
+		SampleRate:     48000,
+		ChannelCount:   2,
+		ProfileLevelID: 30,
+		CPresent:       true,
+	}
+	require.Equal(t, 48000, track.ClockRate())
+	require.Equal(t, "", track.GetControl())
+}
+
+func TestTrackMPEG4AudioLATMClone(t *testing.T) {
+	track := &TrackMPEG4AudioLATM{
+		PayloadType:    96,
+		SampleRate:     48000,
+		ChannelCount:   2,
+		ProfileLevelID: 30,
+		Bitrate:        64000,
+		Object:         2,
+		SBREnabled:     true,
+		Config: &mpeg4audio.StreamMuxConfig{
+			Programs: []*mpeg4audio.StreamMuxConfigProgram{{
+				Layers: []*mpeg4audio.StreamMuxConfigLayer{{
+					AudioSpecificConfig: &mpeg4audio.Config{
+						Type:         2,
+						SampleRate:   48000,
+						ChannelCount: 2,
+					},
+					LatmBufferFullness: 255,
+				}},
+			}},
+		},
+	}
+
+	clone := track.clone()
+	require.NotSame(t, track, clone)
+	require.Equal(t, track, clone)
+}
+
+func TestTrackMPEG4AudioLATMMediaDescription(t *testing.T) {
+	track := &TrackMPEG4AudioLATM{
+		PayloadType:    96,
+		SampleRate:     24000,
+		ChannelCount:   1,
+		ProfileLevelID: 24,
+		Bitrate:        64000,
+		Object:         23,
+		Config: &mpeg4audio.StreamMuxConfig{
+			Programs: []*mpeg4audio.StreamMuxConfigProgram{{
+				Layers: []*mpeg4audio.StreamMuxConfigLayer{{
+					AudioSpecificConfig: &mpeg4audio.Config{
+						Type:         2,
+						SampleRate:   24000,
+						ChannelCount: 1,
+					},
+					LatmBufferFullness: 255,
+				}},
+			}},
+		},
+	}
+
+	md := track.MediaDescription()
+	require.Equal(t, &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "audio",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{"96"},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: "96 MP4A-LATM/24000",
+			},
+			{
+				Key:   "fmtp",
+				Value: "96 profile-level-id=24; bitrate=64000; cpresent=0; object=23; config=400026103fc0",
+			},
+			{
+				Key:   "control",
+				Value: "",
+			},
+		},
+	}, md)
+
+	// test the parsing of the generated media description
+	track2, err := newTrackFromMediaDescription(md)
+	require.NoError(t, err)
+	require.Equal(t, track, track2)
+}
@@ -0,0 +1,121 @@
+package gortsplib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+// TrackMPEG4Video is a MPEG-4 part 2 video track.
+// Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-7.1
+type TrackMPEG4Video struct {
+	PayloadType    uint8
+	ProfileLevelID int
+	Config         []byte
+
+	trackBase
+}
+
+func newTrackMPEG4VideoFromMediaDescription(
+	control string,
+	payloadType uint8,
+	md *psdp.MediaDescription,
+) (*TrackMPEG4Video, error) {
+	t := &TrackMPEG4Video{
+		PayloadType:    payloadType,
+		ProfileLevelID: 1, // default value defined by specification
+		trackBase: trackBase{
+			control: control,
+		},
+	}
+
+	v, ok := md.Attribute("fmtp")
+	if !ok {
+		return t, nil
+	}
+
+	tmp := strings.SplitN(v, " ", 2)
+	if len(tmp) != 2 {
+		return nil, fmt.Errorf("invalid fmtp (%v)", v)
+	}
+
+	for _, kv := range strings.Split(tmp[1], ";") {
+		kv = strings.Trim(kv, " ")
+
+		if len(kv) == 0 {
+			continue
+		}
+
+		tmp := strings.SplitN(kv, "=", 2)
+		if len(tmp) != 2 {
+			return nil, fmt.Errorf("invalid fmtp (%v)", v)
+		}
+
+		switch strings.ToLower(tmp[0]) {
+		case "profile-level-id":
+			val, err := strconv.ParseUint(tmp[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid profile-level-id (%v)", tmp[1])
+			}
+			t.ProfileLevelID = int(val)
+
+		case "config":
+			var err error
+			t.Config, err = hex.DecodeString(tmp[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid config (%v)", tmp[1])
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// ClockRate returns the track clock rate.
+func (t *TrackMPEG4Video) ClockRate() int {
+	return 90000
+}
+
+func (t *TrackMPEG4Video) clone() Track {
+	return &TrackMPEG4Video{
+		PayloadType:    t.PayloadType,
+		ProfileLevelID: t.ProfileLevelID,
+		Config:         t.Config,
+		trackBase:      t.trackBase,
+	}
+}
+
+// MediaDescription returns the track media description in SDP format.
+func (t *TrackMPEG4Video) MediaDescription() *psdp.MediaDescription {
+	typ := strconv.FormatInt(int64(t.PayloadType), 10)
+
+	fmtp := typ + " profile-level-id=" + strconv.FormatInt(int64(t.ProfileLevelID), 10)
+	if t.Config != nil {
+		fmtp += "; config=" + strings.ToUpper(hex.EncodeToString(t.Config))
+	}
+
+	return &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{typ},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: typ + " MP4V-ES/90000",
+			},
+			{
+				Key:   "fmtp",
+				Value: fmtp,
+			},
+			{
+				Key:   "control",
+				Value: t.control,
+			},
+		},
+	}
+}
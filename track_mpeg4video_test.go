@@ -0,0 +1,60 @@
+package gortsplib
+
+import (
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackMPEG4VideoAttributes(t *testing.T) {
+	track := &TrackMPEG4Video{
+		PayloadType:    96,
+		ProfileLevelID: 1,
+		Config:         []byte{0x00, 0x00, 0x01, 0xb0, 0x01},
+	}
+	require.Equal(t, 90000, track.ClockRate())
+	require.Equal(t, "", track.GetControl())
+}
+
+func TestTrackMPEG4VideoClone(t *testing.T) {
+	track := &TrackMPEG4Video{
+		PayloadType:    96,
+		ProfileLevelID: 1,
+		Config:         []byte{0x00, 0x00, 0x01, 0xb0, 0x01},
+	}
+
+	clone := track.clone()
+	require.NotSame(t, track, clone)
+	require.Equal(t, track, clone)
+}
+
+func TestTrackMPEG4VideoMediaDescription(t *testing.T) {
+	track := &TrackMPEG4Video{
+		PayloadType:    96,
+		ProfileLevelID: 1,
+		Config:         []byte{0x00, 0x00, 0x01, 0xb0, 0x01},
+	}
+
+	require.Equal(t, &psdp.MediaDescription{
+		MediaName: psdp.MediaName{
+			Media:   "video",
+			Protos:  []string{"RTP", "AVP"},
+			Formats: []string{"96"},
+		},
+		Attributes: []psdp.Attribute{
+			{
+				Key:   "rtpmap",
+				Value: "96 MP4V-ES/90000",
+			},
+			{
+				Key:   "fmtp",
+				Value: "96 profile-level-id=1; config=000001B001",
+			},
+			{
+				Key:   "control",
+				Value: "",
+			},
+		},
+	}, track.MediaDescription())
+}
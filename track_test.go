@@ -38,6 +38,109 @@ func TestTrackNewFromMediaDescription(t *testing.T) {
 			},
 			&TrackPCMU{},
 		},
+		{
+			"mpeg4 video",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4V-ES/90000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 profile-level-id=1; config=000001B001000001B58913000001000000012000C48D8800F50A041E1463000001B24C61766335382E3133342E313030",
+					},
+				},
+			},
+			&TrackMPEG4Video{
+				PayloadType:    96,
+				ProfileLevelID: 1,
+				Config: []byte{
+					0x00, 0x00, 0x01, 0xb0, 0x01, 0x00, 0x00, 0x01,
+					0xb5, 0x89, 0x13, 0x00, 0x00, 0x01, 0x00, 0x00,
+					0x00, 0x01, 0x20, 0x00, 0xc4, 0x8d, 0x88, 0x00,
+					0xf5, 0x0a, 0x04, 0x1e, 0x14, 0x63, 0x00, 0x00,
+					0x01, 0xb2, 0x4c, 0x61, 0x76, 0x63, 0x35, 0x38,
+					0x2e, 0x31, 0x33, 0x34, 0x2e, 0x31, 0x30, 0x30,
+				},
+			},
+		},
+		{
+			"g722",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"9"},
+				},
+			},
+			&TrackG722{},
+		},
+		{
+			"lpcm 16 static",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"10"},
+				},
+			},
+			&TrackLPCM{
+				PayloadType:  10,
+				BitDepth:     16,
+				SampleRate:   44100,
+				ChannelCount: 2,
+			},
+		},
+		{
+			"lpcm 16 dynamic",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"97"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "97 L16/16000",
+					},
+				},
+			},
+			&TrackLPCM{
+				PayloadType:  97,
+				BitDepth:     16,
+				SampleRate:   16000,
+				ChannelCount: 1,
+			},
+		},
+		{
+			"lpcm 24",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"98"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "98 L24/48000/2",
+					},
+				},
+			},
+			&TrackLPCM{
+				PayloadType:  98,
+				BitDepth:     24,
+				SampleRate:   48000,
+				ChannelCount: 2,
+			},
+		},
 		{
 			"mpeg audio",
 			&psdp.MediaDescription{
@@ -84,6 +187,69 @@ func TestTrackNewFromMediaDescription(t *testing.T) {
 				IndexDeltaLength: 3,
 			},
 		},
+		{
+			"aac latm",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4A-LATM/24000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 profile-level-id=24; object=23; bitrate=64000; cpresent=0; config=400026103fc0",
+					},
+				},
+			},
+			&TrackMPEG4AudioLATM{
+				PayloadType:    96,
+				SampleRate:     24000,
+				ChannelCount:   1,
+				ProfileLevelID: 24,
+				Bitrate:        64000,
+				Object:         23,
+				Config: &mpeg4audio.StreamMuxConfig{
+					Programs: []*mpeg4audio.StreamMuxConfigProgram{{
+						Layers: []*mpeg4audio.StreamMuxConfigLayer{{
+							AudioSpecificConfig: &mpeg4audio.Config{
+								Type:         2,
+								SampleRate:   24000,
+								ChannelCount: 1,
+							},
+							LatmBufferFullness: 255,
+						}},
+					}},
+				},
+			},
+		},
+		{
+			"aac latm in-band config",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4A-LATM/90000/2",
+					},
+				},
+			},
+			&TrackMPEG4AudioLATM{
+				PayloadType:    96,
+				SampleRate:     90000,
+				ChannelCount:   2,
+				ProfileLevelID: 30,
+				CPresent:       true,
+			},
+		},
 		{
 			"aac uppercase",
 			&psdp.MediaDescription{
@@ -385,6 +551,33 @@ func TestTrackNewFromMediaDescription(t *testing.T) {
 				},
 			},
 		},
+		{
+			"h265 with max-don-diff",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 H265/90000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 sprop-pps=RAHBcrRiQA==; sprop-max-don-diff=2",
+					},
+				},
+			},
+			&TrackH265{
+				PayloadType: 96,
+				PPS: []byte{
+					0x44, 0x1, 0xc1, 0x72, 0xb4, 0x62, 0x40,
+				},
+				MaxDONDiff: 2,
+			},
+		},
 		{
 			"vp8",
 			&psdp.MediaDescription{
@@ -451,6 +644,41 @@ func TestTrackNewFromMediaDescription(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			"av1",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 AV1/90000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 level-idx=8;profile=1;tier=0",
+					},
+				},
+			},
+			&TrackAV1{
+				PayloadType: 96,
+				LevelIdx: func() *int {
+					v := 8
+					return &v
+				}(),
+				Profile: func() *int {
+					v := 1
+					return &v
+				}(),
+				Tier: func() *int {
+					v := 0
+					return &v
+				}(),
+			},
+		},
 		{
 			"multiple formats",
 			&psdp.MediaDescription{
@@ -710,6 +938,162 @@ func TestTrackNewFromMediaDescriptionErrors(t *testing.T) {
 			},
 			"sizelength is missing (96 profile-level-id=1; config=1190)",
 		},
+		{
+			"g722 multiple channels",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"9"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "9 G722/8000/2",
+					},
+				},
+			},
+			"G722 tracks must have only one channel",
+		},
+		{
+			"lpcm invalid clock rate",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"97"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "97 L16/aa",
+					},
+				},
+			},
+			"strconv.ParseInt: parsing \"aa\": invalid syntax",
+		},
+		{
+			"lpcm invalid channel count",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"97"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "97 L16/16000/aa",
+					},
+				},
+			},
+			"strconv.ParseInt: parsing \"aa\": invalid syntax",
+		},
+		{
+			"mpeg4 video invalid config",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4V-ES/90000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 profile-level-id=1; config=zz",
+					},
+				},
+			},
+			"invalid config (zz)",
+		},
+		{
+			"mpeg4 video invalid profile-level-id",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4V-ES/90000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 profile-level-id=aa",
+					},
+				},
+			},
+			"invalid profile-level-id (aa)",
+		},
+		{
+			"aac latm missing config",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4A-LATM/24000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 cpresent=0",
+					},
+				},
+			},
+			"config is missing",
+		},
+		{
+			"aac latm invalid config",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4A-LATM/24000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 cpresent=0; config=zz",
+					},
+				},
+			},
+			"invalid LATM config (zz)",
+		},
+		{
+			"aac latm invalid cpresent",
+			&psdp.MediaDescription{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 MP4A-LATM/24000",
+					},
+					{
+						Key:   "fmtp",
+						Value: "96 cpresent=2",
+					},
+				},
+			},
+			"invalid cpresent (2)",
+		},
 		{
 			"opus invalid 1",
 			&psdp.MediaDescription{
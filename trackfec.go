@@ -0,0 +1,138 @@
+package gortsplib
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+	psdp "github.com/pion/sdp/v3"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpfec"
+)
+
+// FECConf is the forward error correction configuration of a track.
+type FECConf struct {
+	// FEC scheme.
+	// It defaults to rtpfec.SchemeULPFEC.
+	Scheme rtpfec.Scheme
+
+	// number of media packets that are protected by each repair packet.
+	// Lower values allow to recover more packets, at the cost of a higher bandwidth.
+	// It defaults to 10.
+	GroupSize int
+}
+
+// trackFEC contains the FEC capabilities of a track.
+// It is exchanged through SDP, with an additional ulpfec (RFC 5109) or flexfec (RFC 8627) format.
+type trackFEC struct {
+	scheme      rtpfec.Scheme
+	payloadType uint8
+}
+
+// fecPayloadTypes returns the payload types of the FEC formats of a media,
+// associated with their scheme.
+func fecPayloadTypes(md *psdp.MediaDescription) map[string]rtpfec.Scheme {
+	ret := make(map[string]rtpfec.Scheme)
+
+	for _, attr := range md.Attributes {
+		if attr.Key != "rtpmap" {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(attr.Value), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		encoding := strings.ToLower(strings.SplitN(parts[1], "/", 2)[0])
+
+		for _, scheme := range []rtpfec.Scheme{rtpfec.SchemeULPFEC, rtpfec.SchemeFlexFEC} {
+			if encoding == scheme.String() {
+				ret[parts[0]] = scheme
+			}
+		}
+	}
+
+	return ret
+}
+
+// trackFECFromMediaDescription returns the FEC capabilities of a media.
+// It returns nil when the media is not protected.
+func trackFECFromMediaDescription(md *psdp.MediaDescription) *trackFEC {
+	for pt, scheme := range fecPayloadTypes(md) {
+		tmp, err := strconv.ParseUint(pt, 10, 8)
+		if err == nil {
+			return &trackFEC{
+				scheme:      scheme,
+				payloadType: uint8(tmp),
+			}
+		}
+	}
+
+	return nil
+}
+
+// trackFECsFromMediaDescriptions returns the FEC capabilities of some medias.
+// It returns nil when no media is protected.
+func trackFECsFromMediaDescriptions(mds []*psdp.MediaDescription) []*trackFEC {
+	var fecs []*trackFEC
+
+	for i, md := range mds {
+		f := trackFECFromMediaDescription(md)
+		if f != nil {
+			if fecs == nil {
+				fecs = make([]*trackFEC, len(mds))
+			}
+			fecs[i] = f
+		}
+	}
+
+	return fecs
+}
+
+// marshal inserts FEC capabilities into the media description of a track.
+func (f *trackFEC) marshal(md *psdp.MediaDescription, clockRate int) {
+	pt := strconv.FormatInt(int64(f.payloadType), 10)
+
+	md.MediaName.Formats = append(md.MediaName.Formats, pt)
+	md.Attributes = append(md.Attributes, psdp.Attribute{
+		Key:   "rtpmap",
+		Value: pt + " " + f.scheme.String() + "/" + strconv.FormatInt(int64(clockRate), 10),
+	})
+
+	// repair-window is mandatory with FlexFEC.
+	// it is the time span, in microseconds, of the protected packets.
+	if f.scheme == rtpfec.SchemeFlexFEC {
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key:   "fmtp",
+			Value: pt + " repair-window=200000",
+		})
+	}
+}
+
+// trackFECReceiver rebuilds lost packets of a track that is being received.
+type trackFECReceiver struct {
+	fec     *trackFEC
+	decoder *rtpfec.Decoder
+}
+
+func newTrackFECReceiver(fec *trackFEC) *trackFECReceiver {
+	return &trackFECReceiver{
+		fec:     fec,
+		decoder: rtpfec.NewDecoder(fec.scheme),
+	}
+}
+
+// processRTP processes a media or repair packet.
+// It returns the media packets that are available, including recovered ones.
+func (r *trackFECReceiver) processRTP(pkt *rtp.Packet) []*rtp.Packet {
+	if pkt.PayloadType == r.fec.payloadType {
+		recovered, err := r.decoder.ProcessRepair(pkt)
+		if err != nil {
+			return nil
+		}
+		return recovered
+	}
+
+	return append([]*rtp.Packet{pkt}, r.decoder.ProcessMedia(pkt)...)
+}
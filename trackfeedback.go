@@ -0,0 +1,293 @@
+package gortsplib
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	psdp "github.com/pion/sdp/v3"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+	"github.com/cobalt-robotics/gortsplib/pkg/rtpretransmitter"
+	"github.com/cobalt-robotics/gortsplib/pkg/srtp"
+)
+
+// trackFeedback contains the RTCP-based feedback messages supported by a track (RFC 4585)
+// and the payload type of its retransmission stream (RFC 4588).
+// It is exchanged through SDP, with the a=rtcp-fb attribute and a rtx format.
+type trackFeedback struct {
+	payloadType    uint8 // payload type of the media
+	nack           bool
+	pli            bool
+	fir            bool
+	rtxPayloadType uint8 // zero when retransmissions are not supported
+}
+
+// newTrackFeedback allocates a trackFeedback that supports all feedback messages
+// and retransmissions, and that uses a payload type that is not used by the track.
+func newTrackFeedback(track Track) *trackFeedback {
+	md := track.MediaDescription()
+
+	var payloadType uint8
+	if len(md.MediaName.Formats) != 0 {
+		tmp, _ := strconv.ParseUint(md.MediaName.Formats[0], 10, 8)
+		payloadType = uint8(tmp)
+	}
+
+	return &trackFeedback{
+		payloadType:    payloadType,
+		nack:           true,
+		pli:            true,
+		fir:            true,
+		rtxPayloadType: unusedPayloadType(md),
+	}
+}
+
+func newTrackFeedbacks(tracks Tracks) []*trackFeedback {
+	feedbacks := make([]*trackFeedback, len(tracks))
+	for i, track := range tracks {
+		feedbacks[i] = newTrackFeedback(track)
+	}
+	return feedbacks
+}
+
+// rtxPayloadTypes returns the payload types of the retransmission formats of a media,
+// associated with the payload types they refer to.
+func rtxPayloadTypes(md *psdp.MediaDescription) map[string]string {
+	rtx := make(map[string]struct{})
+	for _, attr := range md.Attributes {
+		if attr.Key == "rtpmap" {
+			parts := strings.SplitN(strings.TrimSpace(attr.Value), " ", 2)
+			if len(parts) == 2 && strings.HasPrefix(strings.ToLower(parts[1]), "rtx/") {
+				rtx[parts[0]] = struct{}{}
+			}
+		}
+	}
+
+	ret := make(map[string]string)
+
+	for _, attr := range md.Attributes {
+		if attr.Key != "fmtp" {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(attr.Value), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if _, ok := rtx[parts[0]]; !ok {
+			continue
+		}
+
+		for _, kv := range strings.Split(parts[1], ";") {
+			tmp := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+			if len(tmp) == 2 && tmp[0] == "apt" {
+				ret[parts[0]] = tmp[1]
+			}
+		}
+	}
+
+	return ret
+}
+
+// trackFeedbackFromMediaDescription returns the feedback capabilities of a media.
+// It returns nil when the media doesn't support any.
+func trackFeedbackFromMediaDescription(md *psdp.MediaDescription) *trackFeedback {
+	if len(md.MediaName.Formats) == 0 {
+		return nil
+	}
+
+	rtx := rtxPayloadTypes(md)
+	mediaPT := mediaPayloadType(md)
+
+	tmp, err := strconv.ParseUint(mediaPT, 10, 8)
+	if err != nil {
+		return nil
+	}
+
+	f := &trackFeedback{
+		payloadType: uint8(tmp),
+	}
+	found := false
+
+	for _, attr := range md.Attributes {
+		if attr.Key != "rtcp-fb" {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(attr.Value), " ", 2)
+		if len(parts) != 2 || (parts[0] != mediaPT && parts[0] != "*") {
+			continue
+		}
+
+		switch strings.TrimSpace(parts[1]) {
+		case "nack":
+			f.nack = true
+
+		case "nack pli":
+			f.pli = true
+
+		case "ccm fir":
+			f.fir = true
+
+		default:
+			continue
+		}
+
+		found = true
+	}
+
+	for pt, apt := range rtx {
+		if apt == mediaPT {
+			tmp, err := strconv.ParseUint(pt, 10, 8)
+			if err == nil {
+				f.rtxPayloadType = uint8(tmp)
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return f
+}
+
+// trackFeedbacksFromMediaDescriptions returns the feedback capabilities of some medias.
+// It returns nil when no media supports them.
+func trackFeedbacksFromMediaDescriptions(mds []*psdp.MediaDescription) []*trackFeedback {
+	var feedbacks []*trackFeedback
+
+	for i, md := range mds {
+		f := trackFeedbackFromMediaDescription(md)
+		if f != nil {
+			if feedbacks == nil {
+				feedbacks = make([]*trackFeedback, len(mds))
+			}
+			feedbacks[i] = f
+		}
+	}
+
+	return feedbacks
+}
+
+// marshal inserts feedback capabilities into the media description of a track.
+func (f *trackFeedback) marshal(md *psdp.MediaDescription, clockRate int) {
+	if len(md.MediaName.Formats) == 0 {
+		return
+	}
+	pt := md.MediaName.Formats[0]
+
+	if f.nack {
+		md.Attributes = append(md.Attributes, psdp.Attribute{Key: "rtcp-fb", Value: pt + " nack"})
+	}
+
+	if f.pli {
+		md.Attributes = append(md.Attributes, psdp.Attribute{Key: "rtcp-fb", Value: pt + " nack pli"})
+	}
+
+	if f.fir {
+		md.Attributes = append(md.Attributes, psdp.Attribute{Key: "rtcp-fb", Value: pt + " ccm fir"})
+	}
+
+	if f.rtxPayloadType != 0 {
+		rtxPT := strconv.FormatInt(int64(f.rtxPayloadType), 10)
+		md.MediaName.Formats = append(md.MediaName.Formats, rtxPT)
+		md.Attributes = append(md.Attributes,
+			psdp.Attribute{
+				Key:   "rtpmap",
+				Value: rtxPT + " rtx/" + strconv.FormatInt(int64(clockRate), 10),
+			},
+			psdp.Attribute{
+				Key:   "fmtp",
+				Value: rtxPT + " apt=" + pt,
+			})
+	}
+}
+
+// transportProfile returns the transport profile to use with a track,
+// given its crypto context and feedback capabilities.
+func transportProfile(ctx *srtp.Context, f *trackFeedback) headers.TransportProfile {
+	switch {
+	case ctx != nil && f != nil:
+		return headers.TransportProfileSAVPF
+	case ctx != nil:
+		return headers.TransportProfileSAVP
+	case f != nil:
+		return headers.TransportProfileAVPF
+	}
+	return headers.TransportProfileAVP
+}
+
+// trackFeedbackReceiver sends feedback messages about a track that is being received.
+type trackFeedbackReceiver struct {
+	feedback  *trackFeedback
+	mediaSSRC uint32
+}
+
+func newTrackFeedbackReceiver(feedback *trackFeedback) *trackFeedbackReceiver {
+	return &trackFeedbackReceiver{
+		feedback: feedback,
+	}
+}
+
+// processRTP decodes retransmission packets.
+// It returns false when the packet must be discarded.
+func (r *trackFeedbackReceiver) processRTP(pkt *rtp.Packet) (*rtp.Packet, bool) {
+	if r.feedback.rtxPayloadType != 0 && pkt.PayloadType == r.feedback.rtxPayloadType {
+		mediaSSRC := atomic.LoadUint32(&r.mediaSSRC)
+		if mediaSSRC == 0 {
+			return nil, false
+		}
+
+		pkt, err := rtpretransmitter.Decode(pkt, r.feedback.payloadType, mediaSSRC)
+		if err != nil {
+			return nil, false
+		}
+
+		return pkt, true
+	}
+
+	atomic.StoreUint32(&r.mediaSSRC, pkt.SSRC)
+	return pkt, true
+}
+
+// nack returns a NACK that requests the retransmission of missing packets,
+// or nil if there are no missing packets.
+func (r *trackFeedbackReceiver) nack(missing []uint16) rtcp.Packet {
+	if !r.feedback.nack || r.feedback.rtxPayloadType == 0 || len(missing) == 0 {
+		return nil
+	}
+
+	return &rtcp.TransportLayerNack{
+		MediaSSRC: atomic.LoadUint32(&r.mediaSSRC),
+		Nacks:     rtcp.NackPairsFromSequenceNumbers(missing),
+	}
+}
+
+// keyFrameRequest returns a PLI or FIR message that requests a key frame,
+// or nil if the sender doesn't support them.
+func (r *trackFeedbackReceiver) keyFrameRequest() rtcp.Packet {
+	mediaSSRC := atomic.LoadUint32(&r.mediaSSRC)
+
+	switch {
+	case r.feedback.pli:
+		return &rtcp.PictureLossIndication{
+			MediaSSRC: mediaSSRC,
+		}
+
+	case r.feedback.fir:
+		return &rtcp.FullIntraRequest{
+			MediaSSRC: mediaSSRC,
+			FIR: []rtcp.FIREntry{{
+				SSRC: mediaSSRC,
+			}},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package gortsplib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackFeedbackUnmarshal(t *testing.T) {
+	sdp := []byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"c=IN IP4 0.0.0.0\r\n" +
+		"t=0 0\r\n" +
+		"m=video 0 RTP/AVPF 97 96\r\n" +
+		"a=rtpmap:97 rtx/90000\r\n" +
+		"a=fmtp:97 apt=96;rtx-time=3000\r\n" +
+		"a=rtpmap:96 H264/90000\r\n" +
+		"a=fmtp:96 packetization-mode=1; sprop-parameter-sets=Z2QADKw7ULBLQgAAAwACAAADAD0I,aO48gA==\r\n" +
+		"a=rtcp-fb:96 nack\r\n" +
+		"a=rtcp-fb:* ccm fir\r\n" +
+		"a=control:trackID=0\r\n" +
+		"m=audio 0 RTP/AVP 8\r\n" +
+		"a=control:trackID=1\r\n")
+
+	var tracks Tracks
+	_, mds, err := tracks.unmarshal(sdp, false)
+	require.NoError(t, err)
+
+	require.IsType(t, &TrackH264{}, tracks[0])
+	require.Equal(t, uint8(96), tracks[0].(*TrackH264).PayloadType)
+
+	require.Equal(t, []*trackFeedback{
+		{
+			payloadType:    96,
+			nack:           true,
+			fir:            true,
+			rtxPayloadType: 97,
+		},
+		nil,
+	}, trackFeedbacksFromMediaDescriptions(mds))
+}
+
+func TestTrackFeedbackMarshal(t *testing.T) {
+	track := &TrackH264{
+		PayloadType: 96,
+		SPS:         []byte{0x01, 0x02, 0x03, 0x04},
+		PPS:         []byte{0x01, 0x02, 0x03, 0x04},
+	}
+	track.SetControl("trackID=0")
+
+	byts, err := Tracks{track}.marshal(false, nil, newTrackFeedbacks(Tracks{track}), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "v=0\r\n"+
+		"o=- 0 0 IN IP4 127.0.0.1\r\n"+
+		"s=Stream\r\n"+
+		"c=IN IP4 0.0.0.0\r\n"+
+		"t=0 0\r\n"+
+		"m=video 0 RTP/AVPF 96 97\r\n"+
+		"a=rtpmap:96 H264/90000\r\n"+
+		"a=fmtp:96 packetization-mode=1; sprop-parameter-sets=AQIDBA==,AQIDBA==; profile-level-id=020304\r\n"+
+		"a=control:trackID=0\r\n"+
+		"a=rtcp-fb:96 nack\r\n"+
+		"a=rtcp-fb:96 nack pli\r\n"+
+		"a=rtcp-fb:96 ccm fir\r\n"+
+		"a=rtpmap:97 rtx/90000\r\n"+
+		"a=fmtp:97 apt=96\r\n", string(byts))
+
+	var tracks Tracks
+	_, mds, err := tracks.unmarshal(byts, false)
+	require.NoError(t, err)
+	require.Equal(t, Tracks{track}, tracks)
+	require.Equal(t, newTrackFeedbacks(Tracks{track}), trackFeedbacksFromMediaDescriptions(mds))
+}
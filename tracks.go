@@ -15,32 +15,44 @@ type Tracks []Track
 
 // Unmarshal decodes tracks from the SDP format. It returns the decoded SDP.
 func (ts *Tracks) Unmarshal(byts []byte, skipGenericTracksWithoutClockRate bool) (*sdp.SessionDescription, error) {
+	sd, _, err := ts.unmarshal(byts, skipGenericTracksWithoutClockRate)
+	return sd, err
+}
+
+// unmarshal decodes tracks from the SDP format.
+// It returns the decoded SDP and the media descriptions of the tracks.
+func (ts *Tracks) unmarshal(
+	byts []byte,
+	skipGenericTracksWithoutClockRate bool,
+) (*sdp.SessionDescription, []*psdp.MediaDescription, error) {
 	var sd sdp.SessionDescription
 	err := sd.Unmarshal(byts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	*ts = nil
+	var mds []*psdp.MediaDescription
 
 	for i, md := range sd.MediaDescriptions {
-		t, err := newTrackFromMediaDescription(md)
+		t, err := newTrackFromMediaDescription(mediaDescriptionWithoutRepairFormats(md))
 		if err != nil {
 			if skipGenericTracksWithoutClockRate &&
 				strings.HasPrefix(err.Error(), "unable to get clock rate") {
 				continue
 			}
-			return nil, fmt.Errorf("unable to parse track %d: %s", i+1, err)
+			return nil, nil, fmt.Errorf("unable to parse track %d: %s", i+1, err)
 		}
 
 		*ts = append(*ts, t)
+		mds = append(mds, md)
 	}
 
 	if *ts == nil {
-		return nil, fmt.Errorf("no valid tracks found")
+		return nil, nil, fmt.Errorf("no valid tracks found")
 	}
 
-	return &sd, nil
+	return &sd, mds, nil
 }
 
 func (ts Tracks) clone() Tracks {
@@ -59,6 +71,22 @@ func (ts Tracks) setControls() {
 
 // Marshal encodes tracks in the SDP format.
 func (ts Tracks) Marshal(multicast bool) []byte {
+	byts, _ := ts.marshal(multicast, nil, nil, nil)
+	return byts
+}
+
+// marshal encodes tracks in the SDP format.
+// When keys are provided, tracks are marked as encrypted with SRTP
+// and keys are inserted into a=key-mgmt attributes.
+// When feedbacks are provided, tracks are marked as supporting RTCP-based feedback
+// and feedback capabilities are inserted into a=rtcp-fb attributes.
+// When FECs are provided, FEC formats are added to tracks.
+func (ts Tracks) marshal(
+	multicast bool,
+	keys []*srtpKey,
+	feedbacks []*trackFeedback,
+	fecs []*trackFEC,
+) ([]byte, error) {
 	address := "0.0.0.0"
 	if multicast {
 		address = "224.1.0.0"
@@ -83,10 +111,126 @@ func (ts Tracks) Marshal(multicast bool) []byte {
 		},
 	}
 
-	for _, track := range ts {
-		sout.MediaDescriptions = append(sout.MediaDescriptions, track.MediaDescription())
+	for i, track := range ts {
+		md := track.MediaDescription()
+
+		var key *srtpKey
+		if keys != nil {
+			key = keys[i]
+		}
+
+		var feedback *trackFeedback
+		if feedbacks != nil {
+			feedback = feedbacks[i]
+		}
+
+		if key != nil || feedback != nil {
+			profile := "AVP"
+			if key != nil {
+				profile = "SAVP"
+			}
+			if feedback != nil {
+				profile += "F"
+			}
+			md.MediaName.Protos = []string{"RTP", profile}
+		}
+
+		if key != nil {
+			attr, err := key.keyMgmtAttribute()
+			if err != nil {
+				return nil, err
+			}
+			md.Attributes = append(md.Attributes, attr)
+		}
+
+		if feedback != nil {
+			feedback.marshal(md, track.ClockRate())
+		}
+
+		if fecs != nil && fecs[i] != nil {
+			fecs[i].marshal(md, track.ClockRate())
+		}
+
+		sout.MediaDescriptions = append(sout.MediaDescriptions, md)
 	}
 
-	byts, _ := sout.Marshal()
-	return byts
+	return sout.Marshal()
+}
+
+// repairPayloadTypes returns the payload types of the formats of a media
+// that are used to repair lost packets.
+func repairPayloadTypes(md *psdp.MediaDescription) map[string]struct{} {
+	ret := make(map[string]struct{})
+	for pt := range rtxPayloadTypes(md) {
+		ret[pt] = struct{}{}
+	}
+	for pt := range fecPayloadTypes(md) {
+		ret[pt] = struct{}{}
+	}
+	return ret
+}
+
+// mediaPayloadType returns the payload type of the media,
+// that is the first one that is not a repair format.
+func mediaPayloadType(md *psdp.MediaDescription) string {
+	repair := repairPayloadTypes(md)
+	for _, f := range md.MediaName.Formats {
+		if _, ok := repair[f]; !ok {
+			return f
+		}
+	}
+	return ""
+}
+
+// unusedPayloadType returns a dynamic payload type that is not used
+// by a media, nor by the reserved payload types, or zero if there are none.
+func unusedPayloadType(md *psdp.MediaDescription, reserved ...uint8) uint8 {
+	used := make(map[string]struct{})
+	for _, f := range md.MediaName.Formats {
+		used[f] = struct{}{}
+	}
+	for _, pt := range reserved {
+		used[strconv.FormatInt(int64(pt), 10)] = struct{}{}
+	}
+
+	for pt := 96; pt < 128; pt++ {
+		if _, ok := used[strconv.FormatInt(int64(pt), 10)]; !ok {
+			return uint8(pt)
+		}
+	}
+	return 0
+}
+
+// mediaDescriptionWithoutRepairFormats returns a copy of a media description
+// without repair formats, that are not part of the track.
+func mediaDescriptionWithoutRepairFormats(md *psdp.MediaDescription) *psdp.MediaDescription {
+	repair := repairPayloadTypes(md)
+	if len(repair) == 0 {
+		return md
+	}
+
+	isRepair := func(value string) bool {
+		pt := strings.SplitN(strings.TrimSpace(value), " ", 2)[0]
+		_, ok := repair[pt]
+		return ok
+	}
+
+	out := *md
+	out.MediaName.Formats = nil
+	out.Attributes = nil
+
+	for _, f := range md.MediaName.Formats {
+		if _, ok := repair[f]; !ok {
+			out.MediaName.Formats = append(out.MediaName.Formats, f)
+		}
+	}
+
+	for _, attr := range md.Attributes {
+		if (attr.Key == "rtpmap" || attr.Key == "fmtp" || attr.Key == "rtcp-fb") && isRepair(attr.Value) {
+			continue
+		}
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	return &out
 }
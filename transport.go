@@ -1,5 +1,11 @@
 package gortsplib
 
+import (
+	"net"
+
+	"github.com/cobalt-robotics/gortsplib/pkg/headers"
+)
+
 // Transport is a RTSP transport protocol.
 type Transport int
 
@@ -23,3 +29,73 @@ func (t Transport) String() string {
 	}
 	return "unknown"
 }
+
+// transportHeaderFromV20 fills the RTSP 1.0 fields of a Transport header
+// with the addresses contained in the RTSP 2.0 dest_addr and src_addr parameters.
+func transportHeaderFromV20(th *headers.Transport, isRequest bool) {
+	if th.Delivery != nil && *th.Delivery == headers.TransportDeliveryMulticast {
+		if len(th.DestinationAddresses) == 2 && th.Destination == nil && th.Ports == nil {
+			if th.DestinationAddresses[0].IP != nil {
+				ip := th.DestinationAddresses[0].IP
+				th.Destination = &ip
+			}
+			th.Ports = &[2]int{th.DestinationAddresses[0].Port, th.DestinationAddresses[1].Port}
+		}
+		return
+	}
+
+	if len(th.DestinationAddresses) == 2 && th.ClientPorts == nil {
+		// in requests, dest_addr contains the client addresses.
+		// in responses, it repeats them.
+		th.ClientPorts = &[2]int{th.DestinationAddresses[0].Port, th.DestinationAddresses[1].Port}
+	}
+
+	if !isRequest && len(th.SourceAddresses) == 2 && th.ServerPorts == nil {
+		if th.SourceAddresses[0].IP != nil && th.Source == nil {
+			ip := th.SourceAddresses[0].IP
+			th.Source = &ip
+		}
+		th.ServerPorts = &[2]int{th.SourceAddresses[0].Port, th.SourceAddresses[1].Port}
+	}
+}
+
+// transportHeaderToV20 replaces the RTSP 1.0 fields of a Transport header
+// with the RTSP 2.0 dest_addr and src_addr parameters.
+func transportHeaderToV20(th *headers.Transport) {
+	if th.Delivery != nil && *th.Delivery == headers.TransportDeliveryMulticast {
+		if th.Ports != nil {
+			var ip net.IP
+			if th.Destination != nil {
+				ip = *th.Destination
+			}
+			th.DestinationAddresses = []headers.TransportAddress{
+				{IP: ip, Port: th.Ports[0]},
+				{IP: ip, Port: th.Ports[1]},
+			}
+			th.Destination = nil
+			th.Ports = nil
+		}
+		return
+	}
+
+	if th.ClientPorts != nil {
+		th.DestinationAddresses = []headers.TransportAddress{
+			{Port: th.ClientPorts[0]},
+			{Port: th.ClientPorts[1]},
+		}
+		th.ClientPorts = nil
+	}
+
+	if th.ServerPorts != nil {
+		var ip net.IP
+		if th.Source != nil {
+			ip = *th.Source
+		}
+		th.SourceAddresses = []headers.TransportAddress{
+			{IP: ip, Port: th.ServerPorts[0]},
+			{IP: ip, Port: th.ServerPorts[1]},
+		}
+		th.Source = nil
+		th.ServerPorts = nil
+	}
+}